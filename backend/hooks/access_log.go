@@ -0,0 +1,297 @@
+package hooks
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"facet/services"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	accessEventsPageSize    = 50
+	accessEventsMaxPageSize = 500
+)
+
+// AccessEvent is one visit as returned to the admin
+type AccessEvent struct {
+	ID           string `json:"id"`
+	Created      string `json:"created"`
+	ViewID       string `json:"view_id"`
+	ViewName     string `json:"view_name"`
+	ViewSlug     string `json:"view_slug"`
+	ShareTokenID string `json:"share_token_id"`
+	TokenName    string `json:"token_name"`
+	VisitorID    string `json:"visitor_id"`
+	UAFamily     string `json:"ua_family"`
+	IsBot        bool   `json:"is_bot"`
+	ReferrerHost string `json:"referrer_host"`
+	Country      string `json:"country"`
+}
+
+// AccessTokenSummary is the visit history of one share token
+type AccessTokenSummary struct {
+	ShareTokenID string   `json:"share_token_id"`
+	TokenName    string   `json:"token_name"`
+	ViewID       string   `json:"view_id"`
+	Opens        int      `json:"opens"`
+	BotOpens     int      `json:"bot_opens"`
+	Visitors     int      `json:"visitors"`
+	FirstSeen    string   `json:"first_seen"`
+	LastSeen     string   `json:"last_seen"`
+	Countries    []string `json:"countries"`
+	Referrers    []string `json:"referrers"`
+}
+
+// RegisterAccessLogHooks registers the access log endpoints and retention job
+func RegisterAccessLogHooks(app *pocketbase.PocketBase, access *services.AccessLogService) {
+	// Prune old events once a day
+	app.Cron().MustAdd("pruneAccessEvents", "30 3 * * *", func() {
+		deleted, err := pruneAccessEvents(app, time.Now())
+		if err != nil {
+			app.Logger().Warn("Failed to prune access events", "error", err)
+		} else if deleted > 0 {
+			app.Logger().Info("Pruned access events", "deleted", deleted)
+		}
+	})
+
+	app.OnTerminate().BindFunc(func(e *core.TerminateEvent) error {
+		access.Close()
+		return e.Next()
+	})
+
+	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
+		// Who opened my links and when, newest first
+		// GET /api/access-events?view_id=&token_id=&since=2024-01-31&include_bots=true&page=1&limit=50
+		se.Router.GET("/api/access-events", func(e *core.RequestEvent) error {
+			query := e.Request.URL.Query()
+			where, err := accessEventsWhere(query.Get("view_id"), query.Get("token_id"), query.Get("since"), query.Get("include_bots") == "true")
+			if err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			}
+
+			limit, _ := strconv.Atoi(query.Get("limit"))
+			if limit <= 0 {
+				limit = accessEventsPageSize
+			}
+			if limit > accessEventsMaxPageSize {
+				limit = accessEventsMaxPageSize
+			}
+			page, _ := strconv.Atoi(query.Get("page"))
+			if page < 1 {
+				page = 1
+			}
+
+			total, err := app.CountRecords("access_events", where)
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load access events"})
+			}
+			var records []*core.Record
+			err = app.RecordQuery("access_events").
+				AndWhere(where).
+				OrderBy("created DESC").
+				Limit(int64(limit)).
+				Offset(int64((page - 1) * limit)).
+				All(&records)
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load access events"})
+			}
+
+			return e.JSON(http.StatusOK, map[string]interface{}{
+				"items": accessEventsResponse(app, records),
+				"page":  page,
+				"limit": limit,
+				"total": total,
+			})
+		}).Bind(apis.RequireAuth())
+
+		// Per-token summary: opens, distinct visitors, first and last visit
+		// GET /api/access-events/tokens?view_id=
+		se.Router.GET("/api/access-events/tokens", func(e *core.RequestEvent) error {
+			summaries, err := summarizeTokenAccess(app, e.Request.URL.Query().Get("view_id"))
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to summarize access events"})
+			}
+			return e.JSON(http.StatusOK, map[string]interface{}{"tokens": summaries})
+		}).Bind(apis.RequireAuth())
+
+		return se.Next()
+	})
+}
+
+// pruneAccessEvents deletes events older than the configured retention
+func pruneAccessEvents(app core.App, now time.Time) (int64, error) {
+	settings, err := services.LoadSiteSettings(app)
+	if err != nil {
+		return 0, err
+	}
+	if settings.AccessLogRetentionDays <= 0 {
+		return 0, nil
+	}
+
+	cutoff, err := types.ParseDateTime(now.AddDate(0, 0, -settings.AccessLogRetentionDays))
+	if err != nil {
+		return 0, err
+	}
+	result, err := app.DB().Delete("access_events", dbx.NewExp("created < {:cutoff}", dbx.Params{"cutoff": cutoff.String()})).Execute()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// accessEventsWhere builds the condition for the list endpoint
+func accessEventsWhere(viewID, tokenID, since string, includeBots bool) (dbx.Expression, error) {
+	conditions := []dbx.Expression{}
+
+	if viewID != "" {
+		conditions = append(conditions, dbx.HashExp{"view": viewID})
+	}
+	if tokenID != "" {
+		conditions = append(conditions, dbx.HashExp{"share_token": tokenID})
+	}
+	if since != "" {
+		sinceTime, err := time.Parse("2006-01-02", since)
+		if err != nil {
+			sinceTime, err = time.Parse(time.RFC3339, since)
+		}
+		if err != nil {
+			return nil, errors.New("invalid since date (use YYYY-MM-DD)")
+		}
+		dt, _ := types.ParseDateTime(sinceTime)
+		conditions = append(conditions, dbx.NewExp("created >= {:since}", dbx.Params{"since": dt.String()}))
+	}
+	if !includeBots {
+		conditions = append(conditions, dbx.HashExp{"is_bot": false})
+	}
+
+	return dbx.And(conditions...), nil
+}
+
+// accessEventsResponse resolves view and token names for a page of events
+func accessEventsResponse(app core.App, records []*core.Record) []AccessEvent {
+	views := map[string]*core.Record{}
+	tokens := map[string]*core.Record{}
+	lookup := func(cache map[string]*core.Record, collection, id string) *core.Record {
+		if id == "" {
+			return nil
+		}
+		if record, ok := cache[id]; ok {
+			return record
+		}
+		record, _ := app.FindRecordById(collection, id)
+		cache[id] = record
+		return record
+	}
+
+	items := make([]AccessEvent, 0, len(records))
+	for _, record := range records {
+		item := AccessEvent{
+			ID:           record.Id,
+			Created:      formatPipelineDate(record.GetDateTime("created")),
+			ViewID:       record.GetString("view"),
+			ShareTokenID: record.GetString("share_token"),
+			VisitorID:    record.GetString("ip_hash"),
+			UAFamily:     record.GetString("ua_family"),
+			IsBot:        record.GetBool("is_bot"),
+			ReferrerHost: record.GetString("referrer_host"),
+			Country:      record.GetString("country"),
+		}
+		if view := lookup(views, "views", item.ViewID); view != nil {
+			item.ViewName = view.GetString("name")
+			item.ViewSlug = view.GetString("slug")
+		}
+		if token := lookup(tokens, "share_tokens", item.ShareTokenID); token != nil {
+			item.TokenName = token.GetString("name")
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// summarizeTokenAccess aggregates events per share token. Visitors counts
+// distinct IP hashes; because the salt rotates daily, a person returning on
+// another day counts again.
+func summarizeTokenAccess(app core.App, viewID string) ([]AccessTokenSummary, error) {
+	where := dbx.NewExp("share_token != ''")
+	if viewID != "" {
+		where = dbx.And(where, dbx.HashExp{"view": viewID})
+	}
+
+	var rows []struct {
+		ShareToken string `db:"share_token"`
+		View       string `db:"view"`
+		Opens      int    `db:"opens"`
+		BotOpens   int    `db:"bot_opens"`
+		Visitors   int    `db:"visitors"`
+		FirstSeen  string `db:"first_seen"`
+		LastSeen   string `db:"last_seen"`
+		Countries  string `db:"countries"`
+		Referrers  string `db:"referrers"`
+	}
+	err := app.DB().Select(
+		"share_token",
+		"MAX(view) AS view",
+		"SUM(CASE WHEN is_bot THEN 0 ELSE 1 END) AS opens",
+		"SUM(CASE WHEN is_bot THEN 1 ELSE 0 END) AS bot_opens",
+		"COUNT(DISTINCT CASE WHEN is_bot THEN NULL ELSE NULLIF(ip_hash, '') END) AS visitors",
+		"MIN(created) AS first_seen",
+		"MAX(created) AS last_seen",
+		"COALESCE(GROUP_CONCAT(DISTINCT NULLIF(country, '')), '') AS countries",
+		"COALESCE(GROUP_CONCAT(DISTINCT NULLIF(referrer_host, '')), '') AS referrers",
+	).
+		From("access_events").
+		Where(where).
+		GroupBy("share_token").
+		OrderBy("last_seen DESC").
+		All(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]AccessTokenSummary, 0, len(rows))
+	for _, row := range rows {
+		summary := AccessTokenSummary{
+			ShareTokenID: row.ShareToken,
+			ViewID:       row.View,
+			Opens:        row.Opens,
+			BotOpens:     row.BotOpens,
+			Visitors:     row.Visitors,
+			FirstSeen:    formatAccessDate(row.FirstSeen),
+			LastSeen:     formatAccessDate(row.LastSeen),
+			Countries:    splitNonEmpty(row.Countries),
+			Referrers:    splitNonEmpty(row.Referrers),
+		}
+		if token, err := app.FindRecordById("share_tokens", row.ShareToken); err == nil {
+			summary.TokenName = token.GetString("name")
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+func formatAccessDate(value string) string {
+	dt, err := types.ParseDateTime(value)
+	if err != nil {
+		return ""
+	}
+	return formatPipelineDate(dt)
+}
+
+func splitNonEmpty(value string) []string {
+	result := []string{}
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
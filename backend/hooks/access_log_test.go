@@ -0,0 +1,95 @@
+package hooks
+
+import (
+	"testing"
+	"time"
+
+	"facet/services"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestAccessEventsSummaryAndRetention(t *testing.T) {
+	app := newMigratedTestApp(t)
+	_, _, viewID := seedImportFixture(t, app)
+
+	tokens, _ := app.FindCollectionByNameOrId("share_tokens")
+	token := core.NewRecord(tokens)
+	token.Set("view_id", viewID)
+	token.Set("token_hash", "hash")
+	token.Set("token_prefix", "abc")
+	token.Set("name", "Sent to Babbage")
+	token.Set("is_active", true)
+	if err := app.Save(token); err != nil {
+		t.Fatalf("Failed to save token: %v", err)
+	}
+
+	visits := []services.AccessVisit{
+		{IPHash: "a", UAFamily: "Safari on macOS", ReferrerHost: "linkedin.com", Country: "GB"},
+		{IPHash: "a", UAFamily: "Safari on macOS"},
+		{IPHash: "b", UAFamily: "Firefox on Linux", Country: "FR"},
+		{IPHash: "c", UAFamily: "Slack", IsBot: true},
+	}
+	for _, visit := range visits {
+		if err := services.SaveAccessEvent(app, viewID, token.Id, visit); err != nil {
+			t.Fatalf("SaveAccessEvent() error = %v", err)
+		}
+	}
+	if err := services.SaveAccessEvent(app, viewID, "", services.AccessVisit{IPHash: "d"}); err != nil {
+		t.Fatalf("SaveAccessEvent() without token error = %v", err)
+	}
+
+	summaries, err := summarizeTokenAccess(app, viewID)
+	if err != nil {
+		t.Fatalf("summarizeTokenAccess() error = %v", err)
+	}
+	if len(summaries) != 1 {
+		t.Fatalf("summaries = %+v, want one token", summaries)
+	}
+	got := summaries[0]
+	if got.TokenName != "Sent to Babbage" || got.Opens != 3 || got.BotOpens != 1 || got.Visitors != 2 {
+		t.Errorf("summary = %+v", got)
+	}
+	if len(got.Countries) != 2 || len(got.Referrers) != 1 || got.Referrers[0] != "linkedin.com" {
+		t.Errorf("countries = %v, referrers = %v", got.Countries, got.Referrers)
+	}
+	if got.FirstSeen == "" || got.LastSeen == "" {
+		t.Errorf("first/last seen missing: %+v", got)
+	}
+
+	where, err := accessEventsWhere(viewID, token.Id, "", false)
+	if err != nil {
+		t.Fatalf("accessEventsWhere() error = %v", err)
+	}
+	if count, _ := app.CountRecords("access_events", where); count != 3 {
+		t.Errorf("token events without bots = %d, want 3", count)
+	}
+	if _, err := accessEventsWhere("", "", "yesterday", false); err == nil {
+		t.Error("expected an error for an invalid since date")
+	}
+
+	// Age two events past the 90 day default retention
+	old := time.Now().AddDate(0, 0, -91).UTC().Format("2006-01-02 15:04:05.000Z")
+	if _, err := app.DB().Update("access_events", dbx.Params{"created": old}, dbx.In("ip_hash", "b", "d")).Execute(); err != nil {
+		t.Fatalf("Failed to age events: %v", err)
+	}
+	deleted, err := pruneAccessEvents(app, time.Now())
+	if err != nil {
+		t.Fatalf("pruneAccessEvents() error = %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("deleted = %d, want 2", deleted)
+	}
+
+	// Retention 0 keeps everything
+	if _, err := services.UpdateSiteSettings(app, map[string]any{"access_log_retention_days": 0}, nil); err != nil {
+		t.Fatalf("UpdateSiteSettings() error = %v", err)
+	}
+	if _, err := app.DB().Update("access_events", dbx.Params{"created": old}, nil).Execute(); err != nil {
+		t.Fatalf("Failed to age events: %v", err)
+	}
+	if deleted, _ := pruneAccessEvents(app, time.Now()); deleted != 0 {
+		t.Errorf("deleted = %d with retention disabled", deleted)
+	}
+}
@@ -0,0 +1,382 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"facet/services"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+)
+
+// maxPendingAccessRequests caps the unanswered requests per view, so a flood
+// of submissions cannot grow the table without bound
+const maxPendingAccessRequests = 100
+
+var (
+	errAccessRequestsFull = errors.New("too many pending requests")
+	// Approval mints a share link, which opens unlisted views and (when minted
+	// by an approval) private ones
+	errAccessRequestViewNotShareable = errors.New("share links only open unlisted and private views: change the view's visibility to approve")
+	errAccessRequestsUnavailable     = errors.New("access requests are only available on unlisted and private views")
+)
+
+// accessRequestInput is what a visitor submits. Website is a honeypot: the
+// form hides it, so only bots fill it in.
+type accessRequestInput struct {
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Company string `json:"company"`
+	Reason  string `json:"reason"`
+	Website string `json:"website"`
+}
+
+// accessRequestApproval holds the optional limits of the token minted on approval
+type accessRequestApproval struct {
+	ExpiresAt string `json:"expires_at"`
+	MaxUses   int    `json:"max_uses"`
+	SendEmail bool   `json:"send_email"`
+}
+
+// RegisterAccessRequestHooks registers the request access endpoints
+func RegisterAccessRequestHooks(app *pocketbase.PocketBase, share *services.ShareService, rl *services.RateLimitService) {
+	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
+		// Ask for access to an unlisted or private view that opted in
+		// Rate limited: strict tier (5/min), plus a honeypot and a pending cap per view
+		se.Router.POST("/api/view/{slug}/request-access", RateLimitMiddleware(rl, "strict")(func(e *core.RequestEvent) error {
+			view, err := app.FindFirstRecordByFilter(
+				"views",
+				"slug = {:slug} && is_active = true",
+				dbx.Params{"slug": e.Request.PathValue("slug")},
+			)
+			if err != nil || !acceptsAccessRequests(view) {
+				return e.JSON(http.StatusNotFound, map[string]string{"error": "view not found"})
+			}
+
+			var req accessRequestInput
+			if err := e.BindBody(&req); err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+			}
+
+			// Bots get the same answer as people, but nothing is stored
+			if req.Website != "" {
+				return e.JSON(http.StatusOK, map[string]string{"status": "received"})
+			}
+
+			input, err := normalizeAccessRequest(req)
+			if err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			}
+
+			if err := submitAccessRequest(app, view, input); err != nil {
+				if errors.Is(err, errAccessRequestsFull) {
+					return e.JSON(http.StatusTooManyRequests, map[string]string{"error": "this view is not taking requests right now"})
+				}
+				app.Logger().Error("Failed to save access request", "error", err, "view_id", view.Id)
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save request"})
+			}
+
+			return e.JSON(http.StatusOK, map[string]string{"status": "received"})
+		}))
+
+		// List access requests, newest first (admin only)
+		// GET /api/access-requests?status=pending
+		se.Router.GET("/api/access-requests", func(e *core.RequestEvent) error {
+			filter := "1=1"
+			params := dbx.Params{}
+			if status := e.Request.URL.Query().Get("status"); status != "" {
+				filter = "status = {:status}"
+				params["status"] = status
+			}
+
+			records, err := app.FindRecordsByFilter("access_requests", filter, "-created", 200, 0, params)
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to fetch access requests"})
+			}
+			if errs := app.ExpandRecords(records, []string{"view"}, nil); len(errs) > 0 {
+				app.Logger().Warn("Failed to expand access request views", "errors", errs)
+			}
+
+			result := make([]map[string]interface{}, 0, len(records))
+			for _, r := range records {
+				viewName := ""
+				if view := r.ExpandedOne("view"); view != nil {
+					viewName = view.GetString("name")
+				}
+				result = append(result, map[string]interface{}{
+					"id":          r.Id,
+					"view_id":     r.GetString("view"),
+					"view_name":   viewName,
+					"name":        r.GetString("name"),
+					"email":       r.GetString("email"),
+					"company":     r.GetString("company"),
+					"reason":      r.GetString("reason"),
+					"status":      r.GetString("status"),
+					"share_token": r.GetString("share_token"),
+					"reviewed_at": r.GetDateTime("reviewed_at"),
+					"created":     r.GetDateTime("created"),
+				})
+			}
+
+			return e.JSON(http.StatusOK, map[string]interface{}{"requests": result})
+		}).Bind(apis.RequireAuth())
+
+		// Approve a request: mints a share token and optionally emails the link
+		se.Router.POST("/api/access-requests/{id}/approve", func(e *core.RequestEvent) error {
+			record, err := app.FindRecordById("access_requests", e.Request.PathValue("id"))
+			if err != nil {
+				return e.JSON(http.StatusNotFound, map[string]string{"error": "access request not found"})
+			}
+			if record.GetString("status") != "pending" {
+				return e.JSON(http.StatusConflict, map[string]string{"error": "access request was already answered"})
+			}
+
+			var req accessRequestApproval
+			if err := e.BindBody(&req); err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+			}
+
+			// An emailed link is never built from request headers; a link only
+			// shown to the signed-in owner can be
+			baseURL := resolveBaseURL(e)
+			if req.SendEmail {
+				baseURL = configuredAppURL(app)
+				if baseURL == "" {
+					return e.JSON(http.StatusServiceUnavailable, map[string]string{"error": "set APP_URL to email share links"})
+				}
+			}
+			var expiresAt time.Time
+			if req.ExpiresAt != "" {
+				expiresAt, err = parseShareExpiry(req.ExpiresAt)
+				if err != nil {
+					return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid expiration date format"})
+				}
+			}
+
+			tokenRecord, rawToken, err := approveAccessRequest(app, share, record, expiresAt, req.MaxUses)
+			if errors.Is(err, errAccessRequestViewNotShareable) {
+				return e.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+			}
+			if err != nil {
+				app.Logger().Error("Failed to approve access request", "error", err, "request_id", record.Id)
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to create share link"})
+			}
+
+			link := baseURL + "/s/" + url.PathEscape(rawToken)
+			emailed := false
+			if req.SendEmail {
+				viewName := ""
+				if view, err := app.FindRecordById("views", record.GetString("view")); err == nil {
+					viewName = view.GetString("name")
+				}
+				if err := sendAccessGrantedEmail(app, record.GetString("email"), record.GetString("name"), viewName, link); err != nil {
+					app.Logger().Warn("Failed to email access link", "error", err, "request_id", record.Id)
+				} else {
+					emailed = true
+				}
+			}
+
+			return e.JSON(http.StatusOK, map[string]interface{}{
+				"token_id": tokenRecord.Id,
+				"token":    rawToken, // Only returned once!
+				"url":      link,
+				"emailed":  emailed,
+			})
+		}).Bind(apis.RequireAuth())
+
+		// Decline a request; nothing is sent to the visitor
+		se.Router.POST("/api/access-requests/{id}/decline", func(e *core.RequestEvent) error {
+			record, err := app.FindRecordById("access_requests", e.Request.PathValue("id"))
+			if err != nil {
+				return e.JSON(http.StatusNotFound, map[string]string{"error": "access request not found"})
+			}
+			if record.GetString("status") != "pending" {
+				return e.JSON(http.StatusConflict, map[string]string{"error": "access request was already answered"})
+			}
+
+			record.Set("status", "declined")
+			record.Set("reviewed_at", time.Now())
+			if err := app.Save(record); err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to decline"})
+			}
+
+			return e.JSON(http.StatusOK, map[string]string{"status": "declined"})
+		}).Bind(apis.RequireAuth())
+
+		return se.Next()
+	})
+}
+
+// acceptsAccessRequests reports whether visitors may ask for access to a view.
+// Only unlisted and private views qualify, since public and password views have
+// their own way in. A private view that opts in reveals that it exists.
+func acceptsAccessRequests(view *core.Record) bool {
+	return view.GetBool("allow_access_requests") && isAccessRequestVisibility(view.GetString("visibility"))
+}
+
+// validateAccessRequestsSetting rejects allow_access_requests on views that an
+// approval could not open
+func validateAccessRequestsSetting(view *core.Record) error {
+	if view.GetBool("allow_access_requests") && !isAccessRequestVisibility(view.GetString("visibility")) {
+		return errAccessRequestsUnavailable
+	}
+	return nil
+}
+
+func isAccessRequestVisibility(visibility string) bool {
+	return visibility == "unlisted" || visibility == "private"
+}
+
+// grantsPrivateAccess reports whether a share token was minted by approving an
+// access request to its view. Only those tokens open a private view; links
+// created by hand stay closed, as they were before the view went private.
+func grantsPrivateAccess(app core.App, tokenRecord *core.Record) bool {
+	count, err := app.CountRecords("access_requests", dbx.HashExp{
+		"share_token": tokenRecord.Id,
+		"view":        tokenRecord.GetString("view_id"),
+		"status":      "approved",
+	})
+	return err == nil && count > 0
+}
+
+// normalizeAccessRequest trims a submission and checks its fields
+func normalizeAccessRequest(req accessRequestInput) (accessRequestInput, error) {
+	input := accessRequestInput{
+		Name:    strings.TrimSpace(req.Name),
+		Company: strings.TrimSpace(req.Company),
+		Reason:  strings.TrimSpace(req.Reason),
+	}
+
+	if input.Name == "" {
+		return input, errors.New("name is required")
+	}
+	email, err := normalizeRecipientEmail(req.Email)
+	if err != nil {
+		return input, errors.New("a valid email is required")
+	}
+	input.Email = email
+
+	if utf8.RuneCountInString(input.Name) > 100 {
+		return input, errors.New("name is too long")
+	}
+	if utf8.RuneCountInString(input.Company) > 200 {
+		return input, errors.New("company is too long")
+	}
+	if utf8.RuneCountInString(input.Reason) > 1000 {
+		return input, errors.New("reason is too long (1000 characters max)")
+	}
+	return input, nil
+}
+
+// submitAccessRequest stores a request for a view. A repeat from an email that
+// already has a pending request updates that request instead.
+func submitAccessRequest(app core.App, view *core.Record, input accessRequestInput) error {
+	existing, err := app.FindFirstRecordByFilter(
+		"access_requests",
+		"view = {:view} && email = {:email} && status = 'pending'",
+		dbx.Params{"view": view.Id, "email": input.Email},
+	)
+	if err == nil && existing != nil {
+		existing.Set("name", input.Name)
+		existing.Set("company", input.Company)
+		existing.Set("reason", input.Reason)
+		return app.Save(existing)
+	}
+
+	pending, err := app.CountRecords("access_requests", dbx.HashExp{"view": view.Id, "status": "pending"})
+	if err != nil {
+		return err
+	}
+	if pending >= maxPendingAccessRequests {
+		return errAccessRequestsFull
+	}
+
+	collection, err := app.FindCollectionByNameOrId("access_requests")
+	if err != nil {
+		return err
+	}
+	record := core.NewRecord(collection)
+	record.Set("view", view.Id)
+	record.Set("name", input.Name)
+	record.Set("email", input.Email)
+	record.Set("company", input.Company)
+	record.Set("reason", input.Reason)
+	record.Set("status", "pending")
+	return app.Save(record)
+}
+
+// approveAccessRequest mints a share token for the request's view, named after
+// the requester, and marks the request approved. A zero expiresAt or maxUses
+// leaves that limit off.
+func approveAccessRequest(app core.App, share *services.ShareService, request *core.Record, expiresAt time.Time, maxUses int) (*core.Record, string, error) {
+	view, err := app.FindRecordById("views", request.GetString("view"))
+	if err != nil {
+		return nil, "", err
+	}
+	if !isAccessRequestVisibility(view.GetString("visibility")) {
+		return nil, "", errAccessRequestViewNotShareable
+	}
+
+	name := request.GetString("name")
+	if company := request.GetString("company"); company != "" {
+		name += " (" + company + ")"
+	}
+
+	tokenRecord, rawToken, err := newShareTokenRecord(app, share, view.Id, name)
+	if err != nil {
+		return nil, "", err
+	}
+	if !expiresAt.IsZero() {
+		tokenRecord.Set("expires_at", expiresAt)
+	}
+	if maxUses > 0 {
+		tokenRecord.Set("max_uses", maxUses)
+	}
+
+	err = app.RunInTransaction(func(txApp core.App) error {
+		if err := txApp.Save(tokenRecord); err != nil {
+			return err
+		}
+		request.Set("status", "approved")
+		request.Set("share_token", tokenRecord.Id)
+		request.Set("reviewed_at", time.Now())
+		return txApp.Save(request)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return tokenRecord, rawToken, nil
+}
+
+// sendAccessGrantedEmail sends an approved requester their share link
+func sendAccessGrantedEmail(app core.App, to, name, viewName, link string) error {
+	meta := app.Settings().Meta
+	subject := "Your access request was approved"
+	if viewName != "" {
+		subject = fmt.Sprintf("Your access to %s", viewName)
+	}
+	body := fmt.Sprintf(
+		`<p>Hi %s,</p>
+<p>Your request was approved. <a href="%s">Open the link</a> to view it, or copy this address:</p>
+<p>%s</p>`,
+		html.EscapeString(name), html.EscapeString(link), html.EscapeString(link),
+	)
+
+	return app.NewMailClient().Send(&mailer.Message{
+		From:    mail.Address{Address: meta.SenderAddress, Name: meta.SenderName},
+		To:      []mail.Address{{Address: to}},
+		Subject: subject,
+		HTML:    body,
+	})
+}
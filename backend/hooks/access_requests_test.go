@@ -0,0 +1,220 @@
+package hooks
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"facet/services"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+)
+
+func TestAccessRequests(t *testing.T) {
+	app := newMigratedTestApp(t)
+	_, _, viewID := seedImportFixture(t, app)
+	share := services.NewShareService(services.NewCryptoService("test-encryption-key-32-chars-ok!"))
+
+	view, err := app.FindRecordById("views", viewID)
+	if err != nil {
+		t.Fatalf("Failed to find view: %v", err)
+	}
+	if acceptsAccessRequests(view) {
+		t.Error("views should not accept access requests unless they opt in")
+	}
+	view.Set("allow_access_requests", true)
+	for _, visibility := range []string{"public", "password"} {
+		view.Set("visibility", visibility)
+		if acceptsAccessRequests(view) {
+			t.Errorf("%s views should not accept access requests", visibility)
+		}
+		if err := validateAccessRequestsSetting(view); !errors.Is(err, errAccessRequestsUnavailable) {
+			t.Errorf("allow_access_requests on a %s view error = %v, want rejected", visibility, err)
+		}
+	}
+	for _, visibility := range []string{"private", "unlisted"} {
+		view.Set("visibility", visibility)
+		if err := app.Save(view); err != nil {
+			t.Fatalf("Failed to save %s view: %v", visibility, err)
+		}
+		if !acceptsAccessRequests(view) || validateAccessRequestsSetting(view) != nil {
+			t.Errorf("%s view that opted in should accept access requests", visibility)
+		}
+	}
+
+	input, err := normalizeAccessRequest(accessRequestInput{
+		Name:    "  Grace Hopper ",
+		Email:   "Grace@Example.com",
+		Company: "Navy",
+		Reason:  "Hiring for a compiler team",
+	})
+	if err != nil {
+		t.Fatalf("normalizeAccessRequest() error = %v", err)
+	}
+	if input.Name != "Grace Hopper" || input.Email != "grace@example.com" {
+		t.Errorf("normalizeAccessRequest() = %+v, want trimmed name and lowercased email", input)
+	}
+	for _, bad := range []accessRequestInput{
+		{Email: "grace@example.com"},
+		{Name: "Grace", Email: "not-an-email"},
+		{Name: "Grace", Email: "grace@example.com", Reason: strings.Repeat("x", 1001)},
+	} {
+		if _, err := normalizeAccessRequest(bad); err == nil {
+			t.Errorf("normalizeAccessRequest(%+v) should fail", bad)
+		}
+	}
+
+	// A second request from the same address updates the pending one
+	if err := submitAccessRequest(app, view, input); err != nil {
+		t.Fatalf("submitAccessRequest() error = %v", err)
+	}
+	input.Reason = "Hiring for a compiler team, remote"
+	if err := submitAccessRequest(app, view, input); err != nil {
+		t.Fatalf("submitAccessRequest() repeat error = %v", err)
+	}
+	count, err := app.CountRecords("access_requests", dbx.HashExp{"view": viewID})
+	if err != nil || count != 1 {
+		t.Fatalf("access requests = %d, %v; want 1", count, err)
+	}
+	request, err := app.FindFirstRecordByFilter("access_requests", "view = {:view}", dbx.Params{"view": viewID})
+	if err != nil {
+		t.Fatalf("Failed to find request: %v", err)
+	}
+	if request.GetString("status") != "pending" || request.GetString("reason") != input.Reason {
+		t.Errorf("request = %s / %q, want pending with the latest reason", request.GetString("status"), request.GetString("reason"))
+	}
+
+	// Share links cannot open password views, so a request left pending when
+	// the view changed cannot be approved
+	view.Set("allow_access_requests", false)
+	view.Set("visibility", "password")
+	if err := app.Save(view); err != nil {
+		t.Fatalf("Failed to save view: %v", err)
+	}
+	if _, _, err := approveAccessRequest(app, share, request, time.Time{}, 0); !errors.Is(err, errAccessRequestViewNotShareable) {
+		t.Errorf("approving for a password view error = %v, want not shareable", err)
+	}
+	view.Set("allow_access_requests", true)
+	view.Set("visibility", "unlisted")
+	if err := app.Save(view); err != nil {
+		t.Fatalf("Failed to save view: %v", err)
+	}
+
+	expiresAt := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+	tokenRecord, rawToken, err := approveAccessRequest(app, share, request, expiresAt, 3)
+	if err != nil {
+		t.Fatalf("approveAccessRequest() error = %v", err)
+	}
+	found, foundView := findUsableShareToken(app, share, rawToken)
+	if found == nil || found.Id != tokenRecord.Id || foundView.Id != viewID {
+		t.Fatal("minted token should open the requested view")
+	}
+	if found.GetString("name") != "Grace Hopper (Navy)" || found.GetInt("max_uses") != 3 ||
+		!found.GetDateTime("expires_at").Time().Equal(expiresAt) {
+		t.Errorf("token = %q, max_uses %d, expires %v; want the requester's name and limits",
+			found.GetString("name"), found.GetInt("max_uses"), found.GetDateTime("expires_at"))
+	}
+
+	approved, err := app.FindRecordById("access_requests", request.Id)
+	if err != nil {
+		t.Fatalf("Failed to reload request: %v", err)
+	}
+	if approved.GetString("status") != "approved" || approved.GetString("share_token") != tokenRecord.Id ||
+		approved.GetDateTime("reviewed_at").IsZero() {
+		t.Errorf("request after approval = %s, token %q; want approved with the token", approved.GetString("status"), approved.GetString("share_token"))
+	}
+
+	// After an answer, the same address can ask again
+	if err := submitAccessRequest(app, view, input); err != nil {
+		t.Fatalf("submitAccessRequest() after approval error = %v", err)
+	}
+	if count, _ := app.CountRecords("access_requests", dbx.HashExp{"view": viewID}); count != 2 {
+		t.Errorf("access requests = %d, want a new one after approval", count)
+	}
+
+	var sent []*mailer.Message
+	app.OnMailerSend().BindFunc(func(e *core.MailerEvent) error {
+		sent = append(sent, e.Message)
+		return nil
+	})
+	link := "https://cv.example.com/s/" + rawToken
+	if err := sendAccessGrantedEmail(app, "grace@example.com", "Grace <Hopper>", "Recruiters", link); err != nil {
+		t.Fatalf("sendAccessGrantedEmail() error = %v", err)
+	}
+	if len(sent) != 1 || sent[0].To[0].Address != "grace@example.com" ||
+		!strings.Contains(sent[0].HTML, link) || strings.Contains(sent[0].HTML, "<Hopper>") {
+		t.Errorf("email = %+v, want the link sent to the requester with the name escaped", sent)
+	}
+}
+
+func TestPrivateViewAccessRequests(t *testing.T) {
+	app := newMigratedTestApp(t)
+	_, _, viewID := seedImportFixture(t, app)
+	crypto := services.NewCryptoService("test-encryption-key-32-chars-ok!")
+	share := services.NewShareService(crypto)
+	counters := services.NewCounterService(time.Hour)
+
+	view, _ := app.FindRecordById("views", viewID)
+	view.Set("visibility", "private")
+	view.Set("allow_access_requests", true)
+	if err := app.Save(view); err != nil {
+		t.Fatalf("Failed to save view: %v", err)
+	}
+
+	open := func(token string) (*core.Record, *viewAccessDenied) {
+		e := &core.RequestEvent{App: app}
+		e.Request = httptest.NewRequest(http.MethodGet, "/api/view/recruiters/data", nil)
+		if token != "" {
+			e.Request.Header.Set("X-Share-Token", token)
+		}
+		return authorizeViewAccess(app, crypto, share, counters, e, view, true)
+	}
+
+	if _, denied := open(""); denied == nil || denied.Status != http.StatusNotFound {
+		t.Fatalf("private view without a token = %+v, want 404", denied)
+	}
+
+	// A link created by hand does not open a private view
+	manual, rawManual, err := newShareTokenRecord(app, share, viewID, "Manual")
+	if err != nil {
+		t.Fatalf("newShareTokenRecord() error = %v", err)
+	}
+	if err := app.Save(manual); err != nil {
+		t.Fatalf("Failed to save token: %v", err)
+	}
+	if _, denied := open(rawManual); denied == nil || denied.Status != http.StatusNotFound {
+		t.Errorf("private view with a manual link = %+v, want 404", denied)
+	}
+
+	// The link minted by approving a request does
+	input := accessRequestInput{Name: "Grace Hopper", Email: "grace@example.com"}
+	if err := submitAccessRequest(app, view, input); err != nil {
+		t.Fatalf("submitAccessRequest() error = %v", err)
+	}
+	request, _ := app.FindFirstRecordByFilter("access_requests", "view = {:view}", dbx.Params{"view": viewID})
+	tokenRecord, rawToken, err := approveAccessRequest(app, share, request, time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("approveAccessRequest() for a private view error = %v", err)
+	}
+	opened, denied := open(rawToken)
+	if denied != nil || opened == nil || opened.Id != tokenRecord.Id {
+		t.Fatalf("private view with the approved link = %v, %+v; want opened", opened, denied)
+	}
+	if counters.PendingTokenUses(tokenRecord.Id) != 1 {
+		t.Error("opening the private view should count a use of the link")
+	}
+
+	// Revoking the link closes the view again
+	tokenRecord.Set("is_active", false)
+	if err := app.Save(tokenRecord); err != nil {
+		t.Fatalf("Failed to revoke token: %v", err)
+	}
+	if _, denied := open(rawToken); denied == nil || denied.Status != http.StatusNotFound {
+		t.Errorf("private view with a revoked link = %+v, want 404", denied)
+	}
+}
@@ -0,0 +1,313 @@
+package hooks
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"facet/services"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	analyticsDefaultDays = 30
+	analyticsMaxDays     = 366
+	analyticsTopLimit    = 10
+)
+
+// AnalyticsTopItem is a counter key with a readable label
+type AnalyticsTopItem struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	Slug  string `json:"slug,omitempty"`
+	Count int    `json:"count"`
+}
+
+// RegisterAnalyticsHooks registers the first-party analytics endpoints.
+// Counters are written by the public view, post, project and talk endpoints.
+func RegisterAnalyticsHooks(app *pocketbase.PocketBase) {
+	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
+		// Totals per kind for a date range, with the previous period for comparison
+		// GET /api/analytics/summary?from=2024-01-01&to=2024-01-31
+		se.Router.GET("/api/analytics/summary", func(e *core.RequestEvent) error {
+			from, to, err := analyticsRange(e.Request.URL.Query(), time.Now())
+			if err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			}
+
+			totals, err := services.AnalyticsTotals(app, from, to)
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load analytics"})
+			}
+			days := int(to.Sub(from).Hours()/24) + 1
+			previous, err := services.AnalyticsTotals(app, from.AddDate(0, 0, -days), from.AddDate(0, 0, -1))
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load analytics"})
+			}
+
+			top := map[string][]AnalyticsTopItem{}
+			for _, kind := range []string{services.AnalyticsView, services.AnalyticsReferrer, services.AnalyticsToken} {
+				items, err := analyticsTop(app, from, to, kind, 5)
+				if err != nil {
+					return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load analytics"})
+				}
+				top[kind] = items
+			}
+
+			return e.JSON(http.StatusOK, map[string]interface{}{
+				"from":        from.Format(services.AnalyticsDayFormat),
+				"to":          to.Format(services.AnalyticsDayFormat),
+				"total_views": sumContentKinds(totals),
+				"totals":      totals,
+				"previous":    previous,
+				"top_views":   top[services.AnalyticsView],
+				"referrers":   top[services.AnalyticsReferrer],
+				"tokens":      top[services.AnalyticsToken],
+			})
+		}).Bind(apis.RequireAuth())
+
+		// Daily counts, zero-filled
+		// GET /api/analytics/timeseries?kind=post&key={id}&from=&to=
+		se.Router.GET("/api/analytics/timeseries", func(e *core.RequestEvent) error {
+			query := e.Request.URL.Query()
+			from, to, err := analyticsRange(query, time.Now())
+			if err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			}
+			kind := query.Get("kind")
+			if kind != "" && !services.IsAnalyticsKind(kind) {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid kind"})
+			}
+
+			points, err := services.AnalyticsTimeseries(app, from, to, kind, query.Get("key"))
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load analytics"})
+			}
+
+			return e.JSON(http.StatusOK, map[string]interface{}{
+				"from":   from.Format(services.AnalyticsDayFormat),
+				"to":     to.Format(services.AnalyticsDayFormat),
+				"kind":   kind,
+				"key":    query.Get("key"),
+				"points": points,
+			})
+		}).Bind(apis.RequireAuth())
+
+		// Most viewed views, posts, projects or talks (or top referrers/tokens)
+		// GET /api/analytics/top?kind=project&limit=10&from=&to=
+		se.Router.GET("/api/analytics/top", func(e *core.RequestEvent) error {
+			query := e.Request.URL.Query()
+			from, to, err := analyticsRange(query, time.Now())
+			if err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			}
+			kind := query.Get("kind")
+			if kind == "" {
+				kind = services.AnalyticsView
+			}
+			if !services.IsAnalyticsKind(kind) {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid kind"})
+			}
+			limit, _ := strconv.Atoi(query.Get("limit"))
+			if limit <= 0 || limit > 100 {
+				limit = analyticsTopLimit
+			}
+
+			items, err := analyticsTop(app, from, to, kind, limit)
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load analytics"})
+			}
+
+			return e.JSON(http.StatusOK, map[string]interface{}{
+				"from":  from.Format(services.AnalyticsDayFormat),
+				"to":    to.Format(services.AnalyticsDayFormat),
+				"kind":  kind,
+				"items": items,
+			})
+		}).Bind(apis.RequireAuth())
+
+		// All counters in the range as CSV
+		// GET /api/analytics/export?from=&to=
+		se.Router.GET("/api/analytics/export", func(e *core.RequestEvent) error {
+			from, to, err := analyticsRange(e.Request.URL.Query(), time.Now())
+			if err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			}
+
+			rows, err := services.AnalyticsRows(app, from, to)
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load analytics"})
+			}
+
+			filename := "analytics-" + from.Format(services.AnalyticsDayFormat) + "-to-" + to.Format(services.AnalyticsDayFormat) + ".csv"
+			e.Response.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			e.Response.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+			e.Response.WriteHeader(http.StatusOK)
+			return writeAnalyticsCSV(e.Response, rows, newAnalyticsLabeler(app))
+		}).Bind(apis.RequireAuth())
+
+		return se.Next()
+	})
+}
+
+// countPublicHit records an anonymous page view of a view, post, project or talk,
+// along with its referrer and share token. Bots and link previews are not counted.
+// The counts are batched and written by the counter flush loop.
+func countPublicHit(counters *services.CounterService, r *http.Request, kind, key, tokenID string) {
+	if _, bot := services.UserAgentFamily(r.Header.Get("User-Agent")); bot {
+		return
+	}
+	referrer := services.ReferrerHost(r.Header.Get("Referer"))
+	if isOwnHost(referrer, r) {
+		referrer = ""
+	}
+
+	now := time.Now()
+	counters.CountAnalytics(now, kind, key)
+	counters.CountAnalytics(now, services.AnalyticsReferrer, referrer)
+	counters.CountAnalytics(now, services.AnalyticsToken, tokenID)
+}
+
+// isOwnHost reports whether a referrer host is this site, so internal
+// navigation is not counted as a referral
+func isOwnHost(host string, r *http.Request) bool {
+	if host == "" {
+		return false
+	}
+	for _, candidate := range []string{os.Getenv("APP_URL"), "//" + r.Header.Get("X-Forwarded-Host"), "//" + r.Host} {
+		if u, err := url.Parse(candidate); err == nil && u.Hostname() != "" {
+			if strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.") == host {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// analyticsRange reads from/to (YYYY-MM-DD, inclusive) defaulting to the last 30 days
+func analyticsRange(query url.Values, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.UTC().Year(), now.UTC().Month(), now.UTC().Day(), 0, 0, 0, 0, time.UTC)
+	to, from := today, today.AddDate(0, 0, -(analyticsDefaultDays-1))
+
+	if value := query.Get("to"); value != "" {
+		parsed, err := time.Parse(services.AnalyticsDayFormat, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid to date (use YYYY-MM-DD)")
+		}
+		to = parsed
+		from = to.AddDate(0, 0, -(analyticsDefaultDays - 1))
+	}
+	if value := query.Get("from"); value != "" {
+		parsed, err := time.Parse(services.AnalyticsDayFormat, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid from date (use YYYY-MM-DD)")
+		}
+		from = parsed
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, errors.New("from must not be after to")
+	}
+	if to.Sub(from).Hours()/24 >= analyticsMaxDays {
+		return time.Time{}, time.Time{}, errors.New("date range is too long (max 366 days)")
+	}
+	return from, to, nil
+}
+
+// analyticsTop loads the top keys of a kind with labels
+func analyticsTop(app core.App, from, to time.Time, kind string, limit int) ([]AnalyticsTopItem, error) {
+	rows, err := services.AnalyticsTop(app, from, to, kind, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	labeler := newAnalyticsLabeler(app)
+	items := make([]AnalyticsTopItem, 0, len(rows))
+	for _, row := range rows {
+		label, slug := labeler.label(kind, row.Key)
+		items = append(items, AnalyticsTopItem{Key: row.Key, Label: label, Slug: slug, Count: row.Count})
+	}
+	return items, nil
+}
+
+func sumContentKinds(totals map[string]int) int {
+	sum := 0
+	for _, kind := range services.AnalyticsContentKinds {
+		sum += totals[kind]
+	}
+	return sum
+}
+
+// analyticsLabeler resolves counter keys (record ids) to titles, caching lookups
+type analyticsLabeler struct {
+	app   core.App
+	cache map[string][2]string
+}
+
+func newAnalyticsLabeler(app core.App) *analyticsLabeler {
+	return &analyticsLabeler{app: app, cache: map[string][2]string{}}
+}
+
+var analyticsCollections = map[string]struct{ collection, field string }{
+	services.AnalyticsView:    {"views", "name"},
+	services.AnalyticsPost:    {"posts", "title"},
+	services.AnalyticsProject: {"projects", "title"},
+	services.AnalyticsTalk:    {"talks", "title"},
+	services.AnalyticsToken:   {"share_tokens", "name"},
+}
+
+// label returns a display label and slug for a counter key. Deleted records
+// keep their counts and are labelled "(deleted)".
+func (l *analyticsLabeler) label(kind, key string) (string, string) {
+	source, ok := analyticsCollections[kind]
+	if !ok {
+		return key, ""
+	}
+	if cached, ok := l.cache[kind+":"+key]; ok {
+		return cached[0], cached[1]
+	}
+
+	label, slug := "(deleted)", ""
+	if record, err := l.app.FindRecordById(source.collection, key); err == nil {
+		label = record.GetString(source.field)
+		slug = record.GetString("slug")
+		if label == "" {
+			label = key
+		}
+	}
+	l.cache[kind+":"+key] = [2]string{label, slug}
+	return label, slug
+}
+
+func writeAnalyticsCSV(w io.Writer, rows []services.AnalyticsCount, labeler *analyticsLabeler) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"day", "kind", "key", "label", "count"}); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		label, _ := labeler.label(row.Kind, row.Key)
+		if err := writer.Write([]string{row.Day, row.Kind, csvSafe(row.Key), csvSafe(label), strconv.Itoa(row.Count)}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvSafe stops spreadsheets from evaluating a cell as a formula. Referrer
+// hosts come from visitors, so they are not trusted.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,110 @@
+package hooks
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"facet/services"
+)
+
+func TestAnalyticsCountersAndExport(t *testing.T) {
+	app := newMigratedTestApp(t)
+	_, projID, viewID := seedImportFixture(t, app)
+
+	day1 := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	day3 := day1.AddDate(0, 0, 2)
+	hits := []struct {
+		day       time.Time
+		kind, key string
+	}{
+		{day1, services.AnalyticsView, viewID},
+		{day1, services.AnalyticsView, viewID},
+		{day1, services.AnalyticsReferrer, "=cmd|evil.example"},
+		{day3, services.AnalyticsView, viewID},
+		{day3, services.AnalyticsProject, projID},
+		{day3, services.AnalyticsPost, "deletedpost0001"},
+		{day3, services.AnalyticsToken, ""},
+		{day3, "unknown", "x"},
+	}
+	for _, hit := range hits {
+		if err := services.IncrementAnalytics(app, hit.day, hit.kind, hit.key); err != nil {
+			t.Fatalf("IncrementAnalytics() error = %v", err)
+		}
+	}
+
+	if count, _ := app.CountRecords("analytics_daily"); count != 5 {
+		t.Errorf("counter rows = %d, want 5 (same day and key share a row)", count)
+	}
+
+	totals, err := services.AnalyticsTotals(app, day1, day3)
+	if err != nil {
+		t.Fatalf("AnalyticsTotals() error = %v", err)
+	}
+	if totals[services.AnalyticsView] != 3 || totals[services.AnalyticsProject] != 1 || totals[services.AnalyticsToken] != 0 {
+		t.Errorf("totals = %v", totals)
+	}
+	if got := sumContentKinds(totals); got != 5 {
+		t.Errorf("sumContentKinds() = %d, want 5 (referrers are not page views)", got)
+	}
+
+	points, err := services.AnalyticsTimeseries(app, day1, day3, "", "")
+	if err != nil {
+		t.Fatalf("AnalyticsTimeseries() error = %v", err)
+	}
+	if len(points) != 3 || points[0].Count != 2 || points[1].Count != 0 || points[2].Count != 3 {
+		t.Errorf("points = %+v, want 2, 0, 3", points)
+	}
+
+	top, err := analyticsTop(app, day1, day3, services.AnalyticsView, 5)
+	if err != nil {
+		t.Fatalf("analyticsTop() error = %v", err)
+	}
+	if len(top) != 1 || top[0].Count != 3 || top[0].Slug != "recruiters" {
+		t.Errorf("top views = %+v", top)
+	}
+
+	rows, err := services.AnalyticsRows(app, day1, day3)
+	if err != nil {
+		t.Fatalf("AnalyticsRows() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := writeAnalyticsCSV(&buf, rows, newAnalyticsLabeler(app)); err != nil {
+		t.Fatalf("writeAnalyticsCSV() error = %v", err)
+	}
+	csv := buf.String()
+	if !strings.HasPrefix(csv, "day,kind,key,label,count\n") {
+		t.Errorf("missing CSV header:\n%s", csv)
+	}
+	if !strings.Contains(csv, "'=cmd|evil.example") {
+		t.Errorf("formula-like referrer was not escaped:\n%s", csv)
+	}
+	if !strings.Contains(csv, "deletedpost0001,(deleted),1") {
+		t.Errorf("deleted post was not labelled:\n%s", csv)
+	}
+}
+
+func TestAnalyticsRange(t *testing.T) {
+	now := time.Date(2024, 3, 31, 18, 0, 0, 0, time.UTC)
+
+	from, to, err := analyticsRange(url.Values{}, now)
+	if err != nil {
+		t.Fatalf("analyticsRange() error = %v", err)
+	}
+	if from.Format(services.AnalyticsDayFormat) != "2024-03-02" || to.Format(services.AnalyticsDayFormat) != "2024-03-31" {
+		t.Errorf("default range = %s to %s", from, to)
+	}
+
+	invalid := []url.Values{
+		{"from": {"March"}},
+		{"from": {"2024-03-10"}, "to": {"2024-03-01"}},
+		{"from": {"2023-01-01"}, "to": {"2024-03-01"}},
+	}
+	for _, query := range invalid {
+		if _, _, err := analyticsRange(query, now); err == nil {
+			t.Errorf("analyticsRange(%v) expected an error", query)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package hooks
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// applicationStatuses lists pipeline stages in order
+var applicationStatuses = []string{"applied", "interviewing", "offer", "rejected"}
+
+// PipelineToken is a share token's usage as shown in the application pipeline
+type PipelineToken struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	TokenPrefix string `json:"token_prefix"`
+	ViewID      string `json:"view_id"`
+	ViewName    string `json:"view_name"`
+	ViewSlug    string `json:"view_slug"`
+	IsActive    bool   `json:"is_active"`
+	UseCount    int    `json:"use_count"`
+	MaxUses     int    `json:"max_uses"`
+	LastUsedAt  string `json:"last_used_at"`
+	ExpiresAt   string `json:"expires_at"`
+}
+
+// PipelineEntry is one application with the share links sent for it
+type PipelineEntry struct {
+	ID          string          `json:"id"`
+	Company     string          `json:"company"`
+	Role        string          `json:"role"`
+	JobURL      string          `json:"job_url"`
+	Status      string          `json:"status"`
+	ContactName string          `json:"contact_name"`
+	Notes       string          `json:"notes"`
+	AppliedAt   string          `json:"applied_at"`
+	FollowUpAt  string          `json:"follow_up_at"`
+	Tokens      []PipelineToken `json:"tokens"`
+	TotalUses   int             `json:"total_uses"`
+	LastUsedAt  string          `json:"last_used_at"`
+}
+
+// RegisterApplicationHooks registers the job application pipeline endpoint
+func RegisterApplicationHooks(app *pocketbase.PocketBase) {
+	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
+		// List applications with the usage of their share links
+		// GET /api/applications/pipeline?status=interviewing
+		se.Router.GET("/api/applications/pipeline", func(e *core.RequestEvent) error {
+			status := e.Request.URL.Query().Get("status")
+			if status != "" && !isApplicationStatus(status) {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid status"})
+			}
+
+			entries, counts, err := buildApplicationPipeline(app, status)
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load applications"})
+			}
+
+			return e.JSON(http.StatusOK, map[string]interface{}{
+				"applications": entries,
+				"counts":       counts,
+			})
+		}).Bind(apis.RequireAuth())
+
+		return se.Next()
+	})
+}
+
+// buildApplicationPipeline loads applications (optionally filtered by status) with
+// their share tokens. Counts cover every application regardless of the filter.
+func buildApplicationPipeline(app core.App, status string) ([]PipelineEntry, map[string]int, error) {
+	applications, err := app.FindRecordsByFilter("applications", "", "", 0, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	counts := map[string]int{}
+	for _, s := range applicationStatuses {
+		counts[s] = 0
+	}
+
+	entries := []PipelineEntry{}
+	byID := map[string]int{}
+	for _, record := range applications {
+		counts[record.GetString("status")]++
+		if status != "" && record.GetString("status") != status {
+			continue
+		}
+		byID[record.Id] = len(entries)
+		entries = append(entries, PipelineEntry{
+			ID:          record.Id,
+			Company:     record.GetString("company"),
+			Role:        record.GetString("role"),
+			JobURL:      record.GetString("job_url"),
+			Status:      record.GetString("status"),
+			ContactName: record.GetString("contact_name"),
+			Notes:       record.GetString("notes"),
+			AppliedAt:   formatPipelineDate(record.GetDateTime("applied_at")),
+			FollowUpAt:  formatPipelineDate(record.GetDateTime("follow_up_at")),
+			Tokens:      []PipelineToken{},
+		})
+	}
+
+	tokens, err := app.FindRecordsByFilter("share_tokens", "application != ''", "", 0, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	views := map[string]*core.Record{}
+	for _, token := range tokens {
+		idx, ok := byID[token.GetString("application")]
+		if !ok {
+			continue
+		}
+
+		viewID := token.GetString("view_id")
+		view, seen := views[viewID]
+		if !seen {
+			view, _ = app.FindRecordById("views", viewID)
+			views[viewID] = view
+		}
+
+		entry := PipelineToken{
+			ID:          token.Id,
+			Name:        token.GetString("name"),
+			TokenPrefix: token.GetString("token_prefix"),
+			ViewID:      viewID,
+			IsActive:    token.GetBool("is_active"),
+			UseCount:    token.GetInt("use_count"),
+			MaxUses:     token.GetInt("max_uses"),
+			LastUsedAt:  formatPipelineDate(token.GetDateTime("last_used_at")),
+			ExpiresAt:   formatPipelineDate(token.GetDateTime("expires_at")),
+		}
+		if view != nil {
+			entry.ViewName = view.GetString("name")
+			entry.ViewSlug = view.GetString("slug")
+		}
+
+		e := &entries[idx]
+		e.Tokens = append(e.Tokens, entry)
+		e.TotalUses += entry.UseCount
+		// RFC 3339 UTC strings compare chronologically
+		if entry.LastUsedAt > e.LastUsedAt {
+			e.LastUsedAt = entry.LastUsedAt
+		}
+	}
+
+	// Most recently applied first; undated applications go last
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].AppliedAt > entries[j].AppliedAt
+	})
+
+	return entries, counts, nil
+}
+
+// applyApplicationPersonalization adds the greeting and CTA of the token's linked
+// application to a view response. Notes and status are never exposed.
+func applyApplicationPersonalization(app core.App, tokenRecord *core.Record, response map[string]interface{}) {
+	applicationID := tokenRecord.GetString("application")
+	if applicationID == "" {
+		return
+	}
+	application, err := app.FindRecordById("applications", applicationID)
+	if err != nil {
+		return
+	}
+
+	response["greeting"] = applicationGreeting(application)
+	if ctaText := application.GetString("cta_text"); ctaText != "" {
+		response["cta_text"] = ctaText
+	}
+	if ctaURL := application.GetString("cta_url"); ctaURL != "" {
+		response["cta_url"] = ctaURL
+	}
+}
+
+// applicationGreeting returns the application's custom greeting, or one built
+// from the contact name, company and role
+func applicationGreeting(application *core.Record) string {
+	if greeting := strings.TrimSpace(application.GetString("greeting")); greeting != "" {
+		return greeting
+	}
+
+	contact := strings.TrimSpace(application.GetString("contact_name"))
+	company := strings.TrimSpace(application.GetString("company"))
+	role := strings.TrimSpace(application.GetString("role"))
+
+	greeting := "Hello"
+	switch {
+	case contact != "":
+		greeting += ", " + contact
+	case company != "":
+		greeting += ", " + company + " team"
+	}
+	greeting += "!"
+
+	if role != "" && company != "" {
+		greeting += " Thanks for considering me for the " + role + " role at " + company + "."
+	} else if role != "" {
+		greeting += " Thanks for considering me for the " + role + " role."
+	}
+	return greeting
+}
+
+func isApplicationStatus(status string) bool {
+	for _, s := range applicationStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func formatPipelineDate(dt types.DateTime) string {
+	if dt.IsZero() {
+		return ""
+	}
+	return dt.Time().UTC().Format("2006-01-02T15:04:05Z")
+}
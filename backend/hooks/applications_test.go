@@ -0,0 +1,108 @@
+package hooks
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestApplicationPipelineAndGreeting(t *testing.T) {
+	app := newMigratedTestApp(t)
+	_, _, viewID := seedImportFixture(t, app)
+
+	save := func(collection string, fields map[string]interface{}) *core.Record {
+		coll, err := app.FindCollectionByNameOrId(collection)
+		if err != nil {
+			t.Fatalf("Failed to find %s: %v", collection, err)
+		}
+		record := core.NewRecord(coll)
+		for key, value := range fields {
+			record.Set(key, value)
+		}
+		if err := app.Save(record); err != nil {
+			t.Fatalf("Failed to save %s: %v", collection, err)
+		}
+		return record
+	}
+
+	babbage := save("applications", map[string]interface{}{
+		"company":    "Babbage & Co",
+		"role":       "Programmer",
+		"status":     "interviewing",
+		"notes":      "Salary talk on Friday",
+		"applied_at": "1843-06-01 00:00:00.000Z",
+		"cta_text":   "Book a chat",
+	})
+	save("applications", map[string]interface{}{
+		"company":    "Jacquard Looms",
+		"status":     "applied",
+		"applied_at": "1843-07-01 00:00:00.000Z",
+	})
+	token := func(name string, uses int, application string, lastUsed string) {
+		fields := map[string]interface{}{
+			"view_id":      viewID,
+			"token_hash":   "hash-" + name,
+			"token_prefix": name,
+			"name":         name,
+			"is_active":    true,
+			"use_count":    uses,
+			"application":  application,
+		}
+		if lastUsed != "" {
+			fields["last_used_at"] = lastUsed
+		}
+		save("share_tokens", fields)
+	}
+	token("first", 3, babbage.Id, "1843-06-02 10:00:00.000Z")
+	token("second", 2, babbage.Id, "1843-06-05 10:00:00.000Z")
+	token("unlinked", 9, "", "")
+
+	entries, counts, err := buildApplicationPipeline(app, "")
+	if err != nil {
+		t.Fatalf("buildApplicationPipeline() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Company != "Jacquard Looms" {
+		t.Fatalf("entries = %+v, want the most recent application first", entries)
+	}
+	if counts["interviewing"] != 1 || counts["applied"] != 1 || counts["offer"] != 0 {
+		t.Errorf("counts = %v", counts)
+	}
+
+	got := entries[1]
+	if len(got.Tokens) != 2 || got.TotalUses != 5 || got.LastUsedAt != "1843-06-05T10:00:00Z" {
+		t.Errorf("babbage = %+v, want two tokens with 5 uses", got)
+	}
+	if got.Tokens[0].ViewSlug != "recruiters" {
+		t.Errorf("token view = %q, want recruiters", got.Tokens[0].ViewSlug)
+	}
+
+	filtered, _, _ := buildApplicationPipeline(app, "applied")
+	if len(filtered) != 1 || filtered[0].Company != "Jacquard Looms" {
+		t.Errorf("filtered = %+v", filtered)
+	}
+
+	// The token holder sees a greeting and the application's CTA, nothing internal
+	tokenRecord, _ := app.FindFirstRecordByData("share_tokens", "name", "first")
+	response := map[string]interface{}{"cta_text": "Contact", "cta_url": "https://example.com"}
+	applyApplicationPersonalization(app, tokenRecord, response)
+	want := "Hello, Babbage & Co team! Thanks for considering me for the Programmer role at Babbage & Co."
+	if response["greeting"] != want {
+		t.Errorf("greeting = %q, want %q", response["greeting"], want)
+	}
+	if response["cta_text"] != "Book a chat" || response["cta_url"] != "https://example.com" {
+		t.Errorf("cta = %v / %v", response["cta_text"], response["cta_url"])
+	}
+	if _, leaked := response["notes"]; leaked {
+		t.Error("notes leaked into the view response")
+	}
+
+	babbage.Set("contact_name", "Charles")
+	babbage.Set("role", "")
+	if greeting := applicationGreeting(babbage); greeting != "Hello, Charles!" {
+		t.Errorf("greeting = %q", greeting)
+	}
+	babbage.Set("greeting", "Lovely to meet you at the Society, Charles.")
+	if greeting := applicationGreeting(babbage); greeting != "Lovely to meet you at the Society, Charles." {
+		t.Errorf("custom greeting = %q", greeting)
+	}
+}
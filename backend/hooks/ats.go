@@ -0,0 +1,120 @@
+package hooks
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"facet/services"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const maxATSMarkdownSize = 200 << 10
+
+// RegisterATSHooks registers the ATS keyword coverage report
+func RegisterATSHooks(app *pocketbase.PocketBase) {
+	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
+		// Compare a view with a job description and check the resume's formatting.
+		// The resume checked is, in order of preference: pasted Markdown, a completed
+		// Markdown export of the view, or the view rendered through a template.
+		// POST /api/views/{id}/ats-report
+		se.Router.POST("/api/views/{id}/ats-report", func(e *core.RequestEvent) error {
+			var req struct {
+				JobDescription string `json:"job_description"`
+				Markdown       string `json:"markdown"`
+				ExportID       string `json:"export_id"`
+				Template       string `json:"template"`
+			}
+			if err := e.BindBody(&req); err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+			}
+
+			req.JobDescription = strings.TrimSpace(req.JobDescription)
+			if req.JobDescription == "" {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "job_description is required"})
+			}
+			if len(req.JobDescription) > maxJobDescriptionLength {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "job_description is too long (max 20000 characters)"})
+			}
+			if len(req.Markdown) > maxATSMarkdownSize {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "markdown is too long (max 200KB)"})
+			}
+
+			view, err := app.FindRecordById("views", e.Request.PathValue("id"))
+			if err != nil {
+				return e.JSON(http.StatusNotFound, map[string]string{"error": "view not found"})
+			}
+
+			viewData, err := collectViewData(app, view)
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to collect view data"})
+			}
+
+			markdown, source := req.Markdown, "markdown"
+			switch {
+			case strings.TrimSpace(markdown) != "":
+			case req.ExportID != "":
+				markdown, err = readMarkdownExport(app, view, req.ExportID)
+				if err != nil {
+					return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+				}
+				source = "export:" + req.ExportID
+			default:
+				if req.Template == "" {
+					req.Template = "chronological"
+				}
+				tmpl, err := resolveResumeTemplate(app, req.Template)
+				if err != nil {
+					return e.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+				}
+				if tmpl.Format != "markdown" {
+					return e.JSON(http.StatusBadRequest, map[string]string{"error": "only Markdown templates can be checked"})
+				}
+				markdown, err = services.RenderResumeTemplate(tmpl, viewData)
+				if err != nil {
+					return e.JSON(http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+				}
+				source = "template:" + tmpl.Slug
+			}
+
+			report := services.BuildATSReport(req.JobDescription, viewData, markdown)
+			report.ResumeSource = source
+			return e.JSON(http.StatusOK, report)
+		}).Bind(apis.RequireAuth())
+
+		return se.Next()
+	})
+}
+
+// readMarkdownExport returns the contents of a completed Markdown export of the view
+func readMarkdownExport(app core.App, view *core.Record, exportID string) (string, error) {
+	job, err := app.FindRecordById("view_exports", exportID)
+	if err != nil || job.GetString("view") != view.Id {
+		return "", fmt.Errorf("export not found for this view")
+	}
+	if job.GetString("format") != "md" || job.GetString("status") != "completed" || job.GetString("file") == "" {
+		return "", fmt.Errorf("only completed Markdown (md) exports can be checked")
+	}
+
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		return "", fmt.Errorf("failed to open file storage")
+	}
+	defer fsys.Close()
+
+	reader, err := fsys.GetFile(job.BaseFilesPath() + "/" + job.GetString("file"))
+	if err != nil {
+		return "", fmt.Errorf("export file is missing")
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxATSMarkdownSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read export file")
+	}
+	return string(data), nil
+}
@@ -0,0 +1,70 @@
+package hooks
+
+import (
+	"testing"
+	"time"
+
+	"facet/services"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+)
+
+func TestATSReportForMarkdownExport(t *testing.T) {
+	app := newMigratedTestApp(t)
+	expID, _, viewID := seedImportFixture(t, app)
+	view, _ := app.FindRecordById("views", viewID)
+
+	collection, _ := app.FindCollectionByNameOrId("view_exports")
+	job := core.NewRecord(collection)
+	job.Set("view", viewID)
+	job.Set("format", "pdf")
+	job.Set("status", "pending")
+	job.Set("queued_at", time.Now())
+	if err := app.Save(job); err != nil {
+		t.Fatalf("Failed to save export: %v", err)
+	}
+	if _, err := readMarkdownExport(app, view, job.Id); err == nil {
+		t.Error("expected an error for a pending PDF export")
+	}
+
+	file, _ := filesystem.NewFileFromBytes([]byte("# Ada Lovelace\n\n| Role | Year |\n|---|---|\n"), "resume.md")
+	job.Set("format", "md")
+	job.Set("status", "completed")
+	job.Set("file", file)
+	if err := app.Save(job); err != nil {
+		t.Fatalf("Failed to complete export: %v", err)
+	}
+
+	markdown, err := readMarkdownExport(app, view, job.Id)
+	if err != nil {
+		t.Fatalf("readMarkdownExport() error = %v", err)
+	}
+
+	viewData, err := collectViewData(app, view)
+	if err != nil {
+		t.Fatalf("collectViewData() error = %v", err)
+	}
+	report := services.BuildATSReport("Lead Programmer wanted to write a program. Experience with Kubernetes.", viewData, markdown)
+
+	var program *services.ATSKeyword
+	for i := range report.Matched {
+		if report.Matched[i].Keyword == "program" {
+			program = &report.Matched[i]
+		}
+	}
+	if program == nil || len(program.Locations) == 0 || program.Locations[0].ItemID != expID {
+		t.Errorf("program = %+v, want a match on the experience entry", program)
+	}
+	if len(report.Missing) == 0 || report.Missing[0].Skill != "Kubernetes" {
+		t.Errorf("missing = %+v, want Kubernetes first", report.Missing)
+	}
+
+	rules := map[string]bool{}
+	for _, issue := range report.Formatting {
+		rules[issue.Rule] = true
+	}
+	if !rules["table"] || !rules["contact"] {
+		t.Errorf("formatting issues = %+v, want table and contact", report.Formatting)
+	}
+}
@@ -0,0 +1,307 @@
+package hooks
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+)
+
+// Backup archive layout:
+//
+//	data.json                              the same document as /api/export?format=json
+//	files/<collection>/<record id>/<name>  every file attachment referenced by the data
+//	manifest.json                          export metadata and a SHA-256 checksum per entry
+const (
+	backupFormat       = "facet-backup"
+	backupDataPath     = "data.json"
+	backupManifestPath = "manifest.json"
+	backupFilesDir     = "files"
+
+	maxBackupSize = 1024 * 1024 * 1024 // 1GB
+)
+
+// BackupManifest describes the contents of a backup archive
+type BackupManifest struct {
+	Meta   ExportMeta        `json:"meta"`
+	Format string            `json:"format"`
+	Files  []BackupFileEntry `json:"files"`
+}
+
+// BackupFileEntry is a checksummed archive entry
+type BackupFileEntry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// backupFilePath returns the archive path of a record's file attachment
+func backupFilePath(collection, recordID, name string) string {
+	return path.Join(backupFilesDir, collection, recordID, name)
+}
+
+// serveBackupArchive streams a ZIP backup of the export data and its attachments.
+// Headers are sent before the archive is written, so failures after that point
+// can only be logged; the missing manifest makes such an archive fail to restore.
+func serveBackupArchive(e *core.RequestEvent, app core.App, data *ExportData, filename string) error {
+	dataJSON, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return e.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("Failed to serialize JSON: %v", err),
+		})
+	}
+
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		return e.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("Failed to open file storage: %v", err),
+		})
+	}
+	defer fsys.Close()
+
+	e.Response.Header().Set("Content-Type", "application/zip")
+	e.Response.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	e.Response.WriteHeader(http.StatusOK)
+
+	if err := writeBackupArchive(e.Response, app, fsys, data, dataJSON); err != nil {
+		app.Logger().Error("Backup archive failed", "error", err)
+	}
+	return nil
+}
+
+// writeBackupArchive writes data.json, every referenced attachment and the manifest to w
+func writeBackupArchive(w io.Writer, app core.App, fsys *filesystem.System, data *ExportData, dataJSON []byte) error {
+	zw := zip.NewWriter(w)
+	manifest := BackupManifest{Meta: data.Meta, Format: backupFormat}
+
+	entry, err := writeBackupEntry(zw, backupDataPath, func(w io.Writer) error {
+		_, err := w.Write(dataJSON)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	manifest.Files = append(manifest.Files, entry)
+
+	for _, name := range importCollections {
+		collection, err := app.FindCollectionByNameOrId(name)
+		if err != nil {
+			continue
+		}
+
+		for _, record := range exportDocumentRecords(data, name) {
+			recordID := importString(record["id"])
+			if recordID == "" {
+				continue
+			}
+
+			for _, field := range collection.Fields {
+				if _, ok := field.(*core.FileField); !ok {
+					continue
+				}
+
+				for _, filename := range fileNames(record[field.GetName()]) {
+					key := collection.Id + "/" + recordID + "/" + filename
+					entry, err := writeBackupEntry(zw, backupFilePath(name, recordID, filename), func(w io.Writer) error {
+						reader, err := fsys.GetFile(key)
+						if err != nil {
+							return err
+						}
+						defer reader.Close()
+						_, err = io.Copy(w, reader)
+						return err
+					})
+					if err != nil {
+						// A missing attachment should not prevent a backup of everything else
+						app.Logger().Warn("Backup skipped missing file", "collection", name, "record", recordID, "file", filename, "error", err)
+						continue
+					}
+					manifest.Files = append(manifest.Files, entry)
+				}
+			}
+		}
+	}
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, err := writeBackupEntry(zw, backupManifestPath, func(w io.Writer) error {
+		_, err := w.Write(manifestJSON)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// writeBackupEntry adds an entry to the archive, hashing the content as it is written
+func writeBackupEntry(zw *zip.Writer, name string, write func(io.Writer) error) (BackupFileEntry, error) {
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now().UTC(),
+	})
+	if err != nil {
+		return BackupFileEntry{}, err
+	}
+
+	counter := &countingWriter{hash: sha256.New()}
+	if err := write(io.MultiWriter(w, counter)); err != nil {
+		return BackupFileEntry{}, err
+	}
+
+	return BackupFileEntry{
+		Path:   name,
+		Size:   counter.size,
+		SHA256: hex.EncodeToString(counter.hash.Sum(nil)),
+	}, nil
+}
+
+type countingWriter struct {
+	hash hash.Hash
+	size int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.size += int64(len(p))
+	return c.hash.Write(p)
+}
+
+// backupArchive is an opened, checksum-verified backup
+type backupArchive struct {
+	manifest BackupManifest
+	entries  map[string]*zip.File
+}
+
+// openBackupArchive reads a backup ZIP, verifies every manifest checksum and
+// returns the parsed export document together with access to its attachments.
+func openBackupArchive(r io.ReaderAt, size int64) (*ExportData, *backupArchive, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid backup archive: %v", err)
+	}
+
+	archive := &backupArchive{entries: make(map[string]*zip.File, len(zr.File))}
+	for _, f := range zr.File {
+		archive.entries[f.Name] = f
+	}
+
+	manifestBytes, err := archive.read(backupManifestPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("backup archive has no manifest: %v", err)
+	}
+	if err := json.Unmarshal(manifestBytes, &archive.manifest); err != nil {
+		return nil, nil, fmt.Errorf("invalid backup manifest: %v", err)
+	}
+	if archive.manifest.Format != backupFormat {
+		return nil, nil, fmt.Errorf("unsupported backup format %q", archive.manifest.Format)
+	}
+
+	for _, entry := range archive.manifest.Files {
+		if err := archive.verify(entry); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	dataBytes, err := archive.read(backupDataPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("backup archive has no data: %v", err)
+	}
+	data, err := parseExportDocument(dataBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, archive, nil
+}
+
+// verify checks an entry against its manifest size and checksum
+func (a *backupArchive) verify(entry BackupFileEntry) error {
+	content, err := a.read(entry.Path)
+	if err != nil {
+		return fmt.Errorf("backup archive is missing %s", entry.Path)
+	}
+
+	sum := sha256.Sum256(content)
+	if int64(len(content)) != entry.Size || hex.EncodeToString(sum[:]) != entry.SHA256 {
+		return fmt.Errorf("checksum mismatch for %s, the archive is corrupted", entry.Path)
+	}
+	return nil
+}
+
+// listed reports whether an entry is covered by the manifest
+func (a *backupArchive) listed(name string) bool {
+	for _, entry := range a.manifest.Files {
+		if entry.Path == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *backupArchive) read(name string) ([]byte, error) {
+	f, ok := a.entries[name]
+	if !ok {
+		return nil, fmt.Errorf("%s not found", name)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
+
+// openFile loads a record attachment from the archive, keeping its stored name
+func (a *backupArchive) openFile(collection, recordID, name string) (*filesystem.File, error) {
+	entryPath := backupFilePath(collection, recordID, name)
+	if !a.listed(entryPath) {
+		return nil, fmt.Errorf("%s is not part of the backup", entryPath)
+	}
+
+	content, err := a.read(entryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := filesystem.NewFileFromBytes(content, name)
+	if err != nil {
+		return nil, err
+	}
+	file.Name = name
+	return file, nil
+}
+
+// fileNames extracts stored file names from a file field value
+func fileNames(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		if v != "" {
+			return []string{v}
+		}
+	case []string:
+		return v
+	case []interface{}:
+		names := make([]string, 0, len(v))
+		for _, item := range v {
+			if s := importString(item); s != "" {
+				names = append(names, s)
+			}
+		}
+		return names
+	}
+	return nil
+}
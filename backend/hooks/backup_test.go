@@ -0,0 +1,216 @@
+package hooks
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+)
+
+// buildBackup writes a backup archive of app into memory
+func buildBackup(t *testing.T, app core.App) []byte {
+	t.Helper()
+
+	data, err := collectExportData(app)
+	if err != nil {
+		t.Fatalf("Failed to collect export: %v", err)
+	}
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("Failed to encode export: %v", err)
+	}
+
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatalf("Failed to open filesystem: %v", err)
+	}
+	defer fsys.Close()
+
+	var buf bytes.Buffer
+	if err := writeBackupArchive(&buf, app, fsys, data, dataJSON); err != nil {
+		t.Fatalf("Failed to write backup: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// seedUpload stores an upload record with a real file attachment
+func seedUpload(t *testing.T, app core.App, content string) *core.Record {
+	t.Helper()
+
+	collection, err := app.FindCollectionByNameOrId("uploads")
+	if err != nil {
+		t.Fatalf("Failed to find uploads: %v", err)
+	}
+
+	file, err := filesystem.NewFileFromBytes([]byte(content), "notes.txt")
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("title", "Notes")
+	record.Set("file", file)
+	if err := app.Save(record); err != nil {
+		t.Fatalf("Failed to save upload: %v", err)
+	}
+	return record
+}
+
+func readStoredFile(t *testing.T, app core.App, record *core.Record, name string) string {
+	t.Helper()
+
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatalf("Failed to open filesystem: %v", err)
+	}
+	defer fsys.Close()
+
+	reader, err := fsys.GetFile(record.BaseFilesPath() + "/" + name)
+	if err != nil {
+		t.Fatalf("Stored file %s not found: %v", name, err)
+	}
+	defer reader.Close()
+
+	content, _ := io.ReadAll(reader)
+	return string(content)
+}
+
+func TestBackupArchiveRestoresDataAndFiles(t *testing.T) {
+	source := newMigratedTestApp(t)
+	expID, _, viewID := seedImportFixture(t, source)
+	upload := seedUpload(t, source, "first draft")
+	fileName := upload.GetString("file")
+
+	archiveBytes := buildBackup(t, source)
+
+	data, archive, err := openBackupArchive(bytes.NewReader(archiveBytes), int64(len(archiveBytes)))
+	if err != nil {
+		t.Fatalf("Failed to open backup: %v", err)
+	}
+	if len(archive.manifest.Files) != 2 {
+		t.Errorf("manifest lists %d entries, want data.json and one attachment", len(archive.manifest.Files))
+	}
+
+	target := newMigratedTestApp(t)
+	if _, err := importExportData(target, data, archive, ImportOptions{Strategy: ImportStrategyReplace}); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	if _, err := target.FindRecordById("experience", expID); err != nil {
+		t.Errorf("Experience not restored: %v", err)
+	}
+	if _, err := target.FindRecordById("views", viewID); err != nil {
+		t.Errorf("View not restored: %v", err)
+	}
+
+	restored, err := target.FindRecordById("uploads", upload.Id)
+	if err != nil {
+		t.Fatalf("Upload not restored: %v", err)
+	}
+	if restored.GetString("file") != fileName {
+		t.Errorf("file = %q, want original name %q", restored.GetString("file"), fileName)
+	}
+	if content := readStoredFile(t, target, restored, fileName); content != "first draft" {
+		t.Errorf("restored file content = %q", content)
+	}
+
+	// Restoring the same backup again changes nothing
+	result, err := importExportData(target, data, archive, ImportOptions{Strategy: ImportStrategyUpsert})
+	if err != nil {
+		t.Fatalf("Second restore failed: %v", err)
+	}
+	if result.Summary.Created != 0 || result.Summary.Updated != 0 {
+		t.Errorf("Second restore should be a no-op, got %+v", result.Summary)
+	}
+}
+
+func TestBackupArchiveRejectsTamperedEntries(t *testing.T) {
+	app := newMigratedTestApp(t)
+	seedImportFixture(t, app)
+	seedUpload(t, app, "original")
+
+	archiveBytes := buildBackup(t, app)
+	zr, err := zip.NewReader(bytes.NewReader(archiveBytes), int64(len(archiveBytes)))
+	if err != nil {
+		t.Fatalf("Failed to read backup: %v", err)
+	}
+
+	// Rewrite the archive with one attachment modified
+	var tampered bytes.Buffer
+	zw := zip.NewWriter(&tampered)
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		if f.Name != backupDataPath && f.Name != backupManifestPath {
+			content = []byte("modified")
+		}
+		w, _ := zw.Create(f.Name)
+		w.Write(content)
+	}
+	zw.Close()
+
+	if _, _, err := openBackupArchive(bytes.NewReader(tampered.Bytes()), int64(tampered.Len())); err == nil {
+		t.Error("Expected a checksum error for a modified attachment")
+	}
+}
+
+func TestJSONImportSkipsRecordsThatNeedFiles(t *testing.T) {
+	source := newMigratedTestApp(t)
+	seedUpload(t, source, "content")
+	data := exportRoundTrip(t, source, "json")
+
+	target := newMigratedTestApp(t)
+	result, err := importExportData(target, data, nil, ImportOptions{Strategy: ImportStrategyUpsert})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	uploads, _ := target.FindAllRecords("uploads")
+	if len(uploads) != 0 {
+		t.Errorf("uploads without files should be skipped, got %d", len(uploads))
+	}
+	if len(result.Warnings) == 0 {
+		t.Error("Expected a warning for the skipped upload")
+	}
+}
+
+func TestShareTokensRestoreDisabled(t *testing.T) {
+	source := newMigratedTestApp(t)
+	_, _, viewID := seedImportFixture(t, source)
+
+	tokens, _ := source.FindCollectionByNameOrId("share_tokens")
+	token := core.NewRecord(tokens)
+	token.Set("view_id", viewID)
+	token.Set("name", "Acme recruiter")
+	token.Set("token_hash", "secret-hash")
+	token.Set("is_active", true)
+	if err := source.Save(token); err != nil {
+		t.Fatalf("Failed to save share token: %v", err)
+	}
+
+	data := exportRoundTrip(t, source, "json")
+	if _, ok := data.ShareTokens[0]["token_hash"]; ok {
+		t.Fatal("token_hash must not be exported")
+	}
+
+	target := newMigratedTestApp(t)
+	if _, err := importExportData(target, data, nil, ImportOptions{Strategy: ImportStrategyReplace}); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	restored, err := target.FindRecordById("share_tokens", token.Id)
+	if err != nil {
+		t.Fatalf("Share token not restored: %v", err)
+	}
+	if restored.GetBool("is_active") {
+		t.Error("Restored share token should be disabled")
+	}
+	if restored.GetString("token_hash") == "secret-hash" || restored.GetString("token_hash") == "" {
+		t.Errorf("Restored share token hash = %q", restored.GetString("token_hash"))
+	}
+}
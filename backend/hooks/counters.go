@@ -0,0 +1,32 @@
+package hooks
+
+import (
+	"facet/services"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/hook"
+)
+
+// RegisterCounterHooks runs the counter flush loop (view and share token counts,
+// daily analytics and access log events) while the server is up and writes
+// anything pending on shutdown
+func RegisterCounterHooks(app *pocketbase.PocketBase, counters *services.CounterService) {
+	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
+		counters.Start(app)
+		return se.Next()
+	})
+
+	// Runs after the HTTP server's graceful shutdown (priority 0), so hits from
+	// in-flight requests are included
+	app.OnTerminate().Bind(&hook.Handler[*core.TerminateEvent]{
+		Id: "facetFlushCounters",
+		Func: func(e *core.TerminateEvent) error {
+			if err := counters.Stop(app); err != nil {
+				app.Logger().Error("Failed to flush counters on shutdown", "error", err)
+			}
+			return e.Next()
+		},
+		Priority: 1,
+	})
+}
@@ -0,0 +1,164 @@
+package hooks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"facet/services"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestCounterServiceBatchesConcurrentHits(t *testing.T) {
+	app := newMigratedTestApp(t)
+	_, _, viewID := seedImportFixture(t, app)
+
+	tokens, _ := app.FindCollectionByNameOrId("share_tokens")
+	token := core.NewRecord(tokens)
+	token.Set("view_id", viewID)
+	token.Set("token_hash", "hash")
+	token.Set("token_prefix", "abc")
+	token.Set("is_active", true)
+	token.Set("use_count", 2)
+	if err := app.Save(token); err != nil {
+		t.Fatalf("Failed to save token: %v", err)
+	}
+
+	// A record hook must not fire for counter writes
+	saves := 0
+	app.OnRecordUpdate("views").BindFunc(func(e *core.RecordEvent) error {
+		saves++
+		return e.Next()
+	})
+
+	counters := services.NewCounterService(time.Hour)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			counters.IncrementViewCount("views", viewID)
+			counters.IncrementTokenUse(token.Id)
+		}()
+	}
+	wg.Wait()
+
+	if pending := counters.PendingTokenUses(token.Id); pending != 50 {
+		t.Errorf("PendingTokenUses() = %d, want 50", pending)
+	}
+
+	// A second batch after the first flush adds to the stored count
+	if err := counters.Flush(app); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	counters.IncrementViewCount("views", viewID)
+	if err := counters.Stop(app); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	view, _ := app.FindRecordById("views", viewID)
+	if got := view.GetInt("view_count"); got != 51 {
+		t.Errorf("view_count = %d, want 51", got)
+	}
+	if view.GetDateTime("last_viewed_at").IsZero() {
+		t.Error("last_viewed_at was not set")
+	}
+	if saves != 0 {
+		t.Errorf("record update hooks fired %d times", saves)
+	}
+
+	stored, _ := app.FindRecordById("share_tokens", token.Id)
+	if got := stored.GetInt("use_count"); got != 52 {
+		t.Errorf("use_count = %d, want 52", got)
+	}
+	if counters.PendingTokenUses(token.Id) != 0 {
+		t.Error("pending uses remain after flush")
+	}
+}
+
+func TestCounterServiceCountsDemoViews(t *testing.T) {
+	app := newMigratedTestApp(t)
+	_, _, viewID := seedImportFixture(t, app)
+
+	demoView := core.NewRecord(mustFindCollection(t, app, "demo_views"))
+	demoView.Set("name", "Demo")
+	demoView.Set("slug", "demo")
+	demoView.Set("view_count", 3)
+	if err := app.Save(demoView); err != nil {
+		t.Fatalf("Failed to save demo view: %v", err)
+	}
+
+	counters := services.NewCounterService(time.Hour)
+	counters.IncrementViewCount("demo_views", demoView.Id)
+	counters.IncrementViewCount("demo_views", demoView.Id)
+	if err := counters.Flush(app); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	stored, _ := app.FindRecordById("demo_views", demoView.Id)
+	if got := stored.GetInt("view_count"); got != 5 {
+		t.Errorf("demo view_count = %d, want 5", got)
+	}
+	if stored.GetDateTime("last_viewed_at").IsZero() {
+		t.Error("demo last_viewed_at was not set")
+	}
+
+	// The real view with the same counters is left alone
+	view, _ := app.FindRecordById("views", viewID)
+	if got := view.GetInt("view_count"); got != 0 {
+		t.Errorf("view_count = %d, want 0 for demo hits", got)
+	}
+}
+
+func TestCounterServiceBatchesAnalyticsAndAccessEvents(t *testing.T) {
+	app := newMigratedTestApp(t)
+	_, _, viewID := seedImportFixture(t, app)
+
+	counters := services.NewCounterService(time.Hour)
+	request := httptest.NewRequest(http.MethodGet, "/api/view/recruiters/data", nil)
+	request.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15")
+	request.Header.Set("Referer", "https://www.linkedin.com/feed/")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			countPublicHit(counters, request, services.AnalyticsView, viewID, "")
+			counters.RecordAccessEvent(viewID, "", services.AccessVisit{IPHash: "a", UAFamily: "Safari on macOS"})
+		}()
+	}
+	wg.Wait()
+
+	// Nothing is written until the flush
+	if events, _ := app.CountRecords("access_events"); events != 0 {
+		t.Errorf("access_events = %d before flush, want 0", events)
+	}
+	if err := counters.Stop(app); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	totals, err := services.AnalyticsTotals(app, time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("AnalyticsTotals() error = %v", err)
+	}
+	if totals[services.AnalyticsView] != 20 || totals[services.AnalyticsReferrer] != 20 {
+		t.Errorf("totals = %v, want 20 views and 20 referrals", totals)
+	}
+	if events, _ := app.CountRecords("access_events"); events != 20 {
+		t.Errorf("access_events = %d, want 20", events)
+	}
+
+	// A second batch adds to the stored counters
+	countPublicHit(counters, request, services.AnalyticsView, viewID, "")
+	if err := counters.Flush(app); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	top, _ := services.AnalyticsTop(app, time.Now(), time.Now(), services.AnalyticsView, 1)
+	if len(top) != 1 || top[0].Key != viewID || top[0].Count != 21 {
+		t.Errorf("top views = %+v, want %s with 21", top, viewID)
+	}
+}
@@ -0,0 +1,362 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"facet/services"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// customItemsCollection stores the items of every user-defined section
+const customItemsCollection = "custom_items"
+
+// sectionDef describes how a view section is stored and rendered
+type sectionDef struct {
+	Name          string
+	Label         string
+	Collection    string
+	DefaultLayout string
+	Overridable   []string
+	// Fields is the schema of a custom section; nil for built-in sections
+	Fields []customSectionField
+}
+
+// isCustom reports whether the section is user-defined
+func (d sectionDef) isCustom() bool {
+	return d.Collection == customItemsCollection
+}
+
+// builtinSections are the section types backed by their own collections.
+// Overridable lists the fields a view may reword per item; identity and facts
+// (companies, institutions, issuers, dates, URLs) stay as recorded, and a
+// testimonial's quote and author are the author's, not ours to reword.
+// Sync with VALID_LAYOUTS and OVERRIDABLE_FIELDS in frontend/src/lib/pocketbase.ts
+var builtinSections = map[string]sectionDef{
+	"experience":     {Label: "Experience", Collection: "experience", DefaultLayout: "default", Overridable: []string{"title", "description", "bullets", "skills"}},
+	"projects":       {Label: "Projects", Collection: "projects", DefaultLayout: "grid-3", Overridable: []string{"title", "summary", "description", "tech_stack", "categories"}},
+	"education":      {Label: "Education", Collection: "education", DefaultLayout: "default", Overridable: []string{"degree", "field", "description"}},
+	"certifications": {Label: "Certifications", Collection: "certifications", DefaultLayout: "grouped", Overridable: []string{"name"}},
+	"awards":         {Label: "Awards", Collection: "awards", DefaultLayout: "grouped", Overridable: []string{"title", "description"}},
+	"skills":         {Label: "Skills", Collection: "skills", DefaultLayout: "grouped", Overridable: []string{"name", "category", "proficiency"}},
+	"posts":          {Label: "Posts", Collection: "posts", DefaultLayout: "grid-3", Overridable: []string{"title", "excerpt", "tags"}},
+	"talks":          {Label: "Talks", Collection: "talks", DefaultLayout: "default", Overridable: []string{"title", "description"}},
+	"contacts":       {Label: "Contact Methods", Collection: "contact_methods", DefaultLayout: "vertical", Overridable: []string{"label"}},
+	"testimonials":   {Label: "Testimonials", Collection: "testimonials", DefaultLayout: "wall", Overridable: []string{"relationship", "project"}},
+}
+
+// customSectionField is one field of a custom section type
+type customSectionField struct {
+	Name        string `json:"name"`
+	Label       string `json:"label,omitempty"`
+	Type        string `json:"type"`
+	Overridable bool   `json:"overridable,omitempty"`
+}
+
+// customFieldTypes are the field types a custom section can use
+var customFieldTypes = map[string]bool{
+	"text":     true,
+	"textarea": true,
+	"url":      true,
+	"date":     true,
+	"number":   true,
+	"boolean":  true,
+	"list":     true, // list of strings, e.g. tags or co-authors
+}
+
+// customSectionLayouts are rendered by the generic custom section component.
+// Sync with CUSTOM_SECTION_LAYOUTS in frontend/src/lib/pocketbase.ts
+var customSectionLayouts = []string{"default", "grid-2", "grid-3", "compact"}
+
+// customItemColumns are the custom_items columns; schema fields may reuse
+// "title" (to label or override it) but none of the others
+var customItemColumns = map[string]bool{
+	"id": true, "section": true, "title": true, "data": true, "visibility": true,
+	"view_visibility": true, "is_draft": true, "sort_order": true,
+	"created": true, "updated": true, "collectionId": true, "collectionName": true, "expand": true,
+}
+
+// reservedSectionNames cannot be used for custom section types
+var reservedSectionNames = map[string]bool{
+	"profile": true, "hero": true, "custom": true, "sections": true, "views": true,
+}
+
+var sectionTypeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// isCustomSectionName reports whether a section name can refer to a custom section type
+func isCustomSectionName(name string) bool {
+	_, builtin := builtinSections[name]
+	return !builtin && !reservedSectionNames[name] && len(name) <= 50 && sectionTypeNamePattern.MatchString(name)
+}
+
+// loadSectionTypes returns the custom section types by name. Cached until a
+// section type changes.
+func loadSectionTypes(app core.App, isDemoMode bool) map[string]sectionDef {
+	collection := "section_types"
+	if isDemoMode {
+		collection = "demo_section_types"
+	}
+
+	defs, _ := services.CachedValue(app, "facet.sectionTypes:"+collection, []string{collection}, func() (map[string]sectionDef, error) {
+		defs := make(map[string]sectionDef)
+		records, err := app.FindRecordsByFilter(collection, "", "sort_order,label", 0, 0, nil)
+		if err != nil {
+			return defs, nil
+		}
+		for _, record := range records {
+			def, err := sectionDefFromRecord(record)
+			if err != nil {
+				app.Logger().Warn("Invalid custom section type", "error", err, "name", record.GetString("name"))
+				continue
+			}
+			defs[def.Name] = def
+		}
+		return defs, nil
+	})
+	return defs
+}
+
+// sectionDefFromRecord builds the definition of a custom section type
+func sectionDefFromRecord(record *core.Record) (sectionDef, error) {
+	var fields []customSectionField
+	if raw := record.GetString("fields"); raw != "" && raw != "null" {
+		if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+			return sectionDef{}, fmt.Errorf("invalid fields: %w", err)
+		}
+	}
+
+	def := sectionDef{
+		Name:          record.GetString("name"),
+		Label:         record.GetString("label"),
+		Collection:    customItemsCollection,
+		DefaultLayout: record.GetString("default_layout"),
+		Fields:        fields,
+	}
+	if !containsString(customSectionLayouts, def.DefaultLayout) {
+		def.DefaultLayout = "default"
+	}
+	for _, field := range fields {
+		if field.Overridable {
+			def.Overridable = append(def.Overridable, field.Name)
+		}
+	}
+	return def, nil
+}
+
+// resolveSection returns the definition of a built-in or custom section
+func resolveSection(app core.App, name string, isDemoMode bool) (sectionDef, bool) {
+	if def, ok := builtinSections[name]; ok {
+		def.Name = name
+		return def, true
+	}
+	if !isCustomSectionName(name) {
+		return sectionDef{}, false
+	}
+	def, ok := loadSectionTypes(app, isDemoMode)[name]
+	return def, ok
+}
+
+// customField returns the schema field with the given name
+func (d sectionDef) customField(name string) (customSectionField, bool) {
+	for _, field := range d.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return customSectionField{}, false
+}
+
+// validateSectionType checks a section_types record before it is saved
+func validateSectionType(record *core.Record) error {
+	name := record.GetString("name")
+	if !isCustomSectionName(name) {
+		return fmt.Errorf("invalid section name %q: use lowercase letters, digits and underscores, and not a built-in section name", name)
+	}
+	if strings.TrimSpace(record.GetString("label")) == "" {
+		return fmt.Errorf("label is required")
+	}
+	if layout := record.GetString("default_layout"); layout != "" && !containsString(customSectionLayouts, layout) {
+		return fmt.Errorf("default_layout must be one of %s", strings.Join(customSectionLayouts, ", "))
+	}
+
+	def, err := sectionDefFromRecord(record)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	for _, field := range def.Fields {
+		switch {
+		case !sectionTypeNamePattern.MatchString(field.Name):
+			return fmt.Errorf("invalid field name %q", field.Name)
+		case field.Name != "title" && customItemColumns[field.Name]:
+			return fmt.Errorf("field name %q is reserved", field.Name)
+		case seen[field.Name]:
+			return fmt.Errorf("duplicate field %q", field.Name)
+		case !customFieldTypes[field.Type]:
+			return fmt.Errorf("field %q has unknown type %q", field.Name, field.Type)
+		case field.Name == "title" && field.Type != "text":
+			return fmt.Errorf("the title field must be text")
+		}
+		seen[field.Name] = true
+	}
+	return nil
+}
+
+// validateCustomItem checks an item against its section type and normalizes
+// its data to the schema's types. Keys the schema doesn't define are dropped, so
+// items written before a field was removed can still be saved
+func validateCustomItem(app core.App, record *core.Record) error {
+	typesCollection := "section_types"
+	if strings.HasPrefix(record.Collection().Name, "demo_") {
+		typesCollection = "demo_section_types"
+	}
+
+	name := record.GetString("section")
+	typeRecord, err := app.FindFirstRecordByFilter(typesCollection, "name = {:name}", map[string]interface{}{"name": name})
+	if err != nil {
+		return fmt.Errorf("unknown section type %q", name)
+	}
+	def, err := sectionDefFromRecord(typeRecord)
+	if err != nil {
+		return err
+	}
+
+	data := make(map[string]interface{})
+	if raw := record.GetString("data"); raw != "" && raw != "null" {
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			return fmt.Errorf("data must be an object: %w", err)
+		}
+	}
+
+	normalized := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		field, ok := def.customField(key)
+		if !ok || key == "title" || value == nil {
+			continue
+		}
+		converted, err := convertCustomFieldValue(field, value)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", key, err)
+		}
+		normalized[key] = converted
+	}
+	record.Set("data", normalized)
+	return nil
+}
+
+// convertCustomFieldValue checks a value against its field type
+func convertCustomFieldValue(field customSectionField, value interface{}) (interface{}, error) {
+	switch field.Type {
+	case "number":
+		if n, ok := value.(float64); ok {
+			return n, nil
+		}
+		return nil, fmt.Errorf("must be a number")
+	case "boolean":
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("must be true or false")
+	case "list":
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("must be a list")
+		}
+		list := make([]string, 0, len(items))
+		for _, item := range items {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("must be a list of text")
+			}
+			if s = strings.TrimSpace(s); s != "" {
+				list = append(list, s)
+			}
+		}
+		return list, nil
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("must be text")
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return s, nil
+	}
+	switch field.Type {
+	case "url":
+		parsed, err := url.Parse(s)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("must be an http(s) URL")
+		}
+	case "date":
+		if _, err := types.ParseDateTime(s); err != nil {
+			return nil, fmt.Errorf("must be a date (YYYY-MM-DD)")
+		}
+	}
+	return s, nil
+}
+
+// RegisterCustomSectionHooks validates section types and their items, and
+// deletes the items of a section type along with it
+func RegisterCustomSectionHooks(app *pocketbase.PocketBase) {
+	bindCustomSectionHooks(app)
+}
+
+func bindCustomSectionHooks(app core.App) {
+	app.OnRecordCreate("section_types", "demo_section_types").BindFunc(func(e *core.RecordEvent) error {
+		if err := validateSectionType(e.Record); err != nil {
+			return err
+		}
+		return e.Next()
+	})
+
+	app.OnRecordUpdate("section_types", "demo_section_types").BindFunc(func(e *core.RecordEvent) error {
+		if e.Record.GetString("name") != e.Record.Original().GetString("name") {
+			return fmt.Errorf("the name of a section type cannot be changed")
+		}
+		if err := validateSectionType(e.Record); err != nil {
+			return err
+		}
+		return e.Next()
+	})
+
+	app.OnRecordDelete("section_types", "demo_section_types").BindFunc(func(e *core.RecordEvent) error {
+		items := customItemsCollection
+		if strings.HasPrefix(e.Record.Collection().Name, "demo_") {
+			items = "demo_" + items
+		}
+		records, err := e.App.FindRecordsByFilter(items, "section = {:name}", "", 0, 0, map[string]interface{}{"name": e.Record.GetString("name")})
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := e.App.Delete(record); err != nil {
+				return err
+			}
+		}
+		return e.Next()
+	})
+
+	validateItem := func(e *core.RecordEvent) error {
+		if err := validateCustomItem(e.App, e.Record); err != nil {
+			return err
+		}
+		return e.Next()
+	}
+	app.OnRecordCreate(customItemsCollection, "demo_"+customItemsCollection).BindFunc(validateItem)
+	app.OnRecordUpdate(customItemsCollection, "demo_"+customItemsCollection).BindFunc(validateItem)
+}
+
+// customSectionInfo describes a custom section to the public frontend, which
+// renders every custom section with one generic component
+type customSectionInfo struct {
+	Label  string               `json:"label"`
+	Fields []customSectionField `json:"fields"`
+}
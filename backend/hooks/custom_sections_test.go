@@ -0,0 +1,375 @@
+package hooks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestCustomSectionTypeValidation(t *testing.T) {
+	app := newMigratedTestApp(t)
+	bindCustomSectionHooks(app)
+
+	types, err := app.FindCollectionByNameOrId("section_types")
+	if err != nil {
+		t.Fatalf("Failed to find section_types: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		typeKey string
+		fields  []map[string]interface{}
+		layout  string
+		wantErr string
+	}{
+		{"valid", "publications", []map[string]interface{}{{"name": "venue", "type": "text"}, {"name": "title", "type": "text", "overridable": true}}, "grid-2", ""},
+		{"built-in name", "projects", nil, "", "built-in"},
+		{"bad name", "Open Source", nil, "", "invalid section name"},
+		{"reserved field", "press", []map[string]interface{}{{"name": "visibility", "type": "text"}}, "", "reserved"},
+		{"duplicate field", "press", []map[string]interface{}{{"name": "outlet", "type": "text"}, {"name": "outlet", "type": "url"}}, "", "duplicate"},
+		{"unknown type", "press", []map[string]interface{}{{"name": "outlet", "type": "image"}}, "", "unknown type"},
+		{"unknown layout", "press", nil, "carousel", "default_layout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := core.NewRecord(types)
+			record.Set("name", tt.typeKey)
+			record.Set("label", "Section")
+			record.Set("fields", tt.fields)
+			record.Set("default_layout", tt.layout)
+			err := app.Save(record)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Save() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Save() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	publications, err := app.FindFirstRecordByFilter("section_types", "name = 'publications'")
+	if err != nil {
+		t.Fatalf("Failed to find publications: %v", err)
+	}
+	publications.Set("name", "papers")
+	if err := app.Save(publications); err == nil || !strings.Contains(err.Error(), "cannot be changed") {
+		t.Errorf("renaming a section type: error = %v", err)
+	}
+}
+
+// seedCustomSection creates a "publications" section type with items
+func seedCustomSection(t *testing.T, app core.App, prefix string) map[string]*core.Record {
+	t.Helper()
+
+	types, _ := app.FindCollectionByNameOrId(prefix + "section_types")
+	sectionType := core.NewRecord(types)
+	sectionType.Set("name", "publications")
+	sectionType.Set("label", "Publications")
+	sectionType.Set("default_layout", "compact")
+	sectionType.Set("fields", []map[string]interface{}{
+		{"name": "venue", "label": "Venue", "type": "text", "overridable": true},
+		{"name": "year", "type": "number"},
+		{"name": "tags", "type": "list"},
+		{"name": "link", "type": "url"},
+	})
+	if err := app.Save(sectionType); err != nil {
+		t.Fatalf("Failed to save section type: %v", err)
+	}
+
+	items, _ := app.FindCollectionByNameOrId(prefix + "custom_items")
+	records := make(map[string]*core.Record)
+	for _, seed := range []struct {
+		key        string
+		section    string
+		visibility string
+		data       map[string]interface{}
+	}{
+		{"paper", "publications", "public", map[string]interface{}{"venue": "GopherCon", "year": 2023, "tags": []string{"go"}, "link": "https://example.com/paper"}},
+		{"old", "publications", "public", map[string]interface{}{"venue": "ACM", "year": 2009, "tags": []string{"c"}}},
+		{"secret", "publications", "private", map[string]interface{}{"venue": "Internal", "year": 2024}},
+	} {
+		record := core.NewRecord(items)
+		record.Set("section", seed.section)
+		record.Set("title", strings.ToUpper(seed.key[:1])+seed.key[1:])
+		record.Set("visibility", seed.visibility)
+		record.Set("data", seed.data)
+		record.Set("sort_order", len(records))
+		if err := app.Save(record); err != nil {
+			t.Fatalf("Failed to save %s: %v", seed.key, err)
+		}
+		records[seed.key] = record
+	}
+	return records
+}
+
+func TestCustomItemValidation(t *testing.T) {
+	app := newMigratedTestApp(t)
+	bindCustomSectionHooks(app)
+	seedCustomSection(t, app, "")
+
+	items, _ := app.FindCollectionByNameOrId("custom_items")
+	tests := []struct {
+		name    string
+		section string
+		data    map[string]interface{}
+		wantErr string
+	}{
+		{"unknown section", "press", nil, "unknown section type"},
+		{"wrong type", "publications", map[string]interface{}{"year": "last year"}, "must be a number"},
+		{"bad url", "publications", map[string]interface{}{"link": "javascript:alert(1)"}, "URL"},
+		{"list of text", "publications", map[string]interface{}{"tags": []interface{}{"go", 1}}, "list of text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := core.NewRecord(items)
+			record.Set("section", tt.section)
+			record.Set("title", "Item")
+			record.Set("data", tt.data)
+			if err := app.Save(record); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Save() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCustomItemDropsUnknownFields(t *testing.T) {
+	app := newMigratedTestApp(t)
+	bindCustomSectionHooks(app)
+	seedCustomSection(t, app, "")
+
+	items, _ := app.FindCollectionByNameOrId("custom_items")
+	record := core.NewRecord(items)
+	record.Set("section", "publications")
+	record.Set("title", "Item")
+	record.Set("data", map[string]interface{}{"venue": "GopherCon", "publisher": "x", "title": "Other"})
+	if err := app.Save(record); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	saved, err := app.FindRecordById("custom_items", record.Id)
+	if err != nil {
+		t.Fatalf("Failed to reload item: %v", err)
+	}
+	data := make(map[string]interface{})
+	if err := saved.UnmarshalJSONField("data", &data); err != nil {
+		t.Fatalf("Failed to decode data: %v", err)
+	}
+	if len(data) != 1 || data["venue"] != "GopherCon" {
+		t.Errorf("data = %v, want only venue", data)
+	}
+}
+
+func TestCustomSectionsInViews(t *testing.T) {
+	app := newMigratedTestApp(t)
+	bindCustomSectionHooks(app)
+	records := seedCustomSection(t, app, "")
+
+	views, _ := app.FindCollectionByNameOrId("views")
+	view := core.NewRecord(views)
+	view.Set("name", "Research")
+	view.Set("slug", "research")
+	view.Set("is_active", true)
+	view.Set("sections", []map[string]interface{}{
+		{"section": "publications", "enabled": true, "itemConfig": map[string]interface{}{
+			records["paper"].Id: map[string]interface{}{"overrides": map[string]interface{}{"venue": "GopherCon EU", "year": 1999}},
+		}},
+		{"section": "unknown_section", "enabled": true},
+	})
+	if err := app.Save(view); err != nil {
+		t.Fatalf("Failed to save view: %v", err)
+	}
+
+	// The private item is shown in this view only
+	records["secret"].Set("view_visibility", map[string]bool{view.Id: true})
+	if err := app.Save(records["secret"]); err != nil {
+		t.Fatalf("Failed to save secret: %v", err)
+	}
+
+	sections := buildViewSections(app, view, false)
+	if len(sections.Order) != 1 || sections.Order[0] != "publications" {
+		t.Fatalf("order = %v, want [publications]", sections.Order)
+	}
+	if sections.Layouts["publications"] != "compact" || sections.Custom["publications"].Label != "Publications" {
+		t.Errorf("layout = %q, custom = %+v", sections.Layouts["publications"], sections.Custom["publications"])
+	}
+
+	items, _ := sections.Data["publications"].([]map[string]interface{})
+	if len(items) != 3 {
+		t.Fatalf("items = %d, want 3", len(items))
+	}
+	paper := items[0]
+	if paper["title"] != "Paper" || paper["venue"] != "GopherCon EU" || paper["year"] != float64(2023) {
+		t.Errorf("paper = %v, want flattened data with the venue override only", paper)
+	}
+	for _, field := range []string{"data", "is_draft", "view_visibility"} {
+		if _, ok := paper[field]; ok {
+			t.Errorf("serialized item has %q", field)
+		}
+	}
+
+	// Values of a field removed from the type stay in data but are not served
+	publications, _ := app.FindFirstRecordByFilter("section_types", "name = 'publications'")
+	publications.Set("fields", []map[string]interface{}{
+		{"name": "venue", "label": "Venue", "type": "text", "overridable": true},
+		{"name": "year", "type": "number"},
+		{"name": "tags", "type": "list"},
+	})
+	if err := app.Save(publications); err != nil {
+		t.Fatalf("Failed to remove the link field: %v", err)
+	}
+	stored, _ := app.FindRecordById("custom_items", records["paper"].Id)
+	if !strings.Contains(stored.GetString("data"), "https://example.com/paper") {
+		t.Fatalf("data = %s, want the removed field's value kept", stored.GetString("data"))
+	}
+	items, _ = buildViewSections(app, view, false).Data["publications"].([]map[string]interface{})
+	if len(items) != 3 {
+		t.Fatalf("items after removing a field = %d, want 3", len(items))
+	}
+	if link, ok := items[0]["link"]; ok || items[0]["venue"] != "GopherCon EU" {
+		t.Errorf("paper after removing link = %v (link %v), want declared fields only", items[0], link)
+	}
+	publications.Set("fields", []map[string]interface{}{
+		{"name": "venue", "label": "Venue", "type": "text", "overridable": true},
+		{"name": "year", "type": "number"},
+		{"name": "tags", "type": "list"},
+		{"name": "link", "type": "url"},
+	})
+	if err := app.Save(publications); err != nil {
+		t.Fatalf("Failed to restore the link field: %v", err)
+	}
+
+	// Picked items must belong to the section, and rules can use schema fields
+	otherType := core.NewRecord(mustFindCollection(t, app, "section_types"))
+	otherType.Set("name", "press")
+	otherType.Set("label", "Press")
+	if err := app.Save(otherType); err != nil {
+		t.Fatalf("Failed to save press: %v", err)
+	}
+	pressItem := core.NewRecord(mustFindCollection(t, app, "custom_items"))
+	pressItem.Set("section", "press")
+	pressItem.Set("title", "Interview")
+	pressItem.Set("visibility", "public")
+	if err := app.Save(pressItem); err != nil {
+		t.Fatalf("Failed to save press item: %v", err)
+	}
+
+	view.Set("sections", []map[string]interface{}{
+		{"section": "publications", "enabled": true, "items": []string{pressItem.Id, records["old"].Id}},
+		{"section": "press", "enabled": true, "rules": map[string]interface{}{
+			"conditions": []map[string]interface{}{{"field": "title", "op": "contains", "value": "interview"}},
+		}},
+	})
+	if err := app.Save(view); err != nil {
+		t.Fatalf("Failed to save view: %v", err)
+	}
+	sections = buildViewSections(app, view, false)
+	if picked, _ := sections.Data["publications"].([]map[string]interface{}); len(picked) != 1 || picked[0]["title"] != "Old" {
+		t.Errorf("picked publications = %v, want only Old", picked)
+	}
+	if press, _ := sections.Data["press"].([]map[string]interface{}); len(press) != 1 {
+		t.Errorf("press = %v, want the interview", press)
+	}
+
+	def, _ := resolveSection(app, "publications", false)
+	collection := mustFindCollection(t, app, "custom_items")
+	for _, tt := range []struct {
+		condition map[string]interface{}
+		want      string
+	}{
+		{map[string]interface{}{"field": "year", "op": "gte", "value": "2020"}, "Paper,Secret"},
+		{map[string]interface{}{"field": "tags", "op": "contains", "value": "go"}, "Paper"},
+		{map[string]interface{}{"field": "link", "op": "empty"}, "Old,Secret"},
+	} {
+		rules, _ := parseSectionRules(map[string]interface{}{"rules": map[string]interface{}{"conditions": []interface{}{tt.condition}}})
+		matched, err := findRuleSectionRecords(app, collection, def, view.Id, rules)
+		if err != nil {
+			t.Fatalf("findRuleSectionRecords(%v) error = %v", tt.condition, err)
+		}
+		var titles []string
+		for _, record := range matched {
+			titles = append(titles, record.GetString("title"))
+		}
+		if got := strings.Join(titles, ","); got != tt.want {
+			t.Errorf("rule %v matched %q, want %q", tt.condition, got, tt.want)
+		}
+	}
+
+	// Resume data carries the section title
+	view.Set("sections", []map[string]interface{}{{"section": "publications", "enabled": true}})
+	viewData, err := collectViewData(app, view)
+	if err != nil {
+		t.Fatalf("collectViewData() error = %v", err)
+	}
+	if viewData.SectionTitles["publications"] != "Publications" || len(viewData.Sections["publications"]) != 3 {
+		t.Errorf("resume data titles = %v, items = %d", viewData.SectionTitles, len(viewData.Sections["publications"]))
+	}
+
+	// Deleting the type deletes its items
+	sectionType, _ := app.FindFirstRecordByFilter("section_types", "name = 'publications'")
+	if err := app.Delete(sectionType); err != nil {
+		t.Fatalf("Failed to delete section type: %v", err)
+	}
+	if remaining, _ := app.FindRecordsByFilter("custom_items", "section = 'publications'", "", 0, 0); len(remaining) != 0 {
+		t.Errorf("%d items left after deleting their type", len(remaining))
+	}
+}
+
+func TestCustomSectionsDemoTablesAndExport(t *testing.T) {
+	app := newMigratedTestApp(t)
+	bindCustomSectionHooks(app)
+	seedCustomSection(t, app, "")
+	seedCustomSection(t, app, "demo_")
+
+	demoItem, _ := app.FindFirstRecordByFilter("demo_custom_items", "title = 'Old'")
+	demoItem.Set("title", "Demo only")
+	if err := app.Save(demoItem); err != nil {
+		t.Fatalf("Failed to save demo item: %v", err)
+	}
+
+	demoViews := mustFindCollection(t, app, "demo_views")
+	view := core.NewRecord(demoViews)
+	view.Set("name", "Demo")
+	view.Set("slug", "demo")
+	view.Set("sections", []map[string]interface{}{{"section": "publications", "enabled": true}})
+	if err := app.Save(view); err != nil {
+		t.Fatalf("Failed to save demo view: %v", err)
+	}
+
+	items, _ := buildViewSections(app, view, true).Data["publications"].([]map[string]interface{})
+	if len(items) != 2 || items[1]["title"] != "Demo only" {
+		t.Errorf("demo items = %v, want the demo table's items", items)
+	}
+
+	export, err := collectExportData(app)
+	if err != nil {
+		t.Fatalf("collectExportData() error = %v", err)
+	}
+	if len(export.SectionTypes) != 1 || len(export.CustomItems) != 3 {
+		t.Fatalf("export has %d section types and %d items", len(export.SectionTypes), len(export.CustomItems))
+	}
+
+	// Importing into an empty site recreates the types before their items
+	target := newMigratedTestApp(t)
+	bindCustomSectionHooks(target)
+	result, err := importExportData(target, export, nil, ImportOptions{Strategy: ImportStrategyUpsert})
+	if err != nil {
+		t.Fatalf("importExportData() error = %v", err)
+	}
+	if result.Summary.Created != 4 {
+		t.Errorf("created = %d, want 4", result.Summary.Created)
+	}
+}
+
+func mustFindCollection(t *testing.T, app core.App, name string) *core.Collection {
+	t.Helper()
+	collection, err := app.FindCollectionByNameOrId(name)
+	if err != nil {
+		t.Fatalf("Failed to find %s: %v", name, err)
+	}
+	return collection
+}
@@ -115,6 +115,7 @@ func clearDemoTables(app *pocketbase.PocketBase) error {
 		"demo_profile", "demo_experience", "demo_projects", "demo_education",
 		"demo_skills", "demo_certifications", "demo_posts", "demo_talks",
 		"demo_awards", "demo_views", "demo_share_tokens", "demo_contact_methods",
+		"demo_custom_items", "demo_section_types",
 	}
 
 	for _, tableName := range tables {
@@ -556,6 +557,49 @@ func loadDemoDataIntoShadowTables(app *pocketbase.PocketBase) error {
 		}
 	}
 
+	// Custom section type with a few items
+	app.Logger().Info("Creating demo custom section...")
+	sectionTypesColl, _ := app.FindCollectionByNameOrId("demo_section_types")
+	volunteering := core.NewRecord(sectionTypesColl)
+	volunteering.Set("name", "volunteering")
+	volunteering.Set("label", "Volunteering")
+	volunteering.Set("fields", []map[string]interface{}{
+		{"name": "organization", "label": "Organization", "type": "text"},
+		{"name": "role", "label": "Role", "type": "text", "overridable": true},
+		{"name": "year", "label": "Year", "type": "number"},
+		{"name": "description", "label": "Description", "type": "textarea", "overridable": true},
+	})
+	volunteering.Set("default_layout", "grid-2")
+	if err := app.Save(volunteering); err != nil {
+		return err
+	}
+
+	customItemsColl, _ := app.FindCollectionByNameOrId("demo_custom_items")
+	volunteerItems := []struct {
+		title, organization, role, description string
+		year                                   int
+	}{
+		{"Saving Earth (again)", "UNIT", "Scientific Advisor", "Unpaid, uncredited, and frequently arrested by the people I was helping.", 1970},
+		{"Code Club Mentor", "Coal Hill School", "Volunteer Tutor", "Taught teenagers Python. Some of them are now running small empires.", 2015},
+		{"Galactic Peace Talks", "Shadow Proclamation", "Mediator", "Negotiated a ceasefire using only a screwdriver and a very long speech.", 2005},
+	}
+	for i, v := range volunteerItems {
+		item := core.NewRecord(customItemsColl)
+		item.Set("section", "volunteering")
+		item.Set("title", v.title)
+		item.Set("data", map[string]interface{}{
+			"organization": v.organization,
+			"role":         v.role,
+			"year":         v.year,
+			"description":  v.description,
+		})
+		item.Set("visibility", "public")
+		item.Set("sort_order", i)
+		if err := app.Save(item); err != nil {
+			return err
+		}
+	}
+
 	// Create view
 	app.Logger().Info("Creating demo views...")
 	viewsColl, _ := app.FindCollectionByNameOrId("demo_views")
@@ -575,6 +619,7 @@ func loadDemoDataIntoShadowTables(app *pocketbase.PocketBase) error {
 		{"section": "skills", "enabled": true, "layout": "grouped"},
 		{"section": "certifications", "enabled": true, "layout": "grouped"},
 		{"section": "education", "enabled": true, "layout": "default"},
+		{"section": "volunteering", "enabled": true, "layout": "grid-2"},
 	})
 	view.Set("sections", string(sectionsJSON))
 	view.Set("is_active", true)
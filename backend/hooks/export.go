@@ -21,33 +21,44 @@ type ExportMeta struct {
 
 // ExportData contains all exportable profile data
 type ExportData struct {
-	Meta           ExportMeta               `json:"meta" yaml:"meta"`
-	Profile        map[string]interface{}   `json:"profile,omitempty" yaml:"profile,omitempty"`
-	Experience     []map[string]interface{} `json:"experience,omitempty" yaml:"experience,omitempty"`
-	Projects       []map[string]interface{} `json:"projects,omitempty" yaml:"projects,omitempty"`
-	Education      []map[string]interface{} `json:"education,omitempty" yaml:"education,omitempty"`
-	Certifications []map[string]interface{} `json:"certifications,omitempty" yaml:"certifications,omitempty"`
-	Awards         []map[string]interface{} `json:"awards,omitempty" yaml:"awards,omitempty"`
-	Skills         []map[string]interface{} `json:"skills,omitempty" yaml:"skills,omitempty"`
-	Posts          []map[string]interface{} `json:"posts,omitempty" yaml:"posts,omitempty"`
-	Talks          []map[string]interface{} `json:"talks,omitempty" yaml:"talks,omitempty"`
-	Views          []map[string]interface{} `json:"views,omitempty" yaml:"views,omitempty"`
+	Meta            ExportMeta               `json:"meta" yaml:"meta"`
+	Profile         map[string]interface{}   `json:"profile,omitempty" yaml:"profile,omitempty"`
+	Experience      []map[string]interface{} `json:"experience,omitempty" yaml:"experience,omitempty"`
+	Projects        []map[string]interface{} `json:"projects,omitempty" yaml:"projects,omitempty"`
+	Education       []map[string]interface{} `json:"education,omitempty" yaml:"education,omitempty"`
+	Certifications  []map[string]interface{} `json:"certifications,omitempty" yaml:"certifications,omitempty"`
+	Awards          []map[string]interface{} `json:"awards,omitempty" yaml:"awards,omitempty"`
+	Skills          []map[string]interface{} `json:"skills,omitempty" yaml:"skills,omitempty"`
+	Posts           []map[string]interface{} `json:"posts,omitempty" yaml:"posts,omitempty"`
+	Talks           []map[string]interface{} `json:"talks,omitempty" yaml:"talks,omitempty"`
+	Views           []map[string]interface{} `json:"views,omitempty" yaml:"views,omitempty"`
+	ContactMethods  []map[string]interface{} `json:"contact_methods,omitempty" yaml:"contact_methods,omitempty"`
+	Testimonials    []map[string]interface{} `json:"testimonials,omitempty" yaml:"testimonials,omitempty"`
+	SectionTypes    []map[string]interface{} `json:"section_types,omitempty" yaml:"section_types,omitempty"`
+	CustomItems     []map[string]interface{} `json:"custom_items,omitempty" yaml:"custom_items,omitempty"`
+	ExternalMedia   []map[string]interface{} `json:"external_media,omitempty" yaml:"external_media,omitempty"`
+	Uploads         []map[string]interface{} `json:"uploads,omitempty" yaml:"uploads,omitempty"`
+	Applications    []map[string]interface{} `json:"applications,omitempty" yaml:"applications,omitempty"`
+	ShareTokens     []map[string]interface{} `json:"share_tokens,omitempty" yaml:"share_tokens,omitempty"`
+	ResumeTemplates []map[string]interface{} `json:"resume_templates,omitempty" yaml:"resume_templates,omitempty"`
+	SiteSettings    map[string]interface{}   `json:"site_settings,omitempty" yaml:"site_settings,omitempty"`
 }
 
 // RegisterExportHooks registers data export API endpoints
 func RegisterExportHooks(app *pocketbase.PocketBase) {
 	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
 		// Export all data
-		// GET /api/export?format=json|yaml
+		// GET /api/export?format=json|yaml|zip
+		// zip is a full backup: data.json, every file attachment and a checksummed manifest
 		se.Router.GET("/api/export", func(e *core.RequestEvent) error {
 			format := e.Request.URL.Query().Get("format")
 			if format == "" {
 				format = "json"
 			}
 
-			if format != "json" && format != "yaml" {
+			if format != "json" && format != "yaml" && format != "zip" {
 				return e.JSON(http.StatusBadRequest, map[string]string{
-					"error": "Invalid format. Use 'json' or 'yaml'.",
+					"error": "Invalid format. Use 'json', 'yaml' or 'zip'.",
 				})
 			}
 
@@ -63,8 +74,11 @@ func RegisterExportHooks(app *pocketbase.PocketBase) {
 			timestamp := time.Now().Format("2006-01-02")
 			filename := fmt.Sprintf("facet-export-%s.%s", timestamp, format)
 
-			if format == "yaml" {
+			switch format {
+			case "yaml":
 				return serveYAML(e, exportData, filename)
+			case "zip":
+				return serveBackupArchive(e, app, exportData, filename)
 			}
 			return serveJSON(e, exportData, filename)
 		}).Bind(apis.RequireAuth())
@@ -74,10 +88,10 @@ func RegisterExportHooks(app *pocketbase.PocketBase) {
 }
 
 // collectExportData gathers all exportable data from the database
-func collectExportData(app *pocketbase.PocketBase) (*ExportData, error) {
+func collectExportData(app core.App) (*ExportData, error) {
 	export := &ExportData{
 		Meta: ExportMeta{
-			Version:    "1.0.0",
+			Version:    "1.1.0",
 			ExportedAt: time.Now().UTC().Format(time.RFC3339),
 			App:        "Facet",
 		},
@@ -143,6 +157,64 @@ func collectExportData(app *pocketbase.PocketBase) (*ExportData, error) {
 		export.Views = sanitizeViewRecords(viewRecords)
 	}
 
+	// Contact methods
+	contactRecords, err := app.FindRecordsByFilter("contact_methods", "", "-is_primary,sort_order", 0, 0, nil)
+	if err == nil {
+		export.ContactMethods = sanitizeRecords(contactRecords)
+	}
+
+	// Testimonials
+	testimonialRecords, err := app.FindRecordsByFilter("testimonials", "", "-featured,sort_order", 0, 0, nil)
+	if err == nil {
+		export.Testimonials = sanitizeRecords(testimonialRecords)
+	}
+
+	// Custom section types and their items
+	sectionTypeRecords, err := app.FindRecordsByFilter("section_types", "", "sort_order,label", 0, 0, nil)
+	if err == nil {
+		export.SectionTypes = sanitizeRecords(sectionTypeRecords)
+	}
+	customItemRecords, err := app.FindRecordsByFilter("custom_items", "", "section,sort_order", 0, 0, nil)
+	if err == nil {
+		export.CustomItems = sanitizeRecords(customItemRecords)
+	}
+
+	// External media
+	externalMediaRecords, err := app.FindRecordsByFilter("external_media", "", "", 0, 0, nil)
+	if err == nil {
+		export.ExternalMedia = sanitizeRecords(externalMediaRecords)
+	}
+
+	// Uploads
+	uploadRecords, err := app.FindRecordsByFilter("uploads", "", "", 0, 0, nil)
+	if err == nil {
+		export.Uploads = sanitizeRecords(uploadRecords)
+	}
+
+	// Job applications (share tokens may point at them)
+	applicationRecords, err := app.FindRecordsByFilter("applications", "", "-applied_at,company", 0, 0, nil)
+	if err == nil {
+		export.Applications = sanitizeRecords(applicationRecords)
+	}
+
+	// Share tokens (metadata only)
+	shareTokenRecords, err := app.FindRecordsByFilter("share_tokens", "", "name", 0, 0, nil)
+	if err == nil {
+		export.ShareTokens = sanitizeShareTokenRecords(shareTokenRecords)
+	}
+
+	// Resume templates
+	templateRecords, err := app.FindRecordsByFilter("resume_templates", "", "name", 0, 0, nil)
+	if err == nil {
+		export.ResumeTemplates = sanitizeRecords(templateRecords)
+	}
+
+	// Site settings (singleton)
+	settingsRecords, err := app.FindRecordsByFilter("site_settings", "", "", 1, 0, nil)
+	if err == nil && len(settingsRecords) > 0 {
+		export.SiteSettings = sanitizeRecord(settingsRecords[0])
+	}
+
 	return export, nil
 }
 
@@ -156,7 +228,7 @@ func sanitizeRecord(record *core.Record) map[string]interface{} {
 		if key == "collectionId" || key == "collectionName" {
 			continue
 		}
-		data[key] = value
+		data[key] = plainValue(value)
 	}
 
 	// Add the record ID (useful for references)
@@ -165,6 +237,23 @@ func sanitizeRecord(record *core.Record) map[string]interface{} {
 	return data
 }
 
+// plainValue converts PocketBase field values (types.DateTime, types.JSONRaw, ...)
+// into plain strings, numbers, maps and slices by round-tripping them through JSON.
+// Without this the YAML encoder writes dates as {} and JSON fields as byte arrays,
+// and the export could not be imported again.
+func plainValue(value interface{}) interface{} {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	var plain interface{}
+	if err := json.Unmarshal(raw, &plain); err != nil {
+		return value
+	}
+	return plain
+}
+
 // sanitizeRecords converts multiple records to maps
 func sanitizeRecords(records []*core.Record) []map[string]interface{} {
 	result := make([]map[string]interface{}, 0, len(records))
@@ -186,6 +275,18 @@ func sanitizeViewRecords(records []*core.Record) []map[string]interface{} {
 	return result
 }
 
+// sanitizeShareTokenRecords converts share tokens without their token hashes.
+// Tokens are only ever shown once, so restored share links have to be regenerated.
+func sanitizeShareTokenRecords(records []*core.Record) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		data := sanitizeRecord(record)
+		delete(data, "token_hash")
+		result = append(result, data)
+	}
+	return result
+}
+
 // serveJSON sends the export as a JSON file download
 func serveJSON(e *core.RequestEvent, data *ExportData, filename string) error {
 	jsonBytes, err := json.MarshalIndent(data, "", "  ")
@@ -0,0 +1,989 @@
+package hooks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"gopkg.in/yaml.v3"
+)
+
+// Import strategies
+const (
+	// ImportStrategyReplace makes every collection present in the document match it exactly:
+	// records missing from the document are deleted.
+	ImportStrategyReplace = "replace"
+	// ImportStrategyUpsert updates records whose ID already exists and creates the rest.
+	ImportStrategyUpsert = "upsert"
+	// ImportStrategyAppend creates every record with a fresh ID, leaving existing data alone.
+	ImportStrategyAppend = "append"
+)
+
+// Import change actions
+const (
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionDelete    = "delete"
+	ImportActionUnchanged = "unchanged"
+)
+
+const maxImportSize = 20 * 1024 * 1024 // 20MB
+
+// importCollections lists the collections of an export document in import order.
+// Referenced collections come first (external_media before projects, views and
+// applications before share_tokens).
+var importCollections = []string{
+	"site_settings", "profile", "external_media", "uploads",
+	"experience", "projects", "education", "certifications", "awards",
+	"skills", "posts", "talks", "contact_methods", "testimonials",
+	"section_types", "custom_items",
+	"views", "applications", "share_tokens", "resume_templates",
+}
+
+// singletonCollections hold a single record that is updated in place
+var singletonCollections = map[string]bool{
+	"profile":       true,
+	"site_settings": true,
+}
+
+// importSkippedFields are system fields that are never written from a document
+var importSkippedFields = map[string]bool{
+	"id":             true,
+	"collectionId":   true,
+	"collectionName": true,
+	"created":        true,
+	"updated":        true,
+}
+
+// recordIDPattern matches IDs PocketBase accepts for records
+var recordIDPattern = regexp.MustCompile(`^[a-z0-9]{15}$`)
+
+// errImportDryRun rolls back the import transaction after a dry run
+var errImportDryRun = errors.New("import dry run")
+
+// ImportOptions controls how an export document is applied
+type ImportOptions struct {
+	Strategy string `json:"strategy"`
+	DryRun   bool   `json:"dry_run"`
+}
+
+// ImportChange describes what an import does to a single record
+type ImportChange struct {
+	Action   string                 `json:"action"`
+	SourceID string                 `json:"source_id,omitempty"`
+	TargetID string                 `json:"target_id"`
+	Label    string                 `json:"label,omitempty"`
+	Diff     map[string]interface{} `json:"diff,omitempty"`
+}
+
+// ImportSummary counts changes by action
+type ImportSummary struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Deleted   int `json:"deleted"`
+	Unchanged int `json:"unchanged"`
+}
+
+// ImportResult is returned by /api/import for both dry runs and real imports
+type ImportResult struct {
+	Strategy    string                       `json:"strategy"`
+	DryRun      bool                         `json:"dry_run"`
+	Summary     ImportSummary                `json:"summary"`
+	Collections map[string][]ImportChange    `json:"collections"`
+	IDMap       map[string]map[string]string `json:"id_map"`
+	Warnings    []string                     `json:"warnings,omitempty"`
+}
+
+// RegisterImportHooks registers the data import endpoint, the counterpart of /api/export
+func RegisterImportHooks(app *pocketbase.PocketBase) {
+	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
+		// Import a document produced by /api/export
+		// POST /api/import?strategy=replace|upsert|append&dry_run=true
+		// Body: raw JSON/YAML/ZIP, or multipart form with a "file" field.
+		// ZIP backups also restore file attachments after verifying the manifest checksums.
+		se.Router.POST("/api/import", func(e *core.RequestEvent) error {
+			opts, err := parseImportOptions(e)
+			if err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			}
+
+			payload, err := spoolImportPayload(e)
+			if err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			}
+			defer func() {
+				payload.Close()
+				os.Remove(payload.Name())
+			}()
+
+			data, archive, err := readImportDocument(payload)
+			if err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			}
+
+			result, err := importExportData(app, data, archive, opts)
+			if err != nil {
+				app.Logger().Warn("Import failed", "strategy", opts.Strategy, "error", err)
+				return e.JSON(http.StatusBadRequest, map[string]string{
+					"error": fmt.Sprintf("Import failed, no changes were made: %v", err),
+				})
+			}
+
+			if !opts.DryRun {
+				app.Logger().Info("Import completed",
+					"strategy", opts.Strategy,
+					"created", result.Summary.Created,
+					"updated", result.Summary.Updated,
+					"deleted", result.Summary.Deleted,
+				)
+			}
+
+			return e.JSON(http.StatusOK, result)
+		}).Bind(apis.RequireAuth())
+
+		return se.Next()
+	})
+}
+
+// parseImportOptions reads strategy and dry_run from the query string. Form
+// fields are not read: parsing the multipart body here would bypass the size
+// limit that spoolImportPayload puts on it.
+func parseImportOptions(e *core.RequestEvent) (ImportOptions, error) {
+	query := e.Request.URL.Query()
+
+	strategy := query.Get("strategy")
+	if strategy == "" {
+		strategy = ImportStrategyUpsert
+	}
+
+	switch strategy {
+	case ImportStrategyReplace, ImportStrategyUpsert, ImportStrategyAppend:
+	default:
+		return ImportOptions{}, fmt.Errorf("invalid strategy. Use 'replace', 'upsert' or 'append'")
+	}
+
+	dryRun := query.Get("dry_run")
+
+	return ImportOptions{
+		Strategy: strategy,
+		DryRun:   dryRun == "true" || dryRun == "1",
+	}, nil
+}
+
+// spoolImportPayload copies the uploaded file or raw request body to a temporary
+// file, so backup archives of any size can be read without holding them in memory.
+func spoolImportPayload(e *core.RequestEvent) (*os.File, error) {
+	e.Request.Body = http.MaxBytesReader(e.Response, e.Request.Body, maxBackupSize)
+
+	var source io.Reader = e.Request.Body
+	if strings.HasPrefix(e.Request.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := e.Request.FormFile("file")
+		if err != nil {
+			return nil, fmt.Errorf("missing import file")
+		}
+		defer file.Close()
+		source = file
+	}
+
+	tmp, err := os.CreateTemp("", "facet-import-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to store import file")
+	}
+
+	size, err := io.Copy(tmp, source)
+	if err != nil || size == 0 {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read import file (maximum size is 1GB)")
+		}
+		return nil, fmt.Errorf("empty import document")
+	}
+
+	return tmp, nil
+}
+
+// readImportDocument parses a spooled payload as a ZIP backup or a JSON/YAML document
+func readImportDocument(file *os.File) (*ExportData, *backupArchive, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read import file")
+	}
+
+	magic := make([]byte, 4)
+	if _, err := file.ReadAt(magic, 0); err == nil && string(magic) == "PK\x03\x04" {
+		return openBackupArchive(file, info.Size())
+	}
+
+	if info.Size() > maxImportSize {
+		return nil, nil, fmt.Errorf("JSON/YAML documents are limited to 20MB, use a zip backup for larger imports")
+	}
+
+	payload, err := io.ReadAll(io.NewSectionReader(file, 0, info.Size()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read import file")
+	}
+
+	data, err := parseExportDocument(payload)
+	return data, nil, err
+}
+
+// parseExportDocument decodes a JSON or YAML export document and checks its version.
+// YAML is a superset of JSON, so one decoder handles both formats.
+func parseExportDocument(payload []byte) (*ExportData, error) {
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("invalid import document: %v", err)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("empty import document")
+	}
+
+	// Normalize through JSON so YAML timestamps, ints and nested maps
+	// look exactly like values decoded from a JSON export
+	normalized, err := json.Marshal(plainValue(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid import document: %v", err)
+	}
+
+	var data ExportData
+	if err := json.Unmarshal(normalized, &data); err != nil {
+		return nil, fmt.Errorf("invalid import document: %v", err)
+	}
+
+	if data.Meta.App != "" && data.Meta.App != "Facet" {
+		return nil, fmt.Errorf("document was not exported by Facet (app: %q)", data.Meta.App)
+	}
+	if major := strings.SplitN(data.Meta.Version, ".", 2)[0]; major != "1" {
+		return nil, fmt.Errorf("unsupported export version %q", data.Meta.Version)
+	}
+
+	return &data, nil
+}
+
+// exportDocumentRecords returns the records of one collection in an export document
+func exportDocumentRecords(data *ExportData, collection string) []map[string]interface{} {
+	switch collection {
+	case "site_settings":
+		if data.SiteSettings == nil {
+			return nil
+		}
+		return []map[string]interface{}{data.SiteSettings}
+	case "profile":
+		if data.Profile == nil {
+			return nil
+		}
+		return []map[string]interface{}{data.Profile}
+	case "experience":
+		return data.Experience
+	case "projects":
+		return data.Projects
+	case "education":
+		return data.Education
+	case "certifications":
+		return data.Certifications
+	case "awards":
+		return data.Awards
+	case "skills":
+		return data.Skills
+	case "posts":
+		return data.Posts
+	case "talks":
+		return data.Talks
+	case "views":
+		return data.Views
+	case "contact_methods":
+		return data.ContactMethods
+	case "testimonials":
+		return data.Testimonials
+	case "section_types":
+		return data.SectionTypes
+	case "custom_items":
+		return data.CustomItems
+	case "external_media":
+		return data.ExternalMedia
+	case "uploads":
+		return data.Uploads
+	case "applications":
+		return data.Applications
+	case "share_tokens":
+		return data.ShareTokens
+	case "resume_templates":
+		return data.ResumeTemplates
+	}
+	return nil
+}
+
+// importExportData applies an export document in a single transaction.
+// Dry runs execute the same writes (so validation errors surface) and then roll back.
+// archive is the backup the document came from, or nil for plain JSON/YAML documents
+// which carry no file attachments.
+func importExportData(app core.App, data *ExportData, archive *backupArchive, opts ImportOptions) (*ImportResult, error) {
+	var result *ImportResult
+
+	err := app.RunInTransaction(func(txApp core.App) error {
+		imp := newDataImporter(txApp, archive, opts)
+		if err := imp.plan(data); err != nil {
+			return err
+		}
+		if err := imp.apply(); err != nil {
+			return err
+		}
+		result = imp.result
+
+		if opts.DryRun {
+			return errImportDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportDryRun) {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// plannedRecord is a document record with its resolved target
+type plannedRecord struct {
+	collection *core.Collection
+	source     map[string]interface{}
+	sourceID   string
+	targetID   string
+	existing   *core.Record
+	values     map[string]interface{}
+	files      map[string][]*filesystem.File
+	skip       bool
+	change     *ImportChange
+}
+
+// dataImporter resolves IDs, remaps references and writes records for one import
+type dataImporter struct {
+	app     core.App
+	archive *backupArchive
+	opts    ImportOptions
+	planned map[string][]*plannedRecord
+	deletes map[string][]*core.Record
+	idMap   map[string]map[string]string
+	slugs   map[string]map[string]string // collection -> slug -> target ID
+	warned  map[string]bool
+	result  *ImportResult
+}
+
+func newDataImporter(app core.App, archive *backupArchive, opts ImportOptions) *dataImporter {
+	return &dataImporter{
+		app:     app,
+		archive: archive,
+		opts:    opts,
+		planned: make(map[string][]*plannedRecord),
+		deletes: make(map[string][]*core.Record),
+		idMap:   make(map[string]map[string]string),
+		slugs:   make(map[string]map[string]string),
+		warned:  make(map[string]bool),
+		result: &ImportResult{
+			Strategy:    opts.Strategy,
+			DryRun:      opts.DryRun,
+			Collections: make(map[string][]ImportChange),
+			IDMap:       make(map[string]map[string]string),
+		},
+	}
+}
+
+// plan resolves target IDs for every record first, so references between
+// collections (view sections, view_visibility) can be remapped before any write.
+func (imp *dataImporter) plan(data *ExportData) error {
+	for _, name := range importCollections {
+		sources := exportDocumentRecords(data, name)
+		if len(sources) == 0 {
+			continue
+		}
+
+		collection, err := imp.app.FindCollectionByNameOrId(name)
+		if err != nil {
+			return fmt.Errorf("collection %s not found: %w", name, err)
+		}
+
+		if singletonCollections[name] {
+			if err := imp.planSingleton(collection, sources); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := imp.planCollection(collection, sources); err != nil {
+			return err
+		}
+	}
+
+	if err := imp.planDependentDeletes(); err != nil {
+		return err
+	}
+
+	for _, name := range importCollections {
+		for _, p := range imp.planned[name] {
+			if err := imp.resolveValues(p); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// planSingleton maps the document record onto the existing singleton record
+func (imp *dataImporter) planSingleton(collection *core.Collection, sources []map[string]interface{}) error {
+	p := &plannedRecord{
+		collection: collection,
+		source:     sources[0],
+		sourceID:   importString(sources[0]["id"]),
+	}
+
+	existing, err := imp.app.FindRecordsByFilter(collection.Id, "", "", 1, 0, nil)
+	if err == nil && len(existing) > 0 {
+		p.existing = existing[0]
+		p.targetID = existing[0].Id
+	} else if recordIDPattern.MatchString(p.sourceID) && imp.opts.Strategy != ImportStrategyAppend {
+		p.targetID = p.sourceID
+	} else {
+		p.targetID = core.GenerateDefaultRandomId()
+	}
+
+	imp.mapID(collection.Name, p.sourceID, p.targetID)
+	imp.planned[collection.Name] = []*plannedRecord{p}
+	return nil
+}
+
+// planCollection resolves target IDs for a list collection according to the strategy
+func (imp *dataImporter) planCollection(collection *core.Collection, sources []map[string]interface{}) error {
+	seen := make(map[string]bool)
+	targets := make(map[string]bool)
+
+	for _, source := range sources {
+		p := &plannedRecord{
+			collection: collection,
+			source:     source,
+			sourceID:   importString(source["id"]),
+		}
+
+		keepID := imp.opts.Strategy != ImportStrategyAppend &&
+			recordIDPattern.MatchString(p.sourceID) &&
+			!seen[p.sourceID]
+
+		if keepID {
+			p.targetID = p.sourceID
+			if existing, err := imp.app.FindRecordById(collection, p.sourceID); err == nil {
+				p.existing = existing
+			}
+		} else {
+			p.targetID = core.GenerateDefaultRandomId()
+		}
+
+		if p.sourceID != "" && seen[p.sourceID] {
+			imp.warn(fmt.Sprintf("%s: duplicate id %q in document, imported as a new record", collection.Name, p.sourceID))
+		} else {
+			imp.mapID(collection.Name, p.sourceID, p.targetID)
+		}
+
+		seen[p.sourceID] = true
+		targets[p.targetID] = true
+		imp.planned[collection.Name] = append(imp.planned[collection.Name], p)
+	}
+
+	if imp.opts.Strategy != ImportStrategyReplace {
+		return nil
+	}
+
+	existing, err := imp.app.FindAllRecords(collection)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", collection.Name, err)
+	}
+	for _, record := range existing {
+		if !targets[record.Id] {
+			imp.deletes[collection.Name] = append(imp.deletes[collection.Name], record)
+		}
+	}
+
+	return nil
+}
+
+// planDependentDeletes removes share tokens and generated exports of deleted views,
+// which would otherwise block the delete or point at a missing view.
+func (imp *dataImporter) planDependentDeletes() error {
+	dependents := []struct {
+		collection string
+		field      string
+	}{
+		{"share_tokens", "view_id"},
+		{"view_exports", "view"},
+	}
+
+	for _, view := range imp.deletes["views"] {
+		for _, dep := range dependents {
+			records, err := imp.app.FindRecordsByFilter(
+				dep.collection,
+				dep.field+" = {:view}",
+				"",
+				0,
+				0,
+				map[string]interface{}{"view": view.Id},
+			)
+			if err != nil {
+				continue
+			}
+			for _, record := range records {
+				if !imp.isDeleted(dep.collection, record.Id) {
+					imp.deletes[dep.collection] = append(imp.deletes[dep.collection], record)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveValues builds the field values to write and records the planned change
+func (imp *dataImporter) resolveValues(p *plannedRecord) error {
+	name := p.collection.Name
+	values := make(map[string]interface{})
+	files := make(map[string][]*filesystem.File)
+
+	keys := make([]string, 0, len(p.source))
+	for key := range p.source {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := p.source[key]
+
+		if importSkippedFields[key] {
+			continue
+		}
+		// Secrets are never imported; say so when a document carries them
+		if name == "views" && (key == "password" || key == "password_hash") {
+			if importString(value) != "" {
+				imp.warnOnce(name+"."+key, fmt.Sprintf("views.%s is never imported; view passwords are left as they are", key))
+			}
+			continue
+		}
+		if name == "share_tokens" && key == "token_hash" {
+			if importString(value) != "" {
+				imp.warnOnce(name+"."+key, "share_tokens.token_hash is never imported; existing links keep working and restored links are disabled")
+			}
+			continue
+		}
+
+		field := p.collection.Fields.GetByName(key)
+		if field == nil {
+			imp.warnOnce(name+"."+key, fmt.Sprintf("%s.%s does not exist in this installation and was skipped", name, key))
+			continue
+		}
+
+		switch f := field.(type) {
+		case *core.FileField:
+			if imp.archive == nil {
+				imp.warnOnce(name+"."+key, fmt.Sprintf("%s.%s: files are only included in zip backups and were left unchanged", name, key))
+				continue
+			}
+			names := fileNames(value)
+			if p.existing != nil && sameStrings(p.existing.GetStringSlice(key), names) {
+				continue
+			}
+			files[key] = imp.openFiles(p, names)
+			continue
+		case *core.RelationField:
+			value = imp.remapRelation(name, f, value)
+			if f.Required && importString(value) == "" && len(fileNames(value)) == 0 {
+				imp.warn(fmt.Sprintf("%s: %q skipped because its %s no longer exists", name, recordLabel(p.source), key))
+				p.skip = true
+				return nil
+			}
+		}
+
+		switch {
+		case key == "view_visibility":
+			value = remapKeys(value, imp.idMap["views"])
+		case name == "views" && key == "sections":
+			value = imp.remapSections(value)
+		case key == "slug":
+			value = imp.uniqueSlug(p, importString(value))
+		}
+
+		values[key] = value
+	}
+
+	if p.existing == nil {
+		for _, field := range p.collection.Fields {
+			if f, ok := field.(*core.FileField); ok && f.Required && len(files[f.Name]) == 0 {
+				imp.warn(fmt.Sprintf("%s: %q skipped because its %s file is not available", name, recordLabel(p.source), f.Name))
+				p.skip = true
+				return nil
+			}
+		}
+	}
+
+	if name == "views" && importString(values["visibility"]) == "password" &&
+		(p.existing == nil || p.existing.GetString("password_hash") == "") {
+		imp.warn(fmt.Sprintf("views: %q is password protected; set a new password after import", recordLabel(values)))
+	}
+
+	if name == "share_tokens" && p.existing == nil {
+		// Only the HMAC of a token is stored and exports leave it out, so restored
+		// tokens get an unmatchable hash and stay disabled until regenerated
+		values["token_hash"] = security.RandomString(64)
+		values["is_active"] = false
+		imp.warnOnce("share_tokens.is_active", "share_tokens: restored share links are disabled; generate new links for the views you still share")
+	}
+
+	p.values = values
+	p.files = files
+	p.change = &ImportChange{
+		SourceID: p.sourceID,
+		TargetID: p.targetID,
+		Label:    recordLabel(values),
+	}
+
+	if p.existing == nil {
+		p.change.Action = ImportActionCreate
+		return nil
+	}
+
+	before := make(map[string]interface{}, len(values))
+	after := make(map[string]interface{}, len(values))
+	updated := p.existing.Clone()
+	for key, value := range values {
+		before[key] = plainValue(p.existing.Get(key))
+		updated.Set(key, value)
+		after[key] = plainValue(updated.Get(key))
+	}
+
+	p.change.Diff = calculateDiff(before, after)
+	for key, fieldFiles := range files {
+		newNames := make([]string, 0, len(fieldFiles))
+		for _, file := range fieldFiles {
+			newNames = append(newNames, file.Name)
+		}
+		p.change.Diff[key] = map[string]interface{}{
+			"type": "changed",
+			"old":  p.existing.GetStringSlice(key),
+			"new":  newNames,
+		}
+	}
+	if len(p.change.Diff) == 0 {
+		p.change.Action = ImportActionUnchanged
+		p.change.Diff = nil
+	} else {
+		p.change.Action = ImportActionUpdate
+	}
+
+	return nil
+}
+
+// apply performs the planned deletes and writes and fills in the result
+func (imp *dataImporter) apply() error {
+	// Deletes run first (dependents before views) so replaced slugs are free again
+	deleteOrder := append([]string{"view_exports"}, reversed(importCollections)...)
+	for _, name := range deleteOrder {
+		for _, record := range imp.deletes[name] {
+			if err := imp.app.Delete(record); err != nil {
+				return fmt.Errorf("failed to delete %s %s: %w", name, record.Id, err)
+			}
+			imp.record(name, ImportChange{
+				Action:   ImportActionDelete,
+				TargetID: record.Id,
+				Label:    recordLabel(record.FieldsData()),
+			})
+		}
+	}
+
+	for _, name := range importCollections {
+		for _, p := range imp.planned[name] {
+			if p.skip {
+				continue
+			}
+			if p.change.Action != ImportActionUnchanged {
+				record := p.existing
+				if record == nil {
+					record = core.NewRecord(p.collection)
+					record.Id = p.targetID
+				}
+				for key, value := range p.values {
+					record.Set(key, value)
+				}
+				for key, fieldFiles := range p.files {
+					record.Set(key, fieldFiles)
+				}
+				if err := imp.app.Save(record); err != nil {
+					return fmt.Errorf("%s %q: %w", name, p.change.Label, err)
+				}
+			}
+			imp.record(name, *p.change)
+		}
+	}
+
+	for name, ids := range imp.idMap {
+		remapped := make(map[string]string)
+		for sourceID, targetID := range ids {
+			if sourceID != targetID {
+				remapped[sourceID] = targetID
+			}
+		}
+		if len(remapped) > 0 {
+			imp.result.IDMap[name] = remapped
+		}
+	}
+
+	return nil
+}
+
+// record appends a change to the result and updates the summary
+func (imp *dataImporter) record(collection string, change ImportChange) {
+	imp.result.Collections[collection] = append(imp.result.Collections[collection], change)
+
+	switch change.Action {
+	case ImportActionCreate:
+		imp.result.Summary.Created++
+	case ImportActionUpdate:
+		imp.result.Summary.Updated++
+	case ImportActionDelete:
+		imp.result.Summary.Deleted++
+	case ImportActionUnchanged:
+		imp.result.Summary.Unchanged++
+	}
+}
+
+func (imp *dataImporter) mapID(collection, sourceID, targetID string) {
+	if sourceID == "" {
+		return
+	}
+	if imp.idMap[collection] == nil {
+		imp.idMap[collection] = make(map[string]string)
+	}
+	imp.idMap[collection][sourceID] = targetID
+}
+
+func (imp *dataImporter) warn(message string) {
+	imp.result.Warnings = append(imp.result.Warnings, message)
+}
+
+func (imp *dataImporter) warnOnce(key, message string) {
+	if imp.warned[key] {
+		return
+	}
+	imp.warned[key] = true
+	imp.warn(message)
+}
+
+// openFiles loads a record's attachments from the backup archive.
+// Files missing from the archive (e.g. already missing when the backup was taken) are skipped.
+func (imp *dataImporter) openFiles(p *plannedRecord, names []string) []*filesystem.File {
+	files := make([]*filesystem.File, 0, len(names))
+	for _, name := range names {
+		file, err := imp.archive.openFile(p.collection.Name, p.sourceID, name)
+		if err != nil {
+			imp.warn(fmt.Sprintf("%s: %q is missing file %s", p.collection.Name, recordLabel(p.source), name))
+			continue
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+func (imp *dataImporter) isDeleted(collection, id string) bool {
+	for _, record := range imp.deletes[collection] {
+		if record.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// remapSections rewrites item IDs and itemConfig keys of a view's sections
+func (imp *dataImporter) remapSections(value interface{}) interface{} {
+	sections, ok := value.([]interface{})
+	if !ok {
+		return value
+	}
+
+	result := make([]interface{}, 0, len(sections))
+	for _, s := range sections {
+		section, ok := s.(map[string]interface{})
+		if !ok {
+			result = append(result, s)
+			continue
+		}
+
+		ids := imp.idMap[getCollectionName(importString(section["section"]))]
+
+		remapped := make(map[string]interface{}, len(section))
+		for key, v := range section {
+			remapped[key] = v
+		}
+
+		if items, ok := section["items"].([]interface{}); ok {
+			newItems := make([]interface{}, 0, len(items))
+			for _, item := range items {
+				id := importString(item)
+				if target, ok := ids[id]; ok {
+					newItems = append(newItems, target)
+				} else {
+					newItems = append(newItems, item)
+				}
+			}
+			remapped["items"] = newItems
+		}
+
+		if _, ok := section["itemConfig"]; ok {
+			remapped["itemConfig"] = remapKeys(section["itemConfig"], ids)
+		}
+
+		result = append(result, remapped)
+	}
+
+	return result
+}
+
+// remapRelation maps relation IDs to their import targets and drops IDs that
+// do not resolve to a record, which would otherwise fail validation.
+func (imp *dataImporter) remapRelation(collection string, field *core.RelationField, value interface{}) interface{} {
+	related, err := imp.app.FindCollectionByNameOrId(field.CollectionId)
+	if err != nil {
+		return nil
+	}
+
+	var ids []string
+	switch v := value.(type) {
+	case string:
+		if v != "" {
+			ids = []string{v}
+		}
+	case []interface{}:
+		for _, id := range v {
+			if s := importString(id); s != "" {
+				ids = append(ids, s)
+			}
+		}
+	}
+
+	resolved := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if target, ok := imp.idMap[related.Name][id]; ok {
+			resolved = append(resolved, target)
+			continue
+		}
+		if _, err := imp.app.FindRecordById(related, id); err == nil {
+			resolved = append(resolved, id)
+			continue
+		}
+		imp.warnOnce(collection+"."+field.Name+"."+id,
+			fmt.Sprintf("%s.%s: %s %q does not exist and the reference was dropped", collection, field.Name, related.Name, id))
+	}
+
+	if field.IsMultiple() {
+		return resolved
+	}
+	if len(resolved) == 0 {
+		return ""
+	}
+	return resolved[0]
+}
+
+// uniqueSlug returns slug, or slug-2, slug-3... when another record already uses it
+func (imp *dataImporter) uniqueSlug(p *plannedRecord, slug string) string {
+	if slug == "" {
+		return slug
+	}
+
+	name := p.collection.Name
+	if imp.slugs[name] == nil {
+		imp.slugs[name] = make(map[string]string)
+	}
+
+	candidate := slug
+	for i := 2; imp.slugTaken(p, candidate); i++ {
+		candidate = fmt.Sprintf("%s-%d", slug, i)
+	}
+
+	if candidate != slug {
+		imp.warn(fmt.Sprintf("%s: slug %q is already in use, imported as %q", name, slug, candidate))
+	}
+	imp.slugs[name][candidate] = p.targetID
+	return candidate
+}
+
+func (imp *dataImporter) slugTaken(p *plannedRecord, slug string) bool {
+	if owner, ok := imp.slugs[p.collection.Name][slug]; ok && owner != p.targetID {
+		return true
+	}
+
+	record, err := imp.app.FindFirstRecordByData(p.collection, "slug", slug)
+	if err != nil || record.Id == p.targetID {
+		return false
+	}
+	return !imp.isDeleted(p.collection.Name, record.Id)
+}
+
+// remapKeys returns a copy of a JSON object with its keys mapped through ids
+func remapKeys(value interface{}, ids map[string]string) interface{} {
+	object, ok := value.(map[string]interface{})
+	if !ok || len(ids) == 0 {
+		return value
+	}
+
+	result := make(map[string]interface{}, len(object))
+	for key, v := range object {
+		if target, ok := ids[key]; ok {
+			result[target] = v
+		} else {
+			result[key] = v
+		}
+	}
+	return result
+}
+
+// recordLabel picks a human readable label for a record in import results
+func recordLabel(data map[string]interface{}) string {
+	for _, key := range []string{"name", "title", "company", "institution", "slug", "value"} {
+		if label := importString(data[key]); label != "" {
+			return label
+		}
+	}
+	return ""
+}
+
+func importString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return ""
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func reversed(values []string) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[len(values)-1-i] = v
+	}
+	return result
+}
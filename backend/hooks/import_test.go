@@ -0,0 +1,365 @@
+package hooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"gopkg.in/yaml.v3"
+)
+
+// seedImportFixture creates a small profile with a view that references its items
+func seedImportFixture(t *testing.T, app core.App) (expID, projID, viewID string) {
+	t.Helper()
+
+	save := func(collection string, fields map[string]interface{}) *core.Record {
+		coll, err := app.FindCollectionByNameOrId(collection)
+		if err != nil {
+			t.Fatalf("Failed to find %s: %v", collection, err)
+		}
+		record := core.NewRecord(coll)
+		for key, value := range fields {
+			record.Set(key, value)
+		}
+		if err := app.Save(record); err != nil {
+			t.Fatalf("Failed to save %s: %v", collection, err)
+		}
+		return record
+	}
+
+	save("profile", map[string]interface{}{"name": "Ada Lovelace", "headline": "Engineer"})
+	exp := save("experience", map[string]interface{}{
+		"company":    "Analytical Engines",
+		"title":      "Programmer",
+		"start_date": "1842-01-01 00:00:00.000Z",
+		"bullets":    []string{"Wrote the first program"},
+		"visibility": "public",
+	})
+	proj := save("projects", map[string]interface{}{
+		"title":      "Note G",
+		"slug":       "note-g",
+		"visibility": "public",
+	})
+	view := save("views", map[string]interface{}{
+		"name":       "Recruiters",
+		"slug":       "recruiters",
+		"visibility": "public",
+		"is_active":  true,
+		"sections": []map[string]interface{}{
+			{
+				"section": "experience",
+				"enabled": true,
+				"items":   []string{exp.Id},
+				"itemConfig": map[string]interface{}{
+					exp.Id: map[string]interface{}{"overrides": map[string]interface{}{"title": "Lead Programmer"}},
+				},
+			},
+			{"section": "projects", "enabled": true, "items": []string{proj.Id}},
+		},
+	})
+
+	proj.Set("view_visibility", map[string]interface{}{view.Id: true})
+	if err := app.Save(proj); err != nil {
+		t.Fatalf("Failed to save project visibility: %v", err)
+	}
+
+	return exp.Id, proj.Id, view.Id
+}
+
+// exportRoundTrip encodes an export the way /api/export does and parses it back
+func exportRoundTrip(t *testing.T, app core.App, format string) *ExportData {
+	t.Helper()
+
+	data, err := collectExportData(app)
+	if err != nil {
+		t.Fatalf("Failed to collect export: %v", err)
+	}
+
+	var payload []byte
+	if format == "yaml" {
+		payload, err = yaml.Marshal(data)
+	} else {
+		payload, err = json.Marshal(data)
+	}
+	if err != nil {
+		t.Fatalf("Failed to encode %s export: %v", format, err)
+	}
+
+	parsed, err := parseExportDocument(payload)
+	if err != nil {
+		t.Fatalf("Failed to parse %s export: %v", format, err)
+	}
+	return parsed
+}
+
+func TestImportRoundTripIntoEmptyInstance(t *testing.T) {
+	for _, format := range []string{"json", "yaml"} {
+		t.Run(format, func(t *testing.T) {
+			source := newMigratedTestApp(t)
+			expID, projID, viewID := seedImportFixture(t, source)
+
+			// A share token linked to a job application keeps its link
+			applications, _ := source.FindCollectionByNameOrId("applications")
+			application := core.NewRecord(applications)
+			application.Set("company", "Babbage & Co")
+			application.Set("role", "Programmer")
+			application.Set("status", "interviewing")
+			if err := source.Save(application); err != nil {
+				t.Fatalf("Failed to save application: %v", err)
+			}
+			tokens, _ := source.FindCollectionByNameOrId("share_tokens")
+			token := core.NewRecord(tokens)
+			token.Set("view_id", viewID)
+			token.Set("name", "Babbage recruiter")
+			token.Set("token_hash", "secret-hash")
+			token.Set("application", application.Id)
+			if err := source.Save(token); err != nil {
+				t.Fatalf("Failed to save share token: %v", err)
+			}
+
+			data := exportRoundTrip(t, source, format)
+
+			target := newMigratedTestApp(t)
+			result, err := importExportData(target, data, nil, ImportOptions{Strategy: ImportStrategyReplace})
+			if err != nil {
+				t.Fatalf("Import failed: %v", err)
+			}
+			if result.Summary.Created != 6 {
+				t.Errorf("Created = %d, want 6 (%+v)", result.Summary.Created, result.Collections)
+			}
+
+			// IDs are preserved, so references stay valid
+			exp, err := target.FindRecordById("experience", expID)
+			if err != nil {
+				t.Fatalf("Experience not imported with original id: %v", err)
+			}
+			if exp.GetDateTime("start_date").Time().Year() != 1842 {
+				t.Errorf("start_date = %v, want 1842", exp.GetDateTime("start_date"))
+			}
+			if _, err := target.FindRecordById("projects", projID); err != nil {
+				t.Errorf("Project not imported with original id: %v", err)
+			}
+			if _, err := target.FindRecordById("views", viewID); err != nil {
+				t.Errorf("View not imported with original id: %v", err)
+			}
+			restoredApp, err := target.FindRecordById("applications", application.Id)
+			if err != nil {
+				t.Fatalf("Application not imported with original id: %v", err)
+			}
+			if restoredApp.GetString("company") != "Babbage & Co" || restoredApp.GetString("status") != "interviewing" {
+				t.Errorf("application = %s / %s, want the exported values", restoredApp.GetString("company"), restoredApp.GetString("status"))
+			}
+			restoredToken, err := target.FindRecordById("share_tokens", token.Id)
+			if err != nil {
+				t.Fatalf("Share token not imported: %v", err)
+			}
+			if restoredToken.GetString("application") != application.Id {
+				t.Errorf("share token application = %q, want %q", restoredToken.GetString("application"), application.Id)
+			}
+		})
+	}
+}
+
+func TestImportAppendRemapsReferences(t *testing.T) {
+	app := newMigratedTestApp(t)
+	expID, projID, viewID := seedImportFixture(t, app)
+	data := exportRoundTrip(t, app, "json")
+
+	result, err := importExportData(app, data, nil, ImportOptions{Strategy: ImportStrategyAppend})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	newExpID := result.IDMap["experience"][expID]
+	newProjID := result.IDMap["projects"][projID]
+	newViewID := result.IDMap["views"][viewID]
+	if newExpID == "" || newProjID == "" || newViewID == "" {
+		t.Fatalf("Missing id mappings: %v", result.IDMap)
+	}
+
+	view, err := app.FindRecordById("views", newViewID)
+	if err != nil {
+		t.Fatalf("Appended view not found: %v", err)
+	}
+	if view.GetString("slug") != "recruiters-2" {
+		t.Errorf("slug = %q, want recruiters-2", view.GetString("slug"))
+	}
+
+	var sections []struct {
+		Section    string                 `json:"section"`
+		Items      []string               `json:"items"`
+		ItemConfig map[string]interface{} `json:"itemConfig"`
+	}
+	if err := json.Unmarshal([]byte(view.GetString("sections")), &sections); err != nil {
+		t.Fatalf("Failed to decode sections: %v", err)
+	}
+	if len(sections) != 2 || sections[0].Items[0] != newExpID || sections[1].Items[0] != newProjID {
+		t.Errorf("Section items were not remapped: %+v", sections)
+	}
+	if _, ok := sections[0].ItemConfig[newExpID]; !ok {
+		t.Errorf("itemConfig keys were not remapped: %v", sections[0].ItemConfig)
+	}
+
+	project, err := app.FindRecordById("projects", newProjID)
+	if err != nil {
+		t.Fatalf("Appended project not found: %v", err)
+	}
+	if project.GetString("slug") != "note-g-2" {
+		t.Errorf("project slug = %q, want note-g-2", project.GetString("slug"))
+	}
+	var visibility map[string]bool
+	if err := project.UnmarshalJSONField("view_visibility", &visibility); err != nil {
+		t.Fatalf("Failed to decode view_visibility: %v", err)
+	}
+	if !visibility[newViewID] || len(visibility) != 1 {
+		t.Errorf("view_visibility = %v, want key %s", visibility, newViewID)
+	}
+
+	// The profile singleton is updated in place, never duplicated
+	profiles, _ := app.FindAllRecords("profile")
+	if len(profiles) != 1 {
+		t.Errorf("profile count = %d, want 1", len(profiles))
+	}
+}
+
+func TestImportDryRunMakesNoChanges(t *testing.T) {
+	app := newMigratedTestApp(t)
+	expID, _, _ := seedImportFixture(t, app)
+	data := exportRoundTrip(t, app, "json")
+
+	data.Experience[0]["title"] = "Countess"
+	extra, _ := app.FindCollectionByNameOrId("skills")
+	skill := core.NewRecord(extra)
+	skill.Set("name", "Mathematics")
+	if err := app.Save(skill); err != nil {
+		t.Fatalf("Failed to save skill: %v", err)
+	}
+	data.Skills = []map[string]interface{}{{"name": "Poetical science"}}
+
+	result, err := importExportData(app, data, nil, ImportOptions{Strategy: ImportStrategyReplace, DryRun: true})
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+
+	if !result.DryRun {
+		t.Error("Result should be flagged as a dry run")
+	}
+	if result.Summary.Updated != 1 || result.Summary.Created != 1 || result.Summary.Deleted != 1 {
+		t.Errorf("Summary = %+v, want 1 updated, 1 created, 1 deleted", result.Summary)
+	}
+
+	changes := result.Collections["experience"]
+	if len(changes) != 1 || changes[0].Action != ImportActionUpdate {
+		t.Fatalf("experience changes = %+v", changes)
+	}
+	if _, ok := changes[0].Diff["title"]; !ok || len(changes[0].Diff) != 1 {
+		t.Errorf("Diff = %v, want only title", changes[0].Diff)
+	}
+
+	exp, _ := app.FindRecordById("experience", expID)
+	if exp.GetString("title") != "Programmer" {
+		t.Errorf("Dry run modified experience title to %q", exp.GetString("title"))
+	}
+	if _, err := app.FindRecordById("skills", skill.Id); err != nil {
+		t.Error("Dry run deleted a skill")
+	}
+}
+
+func TestImportUpsertIsIdempotent(t *testing.T) {
+	app := newMigratedTestApp(t)
+	seedImportFixture(t, app)
+	data := exportRoundTrip(t, app, "yaml")
+
+	result, err := importExportData(app, data, nil, ImportOptions{Strategy: ImportStrategyUpsert})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	if result.Summary.Created != 0 || result.Summary.Updated != 0 || result.Summary.Deleted != 0 {
+		t.Errorf("Re-importing an unchanged export should be a no-op, got %+v (%+v)", result.Summary, result.Collections)
+	}
+	if len(result.IDMap) != 0 {
+		t.Errorf("IDMap = %v, want empty", result.IDMap)
+	}
+}
+
+func TestImportWarnsAboutSecrets(t *testing.T) {
+	app := newMigratedTestApp(t)
+	_, _, viewID := seedImportFixture(t, app)
+	data := exportRoundTrip(t, app, "json")
+
+	// A hand-edited document carrying secrets, turning the view password protected
+	data.Views[0]["visibility"] = "password"
+	data.Views[0]["password_hash"] = "$2a$10$hash"
+	data.ShareTokens = []map[string]interface{}{{
+		"id":         "tokenimported01",
+		"view_id":    viewID,
+		"name":       "Agency",
+		"token_hash": "secret-hash",
+		"is_active":  true,
+	}}
+
+	result, err := importExportData(app, data, nil, ImportOptions{Strategy: ImportStrategyUpsert})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"views.password_hash is never imported",
+		"share_tokens.token_hash is never imported",
+		"is password protected; set a new password",
+		"restored share links are disabled",
+	} {
+		found := false
+		for _, warning := range result.Warnings {
+			found = found || strings.Contains(warning, want)
+		}
+		if !found {
+			t.Errorf("Warnings = %q, want one about %q", result.Warnings, want)
+		}
+	}
+
+	token, err := app.FindRecordById("share_tokens", "tokenimported01")
+	if err != nil {
+		t.Fatalf("Imported token not found: %v", err)
+	}
+	if token.GetString("token_hash") == "secret-hash" || token.GetBool("is_active") {
+		t.Error("imported token should get an unmatchable hash and stay disabled")
+	}
+}
+
+func TestParseImportOptionsReadsQueryOnly(t *testing.T) {
+	body := strings.NewReader("strategy=replace&dry_run=true")
+	e := &core.RequestEvent{}
+	e.Request = httptest.NewRequest(http.MethodPost, "/api/import?strategy=append", body)
+	e.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	opts, err := parseImportOptions(e)
+	if err != nil {
+		t.Fatalf("parseImportOptions() error = %v", err)
+	}
+	if opts.Strategy != ImportStrategyAppend || opts.DryRun {
+		t.Errorf("parseImportOptions() = %+v, want the query's append without a dry run", opts)
+	}
+	if e.Request.Form != nil {
+		t.Error("parseImportOptions() should not parse the request body")
+	}
+}
+
+func TestParseExportDocumentRejectsUnknownVersions(t *testing.T) {
+	cases := map[string]string{
+		"future version": `{"meta":{"version":"2.0.0","app":"Facet"}}`,
+		"missing meta":   `{"experience":[]}`,
+		"other app":      `{"meta":{"version":"1.0.0","app":"Other"}}`,
+		"not a document": `- just a list`,
+	}
+
+	for name, payload := range cases {
+		if _, err := parseExportDocument([]byte(payload)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"facet/services"
+
+	"github.com/google/uuid"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// jsonResumeSections are the profile sections that have a JSON Resume equivalent
+var jsonResumeSections = []string{
+	"experience", "education", "skills", "projects", "awards", "certifications", "posts",
+}
+
+const maxJSONResumeSize = 5 * 1024 * 1024 // 5MB
+
+// RegisterJSONResumeHooks registers JSON Resume (jsonresume.org) import and export endpoints
+func RegisterJSONResumeHooks(app *pocketbase.PocketBase) {
+	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
+		// Export the whole profile (public and unlisted, non-draft items)
+		// GET /api/export/jsonresume
+		se.Router.GET("/api/export/jsonresume", func(e *core.RequestEvent) error {
+			data, err := collectProfileData(app)
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{
+					"error": fmt.Sprintf("Failed to collect data: %v", err),
+				})
+			}
+
+			return serveJSONResume(e, services.BuildJSONResume(data, resolveBaseURL(e)), "resume.json")
+		}).Bind(apis.RequireAuth())
+
+		// Export a single view, with its section selection, visibility and overrides applied
+		// GET /api/view/{slug}/jsonresume
+		se.Router.GET("/api/view/{slug}/jsonresume", func(e *core.RequestEvent) error {
+			slug := e.Request.PathValue("slug")
+
+			view, err := app.FindFirstRecordByFilter("views", "slug = {:slug}", map[string]interface{}{"slug": slug})
+			if err != nil {
+				return e.JSON(http.StatusNotFound, map[string]string{"error": "view not found"})
+			}
+
+			data, err := collectViewData(app, view)
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{
+					"error": fmt.Sprintf("Failed to collect view data: %v", err),
+				})
+			}
+
+			return serveJSONResume(e, services.BuildJSONResume(data, resolveBaseURL(e)), fmt.Sprintf("resume-%s.json", slug))
+		}).Bind(apis.RequireAuth())
+
+		// Import a JSON Resume document (raw JSON body or multipart "file")
+		// POST /api/import/jsonresume?visibility=private
+		// Uses the same record creation and deduplication as resume upload, without AI
+		se.Router.POST("/api/import/jsonresume", func(e *core.RequestEvent) error {
+			payload, filename, err := readJSONResumePayload(e)
+			if err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]interface{}{
+					"error": NewUserError(
+						"We couldn't read your JSON Resume file.",
+						"Please select a resume.json file smaller than 5MB and try again.",
+						err.Error(),
+					),
+				})
+			}
+
+			parsed, _, err := services.ParseJSONResume(payload)
+			if err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]interface{}{
+					"error": NewUserError(
+						"This doesn't look like a JSON Resume document.",
+						"Check that the file follows the schema at jsonresume.org.",
+						err.Error(),
+					),
+				})
+			}
+
+			visibility := e.Request.URL.Query().Get("visibility")
+			if visibility == "" {
+				visibility = e.Request.FormValue("visibility")
+			}
+			if visibility != "public" && visibility != "unlisted" {
+				visibility = "private"
+			}
+
+			importSessionID := uuid.New().String()
+			imported, deduped, err := createResumeRecordsWithDeduplication(app, parsed, filename, importSessionID, visibility)
+			if err != nil {
+				log.Printf("[JSON-RESUME] Failed to create records: %v", err)
+				return e.JSON(http.StatusInternalServerError, map[string]interface{}{
+					"error": NewUserError(
+						"We read your resume but couldn't save the data.",
+						"This might be a temporary database issue. Please try again in a moment.",
+						fmt.Sprintf("Database error: %v", err),
+					),
+				})
+			}
+
+			profileUpdated, err := fillEmptyProfileFields(app, parsed.Profile)
+			if err != nil {
+				log.Printf("[JSON-RESUME] [WARNING] Failed to update profile: %v", err)
+			}
+
+			counts := make(map[string]int)
+			for _, collection := range []string{"experience", "education", "skills", "certifications", "projects", "awards", "posts"} {
+				counts[collection] = len(imported[collection])
+			}
+
+			return e.JSON(http.StatusOK, map[string]interface{}{
+				"status":          "success",
+				"imported":        imported,
+				"counts":          counts,
+				"deduplicated":    deduped,
+				"profile_updated": profileUpdated,
+				"warnings":        parsed.Metadata.Warnings,
+				"filename":        filename,
+			})
+		}).Bind(apis.RequireAuth())
+
+		return se.Next()
+	})
+}
+
+// collectProfileData gathers every public or unlisted, non-draft item of the
+// JSON Resume sections into the same shape collectViewData produces for a view.
+func collectProfileData(app core.App) (*services.ViewData, error) {
+	data := &services.ViewData{
+		Profile:  make(map[string]interface{}),
+		Sections: make(map[string][]map[string]interface{}),
+	}
+
+	profileRecords, err := app.FindRecordsByFilter("profile", "", "", 1, 0, nil)
+	if err == nil && len(profileRecords) > 0 {
+		profile := profileRecords[0]
+		data.Profile["name"] = profile.GetString("name")
+		data.Profile["headline"] = profile.GetString("headline")
+		data.Profile["location"] = profile.GetString("location")
+		data.Profile["summary"] = profile.GetString("summary")
+		data.Profile["contact_email"] = profile.GetString("contact_email")
+	}
+
+	for _, section := range jsonResumeSections {
+		collection, err := app.FindCollectionByNameOrId(getCollectionName(section))
+		if err != nil {
+			continue
+		}
+
+		filter, sortField, params := sectionListQuery(collection, section)
+		records, err := app.FindRecordsByFilter(collection.Name, filter, sortField, 0, 0, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", collection.Name, err)
+		}
+
+		var visible []*core.Record
+		for _, record := range records {
+			if isRecordVisible(record) {
+				visible = append(visible, record)
+			}
+		}
+		if len(visible) == 0 {
+			continue
+		}
+
+		data.SectionOrder = append(data.SectionOrder, section)
+		for _, item := range serializeRecords(visible) {
+			if plain, ok := plainValue(item).(map[string]interface{}); ok {
+				data.Sections[section] = append(data.Sections[section], plain)
+			}
+		}
+	}
+
+	return data, nil
+}
+
+// fillEmptyProfileFields copies imported profile data into the profile without
+// overwriting anything already set. Creates the profile when none exists yet.
+func fillEmptyProfileFields(app core.App, profileData services.ProfileData) (bool, error) {
+	collection, err := app.FindCollectionByNameOrId("profile")
+	if err != nil {
+		return false, err
+	}
+
+	var profile *core.Record
+	records, err := app.FindRecordsByFilter(collection.Name, "", "", 1, 0, nil)
+	if err == nil && len(records) > 0 {
+		profile = records[0]
+	} else {
+		if profileData.Name == "" {
+			return false, nil
+		}
+		profile = core.NewRecord(collection)
+	}
+
+	changed := false
+	for field, value := range map[string]string{
+		"name":          profileData.Name,
+		"headline":      profileData.Headline,
+		"location":      profileData.Location,
+		"summary":       profileData.Summary,
+		"contact_email": profileData.ContactEmail,
+	} {
+		if value != "" && profile.GetString(field) == "" {
+			profile.Set(field, value)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return false, nil
+	}
+	if err := app.Save(profile); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// readJSONResumePayload returns the uploaded file (or raw body) and a filename used
+// by deduplication to recognise repeated imports of the same document
+func readJSONResumePayload(e *core.RequestEvent) ([]byte, string, error) {
+	e.Request.Body = http.MaxBytesReader(e.Response, e.Request.Body, maxJSONResumeSize)
+
+	if strings.HasPrefix(e.Request.Header.Get("Content-Type"), "multipart/form-data") {
+		file, header, err := e.Request.FormFile("file")
+		if err != nil {
+			return nil, "", fmt.Errorf("file upload error: %v", err)
+		}
+		defer file.Close()
+
+		payload, err := io.ReadAll(file)
+		if err != nil {
+			return nil, "", fmt.Errorf("file read error: %v", err)
+		}
+		return payload, header.Filename, nil
+	}
+
+	payload, err := io.ReadAll(e.Request.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("body read error: %v", err)
+	}
+	if len(payload) == 0 {
+		return nil, "", fmt.Errorf("empty request body")
+	}
+	return payload, "resume.json", nil
+}
+
+// serveJSONResume sends a JSON Resume document as a file download
+func serveJSONResume(e *core.RequestEvent, resume *services.JSONResume, filename string) error {
+	jsonBytes, err := json.MarshalIndent(resume, "", "  ")
+	if err != nil {
+		return e.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("Failed to serialize JSON: %v", err),
+		})
+	}
+
+	e.Response.Header().Set("Content-Type", "application/json")
+	e.Response.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	e.Response.WriteHeader(http.StatusOK)
+	e.Response.Write(jsonBytes)
+	return nil
+}
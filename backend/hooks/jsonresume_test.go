@@ -0,0 +1,121 @@
+package hooks
+
+import (
+	"testing"
+
+	"facet/services"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestJSONResumeImportDeduplicates(t *testing.T) {
+	app := newMigratedTestApp(t)
+
+	payload := []byte(`{
+		"basics": {"name": "Grace Hopper", "label": "Rear Admiral"},
+		"work": [{"name": "US Navy", "position": "Computer Scientist", "startDate": "1943-12"}],
+		"education": [{"institution": "Yale", "area": "Mathematics", "studyType": "PhD", "endDate": "1934"}],
+		"skills": [{"name": "Languages", "level": "Master", "keywords": ["COBOL", "FLOW-MATIC"]}],
+		"awards": [{"title": "National Medal of Technology", "awarder": "US", "date": "1991"}],
+		"publications": [{"name": "The Education of a Computer", "publisher": "ACM", "releaseDate": "1952-05-02"}]
+	}`)
+
+	for run := 1; run <= 2; run++ {
+		parsed, _, err := services.ParseJSONResume(payload)
+		if err != nil {
+			t.Fatalf("ParseJSONResume() error = %v", err)
+		}
+
+		imported, deduped, err := createResumeRecordsWithDeduplication(app, parsed, "resume.json", "session", "private")
+		if err != nil {
+			t.Fatalf("Import %d failed: %v", run, err)
+		}
+		if _, err := fillEmptyProfileFields(app, parsed.Profile); err != nil {
+			t.Fatalf("Profile update failed: %v", err)
+		}
+
+		total := 0
+		for _, ids := range imported {
+			total += len(ids)
+		}
+		if run == 1 && total != 6 {
+			t.Errorf("First import created %d records, want 6 (%v)", total, imported)
+		}
+		if run == 2 && (total != 0 || deduped != 6) {
+			t.Errorf("Second import created %d records and deduplicated %d, want 0 and 6", total, deduped)
+		}
+	}
+
+	profile, err := app.FindFirstRecordByFilter("profile", "")
+	if err != nil || profile.GetString("headline") != "Rear Admiral" {
+		t.Errorf("Profile was not filled from basics: %v", err)
+	}
+
+	post, err := app.FindFirstRecordByFilter("posts", "title = 'The Education of a Computer'")
+	if err != nil {
+		t.Fatalf("Publication was not imported as a post: %v", err)
+	}
+	if post.GetString("visibility") != "private" {
+		t.Errorf("post visibility = %q, want private", post.GetString("visibility"))
+	}
+}
+
+func TestJSONResumeViewExportAppliesOverrides(t *testing.T) {
+	app := newMigratedTestApp(t)
+	expID, _, viewID := seedImportFixture(t, app)
+
+	view, err := app.FindRecordById("views", viewID)
+	if err != nil {
+		t.Fatalf("Failed to find view: %v", err)
+	}
+
+	data, err := collectViewData(app, view)
+	if err != nil {
+		t.Fatalf("collectViewData() error = %v", err)
+	}
+
+	resume := services.BuildJSONResume(data, "")
+	if len(resume.Work) != 1 {
+		t.Fatalf("Work = %+v, want the one experience item (%s)", resume.Work, expID)
+	}
+	if resume.Work[0].Position != "Lead Programmer" {
+		t.Errorf("Position = %q, want view override", resume.Work[0].Position)
+	}
+	if resume.Work[0].StartDate != "1842-01-01" {
+		t.Errorf("StartDate = %q, want 1842-01-01", resume.Work[0].StartDate)
+	}
+	if len(resume.Work[0].Highlights) != 1 {
+		t.Errorf("Highlights = %v", resume.Work[0].Highlights)
+	}
+}
+
+func TestCollectProfileDataSkipsPrivateAndDrafts(t *testing.T) {
+	app := newMigratedTestApp(t)
+	seedImportFixture(t, app)
+
+	collection, _ := app.FindCollectionByNameOrId("experience")
+	for _, fields := range []map[string]interface{}{
+		{"company": "Secret", "title": "Private role", "visibility": "private"},
+		{"company": "Draft", "title": "Draft role", "visibility": "public", "is_draft": true},
+	} {
+		record := core.NewRecord(collection)
+		for key, value := range fields {
+			record.Set(key, value)
+		}
+		if err := app.Save(record); err != nil {
+			t.Fatalf("Failed to save experience: %v", err)
+		}
+	}
+
+	data, err := collectProfileData(app)
+	if err != nil {
+		t.Fatalf("collectProfileData() error = %v", err)
+	}
+
+	if len(data.Sections["experience"]) != 1 {
+		t.Errorf("experience = %v, want only the public item", data.Sections["experience"])
+	}
+	if len(data.Sections["projects"]) != 1 {
+		t.Errorf("projects = %v, want one item", data.Sections["projects"])
+	}
+}
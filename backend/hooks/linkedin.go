@@ -0,0 +1,99 @@
+package hooks
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"facet/services"
+
+	"github.com/google/uuid"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// LinkedIn exports include messages and media metadata, so they are larger than a resume
+const maxLinkedInExportSize = 100 * 1024 * 1024 // 100MB
+
+// RegisterLinkedInImportHooks registers the LinkedIn data export importer
+func RegisterLinkedInImportHooks(app *pocketbase.PocketBase) {
+	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
+		// Import the ZIP from LinkedIn's "Get a copy of your data" (multipart "file")
+		// POST /api/import/linkedin?visibility=private
+		// Positions, education, skills, certifications, projects and received recommendations
+		// are mapped without AI and deduplicated against earlier imports.
+		se.Router.POST("/api/import/linkedin", func(e *core.RequestEvent) error {
+			e.Request.Body = http.MaxBytesReader(e.Response, e.Request.Body, maxLinkedInExportSize)
+
+			file, header, err := e.Request.FormFile("file")
+			if err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]interface{}{
+					"error": NewUserError(
+						"We couldn't read your LinkedIn export.",
+						"Please select the ZIP file LinkedIn emailed you (up to 100MB) and try again.",
+						fmt.Sprintf("File upload error: %v", err),
+					),
+				})
+			}
+			defer file.Close()
+
+			parsed, err := services.ParseLinkedInExport(file, header.Size)
+			if err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]interface{}{
+					"error": NewUserError(
+						"This doesn't look like a LinkedIn data export.",
+						"Request your data from LinkedIn (Settings → Data privacy → Get a copy of your data) and upload the ZIP as-is.",
+						err.Error(),
+					),
+				})
+			}
+
+			visibility := e.Request.URL.Query().Get("visibility")
+			if visibility == "" {
+				visibility = e.Request.FormValue("visibility")
+			}
+			if visibility != "public" && visibility != "unlisted" {
+				visibility = "private"
+			}
+
+			importSessionID := uuid.New().String()
+			imported, deduped, err := createResumeRecordsWithDeduplication(app, parsed, header.Filename, importSessionID, visibility)
+			if err != nil {
+				log.Printf("[LINKEDIN] Failed to create records: %v", err)
+				return e.JSON(http.StatusInternalServerError, map[string]interface{}{
+					"error": NewUserError(
+						"We read your LinkedIn export but couldn't save the data.",
+						"This might be a temporary database issue. Please try again in a moment.",
+						fmt.Sprintf("Database error: %v", err),
+					),
+				})
+			}
+
+			profileUpdated, err := fillEmptyProfileFields(app, parsed.Profile)
+			if err != nil {
+				log.Printf("[LINKEDIN] [WARNING] Failed to update profile: %v", err)
+			}
+
+			counts := make(map[string]int)
+			for _, collection := range []string{"experience", "education", "skills", "certifications", "projects", "testimonials"} {
+				counts[collection] = len(imported[collection])
+			}
+
+			log.Printf("[LINKEDIN] Imported %v from %s (%d duplicates skipped)", counts, header.Filename, deduped)
+
+			return e.JSON(http.StatusOK, map[string]interface{}{
+				"status":            "success",
+				"imported":          imported,
+				"counts":            counts,
+				"deduplicated":      deduped,
+				"profile_updated":   profileUpdated,
+				"import_session_id": importSessionID,
+				"warnings":          parsed.Metadata.Warnings,
+				"filename":          header.Filename,
+			})
+		}).Bind(apis.RequireAuth())
+
+		return se.Next()
+	})
+}
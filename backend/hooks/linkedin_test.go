@@ -0,0 +1,70 @@
+package hooks
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"facet/services"
+)
+
+func TestLinkedInImportDeduplicatesAndTracksSession(t *testing.T) {
+	app := newMigratedTestApp(t)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"Positions.csv":      "Company Name,Title,Description,Location,Started On,Finished On\nUS Navy,Computer Scientist,,,Dec 1943,\n",
+		"Certifications.csv": "Name,Url,Authority,Started On,Finished On,License Number\nCOBOL Design,,CODASYL,1959,,\n",
+		"Recommendations_Received.csv": "First Name,Last Name,Company,Job Title,Text,Creation Date,Status\n" +
+			"Howard,Aiken,Harvard,Professor,Relentless debugger.,\"05/01/21, 09:00 AM\",VISIBLE\n",
+	} {
+		w, _ := zw.Create(name)
+		w.Write([]byte(content))
+	}
+	zw.Close()
+
+	sessions := []string{"first-session", "second-session"}
+	for run, session := range sessions {
+		parsed, err := services.ParseLinkedInExport(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Fatalf("ParseLinkedInExport() error = %v", err)
+		}
+
+		imported, deduped, err := createResumeRecordsWithDeduplication(app, parsed, "Basic_LinkedInDataExport.zip", session, "private")
+		if err != nil {
+			t.Fatalf("Import %d failed: %v", run+1, err)
+		}
+
+		total := 0
+		for _, ids := range imported {
+			total += len(ids)
+		}
+		if run == 0 && total != 3 {
+			t.Errorf("First import created %d records, want 3 (%v)", total, imported)
+		}
+		if run == 1 && (total != 0 || deduped != 3) {
+			t.Errorf("Second import created %d records and deduplicated %d, want 0 and 3", total, deduped)
+		}
+	}
+
+	cert, err := app.FindFirstRecordByFilter("certifications", "name = 'COBOL Design'")
+	if err != nil {
+		t.Fatalf("Certification was not imported: %v", err)
+	}
+	if cert.GetString("import_session_id") != "first-session" {
+		t.Errorf("certification import_session_id = %q", cert.GetString("import_session_id"))
+	}
+
+	testimonial, err := app.FindFirstRecordByFilter("testimonials", "author_name = 'Howard Aiken'")
+	if err != nil {
+		t.Fatalf("Recommendation was not imported as a testimonial: %v", err)
+	}
+	if testimonial.GetString("status") != "pending" || testimonial.GetString("verification_method") != "linkedin" {
+		t.Errorf("testimonial status = %q, verification = %q, want pending/linkedin",
+			testimonial.GetString("status"), testimonial.GetString("verification_method"))
+	}
+	if testimonial.GetString("import_filename") != "Basic_LinkedInDataExport.zip" {
+		t.Errorf("testimonial import_filename = %q", testimonial.GetString("import_filename"))
+	}
+}
@@ -1,21 +1,43 @@
 package hooks
 
 import (
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"facet/services"
 
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/apis"
 	"github.com/pocketbase/pocketbase/core"
 )
 
 // RegisterPasswordHooks registers password protection endpoints (view-level only)
-func RegisterPasswordHooks(app *pocketbase.PocketBase, crypto *services.CryptoService, rl *services.RateLimitService) {
+func RegisterPasswordHooks(app *pocketbase.PocketBase, crypto *services.CryptoService, rl *services.RateLimitService, lockout *services.LockoutService) {
+	bindViewPasswordHooks(app, crypto)
+
+	// Password access tokens are only valid while their stored session is
+	crypto.SetViewAccessRevocationCheck(func(jti, viewID string) bool {
+		return isViewSessionRevoked(app, jti, viewID)
+	})
+	bindViewSessionRevocation(app)
+
+	// Prune long-expired sessions once a day
+	app.Cron().MustAdd("pruneViewSessions", "45 3 * * *", func() {
+		deleted, err := pruneViewSessions(app, time.Now())
+		if err != nil {
+			app.Logger().Warn("Failed to prune view sessions", "error", err)
+		} else if deleted > 0 {
+			app.Logger().Info("Pruned view sessions", "deleted", deleted)
+		}
+	})
+
 	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
 		// Check password for protected view
-		// Rate limited: strict tier (5/min) to prevent brute force attacks
+		// Rate limited: strict tier (5/min) per IP, plus a per-view lockout with
+		// exponential backoff against guessing spread over many IPs
 		se.Router.POST("/api/password/check", RateLimitMiddleware(rl, "strict")(func(e *core.RequestEvent) error {
 			var req struct {
 				ViewID   string `json:"view_id"`
@@ -36,23 +58,44 @@ func RegisterPasswordHooks(app *pocketbase.PocketBase, crypto *services.CryptoSe
 				return e.JSON(http.StatusBadRequest, map[string]string{"error": "view is not password protected"})
 			}
 
-			passwordHash := record.GetString("password_hash")
-			if passwordHash == "" {
-				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "password not configured"})
+			if wait := lockout.RetryAfter(record.Id); wait > 0 {
+				e.Response.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				return e.JSON(http.StatusTooManyRequests, map[string]string{"error": "too many failed attempts, try again later"})
 			}
 
-			if !crypto.CheckPassword(req.Password, passwordHash) {
+			label, named, configured := matchViewPassword(app, crypto, record, req.Password)
+			if !configured {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "password not configured"})
+			}
+			if label == "" {
+				if delay := lockout.Fail(record.Id); delay > 0 {
+					app.Logger().Warn("View password locked after failed attempts", "view_id", record.Id, "retry_after", delay.String())
+				}
 				return e.JSON(http.StatusUnauthorized, map[string]string{"error": "incorrect password"})
 			}
+			lockout.Succeed(record.Id)
+
+			// Record which password opened the view
+			if named != nil {
+				if err := recordViewPasswordUse(app, named); err != nil {
+					app.Logger().Error("Failed to record view password use", "error", err, "password_id", named.Id)
+				}
+			}
+			app.Logger().Info("View password accepted", "view_id", record.Id, "password", label)
 
-			// Generate signed JWT for view access (1 hour expiry)
-			accessToken, expiresAt, err := crypto.GenerateViewAccessJWT(req.ViewID, 1*time.Hour)
+			// Generate signed JWT for view access (1 hour expiry), stored as a
+			// session so it can be revoked
+			accessToken, claims, err := crypto.IssueViewAccessJWT(record.Id, 1*time.Hour)
 			if err != nil {
 				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to generate token"})
 			}
+			if err := recordViewSession(app, claims, label, named); err != nil {
+				app.Logger().Error("Failed to store view session", "error", err, "view_id", record.Id)
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to generate token"})
+			}
 
 			// Calculate expires_in for client convenience
-			expiresIn := int(time.Until(expiresAt).Seconds())
+			expiresIn := int(time.Until(claims.ExpiresAt.Time).Seconds())
 
 			return e.JSON(http.StatusOK, map[string]interface{}{
 				"access_token": accessToken,
@@ -95,6 +138,34 @@ func RegisterPasswordHooks(app *pocketbase.PocketBase, crypto *services.CryptoSe
 			return e.JSON(http.StatusOK, map[string]string{"status": "password set"})
 		}).Bind(apis.RequireAuth())
 
+		// List the password sessions of a view (admin only)
+		// GET /api/password/sessions?view_id=
+		se.Router.GET("/api/password/sessions", func(e *core.RequestEvent) error {
+			viewID := e.Request.URL.Query().Get("view_id")
+			if viewID == "" {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "view_id is required"})
+			}
+
+			sessions, err := listViewSessions(app, viewID, time.Now())
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list sessions"})
+			}
+			return e.JSON(http.StatusOK, map[string]interface{}{"sessions": sessions})
+		}).Bind(apis.RequireAuth())
+
+		// Revoke one password session (admin only)
+		se.Router.POST("/api/password/sessions/{id}/revoke", func(e *core.RequestEvent) error {
+			sessionID := e.Request.PathValue("id")
+			if _, err := app.FindRecordById("view_sessions", sessionID); err != nil {
+				return e.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+			}
+
+			if _, err := revokeViewSessions(app, dbx.HashExp{"id": sessionID}); err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to revoke session"})
+			}
+			return e.JSON(http.StatusOK, map[string]string{"status": "revoked"})
+		}).Bind(apis.RequireAuth())
+
 		return se.Next()
 	})
 }
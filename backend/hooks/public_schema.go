@@ -0,0 +1,190 @@
+package hooks
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// publicSchema classifies every field of a collection for unauthenticated responses.
+//
+// Public fields may be serialized to anonymous visitors of /api/view/{slug}/data,
+// /api/homepage and the other public listing endpoints. Private fields are internal
+// bookkeeping (import tracking, sync state, per-view visibility maps, workflow state)
+// and must never leave the server through those endpoints.
+//
+// Every field a migration adds to a registered collection must appear in exactly one
+// of the two lists; TestPublicSchemasClassifyEveryField enforces this.
+type publicSchema struct {
+	Public  []string
+	Private []string
+	// Flatten names a public JSON object field whose keys are merged into the
+	// serialized item (the schema-defined values of custom section items). Only
+	// keys the section schema declares are merged.
+	Flatten string
+}
+
+// publicSchemas is the registry of public field allowlists, keyed by base collection
+// name. demo_* shadow collections share the schema of their base collection.
+var publicSchemas = map[string]publicSchema{
+	"experience": {
+		Public: []string{
+			"company", "title", "location", "start_date", "end_date",
+			"description", "bullets", "skills", "media", "visibility", "sort_order",
+		},
+		Private: []string{
+			"is_draft", "view_visibility",
+			"import_session_id", "import_filename", "resume_import_id",
+		},
+	},
+	"projects": {
+		Public: []string{
+			"title", "slug", "summary", "description", "tech_stack", "links",
+			"media", "media_refs", "cover_image", "categories", "visibility",
+			"is_featured", "sort_order",
+		},
+		Private: []string{
+			"is_draft", "view_visibility", "source_id", "field_locks", "last_sync",
+			"import_session_id", "import_filename", "resume_import_id",
+		},
+	},
+	"education": {
+		Public: []string{
+			"institution", "degree", "field", "start_date", "end_date",
+			"description", "visibility", "sort_order",
+		},
+		Private: []string{
+			"is_draft", "view_visibility",
+			"import_session_id", "import_filename", "resume_import_id",
+		},
+	},
+	"certifications": {
+		Public: []string{
+			"name", "issuer", "issue_date", "expiry_date", "credential_id",
+			"credential_url", "visibility", "sort_order",
+		},
+		Private: []string{
+			"is_draft", "view_visibility",
+			"import_session_id", "import_filename",
+		},
+	},
+	"awards": {
+		Public: []string{
+			"title", "issuer", "awarded_at", "description", "url", "visibility", "sort_order",
+		},
+		Private: []string{
+			"is_draft", "view_visibility",
+			"import_session_id", "import_filename", "resume_import_id",
+		},
+	},
+	"skills": {
+		Public: []string{
+			"name", "category", "proficiency", "visibility", "sort_order",
+		},
+		Private: []string{
+			"view_visibility",
+			"import_session_id", "import_filename", "resume_import_id",
+		},
+	},
+	"posts": {
+		Public: []string{
+			"title", "slug", "excerpt", "content", "cover_image", "tags",
+			"media", "media_refs", "visibility", "published_at",
+		},
+		Private: []string{
+			"is_draft", "view_visibility",
+		},
+	},
+	"talks": {
+		Public: []string{
+			"title", "slug", "event", "event_url", "date", "location", "description",
+			"slides_url", "video_url", "media", "media_refs", "visibility", "sort_order",
+		},
+		Private: []string{
+			"is_draft", "view_visibility",
+		},
+	},
+	"contact_methods": {
+		Public: []string{
+			"type", "value", "label", "protection_level", "is_primary", "sort_order", "icon",
+		},
+		Private: []string{
+			"view_visibility",
+		},
+	},
+	"testimonials": {
+		Public: []string{
+			"content", "relationship", "project", "author_name", "author_title",
+			"author_company", "author_photo", "author_website", "verification_method",
+			"verified_at", "featured", "sort_order",
+		},
+		Private: []string{
+			"verification_identifier", "verification_data", "status", "request_id",
+			"submitted_at", "approved_at", "rejected_at", "rejection_reason",
+			"import_session_id", "import_filename",
+		},
+	},
+	"custom_items": {
+		Public: []string{
+			"section", "title", "data", "visibility", "sort_order",
+		},
+		Private: []string{
+			"is_draft", "view_visibility",
+		},
+		Flatten: "data",
+	},
+}
+
+// publicSchemaFor returns the public schema for a collection (demo_* aware).
+func publicSchemaFor(collectionName string) (publicSchema, bool) {
+	schema, ok := publicSchemas[strings.TrimPrefix(collectionName, "demo_")]
+	return schema, ok
+}
+
+// isPublicField reports whether a field of the collection may be sent to unauthenticated callers
+func isPublicField(collectionName, field string) bool {
+	schema, ok := publicSchemaFor(collectionName)
+	if !ok {
+		return false
+	}
+	return containsString(schema.Public, field)
+}
+
+// serializePublicRecord converts a record into a map containing only allowlisted fields.
+// Records from collections without a registered schema serialize to just their id.
+// fields is the schema of a custom section item: only those keys of its data are
+// served, so values left behind by removed or renamed fields stay private.
+func serializePublicRecord(record *core.Record, fields []customSectionField) map[string]interface{} {
+	item := map[string]interface{}{"id": record.Id}
+
+	schema, ok := publicSchemaFor(record.Collection().Name)
+	if !ok {
+		return item
+	}
+
+	for _, field := range schema.Public {
+		if record.Collection().Fields.GetByName(field) == nil {
+			continue
+		}
+		item[field] = record.Get(field)
+	}
+
+	if schema.Flatten != "" {
+		var values map[string]interface{}
+		if raw := record.GetString(schema.Flatten); raw != "" && json.Unmarshal([]byte(raw), &values) == nil {
+			for _, field := range fields {
+				value, ok := values[field.Name]
+				if !ok {
+					continue
+				}
+				if _, exists := item[field.Name]; !exists {
+					item[field.Name] = value
+				}
+			}
+		}
+		delete(item, schema.Flatten)
+	}
+
+	return item
+}
@@ -0,0 +1,158 @@
+package hooks
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+
+	_ "facet/migrations"
+)
+
+// newMigratedTestApp boots a throwaway PocketBase app with every migration applied.
+func newMigratedTestApp(t testing.TB) *core.BaseApp {
+	t.Helper()
+
+	app := core.NewBaseApp(core.BaseAppConfig{DataDir: t.TempDir()})
+	if err := app.Bootstrap(); err != nil {
+		t.Fatalf("Failed to bootstrap test app: %v", err)
+	}
+	if err := app.RunAllMigrations(); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+	t.Cleanup(func() {
+		app.ResetBootstrapState()
+	})
+
+	return app
+}
+
+// TestPublicSchemasClassifyEveryField fails when a migration adds a field to a
+// publicly served collection without classifying it as public or private.
+func TestPublicSchemasClassifyEveryField(t *testing.T) {
+	app := newMigratedTestApp(t)
+
+	for name, schema := range publicSchemas {
+		for _, collectionName := range []string{name, "demo_" + name} {
+			collection, err := app.FindCollectionByNameOrId(collectionName)
+			if err != nil {
+				if collectionName == name {
+					t.Errorf("Registered collection %q does not exist after migrations", name)
+				}
+				continue
+			}
+
+			for _, field := range collection.Fields {
+				fieldName := field.GetName()
+				if fieldName == "id" {
+					continue
+				}
+				if !containsString(schema.Public, fieldName) && !containsString(schema.Private, fieldName) {
+					t.Errorf("%s.%s is not classified as public or private in publicSchemas", collectionName, fieldName)
+				}
+			}
+		}
+	}
+}
+
+func TestPublicSchemasAreDisjoint(t *testing.T) {
+	for name, schema := range publicSchemas {
+		for _, field := range schema.Public {
+			if containsString(schema.Private, field) {
+				t.Errorf("%s.%s is classified as both public and private", name, field)
+			}
+		}
+	}
+}
+
+func TestPublicSchemasNeverExposeInternalFields(t *testing.T) {
+	internal := []string{
+		"password_hash", "password", "import_session_id", "import_filename",
+		"resume_import_id", "field_locks", "source_id", "view_visibility",
+	}
+
+	for name, schema := range publicSchemas {
+		for _, field := range internal {
+			if containsString(schema.Public, field) {
+				t.Errorf("%s.%s must not be public", name, field)
+			}
+		}
+	}
+}
+
+// TestEverySectionHasPublicSchema ensures every view section is served through the registry
+func TestEverySectionHasPublicSchema(t *testing.T) {
+	sections := []string{
+		"experience", "projects", "education", "certifications", "awards",
+		"skills", "posts", "talks", "contacts", "testimonials",
+	}
+
+	for _, section := range sections {
+		collectionName := getCollectionName(section)
+		if _, ok := publicSchemaFor(collectionName); !ok {
+			t.Errorf("Section %q (collection %q) has no public schema", section, collectionName)
+		}
+	}
+}
+
+// TestOverridableFieldsArePublic ensures view overrides cannot smuggle private fields back in
+func TestOverridableFieldsArePublic(t *testing.T) {
+	for section, def := range builtinSections {
+		collectionName := getCollectionName(section)
+		for _, field := range def.Overridable {
+			if !isPublicField(collectionName, field) {
+				t.Errorf("Overridable field %s.%s is not public", collectionName, field)
+			}
+		}
+	}
+}
+
+func TestSerializePublicRecordDropsPrivateFields(t *testing.T) {
+	app := newMigratedTestApp(t)
+
+	for _, collectionName := range []string{"projects", "demo_projects"} {
+		collection, err := app.FindCollectionByNameOrId(collectionName)
+		if err != nil {
+			t.Fatalf("Failed to find %s: %v", collectionName, err)
+		}
+
+		record := core.NewRecord(collection)
+		record.Id = "proj123"
+		record.Set("title", "Facet")
+		record.Set("summary", "Profile platform")
+		record.Set("source_id", "src_secret")
+		record.Set("field_locks", `{"title":true}`)
+		record.Set("view_visibility", `{"view1":true}`)
+
+		item := serializePublicRecord(record, nil)
+
+		if item["id"] != "proj123" {
+			t.Errorf("%s: id = %v, want proj123", collectionName, item["id"])
+		}
+		if item["title"] != "Facet" {
+			t.Errorf("%s: title = %v, want Facet", collectionName, item["title"])
+		}
+		for _, field := range []string{"source_id", "field_locks", "view_visibility", "is_draft"} {
+			if _, exists := item[field]; exists {
+				t.Errorf("%s: private field %q was serialized", collectionName, field)
+			}
+		}
+	}
+}
+
+func TestSerializePublicRecordUnknownCollection(t *testing.T) {
+	app := newMigratedTestApp(t)
+
+	collection, err := app.FindCollectionByNameOrId("share_tokens")
+	if err != nil {
+		t.Fatalf("Failed to find share_tokens: %v", err)
+	}
+
+	record := core.NewRecord(collection)
+	record.Id = "tok123"
+	record.Set("token_hash", "secret")
+
+	item := serializePublicRecord(record, nil)
+	if len(item) != 1 || item["id"] != "tok123" {
+		t.Errorf("Unregistered collection should serialize to id only, got %v", item)
+	}
+}
@@ -0,0 +1,193 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"facet/services"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// Cache-Control values for public responses
+const (
+	// Shared caches (a CDN) may store the response but must revalidate it
+	cacheControlPublic = "public, no-cache"
+	// Per-visitor responses (share token or password) stay in the browser
+	cacheControlPrivate = "private, no-cache"
+	// Admin previews are never stored
+	cacheControlNoStore = "no-store"
+)
+
+// responseCacheIgnored lists collections that never feed a public response, so
+// writing to them (visit logs, jobs, secrets) keeps the cache
+var responseCacheIgnored = map[string]bool{
+	"access_events":             true,
+	"access_requests":           true,
+	"analytics_daily":           true,
+	"audit_logs":                true,
+	"ai_providers":              true,
+	"email_verification_tokens": true,
+	"import_proposals":          true,
+	"resume_imports":            true,
+	"share_verifications":       true,
+	"sources":                   true,
+	"testimonial_requests":      true,
+	"users":                     true,
+	"view_exports":              true,
+	"view_passwords":            true,
+	"view_sessions":             true,
+}
+
+// RegisterResponseCacheHooks drops cached public responses whenever content
+// changes. Any record write outside the ignored collections invalidates the
+// whole cache: writes are rare, reads are many.
+func RegisterResponseCacheHooks(app *pocketbase.PocketBase, cache *services.ResponseCache) {
+	bindResponseCacheInvalidation(app, cache)
+}
+
+func bindResponseCacheInvalidation(app core.App, cache *services.ResponseCache) {
+	invalidate := func(e *core.RecordEvent) error {
+		name := e.Record.Collection().Name
+		if !responseCacheIgnored[name] && !strings.HasPrefix(name, "_") {
+			cache.Invalidate()
+		}
+		return e.Next()
+	}
+	app.OnRecordAfterCreateSuccess().BindFunc(invalidate)
+	app.OnRecordAfterUpdateSuccess().BindFunc(invalidate)
+	app.OnRecordAfterDeleteSuccess().BindFunc(invalidate)
+
+	// Schema changes can add or remove public fields
+	invalidateCollection := func(e *core.CollectionEvent) error {
+		cache.Invalidate()
+		return e.Next()
+	}
+	app.OnCollectionAfterCreateSuccess().BindFunc(invalidateCollection)
+	app.OnCollectionAfterUpdateSuccess().BindFunc(invalidateCollection)
+	app.OnCollectionAfterDeleteSuccess().BindFunc(invalidateCollection)
+}
+
+// serveCached writes the cached response for key, building and caching it on a
+// miss. Only 200 responses are cached. Conditional requests get a 304 when the
+// ETag or Last-Modified still match. Authenticated requests (admin previews)
+// bypass the cache entirely and are marked no-store.
+func serveCached(e *core.RequestEvent, cache *services.ResponseCache, key, cacheControl string, build func() (int, string, []byte, error)) error {
+	if e.Auth != nil {
+		status, contentType, body, err := build()
+		if err != nil {
+			return err
+		}
+		e.Response.Header().Set("Cache-Control", cacheControlNoStore)
+		return writeResponse(e, status, contentType, body)
+	}
+
+	entry, gen := cache.Get(key)
+	if entry == nil {
+		status, contentType, body, err := build()
+		if err != nil {
+			return err
+		}
+		if status != http.StatusOK {
+			e.Response.Header().Set("Cache-Control", cacheControlNoStore)
+			return writeResponse(e, status, contentType, body)
+		}
+		entry = cache.Set(key, gen, contentType, body)
+	}
+
+	header := e.Response.Header()
+	header.Set("Cache-Control", cacheControl)
+	header.Set("ETag", entry.ETag)
+	header.Set("Last-Modified", entry.LastModified.Format(http.TimeFormat))
+	header.Add("Vary", "Authorization, X-Share-Token, X-Share-Session, X-Password-Token")
+
+	if notModified(e.Request, entry) {
+		e.Response.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+	return writeResponse(e, http.StatusOK, entry.ContentType, entry.Body)
+}
+
+// notModified evaluates If-None-Match, or If-Modified-Since when no ETag was
+// sent (RFC 9110 13.2.2)
+func notModified(r *http.Request, entry *services.CachedResponse) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == entry.ETag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := r.Header.Get("If-Modified-Since"); since != "" {
+		if t, err := http.ParseTime(since); err == nil && !entry.LastModified.After(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonResponse encodes a JSON body for serveCached
+func jsonResponse(status int, data interface{}) (int, string, []byte, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	return status, "application/json", body, nil
+}
+
+func writeResponse(e *core.RequestEvent, status int, contentType string, body []byte) error {
+	e.Response.Header().Set("Content-Type", contentType)
+	e.Response.WriteHeader(status)
+	_, err := e.Response.Write(body)
+	return err
+}
+
+// ResponseCacheMiddleware caches what a side-effect-free public handler writes,
+// keyed by endpoint and the site URL its links are built from
+func ResponseCacheMiddleware(cache *services.ResponseCache, endpoint string) func(func(*core.RequestEvent) error) func(*core.RequestEvent) error {
+	return func(handler func(*core.RequestEvent) error) func(*core.RequestEvent) error {
+		return func(e *core.RequestEvent) error {
+			key := endpoint + ":" + resolveBaseURL(e)
+			return serveCached(e, cache, key, cacheControlPublic, func() (int, string, []byte, error) {
+				return captureResponse(e, handler)
+			})
+		}
+	}
+}
+
+// bufferedResponse records a handler's response instead of sending it
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header         { return b.header }
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
+func (b *bufferedResponse) WriteHeader(status int)      { b.status = status }
+
+// captureResponse runs handler against a buffer and returns what it wrote.
+// Headers other than Content-Type are copied to the real response.
+func captureResponse(e *core.RequestEvent, handler func(*core.RequestEvent) error) (int, string, []byte, error) {
+	original := e.Response
+	buffer := &bufferedResponse{header: http.Header{}, status: http.StatusOK}
+	e.Response = buffer
+	err := handler(e)
+	e.Response = original
+	if err != nil {
+		return 0, "", nil, err
+	}
+
+	for name, values := range buffer.header {
+		if name != "Content-Type" {
+			original.Header()[name] = values
+		}
+	}
+	return buffer.status, buffer.header.Get("Content-Type"), buffer.body.Bytes(), nil
+}
@@ -0,0 +1,148 @@
+package hooks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"facet/services"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestServeCachedConditionalRequests(t *testing.T) {
+	app := newMigratedTestApp(t)
+	cache := services.NewResponseCache(10, time.Hour)
+	bindResponseCacheInvalidation(app, cache)
+
+	builds := 0
+	status := http.StatusOK
+	build := func() (int, string, []byte, error) {
+		builds++
+		return jsonResponse(status, map[string]string{"hello": "world"})
+	}
+	serve := func(configure func(r *http.Request, e *core.RequestEvent)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/posts", nil)
+		rec := httptest.NewRecorder()
+		e := &core.RequestEvent{App: app}
+		e.Request = req
+		e.Response = rec
+		if configure != nil {
+			configure(req, e)
+		}
+		if err := serveCached(e, cache, "posts", cacheControlPublic, build); err != nil {
+			t.Fatalf("serveCached() error = %v", err)
+		}
+		return rec
+	}
+
+	first := serve(nil)
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" || first.Header().Get("Last-Modified") == "" {
+		t.Fatalf("first response = %d, headers %v", first.Code, first.Header())
+	}
+	if first.Header().Get("Cache-Control") != cacheControlPublic || first.Body.String() != `{"hello":"world"}` {
+		t.Errorf("first response cache-control %q, body %q", first.Header().Get("Cache-Control"), first.Body.String())
+	}
+
+	second := serve(nil)
+	if builds != 1 || second.Header().Get("ETag") != etag || second.Body.String() != first.Body.String() {
+		t.Errorf("cache miss on second request: builds = %d", builds)
+	}
+
+	revalidated := serve(func(r *http.Request, e *core.RequestEvent) {
+		r.Header.Set("If-None-Match", `"other", `+etag)
+	})
+	if revalidated.Code != http.StatusNotModified || revalidated.Body.Len() != 0 {
+		t.Errorf("If-None-Match = %d with %d bytes, want 304", revalidated.Code, revalidated.Body.Len())
+	}
+
+	stale := serve(func(r *http.Request, e *core.RequestEvent) {
+		r.Header.Set("If-None-Match", `"other"`)
+		r.Header.Set("If-Modified-Since", first.Header().Get("Last-Modified"))
+	})
+	if stale.Code != http.StatusOK {
+		t.Errorf("mismatched ETag = %d, want 200 (If-Modified-Since is ignored when an ETag is sent)", stale.Code)
+	}
+
+	modifiedSince := serve(func(r *http.Request, e *core.RequestEvent) {
+		r.Header.Set("If-Modified-Since", first.Header().Get("Last-Modified"))
+	})
+	if modifiedSince.Code != http.StatusNotModified {
+		t.Errorf("If-Modified-Since = %d, want 304", modifiedSince.Code)
+	}
+
+	// Admin previews are built fresh and never stored
+	users, _ := app.FindCollectionByNameOrId("users")
+	admin := serve(func(r *http.Request, e *core.RequestEvent) {
+		e.Auth = core.NewRecord(users)
+		r.Header.Set("If-None-Match", etag)
+	})
+	if admin.Code != http.StatusOK || admin.Header().Get("Cache-Control") != cacheControlNoStore || admin.Header().Get("ETag") != "" || builds != 2 {
+		t.Errorf("admin response = %d, headers %v, builds %d", admin.Code, admin.Header(), builds)
+	}
+
+	// Content changes drop the cache; visit logs do not
+	visits, _ := app.FindCollectionByNameOrId("analytics_daily")
+	counter := core.NewRecord(visits)
+	counter.Set("day", "2024-03-01")
+	counter.Set("kind", "view")
+	counter.Set("key", "abc")
+	if err := app.Save(counter); err != nil {
+		t.Fatalf("Failed to save counter: %v", err)
+	}
+	if cache.Len() != 1 {
+		t.Error("an ignored collection invalidated the cache")
+	}
+	seedImportFixture(t, app)
+	if cache.Len() != 0 {
+		t.Error("content changes did not invalidate the cache")
+	}
+	serve(nil)
+	if builds != 3 {
+		t.Errorf("builds = %d after invalidation, want 3", builds)
+	}
+
+	// Errors are not cached
+	cache.Invalidate()
+	status = http.StatusForbidden
+	if rec := serve(nil); rec.Code != http.StatusForbidden || rec.Header().Get("Cache-Control") != cacheControlNoStore {
+		t.Errorf("error response = %d, cache-control %q", rec.Code, rec.Header().Get("Cache-Control"))
+	}
+	if cache.Len() != 0 {
+		t.Error("error response was cached")
+	}
+}
+
+func TestServeCachedExpiresEntries(t *testing.T) {
+	app := newMigratedTestApp(t)
+	cache := services.NewResponseCache(10, 50*time.Millisecond)
+
+	builds := 0
+	build := func() (int, string, []byte, error) {
+		builds++
+		return jsonResponse(http.StatusOK, map[string]int{"build": builds})
+	}
+	serve := func() string {
+		rec := httptest.NewRecorder()
+		e := &core.RequestEvent{App: app}
+		e.Request = httptest.NewRequest(http.MethodGet, "/api/view/cv/data", nil)
+		e.Response = rec
+		if err := serveCached(e, cache, "view:cv", cacheControlPublic, build); err != nil {
+			t.Fatalf("serveCached() error = %v", err)
+		}
+		return rec.Body.String()
+	}
+
+	serve()
+	if body := serve(); builds != 1 || body != `{"build":1}` {
+		t.Fatalf("second request = %s after %d builds, want the cached response", body, builds)
+	}
+
+	// Rules relative to today change the response without any write
+	time.Sleep(60 * time.Millisecond)
+	if body := serve(); builds != 2 || body != `{"build":2}` {
+		t.Errorf("expired request = %s after %d builds, want a rebuild", body, builds)
+	}
+}
@@ -3,8 +3,11 @@ package hooks
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,7 +16,6 @@ import (
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/apis"
 	"github.com/pocketbase/pocketbase/core"
-	"github.com/pocketbase/pocketbase/tools/filesystem"
 )
 
 // Simple rate limiter for AI resume generation
@@ -59,16 +61,73 @@ func (rl *rateLimiter) allow(key string) bool {
 }
 
 // RegisterResumeHooks registers AI Print (resume generation) endpoints
-func RegisterResumeHooks(app *pocketbase.PocketBase, crypto *services.CryptoService) {
+func RegisterResumeHooks(app *pocketbase.PocketBase, crypto *services.CryptoService, share *services.ShareService, counters *services.CounterService) {
 	ai := services.NewAIService(crypto)
 	resume := services.NewResumeService(ai)
-	limiter := newRateLimiter(5, time.Hour) // 5 generations per hour per IP
+	resume.SetPDFEngine(os.Getenv("RESUME_PDF_ENGINE"))
+	resume.SetDOCXReference(func() []byte { return loadResumeReferenceDOCX(app) })
+	limiter := newRateLimiter(5, time.Hour)        // 5 generations per hour per IP
+	renderLimiter := newRateLimiter(30, time.Hour) // 30 template renders per hour per IP
+
+	jobs := newResumeJobQueue(app, resumeWorkerCount(), func(ctx context.Context, job *core.Record, progress func(int, string)) ([]byte, error) {
+		view, err := app.FindRecordById("views", job.GetString("view"))
+		if err != nil {
+			return nil, fmt.Errorf("view no longer exists")
+		}
+
+		var config services.GenerationConfig
+		if err := job.UnmarshalJSONField("generation_config", &config); err != nil {
+			return nil, fmt.Errorf("invalid generation config")
+		}
+
+		// Jobs queued through a share link render only what its scope unlocks
+		var shareRecord *core.Record
+		if tokenID := job.GetString("share_token"); tokenID != "" {
+			shareRecord, err = app.FindRecordById("share_tokens", tokenID)
+			if err != nil {
+				return nil, fmt.Errorf("share link no longer exists")
+			}
+		}
+
+		progress(10, "Collecting view data")
+		viewData, err := collectScopedViewData(app, view, shareRecord)
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect view data")
+		}
+
+		// Template mode: deterministic, no AI provider involved
+		if config.Template != "" {
+			tmpl, err := resolveResumeTemplate(app, config.Template)
+			if err != nil {
+				return nil, err
+			}
+			progress(50, "Rendering template")
+			return resume.GenerateFromTemplate(tmpl, viewData, job.GetString("format"))
+		}
+
+		provider, err := getActiveProvider(app, crypto, job.GetString("ai_provider"))
+		if err != nil {
+			return nil, err
+		}
+
+		return resume.GenerateResumeWithProgress(ctx, provider, viewData, &config, job.GetString("format"), progress)
+	})
+
+	// Workers stop with the app; jobs they were running are re-queued
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	app.OnTerminate().BindFunc(func(e *core.TerminateEvent) error {
+		stopJobs()
+		return e.Next()
+	})
 
 	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
+		jobs.start(jobsCtx)
 
-		// Check if AI Print is available (Pandoc installed + AI provider configured)
+		// Check if AI Print is available
 		// GET /api/ai-print/status
 		// Public endpoint - just returns capability info, no sensitive data
+		// PDF and DOCX are rendered natively and template mode needs no AI, so generation is always
+		// available; Pandoc only adds conversions to and from HTML templates
 		se.Router.GET("/api/ai-print/status", func(e *core.RequestEvent) error {
 			pandocAvailable := resume.CheckPandocAvailable()
 
@@ -76,17 +135,37 @@ func RegisterResumeHooks(app *pocketbase.PocketBase, crypto *services.CryptoServ
 			providers, err := app.FindRecordsByFilter("ai_providers", "is_active = true", "", 1, 0, nil)
 			aiAvailable := err == nil && len(providers) > 0
 
+			formats := []string{"pdf", "docx", "md", "html"}
+			settings, err := services.LoadSiteSettings(app)
+			referenceDOCX := err == nil && settings.ReferenceDOCX != ""
+
+			var templates []map[string]string
+			for _, tmpl := range services.BuiltinResumeTemplates() {
+				templates = append(templates, map[string]string{"slug": tmpl.Slug, "name": tmpl.Name})
+			}
+			if custom, err := app.FindRecordsByFilter("resume_templates", "", "name", 0, 0, nil); err == nil {
+				for _, record := range custom {
+					templates = append(templates, map[string]string{"slug": record.GetString("slug"), "name": record.GetString("name")})
+				}
+			}
+
 			return e.JSON(http.StatusOK, map[string]interface{}{
-				"available":         pandocAvailable && aiAvailable,
-				"pandoc_installed":  pandocAvailable,
-				"ai_configured":     aiAvailable,
-				"supported_formats": []string{"pdf", "docx"},
+				"available":          true,
+				"ai_available":       aiAvailable,
+				"template_available": true,
+				"pandoc_installed":   pandocAvailable,
+				"pdf_engine":         resume.PDFEngine(),
+				"docx_reference":     referenceDOCX,
+				"ai_configured":      aiAvailable,
+				"supported_formats":  formats,
+				"templates":          templates,
 			})
 		}) // No auth required - public capability check
 
-		// Generate a resume from a view
+		// Queue resume generation for a view
 		// POST /api/view/{slug}/generate
 		// Public endpoint - allows recruiters to generate resumes from public views
+		// Returns 202 with the export ID; follow it via the status or events endpoint
 		se.Router.POST("/api/view/{slug}/generate", func(e *core.RequestEvent) error {
 			slug := e.Request.PathValue("slug")
 
@@ -102,19 +181,16 @@ func RegisterResumeHooks(app *pocketbase.PocketBase, crypto *services.CryptoServ
 			}
 
 			// Find the view
-			views, err := app.FindRecordsByFilter("views", "slug = {:slug}", "", 1, 0, map[string]interface{}{"slug": slug})
-			if err != nil || len(views) == 0 {
+			view, err := app.FindFirstRecordByFilter("views", "slug = {:slug} && is_active = true", map[string]interface{}{"slug": slug})
+			if err != nil {
 				log.Printf("[AI-PRINT] View not found: %s", slug)
 				return e.JSON(http.StatusNotFound, map[string]string{"error": "view not found"})
 			}
-			view := views[0]
 
-			// Check view visibility - only allow public/unlisted views for unauthenticated users
-			visibility := view.GetString("visibility")
-			isAuthenticated := e.Auth != nil
-			if !isAuthenticated && visibility != "public" && visibility != "unlisted" {
-				log.Printf("[AI-PRINT] Unauthorized access to non-public view: %s", slug)
-				return e.JSON(http.StatusForbidden, map[string]string{"error": "this view requires authentication"})
+			// Same access as /api/view/{slug}/data: share token, session or password
+			shareRecord, denied := authorizeViewAccess(app, crypto, share, counters, e, view, false)
+			if denied != nil {
+				return e.JSON(denied.Status, denied.Body)
 			}
 
 			// Parse request body
@@ -125,6 +201,7 @@ func RegisterResumeHooks(app *pocketbase.PocketBase, crypto *services.CryptoServ
 				Style      string   `json:"style"`
 				Length     string   `json:"length"`
 				Emphasis   []string `json:"emphasis"`
+				Template   string   `json:"template"`
 			}
 			if err := e.BindBody(&req); err != nil {
 				log.Printf("[AI-PRINT] Invalid request body: %v", err)
@@ -142,23 +219,39 @@ func RegisterResumeHooks(app *pocketbase.PocketBase, crypto *services.CryptoServ
 				req.Length = "two-page"
 			}
 
-
-			// Check Pandoc availability
-			if !resume.CheckPandocAvailable() {
-				log.Printf("[AI-PRINT] Pandoc not available")
-				return e.JSON(http.StatusServiceUnavailable, map[string]string{
-					"error": "PDF generation is not available. Pandoc is not installed.",
-				})
+			switch req.Format {
+			case "pdf", "docx", "md", "html":
+			default:
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "format must be pdf, docx, md or html"})
 			}
 
-			// Get AI provider
-			provider, err := getActiveProvider(app, crypto, req.ProviderID)
-			if err != nil {
-				log.Printf("[AI-PRINT] No AI provider available: %v", err)
-				return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			var providerID string
+			if req.Template != "" {
+				// Template mode: no AI provider; Pandoc only when converting formats
+				tmpl, err := resolveResumeTemplate(app, req.Template)
+				if err != nil {
+					return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+				}
+				if services.TemplateNeedsPandoc(tmpl, req.Format) && !resume.CheckPandocAvailable() {
+					return e.JSON(http.StatusServiceUnavailable, map[string]string{
+						"error": fmt.Sprintf("%s output from this template requires Pandoc, which is not installed.", strings.ToUpper(req.Format)),
+					})
+				}
+			} else {
+				if req.Format == "md" || req.Format == "html" {
+					return e.JSON(http.StatusBadRequest, map[string]string{"error": "md and html output require a template"})
+				}
+
+				// Resolve the AI provider now so configuration problems are reported immediately
+				provider, err := getActiveProvider(app, crypto, req.ProviderID)
+				if err != nil {
+					log.Printf("[AI-PRINT] No AI provider available: %v", err)
+					return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+				}
+				providerID = provider.ID
 			}
 
-			// Create export record with pending status
+			// Queue the job; a worker picks it up and the client follows progress
 			exportsCollection, err := app.FindCollectionByNameOrId("view_exports")
 			if err != nil {
 				log.Printf("[AI-PRINT] view_exports collection not found: %v", err)
@@ -168,82 +261,103 @@ func RegisterResumeHooks(app *pocketbase.PocketBase, crypto *services.CryptoServ
 			exportRecord := core.NewRecord(exportsCollection)
 			exportRecord.Set("view", view.Id)
 			exportRecord.Set("format", req.Format)
-			exportRecord.Set("status", "processing")
-			exportRecord.Set("ai_provider", provider.ID)
+			exportRecord.Set("status", "pending")
+			exportRecord.Set("ai_provider", providerID)
+			if shareRecord != nil {
+				exportRecord.Set("share_token", shareRecord.Id)
+			}
 			exportRecord.Set("generation_config", map[string]interface{}{
 				"target_role": req.TargetRole,
 				"style":       req.Style,
 				"length":      req.Length,
 				"emphasis":    req.Emphasis,
+				"template":    req.Template,
 			})
+			exportRecord.Set("queued_at", time.Now())
+			exportRecord.Set("progress", 0)
+			exportRecord.Set("progress_message", "Queued")
 
 			if err := app.Save(exportRecord); err != nil {
 				log.Printf("[AI-PRINT] Failed to create export record: %v", err)
 				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to create export"})
 			}
+			jobs.notify()
+
+			statusURL := "/api/view/" + slug + "/exports/" + exportRecord.Id
+			return e.JSON(http.StatusAccepted, map[string]interface{}{
+				"export_id":  exportRecord.Id,
+				"status":     "pending",
+				"format":     req.Format,
+				"status_url": statusURL,
+				"events_url": statusURL + "/events",
+			})
+		}) // Public - authorizeViewAccess above handles authorization
+
+		// Render a view through a template right away
+		// GET /api/view/{slug}/resume?template=chronological&format=md|html
+		// Same access as generation, scoped to the share link that opened the view.
+		// Output is deterministic: the same view always gives the same bytes.
+		se.Router.GET("/api/view/{slug}/resume", func(e *core.RequestEvent) error {
+			slug := e.Request.PathValue("slug")
+
+			if e.Auth == nil && !renderLimiter.allow(e.Request.RemoteAddr) {
+				return e.JSON(http.StatusTooManyRequests, map[string]string{
+					"error": "Rate limit exceeded. Please try again later (max 30 renders per hour).",
+				})
+			}
 
-			// Fetch view data
-			viewData, err := collectViewData(app, view)
+			view, err := app.FindFirstRecordByFilter("views", "slug = {:slug} && is_active = true", map[string]interface{}{"slug": slug})
 			if err != nil {
-				log.Printf("[AI-PRINT] Failed to collect view data: %v", err)
-				exportRecord.Set("status", "failed")
-				exportRecord.Set("error_message", "Failed to collect view data")
-				app.Save(exportRecord)
-				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to collect view data"})
+				return e.JSON(http.StatusNotFound, map[string]string{"error": "view not found"})
 			}
-
-			// Generate resume
-			config := &services.GenerationConfig{
-				TargetRole: req.TargetRole,
-				Style:      req.Style,
-				Length:     req.Length,
-				Emphasis:   req.Emphasis,
+			shareRecord, denied := authorizeViewAccess(app, crypto, share, counters, e, view, false)
+			if denied != nil {
+				return e.JSON(denied.Status, denied.Body)
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
-			defer cancel()
+			query := e.Request.URL.Query()
+			templateSlug := query.Get("template")
+			if templateSlug == "" {
+				templateSlug = "chronological"
+			}
+			format := query.Get("format")
+			if format == "" {
+				format = "md"
+			}
+			if format != "md" && format != "html" {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "format must be md or html (use /generate for pdf and docx)"})
+			}
 
-			fileBytes, err := resume.GenerateResume(ctx, provider, viewData, config, req.Format)
+			tmpl, err := resolveResumeTemplate(app, templateSlug)
 			if err != nil {
-				log.Printf("[AI-PRINT] Resume generation failed: %v", err)
-				exportRecord.Set("status", "failed")
-				exportRecord.Set("error_message", err.Error())
-				app.Save(exportRecord)
-				return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return e.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+			}
+			if services.TemplateNeedsPandoc(tmpl, format) && !resume.CheckPandocAvailable() {
+				return e.JSON(http.StatusServiceUnavailable, map[string]string{
+					"error": fmt.Sprintf("%s output from this template requires Pandoc, which is not installed.", strings.ToUpper(format)),
+				})
 			}
 
-			// Save file to record
-			filename := "resume." + req.Format
-			f, err := filesystem.NewFileFromBytes(fileBytes, filename)
+			viewData, err := collectScopedViewData(app, view, shareRecord)
 			if err != nil {
-				log.Printf("[AI-PRINT] Failed to create file object: %v", err)
-				exportRecord.Set("status", "failed")
-				exportRecord.Set("error_message", "Failed to save file")
-				app.Save(exportRecord)
-				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save file"})
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to collect view data"})
 			}
 
-			exportRecord.Set("file", f)
-			exportRecord.Set("status", "completed")
-			exportRecord.Set("generated_at", time.Now())
-
-			if err := app.Save(exportRecord); err != nil {
-				log.Printf("[AI-PRINT] Failed to save export record with file: %v", err)
-				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save export"})
+			output, err := resume.GenerateFromTemplate(tmpl, viewData, format)
+			if err != nil {
+				return e.JSON(http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
 			}
 
-
-			// Build download URL
-			downloadURL := "/api/files/" + exportsCollection.Id + "/" + exportRecord.Id + "/" + exportRecord.GetString("file")
-
-			return e.JSON(http.StatusOK, map[string]interface{}{
-				"export_id":    exportRecord.Id,
-				"status":       "completed",
-				"format":       req.Format,
-				"download_url": downloadURL,
-				"generated_at": exportRecord.Get("generated_at"),
-			})
-		}) // Public - visibility check above handles authorization
+			contentType := "text/markdown; charset=utf-8"
+			if format == "html" {
+				contentType = "text/html; charset=utf-8"
+				// Rendered item text is not escaped by text/template; never let it run scripts
+				e.Response.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; img-src https: data:")
+			}
+			e.Response.Header().Set("X-Content-Type-Options", "nosniff")
+			e.Response.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"resume-%s.%s\"", slug, format))
+			return e.Blob(http.StatusOK, contentType, output)
+		})
 
 		// List exports for a view
 		// GET /api/view/{slug}/exports
@@ -261,7 +375,7 @@ func RegisterResumeHooks(app *pocketbase.PocketBase, crypto *services.CryptoServ
 			exports, err := app.FindRecordsByFilter(
 				"view_exports",
 				"view = {:viewId}",
-				"-generated_at",
+				"-queued_at,-generated_at",
 				50,
 				0,
 				map[string]interface{}{"viewId": view.Id},
@@ -279,6 +393,8 @@ func RegisterResumeHooks(app *pocketbase.PocketBase, crypto *services.CryptoServ
 					"format":       exp.GetString("format"),
 					"status":       exp.GetString("status"),
 					"generated_at": exp.Get("generated_at"),
+					"queued_at":    exp.Get("queued_at"),
+					"progress":     exp.GetInt("progress"),
 				}
 
 				if exp.GetString("status") == "completed" && exp.GetString("file") != "" {
@@ -304,6 +420,79 @@ func RegisterResumeHooks(app *pocketbase.PocketBase, crypto *services.CryptoServ
 			})
 		}).Bind(apis.RequireAuth())
 
+		// Job status for a queued export
+		// GET /api/view/{slug}/exports/{exportId}
+		// Same access as generation; jobs queued through a share link need that link
+		se.Router.GET("/api/view/{slug}/exports/{exportId}", func(e *core.RequestEvent) error {
+			job, err := findViewExportJob(app, crypto, share, counters, e)
+			if err != nil {
+				return e.JSON(http.StatusNotFound, map[string]string{"error": "export not found"})
+			}
+
+			return e.JSON(http.StatusOK, resumeJobEventFromRecord(job))
+		})
+
+		// Server-sent events with job progress until the job completes or fails
+		// GET /api/view/{slug}/exports/{exportId}/events
+		se.Router.GET("/api/view/{slug}/exports/{exportId}/events", func(e *core.RequestEvent) error {
+			job, err := findViewExportJob(app, crypto, share, counters, e)
+			if err != nil {
+				return e.JSON(http.StatusNotFound, map[string]string{"error": "export not found"})
+			}
+
+			// Subscribe before sending the current state so no update is missed in between
+			events, unsubscribe := jobs.subscribe(job.Id)
+			defer unsubscribe()
+
+			e.Response.Header().Set("Content-Type", "text/event-stream")
+			e.Response.Header().Set("Cache-Control", "no-cache")
+			e.Response.Header().Set("Connection", "keep-alive")
+			e.Response.Header().Set("X-Accel-Buffering", "no") // disable nginx buffering
+			e.Response.WriteHeader(http.StatusOK)
+
+			send := func(ev resumeJobEvent) error {
+				data, err := json.Marshal(ev)
+				if err != nil {
+					return err
+				}
+				if _, err := fmt.Fprintf(e.Response, "event: progress\ndata: %s\n\n", data); err != nil {
+					return err
+				}
+				return e.Flush()
+			}
+
+			current := resumeJobEventFromRecord(job)
+			if err := send(current); err != nil || current.done() {
+				return nil
+			}
+
+			heartbeat := time.NewTicker(15 * time.Second)
+			defer heartbeat.Stop()
+
+			for {
+				select {
+				case <-e.Request.Context().Done():
+					return nil
+				case ev := <-events:
+					if err := send(ev); err != nil || ev.done() {
+						return nil
+					}
+				case <-heartbeat.C:
+					// Catch up on updates dropped while the client was slow
+					if fresh, err := app.FindRecordById("view_exports", job.Id); err == nil {
+						if ev := resumeJobEventFromRecord(fresh); ev.done() {
+							send(ev)
+							return nil
+						}
+					}
+					if _, err := fmt.Fprint(e.Response, ": keep-alive\n\n"); err != nil {
+						return nil
+					}
+					e.Flush()
+				}
+			}
+		})
+
 		// Delete an export
 		// DELETE /api/view/{slug}/exports/{exportId}
 		se.Router.DELETE("/api/view/{slug}/exports/{exportId}", func(e *core.RequestEvent) error {
@@ -325,12 +514,55 @@ func RegisterResumeHooks(app *pocketbase.PocketBase, crypto *services.CryptoServ
 	})
 }
 
+// findViewExportJob loads the export named in the path, checking it belongs to the
+// view and that the caller may open that view (same rules as generation). A job
+// queued through a share link is only shown to callers holding that link.
+func findViewExportJob(app core.App, crypto *services.CryptoService, share *services.ShareService, counters *services.CounterService, e *core.RequestEvent) (*core.Record, error) {
+	view, err := app.FindFirstRecordByFilter("views", "slug = {:slug} && is_active = true", map[string]interface{}{"slug": e.Request.PathValue("slug")})
+	if err != nil {
+		return nil, err
+	}
+
+	shareRecord, denied := authorizeViewAccess(app, crypto, share, counters, e, view, false)
+	if denied != nil {
+		return nil, fmt.Errorf("view access denied")
+	}
+
+	job, err := app.FindRecordById("view_exports", e.Request.PathValue("exportId"))
+	if err != nil {
+		return nil, err
+	}
+	if job.GetString("view") != view.Id {
+		return nil, fmt.Errorf("export belongs to another view")
+	}
+	if tokenID := job.GetString("share_token"); tokenID != "" && e.Auth == nil && (shareRecord == nil || shareRecord.Id != tokenID) {
+		return nil, fmt.Errorf("export belongs to another share link")
+	}
+	return job, nil
+}
+
+// collectScopedViewData gathers resume data for a view, narrowed to the scope of
+// the share token it was opened with, if any
+func collectScopedViewData(app core.App, view, shareRecord *core.Record) (*services.ViewData, error) {
+	viewData, err := collectViewData(app, view)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := shareTokenScope(shareRecord)
+	if err != nil {
+		return nil, err
+	}
+	applyShareScopeToViewData(viewData, scope)
+	return viewData, nil
+}
+
 // collectViewData gathers all view data for resume generation
-func collectViewData(app *pocketbase.PocketBase, view *core.Record) (*services.ViewData, error) {
+func collectViewData(app core.App, view *core.Record) (*services.ViewData, error) {
 	viewData := &services.ViewData{
-		Profile:      make(map[string]interface{}),
-		Sections:     make(map[string][]map[string]interface{}),
-		SectionOrder: []string{},
+		Profile:       make(map[string]interface{}),
+		Sections:      make(map[string][]map[string]interface{}),
+		SectionOrder:  []string{},
+		SectionTitles: make(map[string]string),
 	}
 
 	// Get hero overrides from view
@@ -370,35 +602,63 @@ func collectViewData(app *pocketbase.PocketBase, view *core.Record) (*services.V
 			continue
 		}
 
-		viewData.SectionOrder = append(viewData.SectionOrder, sectionName)
-		collectionName := getCollectionName(sectionName)
-		if collectionName == "" {
+		def, known := resolveSection(app, sectionName, false)
+		if !known {
 			continue
 		}
+		viewData.SectionOrder = append(viewData.SectionOrder, sectionName)
+		if def.isCustom() {
+			viewData.SectionTitles[sectionName] = def.Label
+		}
+		collectionName := def.Collection
 
 		// Check if specific items are selected
 		items, hasItems := section["items"].([]interface{})
 		var records []*core.Record
 
-		if hasItems && len(items) > 0 {
+		rules, err := parseSectionRules(section)
+		if err != nil {
+			continue
+		}
+
+		if rules != nil {
+			// Smart section: records matching the rules
+			collection, err := app.FindCachedCollectionByNameOrId(collectionName)
+			if err != nil {
+				continue
+			}
+			records, err = findRuleSectionRecords(app, collection, def, view.Id, rules)
+			if err != nil {
+				continue
+			}
+		} else if hasItems && len(items) > 0 {
 			// Fetch specific items in order
+			ids := make([]string, 0, len(items))
 			for _, itemID := range items {
 				if id, ok := itemID.(string); ok {
-					record, err := app.FindRecordById(collectionName, id)
-					if err == nil && isRecordVisibleForSection(record, sectionName, view.Id) {
-						records = append(records, record)
-					}
+					ids = append(ids, id)
+				}
+			}
+			itemRecords, _ := findRecordsByIdsOrdered(app, collectionName, ids)
+			for _, record := range itemRecords {
+				if isRecordVisibleForSection(record, sectionName, view.Id) {
+					records = append(records, record)
 				}
 			}
 		} else {
 			// Fetch all non-draft items, then filter by view visibility
+			collection, err := app.FindCachedCollectionByNameOrId(collectionName)
+			if err != nil {
+				continue
+			}
+			filter, sortField, params := sectionListQuery(collection, sectionName)
 			allRecords, fetchErr := app.FindRecordsByFilter(
 				collectionName,
-				"is_draft = false",
-				"sort_order",
+				filter,
+				sortField,
 				100,
 				0,
-				nil,
+				params,
 			)
 			if fetchErr != nil {
 				continue
@@ -421,27 +681,14 @@ func collectViewData(app *pocketbase.PocketBase, view *core.Record) (*services.V
 			}
 		}
 
-		// Convert records to maps with overrides applied
+		// Public fields only (generated resumes can be requested anonymously), with the
+		// view's overrides applied exactly as /api/view/{slug}/data does, converted to
+		// plain values so dates and JSON fields read as strings and slices
 		var sectionItems []map[string]interface{}
-		for _, record := range records {
-			item := make(map[string]interface{})
-
-			// Copy all fields
-			for key, value := range record.FieldsData() {
-				item[key] = value
+		for _, item := range serializeRecordsWithOverrides(records, itemConfig, def) {
+			if plain, ok := plainValue(item).(map[string]interface{}); ok {
+				sectionItems = append(sectionItems, plain)
 			}
-			item["id"] = record.Id
-
-			// Apply overrides if present
-			if overrides, hasOverrides := itemConfig[record.Id]; hasOverrides {
-				for key, value := range overrides {
-					if key != "id" && value != nil && value != "" {
-						item[key] = value
-					}
-				}
-			}
-
-			sectionItems = append(sectionItems, item)
 		}
 
 		viewData.Sections[sectionName] = sectionItems
@@ -0,0 +1,308 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+)
+
+const (
+	resumeJobTimeout      = 120 * time.Second
+	resumeJobMaxAttempts  = 3
+	resumeJobPollInterval = 30 * time.Second
+	defaultResumeWorkers  = 2
+)
+
+// resumeJobFunc produces the file for a view_exports job, reporting progress as it goes
+type resumeJobFunc func(ctx context.Context, job *core.Record, progress func(percent int, message string)) ([]byte, error)
+
+// resumeJobEvent is the job state sent by the status endpoint and the SSE stream
+type resumeJobEvent struct {
+	ExportID    string `json:"export_id"`
+	Status      string `json:"status"`
+	Format      string `json:"format"`
+	Progress    int    `json:"progress"`
+	Message     string `json:"message,omitempty"`
+	Error       string `json:"error,omitempty"`
+	DownloadURL string `json:"download_url,omitempty"`
+	QueuedAt    string `json:"queued_at,omitempty"`
+	GeneratedAt string `json:"generated_at,omitempty"`
+}
+
+// done reports whether the job has reached a final state
+func (ev resumeJobEvent) done() bool {
+	return ev.Status == "completed" || ev.Status == "failed"
+}
+
+// resumeJobQueue runs resume generation in the background.
+// view_exports rows are the queue: the generate endpoint inserts a "pending" row and
+// workers claim the oldest one, moving it through processing to completed or failed.
+// Because the state lives in the database, queued jobs survive restarts.
+type resumeJobQueue struct {
+	app      core.App
+	generate resumeJobFunc
+	workers  int
+	wake     chan struct{}
+
+	mu          sync.Mutex
+	subscribers map[string]map[chan resumeJobEvent]struct{}
+}
+
+func newResumeJobQueue(app core.App, workers int, generate resumeJobFunc) *resumeJobQueue {
+	if workers < 1 {
+		workers = 1
+	}
+	return &resumeJobQueue{
+		app:         app,
+		generate:    generate,
+		workers:     workers,
+		wake:        make(chan struct{}, 1),
+		subscribers: make(map[string]map[chan resumeJobEvent]struct{}),
+	}
+}
+
+// resumeWorkerCount reads RESUME_WORKERS (concurrent generations), defaulting to 2
+func resumeWorkerCount() int {
+	if n, err := strconv.Atoi(os.Getenv("RESUME_WORKERS")); err == nil && n > 0 {
+		return n
+	}
+	return defaultResumeWorkers
+}
+
+// start recovers interrupted jobs and launches the workers. They stop when ctx is cancelled.
+func (q *resumeJobQueue) start(ctx context.Context) {
+	if recovered, err := q.recoverInterrupted(); err != nil {
+		log.Printf("[AI-PRINT] Failed to recover interrupted jobs: %v", err)
+	} else if recovered > 0 {
+		log.Printf("[AI-PRINT] Re-queued %d job(s) interrupted by a restart", recovered)
+	}
+
+	for i := 0; i < q.workers; i++ {
+		go q.worker(ctx)
+	}
+	q.notify()
+}
+
+// notify wakes an idle worker after a job has been queued
+func (q *resumeJobQueue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// recoverInterrupted puts jobs left in "processing" by a previous process back in the queue.
+// Jobs that keep getting interrupted are failed instead so they cannot loop forever.
+func (q *resumeJobQueue) recoverInterrupted() (int, error) {
+	records, err := q.app.FindRecordsByFilter("view_exports", "status = 'processing'", "", 0, 0, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	recovered := 0
+	for _, job := range records {
+		if job.GetInt("attempts") >= resumeJobMaxAttempts {
+			job.Set("status", "failed")
+			job.Set("error_message", "Generation was interrupted too many times")
+		} else {
+			job.Set("status", "pending")
+			job.Set("progress", 0)
+			job.Set("progress_message", "Queued (resumed after restart)")
+			recovered++
+		}
+		if err := q.app.Save(job); err != nil {
+			return recovered, err
+		}
+	}
+	return recovered, nil
+}
+
+func (q *resumeJobQueue) worker(ctx context.Context) {
+	ticker := time.NewTicker(resumeJobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			job, err := q.claim()
+			if err != nil {
+				log.Printf("[AI-PRINT] Failed to claim job: %v", err)
+				break
+			}
+			if job == nil {
+				break
+			}
+			q.run(ctx, job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// claim marks the oldest pending job as processing and returns it (nil when the queue is empty).
+// The read and update share a transaction so two workers never take the same job.
+func (q *resumeJobQueue) claim() (*core.Record, error) {
+	var job *core.Record
+	err := q.app.RunInTransaction(func(txApp core.App) error {
+		records, err := txApp.FindRecordsByFilter("view_exports", "status = 'pending'", "queued_at", 1, 0, nil)
+		if err != nil || len(records) == 0 {
+			return err
+		}
+
+		job = records[0]
+		job.Set("status", "processing")
+		job.Set("attempts", job.GetInt("attempts")+1)
+		job.Set("started_at", time.Now())
+		job.Set("progress", 5)
+		job.Set("progress_message", "Starting")
+		return txApp.Save(job)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if job != nil {
+		q.publish(job)
+	}
+	return job, nil
+}
+
+// run generates the file for a claimed job and stores the outcome on the record
+func (q *resumeJobQueue) run(ctx context.Context, job *core.Record) {
+	jobCtx, cancel := context.WithTimeout(ctx, resumeJobTimeout)
+	defer cancel()
+
+	progress := func(percent int, message string) {
+		job.Set("progress", percent)
+		job.Set("progress_message", message)
+		if err := q.app.Save(job); err != nil {
+			log.Printf("[AI-PRINT] Failed to save progress for %s: %v", job.Id, err)
+		}
+		q.publish(job)
+	}
+
+	fileBytes, err := q.generate(jobCtx, job, progress)
+	if err != nil {
+		if ctx.Err() != nil {
+			// Shutting down: leave the job for the next start to pick up
+			job.Set("status", "pending")
+			job.Set("progress", 0)
+			job.Set("progress_message", "Queued (interrupted by shutdown)")
+			if saveErr := q.app.Save(job); saveErr != nil {
+				log.Printf("[AI-PRINT] Failed to re-queue %s: %v", job.Id, saveErr)
+			}
+			return
+		}
+		if jobCtx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("generation timed out after %s", resumeJobTimeout)
+		}
+		log.Printf("[AI-PRINT] Job %s failed: %v", job.Id, err)
+		q.fail(job, err.Error())
+		return
+	}
+
+	f, err := filesystem.NewFileFromBytes(fileBytes, "resume."+job.GetString("format"))
+	if err != nil {
+		log.Printf("[AI-PRINT] Failed to create file object: %v", err)
+		q.fail(job, "Failed to save file")
+		return
+	}
+
+	job.Set("file", f)
+	job.Set("status", "completed")
+	job.Set("progress", 100)
+	job.Set("progress_message", "Done")
+	job.Set("generated_at", time.Now())
+
+	if err := q.app.Save(job); err != nil {
+		log.Printf("[AI-PRINT] Failed to save export record with file: %v", err)
+		job.Set("file", nil)
+		q.fail(job, "Failed to save file")
+		return
+	}
+
+	q.publish(job)
+}
+
+func (q *resumeJobQueue) fail(job *core.Record, message string) {
+	job.Set("status", "failed")
+	job.Set("error_message", message)
+	job.Set("progress_message", "Failed")
+	if err := q.app.Save(job); err != nil {
+		log.Printf("[AI-PRINT] Failed to mark %s as failed: %v", job.Id, err)
+	}
+	q.publish(job)
+}
+
+// subscribe returns a channel receiving state changes of one job, and a function to stop listening
+func (q *resumeJobQueue) subscribe(exportID string) (<-chan resumeJobEvent, func()) {
+	ch := make(chan resumeJobEvent, 16)
+
+	q.mu.Lock()
+	if q.subscribers[exportID] == nil {
+		q.subscribers[exportID] = make(map[chan resumeJobEvent]struct{})
+	}
+	q.subscribers[exportID][ch] = struct{}{}
+	q.mu.Unlock()
+
+	return ch, func() {
+		q.mu.Lock()
+		delete(q.subscribers[exportID], ch)
+		if len(q.subscribers[exportID]) == 0 {
+			delete(q.subscribers, exportID)
+		}
+		q.mu.Unlock()
+	}
+}
+
+// publish sends the job state to its subscribers. Slow subscribers miss intermediate
+// updates rather than blocking the worker; the SSE stream re-reads the record periodically.
+func (q *resumeJobQueue) publish(job *core.Record) {
+	ev := resumeJobEventFromRecord(job)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for ch := range q.subscribers[job.Id] {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// resumeJobEventFromRecord describes a view_exports record
+func resumeJobEventFromRecord(job *core.Record) resumeJobEvent {
+	ev := resumeJobEvent{
+		ExportID: job.Id,
+		Status:   job.GetString("status"),
+		Format:   job.GetString("format"),
+		Progress: job.GetInt("progress"),
+		Message:  job.GetString("progress_message"),
+	}
+	if queued := job.GetDateTime("queued_at"); !queued.IsZero() {
+		ev.QueuedAt = queued.String()
+	}
+	if generated := job.GetDateTime("generated_at"); !generated.IsZero() {
+		ev.GeneratedAt = generated.String()
+	}
+
+	switch ev.Status {
+	case "completed":
+		if file := job.GetString("file"); file != "" {
+			ev.DownloadURL = "/api/files/" + job.Collection().Id + "/" + job.Id + "/" + file
+		}
+	case "failed":
+		ev.Error = job.GetString("error_message")
+	}
+	return ev
+}
@@ -0,0 +1,213 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"facet/services"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+var testPDF = []byte("%PDF-1.4\n1 0 obj\n<<>>\nendobj\ntrailer\n<<>>\n%%EOF\n")
+
+func queueTestJob(t *testing.T, app core.App, viewID, status string, attempts int, queuedAt time.Time) *core.Record {
+	t.Helper()
+
+	collection, err := app.FindCollectionByNameOrId("view_exports")
+	if err != nil {
+		t.Fatalf("Failed to find view_exports: %v", err)
+	}
+
+	job := core.NewRecord(collection)
+	job.Set("view", viewID)
+	job.Set("format", "pdf")
+	job.Set("status", status)
+	job.Set("attempts", attempts)
+	job.Set("queued_at", queuedAt)
+	if err := app.Save(job); err != nil {
+		t.Fatalf("Failed to save job: %v", err)
+	}
+	return job
+}
+
+func TestResumeJobQueueRecoversInterruptedJobs(t *testing.T) {
+	app := newMigratedTestApp(t)
+	_, _, viewID := seedImportFixture(t, app)
+
+	now := time.Now()
+	interrupted := queueTestJob(t, app, viewID, "processing", 1, now)
+	exhausted := queueTestJob(t, app, viewID, "processing", resumeJobMaxAttempts, now)
+	completed := queueTestJob(t, app, viewID, "completed", 1, now)
+
+	q := newResumeJobQueue(app, 1, nil)
+	recovered, err := q.recoverInterrupted()
+	if err != nil {
+		t.Fatalf("recoverInterrupted() error = %v", err)
+	}
+	if recovered != 1 {
+		t.Errorf("recovered = %d, want 1", recovered)
+	}
+
+	for id, want := range map[string]string{interrupted.Id: "pending", exhausted.Id: "failed", completed.Id: "completed"} {
+		record, _ := app.FindRecordById("view_exports", id)
+		if record.GetString("status") != want {
+			t.Errorf("job %s status = %q, want %q", id, record.GetString("status"), want)
+		}
+	}
+}
+
+func TestResumeJobQueueRunsOldestFirst(t *testing.T) {
+	app := newMigratedTestApp(t)
+	_, _, viewID := seedImportFixture(t, app)
+
+	newer := queueTestJob(t, app, viewID, "pending", 0, time.Now())
+	older := queueTestJob(t, app, viewID, "pending", 0, time.Now().Add(-time.Minute))
+
+	q := newResumeJobQueue(app, 1, func(ctx context.Context, job *core.Record, progress func(int, string)) ([]byte, error) {
+		progress(50, "Halfway")
+		return testPDF, nil
+	})
+
+	job, err := q.claim()
+	if err != nil || job == nil {
+		t.Fatalf("claim() = %v, %v", job, err)
+	}
+	if job.Id != older.Id {
+		t.Errorf("claimed %s, want the older job %s (newer is %s)", job.Id, older.Id, newer.Id)
+	}
+	if job.GetString("status") != "processing" || job.GetInt("attempts") != 1 {
+		t.Errorf("claimed job status = %q, attempts = %d", job.GetString("status"), job.GetInt("attempts"))
+	}
+
+	events, unsubscribe := q.subscribe(job.Id)
+	defer unsubscribe()
+
+	q.run(context.Background(), job)
+
+	var last resumeJobEvent
+	var sawProgress bool
+	for len(events) > 0 {
+		last = <-events
+		if last.Progress == 50 {
+			sawProgress = true
+		}
+	}
+	if !sawProgress {
+		t.Error("Subscriber did not receive the progress update")
+	}
+	if !last.done() || last.Status != "completed" || last.DownloadURL == "" {
+		t.Errorf("last event = %+v, want completed with a download URL", last)
+	}
+
+	stored, _ := app.FindRecordById("view_exports", job.Id)
+	if stored.GetString("file") == "" || stored.GetInt("progress") != 100 {
+		t.Errorf("stored job file = %q, progress = %d", stored.GetString("file"), stored.GetInt("progress"))
+	}
+}
+
+func TestResumeJobQueueFailureAndShutdown(t *testing.T) {
+	app := newMigratedTestApp(t)
+	_, _, viewID := seedImportFixture(t, app)
+
+	failing := newResumeJobQueue(app, 1, func(ctx context.Context, job *core.Record, progress func(int, string)) ([]byte, error) {
+		return nil, errors.New("AI generation failed: quota exceeded")
+	})
+	queueTestJob(t, app, viewID, "pending", 0, time.Now())
+	job, _ := failing.claim()
+	failing.run(context.Background(), job)
+
+	stored, _ := app.FindRecordById("view_exports", job.Id)
+	if stored.GetString("status") != "failed" || stored.GetString("error_message") == "" {
+		t.Errorf("failed job status = %q, error = %q", stored.GetString("status"), stored.GetString("error_message"))
+	}
+
+	// A job interrupted by shutdown goes back to the queue instead of failing
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupted := newResumeJobQueue(app, 1, func(jobCtx context.Context, job *core.Record, progress func(int, string)) ([]byte, error) {
+		cancel()
+		<-jobCtx.Done()
+		return nil, jobCtx.Err()
+	})
+	queueTestJob(t, app, viewID, "pending", 0, time.Now())
+	job, _ = interrupted.claim()
+	interrupted.run(ctx, job)
+
+	stored, _ = app.FindRecordById("view_exports", job.Id)
+	if stored.GetString("status") != "pending" {
+		t.Errorf("interrupted job status = %q, want pending", stored.GetString("status"))
+	}
+}
+
+func TestViewExportJobAccess(t *testing.T) {
+	app := newMigratedTestApp(t)
+	_, _, viewID := seedImportFixture(t, app)
+	crypto := services.NewCryptoService("test-encryption-key-32-chars-ok!")
+	share := services.NewShareService(crypto)
+	counters := services.NewCounterService(time.Hour)
+
+	view, _ := app.FindRecordById("views", viewID)
+	view.Set("visibility", "unlisted")
+	if err := app.Save(view); err != nil {
+		t.Fatalf("Failed to save view: %v", err)
+	}
+
+	tokens, _ := app.FindCollectionByNameOrId("share_tokens")
+	mint := func(name string) (string, *core.Record) {
+		raw, _ := share.GenerateToken()
+		record := core.NewRecord(tokens)
+		record.Set("view_id", viewID)
+		record.Set("token_hash", share.HMACToken(raw))
+		record.Set("token_prefix", share.TokenPrefix(raw))
+		record.Set("name", name)
+		record.Set("is_active", true)
+		if err := app.Save(record); err != nil {
+			t.Fatalf("Failed to save token: %v", err)
+		}
+		return raw, record
+	}
+	agencyRaw, agency := mint("Agency")
+	otherRaw, _ := mint("Other")
+
+	job := queueTestJob(t, app, viewID, "pending", 0, time.Now())
+	job.Set("share_token", agency.Id)
+	if err := app.Save(job); err != nil {
+		t.Fatalf("Failed to save job: %v", err)
+	}
+
+	request := func(token string) *core.RequestEvent {
+		e := &core.RequestEvent{App: app}
+		e.Request = httptest.NewRequest(http.MethodGet, "/api/view/recruiters/export/"+job.Id, nil)
+		e.Request.SetPathValue("slug", "recruiters")
+		e.Request.SetPathValue("exportId", job.Id)
+		if token != "" {
+			e.Request.Header.Set("X-Share-Token", token)
+		}
+		return e
+	}
+
+	if _, err := findViewExportJob(app, crypto, share, counters, request("")); err == nil {
+		t.Error("export of an unlisted view should need a share token")
+	}
+	if _, err := findViewExportJob(app, crypto, share, counters, request(otherRaw)); err == nil {
+		t.Error("export queued through one share link should not be visible through another")
+	}
+	if found, err := findViewExportJob(app, crypto, share, counters, request(agencyRaw)); err != nil || found.Id != job.Id {
+		t.Errorf("findViewExportJob() = %v, %v; want the job for its own share link", found, err)
+	}
+	if counters.PendingTokenUses(agency.Id) != 0 {
+		t.Error("following an export should not count as a share link use")
+	}
+
+	view.Set("is_active", false)
+	if err := app.Save(view); err != nil {
+		t.Fatalf("Failed to deactivate view: %v", err)
+	}
+	if _, err := findViewExportJob(app, crypto, share, counters, request(agencyRaw)); err == nil {
+		t.Error("exports of an inactive view should not be served")
+	}
+}
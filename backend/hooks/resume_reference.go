@@ -0,0 +1,129 @@
+package hooks
+
+import (
+	"io"
+	"log"
+	"net/http"
+
+	"facet/services"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+)
+
+const maxReferenceDOCXSize = 10 << 20 // matches the site_settings field limit
+
+// RegisterResumeReferenceHooks manages the reference .docx whose fonts and colours DOCX
+// resume exports use. It is stored on the site_settings record.
+func RegisterResumeReferenceHooks(app *pocketbase.PocketBase) {
+	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
+		// GET /api/resume-reference-docx
+		se.Router.GET("/api/resume-reference-docx", func(e *core.RequestEvent) error {
+			settings, err := services.LoadSiteSettings(app)
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load site settings"})
+			}
+			return e.JSON(http.StatusOK, referenceDOCXResponse(settings))
+		}).Bind(apis.RequireAuth())
+
+		// Upload a reference document (multipart "file"); replaces any previous one
+		// POST /api/resume-reference-docx
+		se.Router.POST("/api/resume-reference-docx", func(e *core.RequestEvent) error {
+			e.Request.Body = http.MaxBytesReader(e.Response, e.Request.Body, maxReferenceDOCXSize+1<<20)
+
+			file, header, err := e.Request.FormFile("file")
+			if err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "Select a .docx file up to 10MB"})
+			}
+			defer file.Close()
+
+			data, err := io.ReadAll(io.LimitReader(file, maxReferenceDOCXSize+1))
+			if err != nil || len(data) > maxReferenceDOCXSize {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "Select a .docx file up to 10MB"})
+			}
+			if err := services.ValidateReferenceDOCX(data); err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			}
+
+			settings, err := services.LoadSiteSettings(app)
+			if err != nil || settings.Record == nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load site settings"})
+			}
+			if settings.Record.Collection().Fields.GetByName("resume_reference_docx") == nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "reference document storage is not configured"})
+			}
+
+			f, err := filesystem.NewFileFromBytes(data, header.Filename)
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to store reference document"})
+			}
+			settings.Record.Set("resume_reference_docx", f)
+			if err := app.Save(settings.Record); err != nil {
+				log.Printf("[AI-PRINT] Failed to save reference DOCX: %v", err)
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to store reference document"})
+			}
+
+			settings, err = services.LoadSiteSettings(app)
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load site settings"})
+			}
+			return e.JSON(http.StatusOK, referenceDOCXResponse(settings))
+		}).Bind(apis.RequireAuth())
+
+		// Remove the reference document; DOCX exports go back to the built-in styles
+		// DELETE /api/resume-reference-docx
+		se.Router.DELETE("/api/resume-reference-docx", func(e *core.RequestEvent) error {
+			settings, err := services.LoadSiteSettings(app)
+			if err != nil || settings.Record == nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load site settings"})
+			}
+			if settings.ReferenceDOCX != "" {
+				settings.Record.Set("resume_reference_docx", "")
+				if err := app.Save(settings.Record); err != nil {
+					return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to remove reference document"})
+				}
+			}
+			return e.JSON(http.StatusOK, map[string]interface{}{"uploaded": false, "filename": ""})
+		}).Bind(apis.RequireAuth())
+
+		return se.Next()
+	})
+}
+
+func referenceDOCXResponse(settings *services.SiteSettings) map[string]interface{} {
+	return map[string]interface{}{
+		"uploaded": settings.ReferenceDOCX != "",
+		"filename": settings.ReferenceDOCX,
+	}
+}
+
+// loadResumeReferenceDOCX returns the uploaded reference document, or nil when there is none
+func loadResumeReferenceDOCX(app core.App) []byte {
+	settings, err := services.LoadSiteSettings(app)
+	if err != nil || settings.Record == nil || settings.ReferenceDOCX == "" {
+		return nil
+	}
+
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		log.Printf("[AI-PRINT] Failed to open file storage: %v", err)
+		return nil
+	}
+	defer fsys.Close()
+
+	reader, err := fsys.GetFile(settings.Record.BaseFilesPath() + "/" + settings.ReferenceDOCX)
+	if err != nil {
+		log.Printf("[AI-PRINT] Reference DOCX missing from storage: %v", err)
+		return nil
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxReferenceDOCXSize))
+	if err != nil {
+		log.Printf("[AI-PRINT] Failed to read reference DOCX: %v", err)
+		return nil
+	}
+	return data
+}
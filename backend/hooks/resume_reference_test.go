@@ -0,0 +1,65 @@
+package hooks
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"facet/services"
+
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+)
+
+func TestResumeReferenceDOCXRoundTrip(t *testing.T) {
+	app := newMigratedTestApp(t)
+
+	if data := loadResumeReferenceDOCX(app); data != nil {
+		t.Fatalf("expected no reference document, got %d bytes", len(data))
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, _ := zw.Create("word/styles.xml")
+	f.Write([]byte(`<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Georgia"/></w:rPr></w:rPrDefault></w:docDefaults></w:styles>`))
+	zw.Close()
+	reference := buf.Bytes()
+
+	settings, err := services.LoadSiteSettings(app)
+	if err != nil {
+		t.Fatalf("LoadSiteSettings() error = %v", err)
+	}
+	file, _ := filesystem.NewFileFromBytes(reference, "brand.docx")
+	settings.Record.Set("resume_reference_docx", file)
+	if err := app.Save(settings.Record); err != nil {
+		t.Fatalf("Failed to store reference document: %v", err)
+	}
+
+	loaded := loadResumeReferenceDOCX(app)
+	if !bytes.Equal(loaded, reference) {
+		t.Fatalf("loaded reference = %d bytes, want %d", len(loaded), len(reference))
+	}
+
+	resume := services.NewResumeService(nil)
+	resume.SetDOCXReference(func() []byte { return loadResumeReferenceDOCX(app) })
+	tmpl, _ := services.BuiltinResumeTemplate("chronological")
+	output, err := resume.GenerateFromTemplate(tmpl, &services.ViewData{Profile: map[string]interface{}{"name": "Ada"}}, "docx")
+	if err != nil {
+		t.Fatalf("GenerateFromTemplate(docx) error = %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(output), int64(len(output)))
+	if err != nil {
+		t.Fatalf("output is not a .docx: %v", err)
+	}
+	for _, f := range zr.File {
+		if f.Name != "word/styles.xml" {
+			continue
+		}
+		rc, _ := f.Open()
+		var styles bytes.Buffer
+		styles.ReadFrom(rc)
+		rc.Close()
+		if !bytes.Contains(styles.Bytes(), []byte("Georgia")) {
+			t.Error("DOCX export did not use the reference styles")
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package hooks
+
+import (
+	"fmt"
+	"net/http"
+
+	"facet/services"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// RegisterResumeTemplateHooks validates user templates and lists the available templates
+func RegisterResumeTemplateHooks(app *pocketbase.PocketBase) {
+	validate := func(e *core.RecordEvent) error {
+		if _, builtin := services.BuiltinResumeTemplate(e.Record.GetString("slug")); builtin {
+			return fmt.Errorf("slug %q is reserved for a built-in template", e.Record.GetString("slug"))
+		}
+		if err := services.ValidateResumeTemplate(e.Record.GetString("content")); err != nil {
+			return err
+		}
+		return e.Next()
+	}
+	app.OnRecordCreate("resume_templates").BindFunc(validate)
+	app.OnRecordUpdate("resume_templates").BindFunc(validate)
+
+	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
+		// List built-in and custom resume templates
+		// GET /api/resume-templates
+		// Built-ins include their content so they can be copied as a starting point
+		se.Router.GET("/api/resume-templates", func(e *core.RequestEvent) error {
+			templates := services.BuiltinResumeTemplates()
+
+			records, err := app.FindRecordsByFilter("resume_templates", "", "name", 0, 0, nil)
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load templates"})
+			}
+			for _, record := range records {
+				tmpl := resumeTemplateFromRecord(record)
+				tmpl.Content = ""
+				templates = append(templates, tmpl)
+			}
+
+			return e.JSON(http.StatusOK, map[string]interface{}{
+				"templates": templates,
+			})
+		}).Bind(apis.RequireAuth())
+
+		return se.Next()
+	})
+}
+
+// resolveResumeTemplate finds a built-in template by slug, then a custom one
+func resolveResumeTemplate(app core.App, slug string) (services.ResumeTemplate, error) {
+	if tmpl, ok := services.BuiltinResumeTemplate(slug); ok {
+		return tmpl, nil
+	}
+
+	record, err := app.FindFirstRecordByData("resume_templates", "slug", slug)
+	if err != nil {
+		return services.ResumeTemplate{}, fmt.Errorf("template %q not found", slug)
+	}
+	return resumeTemplateFromRecord(record), nil
+}
+
+func resumeTemplateFromRecord(record *core.Record) services.ResumeTemplate {
+	return services.ResumeTemplate{
+		Slug:        record.GetString("slug"),
+		Name:        record.GetString("name"),
+		Description: record.GetString("description"),
+		Format:      record.GetString("format"),
+		Content:     record.GetString("content"),
+	}
+}
@@ -0,0 +1,95 @@
+package hooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"facet/services"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestResumeTemplateValidation(t *testing.T) {
+	app := newMigratedTestApp(t)
+	pb := &pocketbase.PocketBase{App: app}
+	RegisterResumeTemplateHooks(pb)
+
+	collection, err := app.FindCollectionByNameOrId("resume_templates")
+	if err != nil {
+		t.Fatalf("Failed to find resume_templates: %v", err)
+	}
+
+	save := func(slug, content string) error {
+		record := core.NewRecord(collection)
+		record.Set("name", slug)
+		record.Set("slug", slug)
+		record.Set("format", "markdown")
+		record.Set("content", content)
+		return app.Save(record)
+	}
+
+	if err := save("chronological", `{{.Name}}`); err == nil {
+		t.Error("Expected built-in slug to be rejected")
+	}
+	if err := save("broken", `{{range .Sections}}`); err == nil {
+		t.Error("Expected an invalid template to be rejected")
+	}
+	if err := save("one-liner", `{{.Name}} — {{.Headline}}`); err != nil {
+		t.Fatalf("Valid template rejected: %v", err)
+	}
+
+	tmpl, err := resolveResumeTemplate(app, "one-liner")
+	if err != nil || tmpl.Builtin || tmpl.Content != `{{.Name}} — {{.Headline}}` {
+		t.Errorf("resolveResumeTemplate(custom) = %+v, %v", tmpl, err)
+	}
+	if tmpl, err := resolveResumeTemplate(app, "academic"); err != nil || !tmpl.Builtin {
+		t.Errorf("resolveResumeTemplate(builtin) = %+v, %v", tmpl, err)
+	}
+	if _, err := resolveResumeTemplate(app, "missing"); err == nil {
+		t.Error("Expected an error for an unknown template")
+	}
+}
+
+func TestTemplateExportJobStoresMarkdown(t *testing.T) {
+	app := newMigratedTestApp(t)
+	_, _, viewID := seedImportFixture(t, app)
+
+	view, _ := app.FindRecordById("views", viewID)
+	viewData, err := collectViewData(app, view)
+	if err != nil {
+		t.Fatalf("collectViewData() error = %v", err)
+	}
+
+	tmpl, _ := services.BuiltinResumeTemplate("chronological")
+	resume := services.NewResumeService(nil)
+
+	collection, _ := app.FindCollectionByNameOrId("view_exports")
+	job := core.NewRecord(collection)
+	job.Set("view", viewID)
+	job.Set("format", "md")
+	job.Set("status", "pending")
+	job.Set("queued_at", time.Now())
+	if err := app.Save(job); err != nil {
+		t.Fatalf("Failed to queue md export: %v", err)
+	}
+
+	q := newResumeJobQueue(app, 1, func(ctx context.Context, job *core.Record, progress func(int, string)) ([]byte, error) {
+		return resume.GenerateFromTemplate(tmpl, viewData, "md")
+	})
+	claimed, _ := q.claim()
+	q.run(context.Background(), claimed)
+
+	stored, _ := app.FindRecordById("view_exports", job.Id)
+	if stored.GetString("status") != "completed" || stored.GetString("file") == "" {
+		t.Fatalf("md export status = %q, error = %q", stored.GetString("status"), stored.GetString("error_message"))
+	}
+
+	// Rendering the same view again gives the same bytes
+	first, _ := resume.GenerateFromTemplate(tmpl, viewData, "md")
+	second, _ := resume.GenerateFromTemplate(tmpl, viewData, "md")
+	if string(first) != string(second) {
+		t.Error("Template output is not reproducible")
+	}
+}
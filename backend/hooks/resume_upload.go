@@ -554,7 +554,7 @@ func createResumeRecords(app *pocketbase.PocketBase, parsed *services.ParsedResu
 // The filename-based deduplication for experience/projects allows:
 // 1. Same resume imported multiple times → prevents duplicates
 // 2. Different resumes with same role → creates faceted views
-func createResumeRecordsWithDeduplication(app *pocketbase.PocketBase, parsed *services.ParsedResume, filename string, importSessionID string, visibility string) (map[string][]string, int, error) {
+func createResumeRecordsWithDeduplication(app core.App, parsed *services.ParsedResume, filename string, importSessionID string, visibility string) (map[string][]string, int, error) {
 	imported := make(map[string][]string)
 	duplicateCount := 0
 
@@ -577,7 +577,7 @@ func createResumeRecordsWithDeduplication(app *pocketbase.PocketBase, parsed *se
 			// This allows same role from DIFFERENT resumes (faceted views)
 			// but prevents duplicates from same resume imported multiple times
 			filter := fmt.Sprintf("company = '%s' && title = '%s' && start_date = '%s' && import_filename = '%s'",
-				escapeFilter(exp.Company), escapeFilter(exp.Title), exp.StartDate, escapeFilter(filename))
+				escapeFilter(exp.Company), escapeFilter(exp.Title), storedDate(exp.StartDate), escapeFilter(filename))
 			existing, err := app.FindRecordsByFilter(expCollection.Name, filter, "", 1, 0)
 			if err != nil {
 				log.Printf("[RESUME-UPLOAD] [ERROR] Filter query failed for experience '%s at %s': %v", exp.Title, exp.Company, err)
@@ -632,9 +632,9 @@ func createResumeRecordsWithDeduplication(app *pocketbase.PocketBase, parsed *se
 			// Check for duplicate across ALL imports (not session-specific)
 			// A degree from MIT is the same degree regardless of which resume lists it
 			var filter string
-			if edu.EndDate != "" && edu.EndDate != "null" {
+			if endDate := storedDate(edu.EndDate); endDate != "" {
 				filter = fmt.Sprintf("institution = '%s' && degree = '%s' && field = '%s' && end_date = '%s'",
-					escapeFilter(edu.Institution), escapeFilter(edu.Degree), escapeFilter(edu.Field), edu.EndDate)
+					escapeFilter(edu.Institution), escapeFilter(edu.Degree), escapeFilter(edu.Field), endDate)
 			} else {
 				filter = fmt.Sprintf("institution = '%s' && degree = '%s' && field = '%s'",
 					escapeFilter(edu.Institution), escapeFilter(edu.Degree), escapeFilter(edu.Field))
@@ -831,9 +831,9 @@ func createResumeRecordsWithDeduplication(app *pocketbase.PocketBase, parsed *se
 				// Check for duplicate across ALL imports (not session-specific)
 				// Match on title + issuer + date for uniqueness
 				var filter string
-				if award.AwardedAt != "" && award.AwardedAt != "null" {
+				if awardedAt := storedDate(award.AwardedAt); awardedAt != "" {
 					filter = fmt.Sprintf("title = '%s' && issuer = '%s' && awarded_at = '%s'",
-						escapeFilter(award.Title), escapeFilter(award.Issuer), award.AwardedAt)
+						escapeFilter(award.Title), escapeFilter(award.Issuer), awardedAt)
 				} else {
 					filter = fmt.Sprintf("title = '%s' && issuer = '%s'",
 						escapeFilter(award.Title), escapeFilter(award.Issuer))
@@ -908,9 +908,120 @@ func createResumeRecordsWithDeduplication(app *pocketbase.PocketBase, parsed *se
 		}
 	}
 
+	// Create posts from publications with cross-session deduplication
+	// Strategy: A publication is a single published work - match on title
+	if len(parsed.Publications) > 0 {
+		postsCollection, err := app.FindCollectionByNameOrId(getTableName("posts"))
+		if err != nil {
+			log.Printf("[RESUME-UPLOAD] Posts collection not found (skipping): %v", err)
+		} else {
+			for _, pub := range parsed.Publications {
+				existing, err := app.FindRecordsByFilter(postsCollection.Name, "title = {:title}", "", 1, 0,
+					map[string]interface{}{"title": pub.Title})
+				if err == nil && len(existing) > 0 {
+					duplicateCount++
+					continue
+				}
+
+				record := core.NewRecord(postsCollection)
+				record.Set("title", pub.Title)
+				record.Set("slug", generateSlug(pub.Title))
+				record.Set("excerpt", pub.Summary)
+				record.Set("content", publicationContent(pub))
+				if normalized := normalizeDate(pub.ReleaseDate); normalized != "" {
+					record.Set("published_at", normalized)
+				}
+				record.Set("visibility", visibility)
+				record.Set("is_draft", false)
+
+				if err := app.Save(record); err != nil {
+					log.Printf("[RESUME-UPLOAD] Failed to create post: %v", err)
+					continue
+				}
+				imported["posts"] = append(imported["posts"], record.Id)
+			}
+		}
+	}
+
+	// Create testimonials with cross-session deduplication
+	// Strategy: The same author writing the same text is the same recommendation.
+	// Imported testimonials start as pending so nothing is published without review.
+	if len(parsed.Testimonials) > 0 {
+		testimonialsCollection, err := app.FindCollectionByNameOrId("testimonials")
+		if err != nil {
+			log.Printf("[RESUME-UPLOAD] Testimonials collection not found (skipping): %v", err)
+		} else {
+			for _, testimonial := range parsed.Testimonials {
+				existing, err := app.FindRecordsByFilter(testimonialsCollection.Name,
+					"author_name = {:author} && content = {:content}", "", 1, 0,
+					map[string]interface{}{"author": testimonial.AuthorName, "content": testimonial.Content})
+				if err == nil && len(existing) > 0 {
+					duplicateCount++
+					continue
+				}
+
+				verificationMethod := testimonial.Source
+				if verificationMethod == "" {
+					verificationMethod = "none"
+				}
+
+				record := core.NewRecord(testimonialsCollection)
+				record.Set("author_name", testimonial.AuthorName)
+				record.Set("author_title", testimonial.AuthorTitle)
+				record.Set("author_company", testimonial.AuthorCompany)
+				record.Set("content", testimonial.Content)
+				record.Set("relationship", "other")
+				record.Set("verification_method", verificationMethod)
+				record.Set("status", "pending")
+				if normalized := normalizeDate(testimonial.Date); normalized != "" {
+					record.Set("submitted_at", normalized)
+				}
+				record.Set("import_session_id", importSessionID)
+				record.Set("import_filename", filename)
+				record.Set("featured", false)
+				record.Set("sort_order", 0)
+
+				if err := app.Save(record); err != nil {
+					log.Printf("[RESUME-UPLOAD] Failed to create testimonial: %v", err)
+					continue
+				}
+				imported["testimonials"] = append(imported["testimonials"], record.Id)
+			}
+		}
+	}
+
 	return imported, duplicateCount, nil
 }
 
+// publicationContent builds post content for an imported publication
+func publicationContent(pub services.PublicationData) string {
+	var sb strings.Builder
+	sb.WriteString(pub.Summary)
+	if pub.URL != "" || pub.Publisher != "" {
+		if sb.Len() > 0 {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString("Published")
+		if pub.Publisher != "" {
+			sb.WriteString(" in *" + pub.Publisher + "*")
+		}
+		if pub.URL != "" {
+			sb.WriteString(": <" + pub.URL + ">")
+		}
+	}
+	return sb.String()
+}
+
+// storedDate converts a resume date to the form date fields are stored in,
+// so deduplication filters match records created by earlier imports
+func storedDate(dateStr string) string {
+	normalized := normalizeDate(dateStr)
+	if normalized == "" {
+		return ""
+	}
+	return normalized + " 00:00:00.000Z"
+}
+
 // escapeFilter escapes single quotes for SQL filter strings
 func escapeFilter(s string) string {
 	return strings.ReplaceAll(s, "'", "''")
@@ -44,12 +44,16 @@ func enforceCollectionRules(app *pocketbase.PocketBase) error {
 		"posts",
 		"talks",
 		"views",
+		"section_types",
+		"custom_items",
 		// Sensitive/admin collections
 		"share_tokens",
 		"sources",
 		"ai_providers",
 		"import_proposals",
 		"settings",
+		"applications",
+		"view_passwords",
 	}
 
 	for _, name := range allManagedCollections {
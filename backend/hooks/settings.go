@@ -22,12 +22,16 @@ func RegisterSiteSettingsHooks(app *pocketbase.PocketBase) {
 			return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load site settings"})
 		}
 
-			return e.JSON(http.StatusOK, map[string]any{
+			response := map[string]any{
 				"homepage_enabled":     settings.HomepageEnabled,
 				"landing_page_message": settings.LandingPageMessage,
 				"custom_css":           settings.CustomCSS,
-				"ga_measurement_id":    settings.GAMeasurementID,
-			})
+			}
+			// Admin-only settings
+			if e.Auth != nil {
+				response["access_log_retention_days"] = settings.AccessLogRetentionDays
+			}
+			return e.JSON(http.StatusOK, response)
 		})
 
 		// Authenticated: update site settings
@@ -40,7 +44,8 @@ func RegisterSiteSettingsHooks(app *pocketbase.PocketBase) {
 				HomepageEnabled    *bool  `json:"homepage_enabled"`
 				LandingPageMessage string `json:"landing_page_message"`
 				CustomCSS          string `json:"custom_css"`
-				GAMeasurementID    string `json:"ga_measurement_id"`
+				// Days to keep access events (0 = forever)
+				AccessLogRetentionDays *int `json:"access_log_retention_days"`
 			}
 
 			if err := e.BindBody(&req); err != nil {
@@ -66,12 +71,13 @@ func RegisterSiteSettingsHooks(app *pocketbase.PocketBase) {
 				}
 				updates["custom_css"] = css
 			}
-			if req.GAMeasurementID != "" || req.GAMeasurementID == "" {
-				id := strings.TrimSpace(req.GAMeasurementID)
-				if len(id) > 100 {
-					id = id[:100]
+
+			if req.AccessLogRetentionDays != nil {
+				days := *req.AccessLogRetentionDays
+				if days < 0 || days > 3650 {
+					return apis.NewBadRequestError("access_log_retention_days must be between 0 and 3650", nil)
 				}
-				updates["ga_measurement_id"] = id
+				updates["access_log_retention_days"] = days
 			}
 
 			settings, err := services.UpdateSiteSettings(app, updates, app.Logger())
@@ -83,7 +89,8 @@ func RegisterSiteSettingsHooks(app *pocketbase.PocketBase) {
 				"homepage_enabled":     settings.HomepageEnabled,
 				"landing_page_message": settings.LandingPageMessage,
 				"custom_css":           settings.CustomCSS,
-				"ga_measurement_id":    settings.GAMeasurementID,
+
+				"access_log_retention_days": settings.AccessLogRetentionDays,
 			})
 		})
 
@@ -1,6 +1,7 @@
 package hooks
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
@@ -12,7 +13,9 @@ import (
 )
 
 // RegisterShareHooks registers share token related endpoints
-func RegisterShareHooks(app *pocketbase.PocketBase, share *services.ShareService, crypto *services.CryptoService, rl *services.RateLimitService) {
+func RegisterShareHooks(app *pocketbase.PocketBase, share *services.ShareService, crypto *services.CryptoService, rl *services.RateLimitService, counters *services.CounterService) {
+	bindShareScopeValidation(app)
+
 	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
 		// Validate a share token
 		// NOTE: All failure cases return the same generic error to prevent oracle attacks.
@@ -55,10 +58,13 @@ func RegisterShareHooks(app *pocketbase.PocketBase, share *services.ShareService
 				return e.JSON(http.StatusOK, invalidResponse)
 			}
 
-			// Check max uses - return same error to prevent oracle
-			useCount := tokenRecord.GetInt("use_count")
+			// Check max uses (including unflushed uses) - return same error to prevent oracle.
+			// Recipient-bound tokens count verified sessions instead; their limit
+			// is checked when a new session is started.
+			bound := isRecipientBound(tokenRecord)
+			useCount := tokenRecord.GetInt("use_count") + counters.PendingTokenUses(tokenRecord.Id)
 			maxUses := tokenRecord.GetInt("max_uses")
-			if maxUses > 0 && useCount >= maxUses {
+			if !bound && maxUses > 0 && useCount >= maxUses {
 				return e.JSON(http.StatusOK, invalidResponse)
 			}
 
@@ -78,10 +84,18 @@ func RegisterShareHooks(app *pocketbase.PocketBase, share *services.ShareService
 				return e.JSON(http.StatusOK, invalidResponse)
 			}
 
+			if bound {
+				return e.JSON(http.StatusOK, services.ShareTokenValidation{
+					Valid:                true,
+					ViewID:               viewID,
+					ViewSlug:             viewRecord.GetString("slug"),
+					VerificationRequired: true,
+					RecipientHint:        services.MaskEmail(tokenRecord.GetString("recipient_email")),
+				})
+			}
+
 			// Update usage
-			tokenRecord.Set("use_count", useCount+1)
-			tokenRecord.Set("last_used_at", time.Now())
-			app.Save(tokenRecord)
+			counters.IncrementTokenUse(tokenRecord.Id)
 
 			return e.JSON(http.StatusOK, services.ShareTokenValidation{
 				Valid:    true,
@@ -90,6 +104,79 @@ func RegisterShareHooks(app *pocketbase.PocketBase, share *services.ShareService
 			})
 		}))
 
+		// Email a one-time code and link to the recipient of a recipient-bound token
+		// Rate limited: strict tier (5/min) since every call sends an email
+		se.Router.POST("/api/share/verify/start", RateLimitMiddleware(rl, "strict")(func(e *core.RequestEvent) error {
+			var req struct {
+				Token string `json:"token"`
+			}
+			if err := e.BindBody(&req); err != nil || req.Token == "" {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "token required"})
+			}
+
+			tokenRecord, viewRecord := findUsableShareToken(app, share, req.Token)
+			if tokenRecord == nil || !isRecipientBound(tokenRecord) {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid token"})
+			}
+
+			err := startShareVerification(app, share, counters, tokenRecord, req.Token, configuredAppURL(app), viewRecord.GetString("name"))
+			if errors.Is(err, errShareTokenUsedUp) {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid token"})
+			}
+			if errors.Is(err, errShareVerificationLimited) {
+				return e.JSON(http.StatusTooManyRequests, map[string]string{"error": "too many codes requested, try again later"})
+			}
+			if errors.Is(err, errAppURLNotConfigured) {
+				app.Logger().Error("Cannot send share verification email: set APP_URL", "token_id", tokenRecord.Id)
+				return e.JSON(http.StatusServiceUnavailable, map[string]string{"error": "verification email is not configured"})
+			}
+			if err != nil {
+				app.Logger().Error("Failed to send share verification email", "error", err, "token_id", tokenRecord.Id)
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to send verification email"})
+			}
+
+			return e.JSON(http.StatusOK, map[string]string{
+				"status":         "sent",
+				"recipient_hint": services.MaskEmail(tokenRecord.GetString("recipient_email")),
+			})
+		}))
+
+		// Exchange an emailed code (or magic link secret) for a share session
+		// Rate limited: strict tier (5/min) on top of the per-code attempt limit
+		se.Router.POST("/api/share/verify/confirm", RateLimitMiddleware(rl, "strict")(func(e *core.RequestEvent) error {
+			var req struct {
+				Token string `json:"token"`
+				Code  string `json:"code"`
+				Link  string `json:"link"`
+			}
+			if err := e.BindBody(&req); err != nil || req.Token == "" || (req.Code == "" && req.Link == "") {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "token and code required"})
+			}
+
+			tokenRecord, viewRecord := findUsableShareToken(app, share, req.Token)
+			if tokenRecord == nil || !isRecipientBound(tokenRecord) {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid or expired code"})
+			}
+
+			if err := confirmShareVerification(app, share, counters, tokenRecord, req.Code, req.Link); err != nil {
+				if !errors.Is(err, errShareVerificationInvalid) {
+					app.Logger().Error("Failed to confirm share verification", "error", err, "token_id", tokenRecord.Id)
+				}
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid or expired code"})
+			}
+
+			session, expiresAt, err := crypto.GenerateShareSessionJWT(viewRecord.Id, tokenRecord.Id, shareSessionDuration(tokenRecord, time.Now()))
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to create session"})
+			}
+
+			return e.JSON(http.StatusOK, map[string]interface{}{
+				"session":    session,
+				"expires_at": expiresAt.Format(time.RFC3339),
+				"view_slug":  viewRecord.GetString("slug"),
+			})
+		}))
+
 		// Generate a new share token
 		se.Router.POST("/api/share/generate", func(e *core.RequestEvent) error {
 			if e.Auth == nil {
@@ -101,6 +188,12 @@ func RegisterShareHooks(app *pocketbase.PocketBase, share *services.ShareService
 				Name      string  `json:"name"`
 				ExpiresAt *string `json:"expires_at"` // Accept as string, parse below
 				MaxUses   int     `json:"max_uses"`
+				// Optional: link the token to a job application for a personal greeting
+				ApplicationID string `json:"application_id"`
+				// Optional: only this recipient can open the link, after confirming their email
+				RecipientEmail string `json:"recipient_email"`
+				// Optional: sections, hidden items and redacted fields the token is limited to
+				Scope *services.ShareScope `json:"scope"`
 			}
 
 			if err := e.BindBody(&req); err != nil {
@@ -113,41 +206,34 @@ func RegisterShareHooks(app *pocketbase.PocketBase, share *services.ShareService
 				return e.JSON(http.StatusNotFound, map[string]string{"error": "view not found"})
 			}
 
-			// Generate token
-			rawToken, err := share.GenerateToken()
-			if err != nil {
-				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to generate token"})
+			if req.RecipientEmail != "" {
+				email, err := normalizeRecipientEmail(req.RecipientEmail)
+				if err != nil {
+					return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+				}
+				req.RecipientEmail = email
+			}
+
+			if req.Scope != nil {
+				if err := validateShareScope(app, *req.Scope, false); err != nil {
+					return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid scope: " + err.Error()})
+				}
 			}
 
-			tokenHMAC := share.HMACToken(rawToken)
-			tokenPrefix := share.TokenPrefix(rawToken)
+			if req.ApplicationID != "" {
+				if _, err := app.FindRecordById("applications", req.ApplicationID); err != nil {
+					return e.JSON(http.StatusNotFound, map[string]string{"error": "application not found"})
+				}
+			}
 
-			// Create token record
-			collection, err := app.FindCollectionByNameOrId("share_tokens")
+			// Generate token and create its record
+			record, rawToken, err := newShareTokenRecord(app, share, req.ViewID, req.Name)
 			if err != nil {
-				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "collection not found"})
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to generate token"})
 			}
 
-			record := core.NewRecord(collection)
-			record.Set("view_id", req.ViewID)
-			record.Set("token_hash", tokenHMAC)
-			record.Set("token_prefix", tokenPrefix) // For O(1) indexed lookup
-			record.Set("name", req.Name)
-			record.Set("is_active", true)
-			record.Set("use_count", 0)
-
 			if req.ExpiresAt != nil && *req.ExpiresAt != "" {
-				// Parse datetime-local format (e.g., "2024-01-15T14:30")
-				// Try multiple formats
-				expiresAt, err := time.Parse("2006-01-02T15:04", *req.ExpiresAt)
-				if err != nil {
-					// Try with seconds
-					expiresAt, err = time.Parse("2006-01-02T15:04:05", *req.ExpiresAt)
-				}
-				if err != nil {
-					// Try RFC3339
-					expiresAt, err = time.Parse(time.RFC3339, *req.ExpiresAt)
-				}
+				expiresAt, err := parseShareExpiry(*req.ExpiresAt)
 				if err != nil {
 					return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid expiration date format"})
 				}
@@ -156,6 +242,15 @@ func RegisterShareHooks(app *pocketbase.PocketBase, share *services.ShareService
 			if req.MaxUses > 0 {
 				record.Set("max_uses", req.MaxUses)
 			}
+			if req.ApplicationID != "" {
+				record.Set("application", req.ApplicationID)
+			}
+			if req.RecipientEmail != "" {
+				record.Set("recipient_email", req.RecipientEmail)
+			}
+			if req.Scope != nil && !req.Scope.IsZero() {
+				record.Set("scope", req.Scope)
+			}
 
 			if err := app.Save(record); err != nil {
 				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save token"})
@@ -191,3 +286,62 @@ func RegisterShareHooks(app *pocketbase.PocketBase, share *services.ShareService
 		return se.Next()
 	})
 }
+
+// newShareTokenRecord generates a share token for a view and returns its unsaved
+// record along with the raw token, which is shown once and never stored
+func newShareTokenRecord(app core.App, share *services.ShareService, viewID, name string) (*core.Record, string, error) {
+	rawToken, err := share.GenerateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	collection, err := app.FindCollectionByNameOrId("share_tokens")
+	if err != nil {
+		return nil, "", err
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("view_id", viewID)
+	record.Set("token_hash", share.HMACToken(rawToken))
+	record.Set("token_prefix", share.TokenPrefix(rawToken)) // For O(1) indexed lookup
+	record.Set("name", name)
+	record.Set("is_active", true)
+	record.Set("use_count", 0)
+	return record, rawToken, nil
+}
+
+// parseShareExpiry parses a token expiry in datetime-local format
+// (e.g., "2024-01-15T14:30"), with seconds, or RFC3339
+func parseShareExpiry(value string) (time.Time, error) {
+	expiresAt, err := time.Parse("2006-01-02T15:04", value)
+	if err != nil {
+		expiresAt, err = time.Parse("2006-01-02T15:04:05", value)
+	}
+	if err != nil {
+		expiresAt, err = time.Parse(time.RFC3339, value)
+	}
+	return expiresAt, err
+}
+
+// findUsableShareToken looks up an active, unexpired share token and its active view
+func findUsableShareToken(app core.App, share *services.ShareService, rawToken string) (*core.Record, *core.Record) {
+	tokenRecord, err := app.FindFirstRecordByFilter(
+		"share_tokens",
+		"token_hash = {:hash} && is_active = true",
+		map[string]interface{}{"hash": share.HMACToken(rawToken)},
+	)
+	if err != nil || tokenRecord == nil {
+		return nil, nil
+	}
+
+	expiresAt := tokenRecord.GetDateTime("expires_at")
+	if !expiresAt.IsZero() && time.Now().After(expiresAt.Time()) {
+		return nil, nil
+	}
+
+	viewRecord, err := app.FindRecordById("views", tokenRecord.GetString("view_id"))
+	if err != nil || !viewRecord.GetBool("is_active") {
+		return nil, nil
+	}
+	return tokenRecord, viewRecord
+}
@@ -0,0 +1,184 @@
+package hooks
+
+import (
+	"fmt"
+	"strings"
+
+	"facet/services"
+
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// profileScopeKey is the redact key of a share scope that covers the profile
+const profileScopeKey = "profile"
+
+// profileRedactableFields are the profile fields of the data response a scoped
+// share token may leave out
+var profileRedactableFields = []string{
+	"name", "headline", "location", "summary", "contact_email", "contact_links",
+	"hero_image_url", "avatar_url",
+}
+
+// bindShareScopeValidation rejects share tokens whose scope names unknown
+// sections, malformed item IDs or fields that could not be redacted
+func bindShareScopeValidation(app core.App) {
+	validate := func(e *core.RecordEvent) error {
+		scope, err := services.ParseShareScope(e.Record.GetString("scope"))
+		if err != nil {
+			return apis.NewBadRequestError("invalid scope: expected lists of sections and hidden_items and a redact object", nil)
+		}
+		if err := validateShareScope(e.App, scope, strings.HasPrefix(e.Record.Collection().Name, "demo_")); err != nil {
+			return apis.NewBadRequestError("invalid scope: "+err.Error(), nil)
+		}
+		return e.Next()
+	}
+	app.OnRecordCreate("share_tokens", "demo_share_tokens").BindFunc(validate)
+	app.OnRecordUpdate("share_tokens", "demo_share_tokens").BindFunc(validate)
+}
+
+// validateShareScope checks that a scope only refers to sections that exist and
+// to fields the data response actually sends
+func validateShareScope(app core.App, scope services.ShareScope, isDemoMode bool) error {
+	for _, name := range scope.Sections {
+		if _, ok := resolveSection(app, name, isDemoMode); !ok {
+			return fmt.Errorf("unknown section %q", name)
+		}
+	}
+
+	for _, id := range scope.HiddenItems {
+		if !recordIDPattern.MatchString(id) {
+			return fmt.Errorf("hidden item %q is not a record id", id)
+		}
+	}
+
+	for key, fields := range scope.Redact {
+		if key == profileScopeKey {
+			for _, field := range fields {
+				if !containsString(profileRedactableFields, field) {
+					return fmt.Errorf("profile field %s cannot be redacted (allowed: %s)", field, strings.Join(profileRedactableFields, ", "))
+				}
+			}
+			continue
+		}
+
+		def, ok := resolveSection(app, key, isDemoMode)
+		if !ok {
+			return fmt.Errorf("unknown section %q", key)
+		}
+		for _, field := range fields {
+			if !isRedactableField(def, field) {
+				return fmt.Errorf("section %s has no field %s to redact", key, field)
+			}
+		}
+	}
+	return nil
+}
+
+// isRedactableField reports whether a field is sent for the items of a section.
+// The id is kept: the page needs it to render the item at all.
+func isRedactableField(def sectionDef, field string) bool {
+	if field == "id" {
+		return false
+	}
+	if def.isCustom() {
+		if field == "title" {
+			return true
+		}
+		_, ok := def.customField(field)
+		return ok
+	}
+	return isPublicField(def.Collection, field)
+}
+
+// shareTokenScope returns the scope of the share token that opened a view
+func shareTokenScope(shareRecord *core.Record) (services.ShareScope, error) {
+	if shareRecord == nil {
+		return services.ShareScope{}, nil
+	}
+	return services.ParseShareScope(shareRecord.GetString("scope"))
+}
+
+// applyShareScope narrows the sections of a view to what a scoped share token
+// unlocks: sections outside the scope, hidden items and redacted fields are dropped
+func applyShareScope(sections *viewSections, scope services.ShareScope) {
+	if scope.IsZero() {
+		return
+	}
+
+	order := sections.Order[:0]
+	for _, name := range sections.Order {
+		if scope.AllowsSection(name) {
+			order = append(order, name)
+			continue
+		}
+		delete(sections.Data, name)
+		delete(sections.Layouts, name)
+		delete(sections.Widths, name)
+		delete(sections.Custom, name)
+	}
+	sections.Order = order
+
+	for name, data := range sections.Data {
+		items, ok := data.([]map[string]interface{})
+		if !ok || items == nil {
+			continue
+		}
+		kept := make([]map[string]interface{}, 0, len(items))
+		for _, item := range items {
+			if id, _ := item["id"].(string); scope.HidesItem(id) {
+				continue
+			}
+			redactFields(item, scope.Redact[name])
+			kept = append(kept, item)
+		}
+		sections.Data[name] = kept
+	}
+}
+
+// redactFields removes fields from a serialized record
+func redactFields(item map[string]interface{}, fields []string) {
+	for _, field := range fields {
+		if field == "id" {
+			continue
+		}
+		delete(item, field)
+	}
+}
+
+// applyShareScopeToViewData narrows resume data the way applyShareScope narrows
+// the data response, so a scoped link cannot render more than it can open
+func applyShareScopeToViewData(data *services.ViewData, scope services.ShareScope) {
+	if scope.IsZero() {
+		return
+	}
+
+	order := data.SectionOrder[:0]
+	for _, name := range data.SectionOrder {
+		if scope.AllowsSection(name) {
+			order = append(order, name)
+			continue
+		}
+		delete(data.Sections, name)
+		delete(data.SectionTitles, name)
+	}
+	data.SectionOrder = order
+
+	for name, items := range data.Sections {
+		if !scope.AllowsSection(name) {
+			delete(data.Sections, name)
+			continue
+		}
+		kept := make([]map[string]interface{}, 0, len(items))
+		for _, item := range items {
+			if id, _ := item["id"].(string); scope.HidesItem(id) {
+				continue
+			}
+			redactFields(item, scope.Redact[name])
+			kept = append(kept, item)
+		}
+		data.Sections[name] = kept
+	}
+
+	redactFields(data.Profile, scope.Redact[profileScopeKey])
+}
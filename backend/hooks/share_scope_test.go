@@ -0,0 +1,195 @@
+package hooks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestShareScopeValidation(t *testing.T) {
+	app := newMigratedTestApp(t)
+	bindShareScopeValidation(app)
+	bindCustomSectionHooks(app)
+	seedCustomSection(t, app, "")
+	_, _, viewID := seedImportFixture(t, app)
+
+	tokens, err := app.FindCollectionByNameOrId("share_tokens")
+	if err != nil {
+		t.Fatalf("Failed to find share_tokens: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		scope   interface{}
+		wantErr string
+	}{
+		{"no scope", nil, ""},
+		{"sections", map[string]interface{}{"sections": []string{"experience", "publications"}}, ""},
+		{"redact", map[string]interface{}{"redact": map[string]interface{}{
+			"experience":   []string{"company", "location"},
+			"publications": []string{"venue"},
+			"profile":      []string{"contact_email", "location"},
+		}}, ""},
+		{"hidden items", map[string]interface{}{"hidden_items": []string{"abcdefghij12345"}}, ""},
+		{"unknown section", map[string]interface{}{"sections": []string{"hobbies"}}, `unknown section "hobbies"`},
+		{"bad item id", map[string]interface{}{"hidden_items": []string{"../etc"}}, "is not a record id"},
+		{"private field", map[string]interface{}{"redact": map[string]interface{}{"experience": []string{"view_visibility"}}}, "has no field view_visibility"},
+		{"id", map[string]interface{}{"redact": map[string]interface{}{"projects": []string{"id"}}}, "has no field id"},
+		{"unknown custom field", map[string]interface{}{"redact": map[string]interface{}{"publications": []string{"isbn"}}}, "has no field isbn"},
+		{"profile field", map[string]interface{}{"redact": map[string]interface{}{"profile": []string{"visibility"}}}, "profile field visibility cannot be redacted"},
+		{"malformed", map[string]interface{}{"sections": "experience"}, "expected lists of sections"},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := core.NewRecord(tokens)
+			record.Set("view_id", viewID)
+			record.Set("token_hash", "hash-"+tt.name)
+			record.Set("token_prefix", "prefix"+string(rune('a'+i)))
+			record.Set("name", tt.name)
+			record.Set("is_active", true)
+			if tt.scope != nil {
+				record.Set("scope", tt.scope)
+			}
+
+			err := app.Save(record)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Save() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Save() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScopedShareTokenResponse(t *testing.T) {
+	app := newMigratedTestApp(t)
+	expID, _, viewID := seedImportFixture(t, app)
+
+	save := func(collection string, fields map[string]interface{}) *core.Record {
+		coll, err := app.FindCollectionByNameOrId(collection)
+		if err != nil {
+			t.Fatalf("Failed to find %s: %v", collection, err)
+		}
+		record := core.NewRecord(coll)
+		for key, value := range fields {
+			record.Set(key, value)
+		}
+		if err := app.Save(record); err != nil {
+			t.Fatalf("Failed to save %s: %v", collection, err)
+		}
+		return record
+	}
+
+	hidden := save("experience", map[string]interface{}{
+		"company":    "Difference Engines",
+		"title":      "Consultant",
+		"start_date": "1840-01-01 00:00:00.000Z",
+		"visibility": "public",
+	})
+
+	view, err := app.FindRecordById("views", viewID)
+	if err != nil {
+		t.Fatalf("Failed to find view: %v", err)
+	}
+	view.Set("sections", []map[string]interface{}{
+		{"section": "experience", "enabled": true, "items": []string{expID, hidden.Id}},
+		{"section": "projects", "enabled": true},
+	})
+	if err := app.Save(view); err != nil {
+		t.Fatalf("Failed to save view: %v", err)
+	}
+
+	profile, err := app.FindFirstRecordByFilter("profile", "")
+	if err != nil {
+		t.Fatalf("Failed to find profile: %v", err)
+	}
+	profile.Set("contact_email", "ada@example.com")
+	profile.Set("location", "London")
+	if err := app.Save(profile); err != nil {
+		t.Fatalf("Failed to save profile: %v", err)
+	}
+
+	token := save("share_tokens", map[string]interface{}{
+		"view_id":      viewID,
+		"token_hash":   "hash-agency",
+		"token_prefix": "agency",
+		"name":         "Agency",
+		"is_active":    true,
+		"scope": map[string]interface{}{
+			"sections":     []string{"experience"},
+			"hidden_items": []string{hidden.Id},
+			"redact": map[string]interface{}{
+				"experience": []string{"company"},
+				"profile":    []string{"contact_email"},
+			},
+		},
+	})
+
+	response := buildViewResponse(app, view, "recruiters", token, false)
+
+	if order := response["section_order"].([]string); len(order) != 1 || order[0] != "experience" {
+		t.Errorf("section_order = %v, want [experience]", order)
+	}
+	sections := response["sections"].(map[string]interface{})
+	if _, ok := sections["projects"]; ok {
+		t.Error("projects should be left out of a scope without it")
+	}
+	if _, ok := response["section_layouts"].(map[string]string)["projects"]; ok {
+		t.Error("section_layouts should not describe a section outside the scope")
+	}
+
+	items := sections["experience"].([]map[string]interface{})
+	if len(items) != 1 || items[0]["id"] != expID {
+		t.Fatalf("experience = %v, want only %s", items, expID)
+	}
+	if _, ok := items[0]["company"]; ok {
+		t.Errorf("company should be redacted, got %v", items[0])
+	}
+	if items[0]["title"] != "Programmer" {
+		t.Errorf("title = %v, want the unredacted title", items[0]["title"])
+	}
+
+	profileData := response["profile"].(map[string]interface{})
+	if _, ok := profileData["contact_email"]; ok {
+		t.Error("contact_email should be redacted from the profile")
+	}
+	if profileData["location"] != "London" {
+		t.Errorf("location = %v, want London", profileData["location"])
+	}
+
+	// Rendered resumes get the same scope
+	resumeData, err := collectScopedViewData(app, view, token)
+	if err != nil {
+		t.Fatalf("collectScopedViewData() error = %v", err)
+	}
+	if len(resumeData.SectionOrder) != 1 || resumeData.SectionOrder[0] != "experience" {
+		t.Errorf("resume section order = %v, want [experience]", resumeData.SectionOrder)
+	}
+	if _, ok := resumeData.Sections["projects"]; ok {
+		t.Error("resume data should leave out projects")
+	}
+	if got := resumeData.Sections["experience"]; len(got) != 1 || got[0]["id"] != expID || got[0]["company"] != nil {
+		t.Errorf("resume experience = %v, want only %s without its company", got, expID)
+	}
+	if _, ok := resumeData.Profile["contact_email"]; ok {
+		t.Error("contact_email should be redacted from resume data")
+	}
+
+	// Without a token the whole view is returned
+	unscoped := buildViewResponse(app, view, "recruiters", nil, false)
+	if order := unscoped["section_order"].([]string); len(order) != 2 {
+		t.Errorf("unscoped section_order = %v, want both sections", order)
+	}
+	if got := unscoped["sections"].(map[string]interface{})["experience"].([]map[string]interface{}); len(got) != 2 || got[0]["company"] == nil {
+		t.Errorf("unscoped experience = %v, want both items with their company", got)
+	}
+	if unscoped["profile"].(map[string]interface{})["contact_email"] != "ada@example.com" {
+		t.Error("unscoped profile should keep contact_email")
+	}
+}
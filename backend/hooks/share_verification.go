@@ -0,0 +1,270 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/mail"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"facet/services"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// shareSessionHeader carries the session of a verified recipient of a
+// recipient-bound share token, next to the token itself in X-Share-Token
+const shareSessionHeader = "X-Share-Session"
+
+var (
+	errShareVerificationInvalid = errors.New("invalid or expired code")
+	errShareTokenUsedUp         = errors.New("share token has no uses left")
+	errShareVerificationLimited = errors.New("too many verification requests")
+	errAppURLNotConfigured      = errors.New("APP_URL is not configured")
+)
+
+// configuredAppURL returns the public URL that emailed links are built from:
+// APP_URL, else the app URL in the PocketBase settings. Unlike resolveBaseURL
+// it never trusts request headers, since a spoofed Host would send the
+// recipient a link, secrets included, to someone else's site.
+func configuredAppURL(app core.App) string {
+	for _, candidate := range []string{os.Getenv("APP_URL"), app.Settings().Meta.AppURL} {
+		if candidate = strings.TrimSpace(candidate); candidate != "" {
+			return strings.TrimSuffix(candidate, "/")
+		}
+	}
+	return ""
+}
+
+// isRecipientBound reports whether opening a share token needs its recipient
+// to confirm their email first
+func isRecipientBound(tokenRecord *core.Record) bool {
+	return tokenRecord.GetString("recipient_email") != ""
+}
+
+// normalizeRecipientEmail checks an address given for a recipient-bound token
+// and returns it lowercased
+func normalizeRecipientEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || !strings.Contains(email[strings.LastIndex(email, "@"):], ".") {
+		return "", fmt.Errorf("invalid recipient email")
+	}
+	return strings.ToLower(email), nil
+}
+
+// hasShareSession reports whether the request carries a verified session for
+// the recipient-bound token
+func hasShareSession(e *core.RequestEvent, crypto *services.CryptoService, tokenRecord *core.Record) bool {
+	claims, err := crypto.ValidateShareSessionJWT(e.Request.Header.Get(shareSessionHeader))
+	if err != nil {
+		return false
+	}
+	return claims.ShareTokenID == tokenRecord.Id && claims.ViewID == tokenRecord.GetString("view_id")
+}
+
+// recentShareVerifications returns the codes sent for a token within the
+// verification window and the wrong codes entered against them. Both survive
+// new code requests, so asking again neither sends unlimited emails nor
+// buys fresh guesses.
+func recentShareVerifications(app core.App, tokenID string, now time.Time) ([]*core.Record, int, error) {
+	records, err := app.FindRecordsByFilter(
+		"share_verifications",
+		"share_token = {:token} && created >= {:since}",
+		"",
+		0,
+		0,
+		map[string]interface{}{"token": tokenID, "since": windowStart(now).String()},
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	failures := 0
+	for _, record := range records {
+		failures += record.GetInt("attempts")
+	}
+	return records, failures, nil
+}
+
+// windowStart is the start of the verification window ending at now
+func windowStart(now time.Time) types.DateTime {
+	start, _ := types.ParseDateTime(now.Add(-services.ShareVerificationWindow))
+	return start
+}
+
+// startShareVerification emails a one-time code and a magic link to the
+// recipient of a share token. Earlier pending codes for the token stop working.
+func startShareVerification(app core.App, share *services.ShareService, counters *services.CounterService, tokenRecord *core.Record, rawToken, baseURL, viewName string) error {
+	if baseURL == "" {
+		return errAppURLNotConfigured
+	}
+
+	// Bound tokens count verified sessions, so max_uses caps new sessions
+	useCount := tokenRecord.GetInt("use_count") + counters.PendingTokenUses(tokenRecord.Id)
+	if maxUses := tokenRecord.GetInt("max_uses"); maxUses > 0 && useCount >= maxUses {
+		return errShareTokenUsedUp
+	}
+
+	now := time.Now()
+	recent, failures, err := recentShareVerifications(app, tokenRecord.Id, now)
+	if err != nil {
+		return err
+	}
+	if len(recent) >= services.ShareVerificationMaxCodes || failures >= services.ShareVerificationMaxFailures {
+		return errShareVerificationLimited
+	}
+
+	// Earlier codes are expired rather than deleted so they still count above;
+	// codes from before the window no longer matter
+	for _, record := range recent {
+		if record.GetDateTime("verified_at").IsZero() && now.Before(record.GetDateTime("expires_at").Time()) {
+			record.Set("expires_at", now)
+			if err := app.Save(record); err != nil {
+				return err
+			}
+		}
+	}
+	stale, err := app.FindRecordsByFilter(
+		"share_verifications",
+		"share_token = {:token} && created < {:since}",
+		"",
+		0,
+		0,
+		map[string]interface{}{"token": tokenRecord.Id, "since": windowStart(now).String()},
+	)
+	if err != nil {
+		return err
+	}
+	for _, record := range stale {
+		if err := app.Delete(record); err != nil {
+			return err
+		}
+	}
+
+	code, err := share.GenerateVerificationCode()
+	if err != nil {
+		return err
+	}
+	link, err := share.GenerateToken()
+	if err != nil {
+		return err
+	}
+
+	collection, err := app.FindCollectionByNameOrId("share_verifications")
+	if err != nil {
+		return err
+	}
+	record := core.NewRecord(collection)
+	record.Set("share_token", tokenRecord.Id)
+	record.Set("code_hash", share.HMACToken(code))
+	record.Set("link_hash", share.HMACToken(link))
+	record.Set("expires_at", now.Add(services.ShareVerificationTTL))
+	record.Set("attempts", 0)
+	if err := app.Save(record); err != nil {
+		return err
+	}
+
+	magicLink := baseURL + "/s/" + url.PathEscape(rawToken) + "?verify=" + url.QueryEscape(link)
+	return sendShareVerificationEmail(app, tokenRecord.GetString("recipient_email"), viewName, code, magicLink)
+}
+
+// confirmShareVerification checks an emailed code, or the secret of the magic
+// link, against the pending verification of a token. A successful check uses
+// up the verification and counts one use of the token.
+func confirmShareVerification(app core.App, share *services.ShareService, counters *services.CounterService, tokenRecord *core.Record, code, link string) error {
+	var record *core.Record
+	var err error
+	if link != "" {
+		record, err = app.FindFirstRecordByFilter(
+			"share_verifications",
+			"link_hash = {:hash} && share_token = {:token}",
+			map[string]interface{}{"hash": share.HMACToken(link), "token": tokenRecord.Id},
+		)
+	} else {
+		var records []*core.Record
+		records, err = app.FindRecordsByFilter(
+			"share_verifications",
+			"share_token = {:token} && verified_at = '' && expires_at > {:now}",
+			"-created",
+			1,
+			0,
+			map[string]interface{}{"token": tokenRecord.Id, "now": types.NowDateTime().String()},
+		)
+		if err == nil && len(records) > 0 {
+			record = records[0]
+		}
+	}
+	if err != nil || record == nil {
+		return errShareVerificationInvalid
+	}
+
+	if !record.GetDateTime("verified_at").IsZero() ||
+		!time.Now().Before(record.GetDateTime("expires_at").Time()) ||
+		record.GetInt("attempts") >= services.ShareVerificationMaxAttempts {
+		return errShareVerificationInvalid
+	}
+
+	// Wrong codes add up across every code sent in the window
+	if link == "" {
+		_, failures, err := recentShareVerifications(app, tokenRecord.Id, time.Now())
+		if err != nil {
+			return err
+		}
+		if failures >= services.ShareVerificationMaxFailures {
+			return errShareVerificationInvalid
+		}
+	}
+
+	if link == "" && !share.ValidateTokenHMAC(strings.TrimSpace(code), record.GetString("code_hash")) {
+		record.Set("attempts", record.GetInt("attempts")+1)
+		if err := app.Save(record); err != nil {
+			return err
+		}
+		return errShareVerificationInvalid
+	}
+
+	record.Set("verified_at", time.Now())
+	if err := app.Save(record); err != nil {
+		return err
+	}
+	counters.IncrementTokenUse(tokenRecord.Id)
+	return nil
+}
+
+// shareSessionDuration is how long a verified recipient keeps access: a week,
+// but never past the expiry of the token itself
+func shareSessionDuration(tokenRecord *core.Record, now time.Time) time.Duration {
+	duration := services.ShareSessionDuration
+	if expiresAt := tokenRecord.GetDateTime("expires_at"); !expiresAt.IsZero() {
+		if remaining := expiresAt.Time().Sub(now); remaining < duration {
+			duration = remaining
+		}
+	}
+	return duration
+}
+
+// sendShareVerificationEmail sends the recipient of a share token their code and link
+func sendShareVerificationEmail(app core.App, to, viewName, code, magicLink string) error {
+	meta := app.Settings().Meta
+	subject := fmt.Sprintf("Your code to view %s", viewName)
+	body := fmt.Sprintf(
+		`<p>Someone shared <strong>%s</strong> with you.</p>
+<p>Enter this code to open it: <strong style="font-size:1.25em;letter-spacing:0.1em">%s</strong></p>
+<p>Or <a href="%s">open the link directly</a>.</p>
+<p>The code expires in %d minutes. If you did not expect this email, you can ignore it.</p>`,
+		html.EscapeString(viewName), code, html.EscapeString(magicLink), int(services.ShareVerificationTTL.Minutes()),
+	)
+
+	return app.NewMailClient().Send(&mailer.Message{
+		From:    mail.Address{Address: meta.SenderAddress, Name: meta.SenderName},
+		To:      []mail.Address{{Address: to}},
+		Subject: subject,
+		HTML:    body,
+	})
+}
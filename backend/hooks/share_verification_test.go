@@ -0,0 +1,286 @@
+package hooks
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"facet/services"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestRecipientBoundShareVerification(t *testing.T) {
+	app := newMigratedTestApp(t)
+	_, _, viewID := seedImportFixture(t, app)
+
+	crypto := services.NewCryptoService("test-encryption-key-32-chars-ok!")
+	share := services.NewShareService(crypto)
+	counters := services.NewCounterService(time.Hour)
+
+	var sent []*mailer.Message
+	app.OnMailerSend().BindFunc(func(e *core.MailerEvent) error {
+		sent = append(sent, e.Message)
+		return nil
+	})
+
+	rawToken, _ := share.GenerateToken()
+	tokens, err := app.FindCollectionByNameOrId("share_tokens")
+	if err != nil {
+		t.Fatalf("Failed to find share_tokens: %v", err)
+	}
+	token := core.NewRecord(tokens)
+	token.Set("view_id", viewID)
+	token.Set("token_hash", share.HMACToken(rawToken))
+	token.Set("token_prefix", share.TokenPrefix(rawToken))
+	token.Set("is_active", true)
+	token.Set("max_uses", 3)
+	token.Set("recipient_email", "grace@example.com")
+	if err := app.Save(token); err != nil {
+		t.Fatalf("Failed to save token: %v", err)
+	}
+	if !isRecipientBound(token) {
+		t.Fatal("token with a recipient email should be recipient-bound")
+	}
+
+	// Links are only built from the configured app URL, never the request
+	t.Setenv("APP_URL", "")
+	app.Settings().Meta.AppURL = ""
+	if got := configuredAppURL(app); got != "" {
+		t.Errorf("configuredAppURL() = %q, want empty when nothing is configured", got)
+	}
+	if err := startShareVerification(app, share, counters, token, rawToken, configuredAppURL(app), "Recruiters"); !errors.Is(err, errAppURLNotConfigured) || len(sent) != 0 {
+		t.Errorf("start without an app URL error = %v, %d emails; want not configured and no email", err, len(sent))
+	}
+	app.Settings().Meta.AppURL = "https://settings.example.com/"
+	if got := configuredAppURL(app); got != "https://settings.example.com" {
+		t.Errorf("configuredAppURL() = %q, want the settings URL", got)
+	}
+	t.Setenv("APP_URL", "https://cv.example.com")
+	if got := configuredAppURL(app); got != "https://cv.example.com" {
+		t.Errorf("configuredAppURL() = %q, want APP_URL first", got)
+	}
+
+	codePattern := regexp.MustCompile(`\b(\d{6})\b`)
+	linkPattern := regexp.MustCompile(`verify=([^"&]+)`)
+	start := func() (code, link string) {
+		t.Helper()
+		if err := startShareVerification(app, share, counters, token, rawToken, "https://cv.example.com", "Recruiters"); err != nil {
+			t.Fatalf("startShareVerification() error = %v", err)
+		}
+		message := sent[len(sent)-1]
+		if len(message.To) != 1 || message.To[0].Address != "grace@example.com" {
+			t.Fatalf("email sent to %v, want the recipient", message.To)
+		}
+		code = codePattern.FindStringSubmatch(message.HTML)[1]
+		escaped := linkPattern.FindStringSubmatch(message.HTML)[1]
+		link, _ = url.QueryUnescape(escaped)
+		return code, link
+	}
+
+	code, _ := start()
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	if err := confirmShareVerification(app, share, counters, token, wrong, ""); !errors.Is(err, errShareVerificationInvalid) {
+		t.Fatalf("wrong code error = %v, want invalid", err)
+	}
+	if err := confirmShareVerification(app, share, counters, token, code, ""); err != nil {
+		t.Fatalf("confirmShareVerification() error = %v", err)
+	}
+	if uses := counters.PendingTokenUses(token.Id); uses != 1 {
+		t.Errorf("pending uses = %d, want 1 after one verified session", uses)
+	}
+	if err := confirmShareVerification(app, share, counters, token, code, ""); !errors.Is(err, errShareVerificationInvalid) {
+		t.Errorf("reused code error = %v, want invalid", err)
+	}
+
+	// A new request replaces the pending code; the magic link works on its own
+	oldCode, _ := start()
+	_, link := start()
+	if err := confirmShareVerification(app, share, counters, token, oldCode, ""); !errors.Is(err, errShareVerificationInvalid) {
+		t.Errorf("replaced code error = %v, want invalid", err)
+	}
+	if err := confirmShareVerification(app, share, counters, token, "", link); err != nil {
+		t.Fatalf("magic link error = %v", err)
+	}
+	if uses := counters.PendingTokenUses(token.Id); uses != 2 {
+		t.Errorf("pending uses = %d, want 2", uses)
+	}
+
+	// Too many wrong codes burn the verification
+	code, _ = start()
+	for i := 0; i < services.ShareVerificationMaxAttempts; i++ {
+		confirmShareVerification(app, share, counters, token, wrong, "")
+	}
+	if err := confirmShareVerification(app, share, counters, token, code, ""); !errors.Is(err, errShareVerificationInvalid) {
+		t.Errorf("code after too many attempts error = %v, want invalid", err)
+	}
+
+	// Expired codes are rejected
+	code, _ = start()
+	pending, err := app.FindFirstRecordByFilter("share_verifications", "share_token = {:token} && verified_at = '' && expires_at > {:now}", map[string]interface{}{"token": token.Id, "now": types.NowDateTime().String()})
+	if err != nil {
+		t.Fatalf("Failed to find pending verification: %v", err)
+	}
+	pending.Set("expires_at", time.Now().Add(-time.Minute))
+	if err := app.Save(pending); err != nil {
+		t.Fatalf("Failed to expire verification: %v", err)
+	}
+	if err := confirmShareVerification(app, share, counters, token, code, ""); !errors.Is(err, errShareVerificationInvalid) {
+		t.Errorf("expired code error = %v, want invalid", err)
+	}
+
+	// max_uses caps the number of verified sessions
+	code, _ = start()
+	if err := confirmShareVerification(app, share, counters, token, code, ""); err != nil {
+		t.Fatalf("third session error = %v", err)
+	}
+	if err := startShareVerification(app, share, counters, token, rawToken, "https://cv.example.com", "Recruiters"); !errors.Is(err, errShareTokenUsedUp) {
+		t.Errorf("start after max uses error = %v, want used up", err)
+	}
+}
+
+func TestShareVerificationLimits(t *testing.T) {
+	app := newMigratedTestApp(t)
+	_, _, viewID := seedImportFixture(t, app)
+
+	crypto := services.NewCryptoService("test-encryption-key-32-chars-ok!")
+	share := services.NewShareService(crypto)
+	counters := services.NewCounterService(time.Hour)
+
+	var sent []*mailer.Message
+	app.OnMailerSend().BindFunc(func(e *core.MailerEvent) error {
+		sent = append(sent, e.Message)
+		return nil
+	})
+
+	rawToken, _ := share.GenerateToken()
+	tokens, _ := app.FindCollectionByNameOrId("share_tokens")
+	token := core.NewRecord(tokens)
+	token.Set("view_id", viewID)
+	token.Set("token_hash", share.HMACToken(rawToken))
+	token.Set("token_prefix", share.TokenPrefix(rawToken))
+	token.Set("is_active", true)
+	token.Set("recipient_email", "grace@example.com")
+	if err := app.Save(token); err != nil {
+		t.Fatalf("Failed to save token: %v", err)
+	}
+
+	start := func() error {
+		return startShareVerification(app, share, counters, token, rawToken, "https://cv.example.com", "Recruiters")
+	}
+	codePattern := regexp.MustCompile(`\b(\d{6})\b`)
+	wrongCode := func() string {
+		if codePattern.FindStringSubmatch(sent[len(sent)-1].HTML)[1] == "000000" {
+			return "111111"
+		}
+		return "000000"
+	}
+
+	// Wrong codes add up across new code requests
+	for i := 0; i*services.ShareVerificationMaxAttempts < services.ShareVerificationMaxFailures; i++ {
+		if err := start(); err != nil {
+			t.Fatalf("start %d error = %v", i, err)
+		}
+		wrong := wrongCode()
+		for j := 0; j < services.ShareVerificationMaxAttempts; j++ {
+			confirmShareVerification(app, share, counters, token, wrong, "")
+		}
+	}
+	emails := len(sent)
+	if err := start(); !errors.Is(err, errShareVerificationLimited) || len(sent) != emails {
+		t.Errorf("start after too many wrong codes error = %v, %d new emails; want limited and none", err, len(sent)-emails)
+	}
+
+	// A new window starts over
+	backdate := func() {
+		t.Helper()
+		old, _ := types.ParseDateTime(time.Now().Add(-services.ShareVerificationWindow - time.Minute))
+		if _, err := app.DB().NewQuery("UPDATE {{share_verifications}} SET [[created]] = {:at}").Bind(map[string]interface{}{"at": old.String()}).Execute(); err != nil {
+			t.Fatalf("Failed to backdate verifications: %v", err)
+		}
+	}
+	backdate()
+	if err := start(); err != nil {
+		t.Fatalf("start in a new window error = %v", err)
+	}
+	if stale, _ := app.FindRecordsByFilter("share_verifications", "share_token = {:token}", "", 0, 0, map[string]interface{}{"token": token.Id}); len(stale) != 1 {
+		t.Errorf("%d verifications kept, want only the new one", len(stale))
+	}
+
+	// Only so many codes are emailed per window
+	for i := 1; i < services.ShareVerificationMaxCodes; i++ {
+		if err := start(); err != nil {
+			t.Fatalf("start %d error = %v", i, err)
+		}
+	}
+	emails = len(sent)
+	if err := start(); !errors.Is(err, errShareVerificationLimited) || len(sent) != emails {
+		t.Errorf("start past the code limit error = %v, %d new emails; want limited and none", err, len(sent)-emails)
+	}
+}
+
+func TestShareSessionForToken(t *testing.T) {
+	crypto := services.NewCryptoService("test-encryption-key-32-chars-ok!")
+	app := newMigratedTestApp(t)
+
+	tokens, err := app.FindCollectionByNameOrId("share_tokens")
+	if err != nil {
+		t.Fatalf("Failed to find share_tokens: %v", err)
+	}
+	token := core.NewRecord(tokens)
+	token.Id = "token1234567890"
+	token.Set("view_id", "view12345678901")
+	token.Set("recipient_email", "grace@example.com")
+
+	request := func(session string) *core.RequestEvent {
+		e := &core.RequestEvent{App: app}
+		e.Request = httptest.NewRequest(http.MethodGet, "/api/view/recruiters/data", nil)
+		if session != "" {
+			e.Request.Header.Set(shareSessionHeader, session)
+		}
+		return e
+	}
+
+	session, _, _ := crypto.GenerateShareSessionJWT("view12345678901", token.Id, time.Hour)
+	if !hasShareSession(request(session), crypto, token) {
+		t.Error("session for the token should be accepted")
+	}
+	if hasShareSession(request(""), crypto, token) {
+		t.Error("missing session should be rejected")
+	}
+	otherToken, _, _ := crypto.GenerateShareSessionJWT("view12345678901", "other1234567890", time.Hour)
+	if hasShareSession(request(otherToken), crypto, token) {
+		t.Error("session of another token should be rejected")
+	}
+	viewAccess, _, _ := crypto.GenerateViewAccessJWT("view12345678901", time.Hour)
+	if hasShareSession(request(viewAccess), crypto, token) {
+		t.Error("password access token should not count as a share session")
+	}
+
+	now := time.Now()
+	if got := shareSessionDuration(token, now); got != services.ShareSessionDuration {
+		t.Errorf("shareSessionDuration() = %v, want %v without token expiry", got, services.ShareSessionDuration)
+	}
+	token.Set("expires_at", now.Add(2*time.Hour))
+	if got := shareSessionDuration(token, now); got > 2*time.Hour || got < 2*time.Hour-time.Second {
+		t.Errorf("shareSessionDuration() = %v, want capped at the token expiry", got)
+	}
+
+	if got, err := normalizeRecipientEmail(" Grace@Example.com "); err != nil || got != "grace@example.com" {
+		t.Errorf("normalizeRecipientEmail() = %q, %v; want grace@example.com", got, err)
+	}
+	for _, input := range []string{"", "grace", "grace@example", "Grace <grace@example.com>"} {
+		if _, err := normalizeRecipientEmail(input); err == nil {
+			t.Errorf("normalizeRecipientEmail(%q) should fail", input)
+		}
+	}
+}
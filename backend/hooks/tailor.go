@@ -0,0 +1,219 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"facet/services"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const maxJobDescriptionLength = 20000
+
+// RegisterTailorHooks registers the job-description tailoring endpoint
+func RegisterTailorHooks(app *pocketbase.PocketBase, ai *services.AIService, crypto *services.CryptoService) {
+	tailor := services.NewTailorService(ai)
+
+	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
+		// Score a view's content against a job description and save the proposal as a
+		// new inactive view for review in the view editor
+		// POST /api/views/{id}/tailor
+		se.Router.POST("/api/views/{id}/tailor", func(e *core.RequestEvent) error {
+			var req struct {
+				JobDescription string `json:"job_description"`
+				Mode           string `json:"mode"` // keyword (default) or ai
+				ProviderID     string `json:"provider_id"`
+				Name           string `json:"name"`
+			}
+			if err := e.BindBody(&req); err != nil {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+			}
+
+			req.JobDescription = strings.TrimSpace(req.JobDescription)
+			if req.JobDescription == "" {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "job_description is required"})
+			}
+			if len(req.JobDescription) > maxJobDescriptionLength {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "job_description is too long (max 20000 characters)"})
+			}
+			if req.Mode == "" {
+				req.Mode = services.TailorModeKeyword
+			}
+			if req.Mode != services.TailorModeKeyword && req.Mode != services.TailorModeAI {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "mode must be 'keyword' or 'ai'"})
+			}
+
+			source, err := app.FindRecordById("views", e.Request.PathValue("id"))
+			if err != nil {
+				return e.JSON(http.StatusNotFound, map[string]string{"error": "view not found"})
+			}
+
+			sourceSections, pool, err := tailorPool(app, source)
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to collect view data"})
+			}
+			candidates := services.TailorCandidates(pool)
+			if len(candidates) == 0 {
+				return e.JSON(http.StatusBadRequest, map[string]string{"error": "this view has no experience, projects, skills or talks to tailor"})
+			}
+
+			var scores []services.TailorScore
+			if req.Mode == services.TailorModeAI {
+				provider, err := getActiveProvider(app, crypto, req.ProviderID)
+				if err != nil {
+					return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+				}
+
+				ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+				defer cancel()
+
+				scores, err = tailor.ScoreWithAI(ctx, provider, req.JobDescription, candidates)
+				if err != nil {
+					log.Printf("[TAILOR] AI scoring failed for view %s: %v", source.Id, err)
+					return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				}
+			} else {
+				scores = services.ScoreByKeywords(req.JobDescription, candidates)
+			}
+
+			sections := services.ProposeTailoredSections(sourceSections, scores)
+
+			view, err := createTailoredView(app, source, req.Name, sections)
+			if err != nil {
+				log.Printf("[TAILOR] Failed to create tailored view from %s: %v", source.Id, err)
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to create tailored view"})
+			}
+
+			return e.JSON(http.StatusOK, map[string]interface{}{
+				"view": map[string]interface{}{
+					"id":        view.Id,
+					"name":      view.GetString("name"),
+					"slug":      view.GetString("slug"),
+					"is_active": false,
+				},
+				"mode":     req.Mode,
+				"sections": sections,
+				"ranking":  rankTailorScores(scores),
+			})
+		}).Bind(apis.RequireAuth())
+
+		return se.Next()
+	})
+}
+
+// tailorPool collects everything the source view could show in the tailorable
+// sections, not just the items it currently selects, with its overrides applied.
+// It also returns the source view's sections.
+func tailorPool(app core.App, source *core.Record) ([]map[string]interface{}, *services.ViewData, error) {
+	var sections []map[string]interface{}
+	if raw := source.GetString("sections"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &sections); err != nil {
+			return nil, nil, fmt.Errorf("parse sections: %w", err)
+		}
+	}
+
+	tailorable := make(map[string]bool, len(services.TailorSections))
+	for _, name := range services.TailorSections {
+		tailorable[name] = true
+	}
+
+	var probeSections []map[string]interface{}
+	for _, section := range sections {
+		name, _ := section["section"].(string)
+		if !tailorable[name] {
+			continue
+		}
+		delete(tailorable, name)
+		probeSections = append(probeSections, map[string]interface{}{
+			"section":    name,
+			"enabled":    true,
+			"itemConfig": section["itemConfig"],
+		})
+	}
+	for _, name := range services.TailorSections {
+		if tailorable[name] {
+			probeSections = append(probeSections, map[string]interface{}{"section": name, "enabled": true})
+		}
+	}
+
+	probe := source.Clone()
+	probe.Set("sections", probeSections)
+	data, err := collectViewData(app, probe)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sections, data, nil
+}
+
+// createTailoredView saves the proposed sections as an inactive copy of the source view
+func createTailoredView(app core.App, source *core.Record, name string, sections []map[string]interface{}) (*core.Record, error) {
+	collection, err := app.FindCollectionByNameOrId("views")
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Tailored: " + source.GetString("name")
+	}
+	if runes := []rune(name); len(runes) > 200 {
+		name = string(runes[:200])
+	}
+
+	base := generateSlug(source.GetString("slug") + "-tailored")
+	if base == "" || !isValidSlug(base) {
+		base = "tailored-view"
+	}
+	slug := base
+	for i := 2; ; i++ {
+		if _, err := app.FindFirstRecordByData("views", "slug", slug); err != nil {
+			break
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+
+	view := core.NewRecord(collection)
+	for _, field := range []string{"hero_headline", "hero_summary", "cta_text", "cta_url", "accent_color"} {
+		if collection.Fields.GetByName(field) != nil {
+			view.Set(field, source.Get(field))
+		}
+	}
+	view.Set("name", name)
+	view.Set("slug", slug)
+	view.Set("description", fmt.Sprintf("Tailored from %q for a job description. Review before activating.", source.GetString("name")))
+	view.Set("visibility", "unlisted")
+	view.Set("is_active", false)
+	view.Set("is_default", false)
+	view.Set("sections", sections)
+
+	if err := app.Save(view); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// rankTailorScores orders scores for display: by section, then most relevant first
+func rankTailorScores(scores []services.TailorScore) []services.TailorScore {
+	sectionOrder := make(map[string]int, len(services.TailorSections))
+	for i, name := range services.TailorSections {
+		sectionOrder[name] = i
+	}
+
+	ranked := append([]services.TailorScore(nil), scores...)
+	sort.SliceStable(ranked, func(a, b int) bool {
+		if ranked[a].Section != ranked[b].Section {
+			return sectionOrder[ranked[a].Section] < sectionOrder[ranked[b].Section]
+		}
+		return ranked[a].Score > ranked[b].Score
+	})
+	return ranked
+}
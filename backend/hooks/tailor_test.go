@@ -0,0 +1,60 @@
+package hooks
+
+import (
+	"testing"
+
+	"facet/services"
+)
+
+func TestTailoredViewFromSource(t *testing.T) {
+	app := newMigratedTestApp(t)
+	expID, projID, viewID := seedImportFixture(t, app)
+
+	source, err := app.FindRecordById("views", viewID)
+	if err != nil {
+		t.Fatalf("Failed to find view: %v", err)
+	}
+
+	sections, pool, err := tailorPool(app, source)
+	if err != nil {
+		t.Fatalf("tailorPool() error = %v", err)
+	}
+	if len(pool.Sections["experience"]) != 1 || pool.Sections["experience"][0]["title"] != "Lead Programmer" {
+		t.Fatalf("pool experience = %v, want the source view's overrides applied", pool.Sections["experience"])
+	}
+
+	scores := services.ScoreByKeywords("Programmer to write the first program for Note G", services.TailorCandidates(pool))
+	proposed := services.ProposeTailoredSections(sections, scores)
+
+	first, err := createTailoredView(app, source, "", proposed)
+	if err != nil {
+		t.Fatalf("createTailoredView() error = %v", err)
+	}
+	second, err := createTailoredView(app, source, "Acme application", proposed)
+	if err != nil {
+		t.Fatalf("createTailoredView() error = %v", err)
+	}
+
+	if first.GetString("slug") != "recruiters-tailored" || second.GetString("slug") != "recruiters-tailored-2" {
+		t.Errorf("slugs = %q, %q", first.GetString("slug"), second.GetString("slug"))
+	}
+	if first.GetString("name") != "Tailored: Recruiters" || second.GetString("name") != "Acme application" {
+		t.Errorf("names = %q, %q", first.GetString("name"), second.GetString("name"))
+	}
+	if first.GetBool("is_active") || first.GetBool("is_default") {
+		t.Error("tailored view should be created as an inactive draft")
+	}
+
+	saved, _ := app.FindRecordById("views", first.Id)
+	data, err := collectViewData(app, saved)
+	if err != nil {
+		t.Fatalf("collectViewData() error = %v", err)
+	}
+	experience := data.Sections["experience"]
+	if len(experience) != 1 || experience[0]["id"] != expID || experience[0]["title"] != "Lead Programmer" {
+		t.Errorf("tailored experience = %v", experience)
+	}
+	if projects := data.Sections["projects"]; len(projects) != 1 || projects[0]["id"] != projID {
+		t.Errorf("tailored projects = %v", projects)
+	}
+}
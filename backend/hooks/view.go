@@ -13,6 +13,7 @@ import (
 
 	"facet/services"
 
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/apis"
 	"github.com/pocketbase/pocketbase/core"
@@ -22,14 +23,22 @@ import (
 // getTableName returns the correct table name based on demo mode
 // If demo mode is ON, returns "demo_<collection>", otherwise returns "<collection>"
 func getTableName(app core.App, collection string) string {
-	// Check if demo data exists (indicates demo mode is ON)
-	demoProfile, _ := app.FindFirstRecordByFilter("demo_profile", "")
-	if demoProfile != nil {
+	if isDemoModeEnabled(app) {
 		return "demo_" + collection
 	}
 	return collection
 }
 
+// isDemoModeEnabled reports whether demo data exists (indicates demo mode is ON).
+// Cached until a demo_profile record is created, updated or deleted.
+func isDemoModeEnabled(app core.App) bool {
+	enabled, _ := services.CachedValue(app, "facet.demoMode", []string{"demo_profile"}, func() (bool, error) {
+		demoProfile, _ := app.FindFirstRecordByFilter("demo_profile", "")
+		return demoProfile != nil, nil
+	})
+	return enabled
+}
+
 // fetchExternalMedia safely loads external_media records by IDs without relying on expand rules.
 func fetchExternalMedia(app core.App, ids []string) ([]map[string]interface{}, error) {
 	if len(ids) == 0 {
@@ -207,7 +216,7 @@ func fileURL(collectionID, recordID, filename string, transform string) string {
 }
 
 // RegisterViewHooks registers view-related API endpoints
-func RegisterViewHooks(app *pocketbase.PocketBase, crypto *services.CryptoService, share *services.ShareService, rl *services.RateLimitService) {
+func RegisterViewHooks(app *pocketbase.PocketBase, crypto *services.CryptoService, share *services.ShareService, rl *services.RateLimitService, access *services.AccessLogService, counters *services.CounterService, cache *services.ResponseCache) {
 	// Register views collection hooks for validation
 	registerViewsValidation(app, crypto)
 
@@ -242,14 +251,29 @@ func RegisterViewHooks(app *pocketbase.PocketBase, crypto *services.CryptoServic
 			visibility := view.GetString("visibility")
 
 			isAuthenticated := e.Auth != nil
+
+			// Private views are not discoverable: same 404 as a missing slug, unless
+			// the view takes access requests or the caller holds an approved link
+			if visibility == "private" && !isAuthenticated {
+				opensWithToken := false
+				if valid, tokenRecord := validateShareToken(app, share, counters, extractShareToken(e), view.Id); valid {
+					opensWithToken = viewsCollection == "views" && grantsPrivateAccess(app, tokenRecord)
+				}
+				if !opensWithToken && (viewsCollection != "views" || !acceptsAccessRequests(view)) {
+					return e.JSON(http.StatusNotFound, map[string]string{"error": "view not found"})
+				}
+			}
+
 			return e.JSON(http.StatusOK, map[string]interface{}{
 				"view_id":           view.Id,
 				"view_name":         view.GetString("name"),
 				"slug":              slug,
 				"visibility":        visibility,
 				"requires_password": visibility == "password" && !isAuthenticated,
-				"requires_token":    visibility == "unlisted" && !isAuthenticated,
+				"requires_token":    (visibility == "unlisted" || visibility == "private") && !isAuthenticated,
 				"is_authenticated":  isAuthenticated,
+				// Requests go to the real views only, not the demo tables
+				"accepts_access_requests": viewsCollection == "views" && acceptsAccessRequests(view) && !isAuthenticated,
 			})
 		}))
 
@@ -259,10 +283,7 @@ func RegisterViewHooks(app *pocketbase.PocketBase, crypto *services.CryptoServic
 			slug := e.Request.PathValue("slug")
 
 			// Check if demo mode is enabled
-			isDemoMode := false
-			if demoProfile, _ := app.FindFirstRecordByFilter("demo_profile", ""); demoProfile != nil {
-				isDemoMode = true
-			}
+			isDemoMode := isDemoModeEnabled(app)
 
 			// Use demo-aware collection name
 			viewsCollection := "views"
@@ -287,255 +308,43 @@ func RegisterViewHooks(app *pocketbase.PocketBase, crypto *services.CryptoServic
 			visibility := view.GetString("visibility")
 			shouldCountView := e.Auth == nil
 
-			// Check access based on visibility
-			switch visibility {
-			case "private":
-				// Private views return 404 to prevent leaking existence
-				// Only authenticated admin users can access private views
-				if e.Auth == nil {
-					return e.JSON(http.StatusNotFound, map[string]string{"error": "view not found"})
-				}
-
-			case "password":
-				if e.Auth != nil {
-					break
-				}
-				token := extractPasswordToken(e)
-				if token == "" {
-					return e.JSON(http.StatusUnauthorized, map[string]string{"error": "password token required"})
-				}
-
-				viewID, err := crypto.ValidateViewAccessJWT(token)
-				if err != nil {
-					return e.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid or expired token"})
-				}
-
-				if viewID != view.Id {
-					return e.JSON(http.StatusUnauthorized, map[string]string{"error": "token not valid for this view"})
-				}
-
-			case "unlisted":
-				if e.Auth != nil {
-					break
-				}
-				shareToken := extractShareToken(e)
-				if shareToken == "" {
-					return e.JSON(http.StatusUnauthorized, map[string]string{"error": "share token required"})
-				}
-
-				valid, tokenRecord := validateShareToken(app, share, shareToken, view.Id)
-				if !valid {
-					return e.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid or expired share token"})
-				}
-
-				if tokenRecord != nil {
-					useCount := tokenRecord.GetInt("use_count")
-					tokenRecord.Set("use_count", useCount+1)
-					tokenRecord.Set("last_used_at", time.Now())
-					app.Save(tokenRecord)
-				}
-
-			case "public":
-				// Public views are accessible to everyone
+			// Share token used to open the view, if any (for application personalization)
+			shareRecord, denied := authorizeViewAccess(app, crypto, share, counters, e, view, true)
+			if denied != nil {
+				return e.JSON(denied.Status, denied.Body)
 			}
 
 			if shouldCountView {
-				// Increment view count and last_viewed_at in the background
-				go func(viewID string, collection string) {
-					record, err := app.FindRecordById(collection, viewID)
-					if err != nil {
-						return
-					}
-					record.Set("view_count", record.GetInt("view_count")+1)
-					record.Set("last_viewed_at", time.Now())
-					if err := app.Save(record); err != nil {
-						app.Logger().Warn("Failed to update view metrics", "error", err, "view_id", viewID)
-					}
-				}(view.Id, viewsCollection)
-			}
-
-			// Build view response
-			response := map[string]interface{}{
-				"id":         view.Id,
-				"slug":       slug,
-				"name":       view.GetString("name"),
-				"visibility": visibility,
-			}
-
-			// Apply overrides if present
-			if headline := view.GetString("hero_headline"); headline != "" {
-				response["hero_headline"] = headline
-			}
-			if summary := view.GetString("hero_summary"); summary != "" {
-				response["hero_summary"] = summary
-			}
-			if ctaText := view.GetString("cta_text"); ctaText != "" {
-				response["cta_text"] = ctaText
-			}
-			if ctaURL := view.GetString("cta_url"); ctaURL != "" {
-				response["cta_url"] = ctaURL
-			}
-
-			// Include view-specific accent color (null/empty means inherit from profile)
-			if accentColor := view.GetString("accent_color"); accentColor != "" {
-				response["accent_color"] = accentColor
-			}
-
-			if heroImage := view.GetString("hero_image"); heroImage != "" {
-				response["hero_image_url"] = "/api/files/" + view.Collection().Id + "/" + view.Id + "/" + url.PathEscape(heroImage)
-			}
-
-			// Get sections configuration
-			sectionsJSON := view.GetString("sections")
-			var sections []map[string]interface{}
-			if sectionsJSON != "" {
-				json.Unmarshal([]byte(sectionsJSON), &sections)
-			}
-
-			// Fetch content for each enabled section
-			sectionData := make(map[string]interface{})
-			// Track section order for frontend rendering
-			var sectionOrder []string
-			// Track layouts for each section
-			sectionLayouts := make(map[string]string)
-			// Track widths for each section (Phase 6.3)
-			sectionWidths := make(map[string]string)
-
-			for _, section := range sections {
-				sectionName, ok := section["section"].(string)
-				if !ok {
-					continue
-				}
-				enabled, ok := section["enabled"].(bool)
-				if !ok || !enabled {
-					continue
-				}
-				// Add to order list
-				sectionOrder = append(sectionOrder, sectionName)
-
-				// Extract layout (default to "default" if not specified)
-				if layout, ok := section["layout"].(string); ok && layout != "" {
-					sectionLayouts[sectionName] = layout
-				} else {
-					sectionLayouts[sectionName] = getDefaultLayout(sectionName)
-				}
-
-				// Extract width (default to "full" if not specified)
-				if width, ok := section["width"].(string); ok && width != "" {
-					sectionWidths[sectionName] = width
-				} else {
-					sectionWidths[sectionName] = "full"
-				}
-
-				items, ok := section["items"].([]interface{})
-				collectionName := getCollectionName(sectionName)
-				if collectionName == "" {
-					continue
-				}
-				// Use demo collection if demo mode is enabled
-				if isDemoMode {
-					collectionName = "demo_" + collectionName
-				}
-
-				// Extract itemConfig for overrides
-				itemConfig := make(map[string]map[string]interface{})
-				if itemConfigRaw, ok := section["itemConfig"].(map[string]interface{}); ok {
-					for itemID, config := range itemConfigRaw {
-						if configMap, ok := config.(map[string]interface{}); ok {
-							itemConfig[itemID] = configMap
-						}
-					}
-				}
-
-				if ok && len(items) > 0 {
-					var itemRecords []*core.Record
-					for _, itemID := range items {
-						if id, ok := itemID.(string); ok {
-							record, err := app.FindRecordById(collectionName, id)
-							if err == nil && isRecordVisibleForSection(record, sectionName, view.Id) {
-								itemRecords = append(itemRecords, record)
-							}
-						}
-					}
-					sectionData[sectionName] = serializeRecordsWithOverrides(itemRecords, itemConfig, sectionName)
-				} else {
-					var filter string
-					var sortField string
-					if sectionName == "contacts" {
-						filter = ""
-						sortField = "-is_primary,-sort_order"
-					} else if sectionName == "testimonials" {
-						filter = "status = 'approved'"
-						sortField = "-featured,-sort_order"
-					} else {
-						filter = "is_draft = false"
-						sortField = "sort_order"
-					}
-
-					allRecords, err := app.FindRecordsByFilter(
-						collectionName,
-						filter,
-						sortField,
-						100,
-						0,
-						nil,
-					)
-					if err == nil {
-						var visibleRecords []*core.Record
-						for _, record := range allRecords {
-							if isRecordVisibleForSection(record, sectionName, view.Id) {
-								visibleRecords = append(visibleRecords, record)
-							}
-						}
-						sectionData[sectionName] = serializeRecordsWithOverrides(visibleRecords, itemConfig, sectionName)
+				// Batched with other hits and written by the counter flush loop
+				counters.IncrementViewCount(viewsCollection, view.Id)
+
+				// Log the visit and count it; demo views are not tracked
+				if !isDemoMode {
+					tokenID := ""
+					if shareRecord != nil {
+						tokenID = shareRecord.Id
 					}
+					countPublicHit(counters, e.Request, services.AnalyticsView, view.Id, tokenID)
+					counters.RecordAccessEvent(view.Id, tokenID, access.Describe(e.Request, time.Now()))
 				}
 			}
 
-			response["sections"] = sectionData
-			response["section_order"] = sectionOrder
-			response["section_layouts"] = sectionLayouts
-			response["section_widths"] = sectionWidths
-
-			// Fetch profile data for the view
-			profileTableName := "profile"
-			if isDemoMode {
-				profileTableName = "demo_profile"
+			// The response depends only on content and on who is asking: a share
+			// token can personalize it, and password views must not be shared by a CDN
+			accessContext := "public"
+			if shareRecord != nil {
+				accessContext = "token:" + shareRecord.Id
+			} else if visibility != "public" {
+				accessContext = visibility
 			}
-			profileRecords, err := app.FindRecordsByFilter(
-				profileTableName,
-				"visibility != 'private'",
-				"",
-				1,
-				0,
-				nil,
-			)
-			if err == nil && len(profileRecords) > 0 {
-				profile := profileRecords[0]
-				profileData := map[string]interface{}{
-					"id":            profile.Id,
-					"name":          profile.GetString("name"),
-					"headline":      profile.GetString("headline"),
-					"location":      profile.GetString("location"),
-					"summary":       profile.GetString("summary"),
-					"contact_email": profile.GetString("contact_email"),
-					"contact_links": profile.Get("contact_links"),
-					"visibility":    profile.GetString("visibility"),
-					"accent_color":  profile.GetString("accent_color"),
-				}
-
-				// Include file URLs if present
-				if heroImage := profile.GetString("hero_image"); heroImage != "" {
-					profileData["hero_image_url"] = "/api/files/" + profile.Collection().Id + "/" + profile.Id + "/" + heroImage
-				}
-				if avatar := profile.GetString("avatar"); avatar != "" {
-					profileData["avatar_url"] = "/api/files/" + profile.Collection().Id + "/" + profile.Id + "/" + avatar
-				}
-
-				response["profile"] = profileData
+			cacheControl := cacheControlPublic
+			if accessContext != "public" {
+				cacheControl = cacheControlPrivate
 			}
 
-			return e.JSON(http.StatusOK, response)
+			return serveCached(e, cache, "view-data:"+slug+":"+accessContext, cacheControl, func() (int, string, []byte, error) {
+				return jsonResponse(http.StatusOK, buildViewResponse(app, view, slug, shareRecord, isDemoMode))
+			})
 		}))
 
 		// Get default view slug/data
@@ -614,7 +423,7 @@ func RegisterViewHooks(app *pocketbase.PocketBase, crypto *services.CryptoServic
 		// DEPRECATED: Use /api/default-view + /api/view/{slug}/data instead
 		// Kept for backwards compatibility during migration
 		// Rate limited: normal tier (60/min) to prevent scraping
-		se.Router.GET("/api/homepage", RateLimitMiddleware(rl, "normal")(func(e *core.RequestEvent) error {
+		se.Router.GET("/api/homepage", RateLimitMiddleware(rl, "normal")(ResponseCacheMiddleware(cache, "homepage")(func(e *core.RequestEvent) error {
 			response := make(map[string]interface{})
 
 			settings, err := services.LoadSiteSettings(app)
@@ -794,12 +603,12 @@ func RegisterViewHooks(app *pocketbase.PocketBase, crypto *services.CryptoServic
 			}
 
 			return e.JSON(http.StatusOK, response)
-		}))
+		})))
 
 		// Public posts listing
 		// Rate limited: normal tier (60/min)
 		// Returns all non-private, non-draft posts for the index page
-		se.Router.GET("/api/posts", RateLimitMiddleware(rl, "normal")(func(e *core.RequestEvent) error {
+		se.Router.GET("/api/posts", RateLimitMiddleware(rl, "normal")(ResponseCacheMiddleware(cache, "posts")(func(e *core.RequestEvent) error {
 
 			settings, err := services.LoadSiteSettings(app)
 			if err != nil {
@@ -866,10 +675,10 @@ func RegisterViewHooks(app *pocketbase.PocketBase, crypto *services.CryptoServic
 				"posts":   posts,
 				"profile": profile,
 			})
-		}))
+		})))
 
 		// RSS feed for public posts
-		se.Router.GET("/rss.xml", RateLimitMiddleware(rl, "normal")(func(e *core.RequestEvent) error {
+		se.Router.GET("/rss.xml", RateLimitMiddleware(rl, "normal")(ResponseCacheMiddleware(cache, "rss")(func(e *core.RequestEvent) error {
 
 			// Fetch profile for channel metadata
 			channelTitle := "Facet - Latest Posts"
@@ -971,10 +780,10 @@ func RegisterViewHooks(app *pocketbase.PocketBase, crypto *services.CryptoServic
 			e.Response.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
 			_, _ = e.Response.Write(data)
 			return nil
-		}))
+		})))
 
 		// iCal feed for public talks
-		se.Router.GET("/talks.ics", RateLimitMiddleware(rl, "normal")(func(e *core.RequestEvent) error {
+		se.Router.GET("/talks.ics", RateLimitMiddleware(rl, "normal")(ResponseCacheMiddleware(cache, "talks-ics")(func(e *core.RequestEvent) error {
 
 			// Fetch profile for calendar metadata
 			calendarName := "Facet Talks"
@@ -1074,7 +883,7 @@ func RegisterViewHooks(app *pocketbase.PocketBase, crypto *services.CryptoServic
 			e.Response.Header().Set("Content-Type", "text/calendar; charset=utf-8")
 			_, _ = e.Response.Write([]byte(builder.String()))
 			return nil
-		}))
+		})))
 
 		// Public talks listing
 		// Rate limited: normal tier (60/min)
@@ -1302,6 +1111,10 @@ func RegisterViewHooks(app *pocketbase.PocketBase, crypto *services.CryptoServic
 				return e.JSON(http.StatusNotFound, map[string]string{"error": "post not found"})
 			}
 
+			if !isAuthenticated && postsCollection == "posts" {
+				countPublicHit(counters, e.Request, services.AnalyticsPost, post.Id, "")
+			}
+
 			response := map[string]interface{}{
 				"id":               post.Id,
 				"title":            post.GetString("title"),
@@ -1449,6 +1262,10 @@ func RegisterViewHooks(app *pocketbase.PocketBase, crypto *services.CryptoServic
 				}
 			}
 
+			if !isAuthenticated && projectsCollection == "projects" {
+				countPublicHit(counters, e.Request, services.AnalyticsProject, project.Id, "")
+			}
+
 			response := map[string]interface{}{
 				"id":               project.Id,
 				"title":            project.GetString("title"),
@@ -1565,6 +1382,10 @@ func RegisterViewHooks(app *pocketbase.PocketBase, crypto *services.CryptoServic
 				return e.JSON(http.StatusNotFound, map[string]string{"error": "talk not found"})
 			}
 
+			if !isAuthenticated && talksCollection == "talks" {
+				countPublicHit(counters, e.Request, services.AnalyticsTalk, talk.Id, "")
+			}
+
 			response := map[string]interface{}{
 				"id":               talk.Id,
 				"title":            talk.GetString("title"),
@@ -1660,60 +1481,16 @@ func RegisterViewHooks(app *pocketbase.PocketBase, crypto *services.CryptoServic
 	})
 }
 
+// getCollectionName returns the collection backing a section: the built-in
+// collection, or custom_items for user-defined sections
 func getCollectionName(section string) string {
-	switch section {
-	case "experience":
-		return "experience"
-	case "projects":
-		return "projects"
-	case "education":
-		return "education"
-	case "certifications":
-		return "certifications"
-	case "awards":
-		return "awards"
-	case "skills":
-		return "skills"
-	case "posts":
-		return "posts"
-	case "talks":
-		return "talks"
-	case "contacts":
-		return "contact_methods"
-	case "testimonials":
-		return "testimonials"
-	default:
-		return ""
+	if def, ok := builtinSections[section]; ok {
+		return def.Collection
 	}
-}
-
-// getDefaultLayout returns the default layout for a section type
-// Sync with VALID_LAYOUTS in frontend/src/lib/pocketbase.ts
-func getDefaultLayout(section string) string {
-	switch section {
-	case "experience":
-		return "default"
-	case "projects":
-		return "grid-3"
-	case "education":
-		return "default"
-	case "certifications":
-		return "grouped"
-	case "awards":
-		return "grouped"
-	case "skills":
-		return "grouped"
-	case "posts":
-		return "grid-3"
-	case "talks":
-		return "default"
-	case "contacts":
-		return "vertical"
-	case "testimonials":
-		return "wall"
-	default:
-		return "default"
+	if isCustomSectionName(section) {
+		return customItemsCollection
 	}
+	return ""
 }
 
 func isRecordVisible(record *core.Record) bool {
@@ -1723,6 +1500,11 @@ func isRecordVisible(record *core.Record) bool {
 }
 
 func isRecordVisibleForSection(record *core.Record, section string, viewId string) bool {
+	// Custom section items share one collection; only the section's own items belong to it
+	if strings.TrimPrefix(record.Collection().Name, "demo_") == customItemsCollection && record.GetString("section") != section {
+		return false
+	}
+
 	viewVisibility := record.Get("view_visibility")
 
 	if isRecordVisible(record) {
@@ -1804,45 +1586,29 @@ func isVisibleInView(record *core.Record, viewId string) bool {
 	return false
 }
 
+// serializeRecords serializes records for unauthenticated callers using the public schema registry
 func serializeRecords(records []*core.Record) []map[string]interface{} {
 	var result []map[string]interface{}
 	for _, record := range records {
-		item := make(map[string]interface{})
-		for key, value := range record.FieldsData() {
-			// Skip sensitive fields
-			if key == "password_hash" {
-				continue
-			}
-			item[key] = value
-		}
-		item["id"] = record.Id
-		result = append(result, item)
+		result = append(result, serializePublicRecord(record, nil))
 	}
 	return result
 }
 
-// serializeRecordsWithOverrides serializes records and applies view-specific field overrides
-func serializeRecordsWithOverrides(records []*core.Record, itemConfig map[string]map[string]interface{}, sectionName string) []map[string]interface{} {
+// serializeRecordsWithOverrides serializes the records of a section (public fields only)
+// and applies view-specific field overrides
+func serializeRecordsWithOverrides(records []*core.Record, itemConfig map[string]map[string]interface{}, def sectionDef) []map[string]interface{} {
 	var result []map[string]interface{}
-	overridableFields := getOverridableFields(sectionName)
 
 	for _, record := range records {
-		item := make(map[string]interface{})
-		for key, value := range record.FieldsData() {
-			// Skip sensitive fields
-			if key == "password_hash" {
-				continue
-			}
-			item[key] = value
-		}
-		item["id"] = record.Id
+		item := serializePublicRecord(record, def.Fields)
 
 		// Apply overrides if present for this item
 		if config, exists := itemConfig[record.Id]; exists {
 			if overrides, ok := config["overrides"].(map[string]interface{}); ok {
 				for field, value := range overrides {
 					// Only apply overrides for allowed fields
-					if containsString(overridableFields, field) {
+					if containsString(def.Overridable, field) {
 						item[field] = value
 					}
 				}
@@ -1854,20 +1620,305 @@ func serializeRecordsWithOverrides(records []*core.Record, itemConfig map[string
 	return result
 }
 
-// getOverridableFields returns the list of fields that can be overridden per section
-func getOverridableFields(sectionName string) []string {
+// buildViewResponse assembles the /api/view/{slug}/data payload for a view the
+// caller may access
+func buildViewResponse(app core.App, view *core.Record, slug string, shareRecord *core.Record, isDemoMode bool) map[string]interface{} {
+	// Build view response
+	response := map[string]interface{}{
+		"id":         view.Id,
+		"slug":       slug,
+		"name":       view.GetString("name"),
+		"visibility": view.GetString("visibility"),
+	}
+
+	// Apply overrides if present
+	if headline := view.GetString("hero_headline"); headline != "" {
+		response["hero_headline"] = headline
+	}
+	if summary := view.GetString("hero_summary"); summary != "" {
+		response["hero_summary"] = summary
+	}
+	if ctaText := view.GetString("cta_text"); ctaText != "" {
+		response["cta_text"] = ctaText
+	}
+	if ctaURL := view.GetString("cta_url"); ctaURL != "" {
+		response["cta_url"] = ctaURL
+	}
+
+	// Include view-specific accent color (null/empty means inherit from profile)
+	if accentColor := view.GetString("accent_color"); accentColor != "" {
+		response["accent_color"] = accentColor
+	}
+
+	if heroImage := view.GetString("hero_image"); heroImage != "" {
+		response["hero_image_url"] = "/api/files/" + view.Collection().Id + "/" + view.Id + "/" + url.PathEscape(heroImage)
+	}
+
+	// Links made for a job application greet the holder and may carry their own CTA
+	if shareRecord != nil {
+		applyApplicationPersonalization(app, shareRecord, response)
+	}
+
+	// A scoped link sees only part of the view; validateShareToken has already
+	// turned away tokens whose scope cannot be read
+	scope, _ := shareTokenScope(shareRecord)
+
+	sections := buildViewSections(app, view, isDemoMode)
+	applyShareScope(&sections, scope)
+	response["sections"] = sections.Data
+	response["section_order"] = sections.Order
+	response["section_layouts"] = sections.Layouts
+	response["section_widths"] = sections.Widths
+	response["custom_sections"] = sections.Custom
+
+	// Fetch profile data for the view
+	profileTableName := "profile"
+	if isDemoMode {
+		profileTableName = "demo_profile"
+	}
+	profileRecords, err := app.FindRecordsByFilter(
+		profileTableName,
+		"visibility != 'private'",
+		"",
+		1,
+		0,
+		nil,
+	)
+	if err == nil && len(profileRecords) > 0 {
+		profile := profileRecords[0]
+		profileData := map[string]interface{}{
+			"id":            profile.Id,
+			"name":          profile.GetString("name"),
+			"headline":      profile.GetString("headline"),
+			"location":      profile.GetString("location"),
+			"summary":       profile.GetString("summary"),
+			"contact_email": profile.GetString("contact_email"),
+			"contact_links": profile.Get("contact_links"),
+			"visibility":    profile.GetString("visibility"),
+			"accent_color":  profile.GetString("accent_color"),
+		}
+
+		// Include file URLs if present
+		if heroImage := profile.GetString("hero_image"); heroImage != "" {
+			profileData["hero_image_url"] = "/api/files/" + profile.Collection().Id + "/" + profile.Id + "/" + heroImage
+		}
+		if avatar := profile.GetString("avatar"); avatar != "" {
+			profileData["avatar_url"] = "/api/files/" + profile.Collection().Id + "/" + profile.Id + "/" + avatar
+		}
+		redactFields(profileData, scope.Redact[profileScopeKey])
+
+		response["profile"] = profileData
+	}
+
+	return response
+}
+
+// viewSections is the content of a view's enabled sections, ready for the data response
+type viewSections struct {
+	Data    map[string]interface{}
+	Order   []string
+	Layouts map[string]string
+	Widths  map[string]string
+	// Custom describes the user-defined sections among them
+	Custom map[string]customSectionInfo
+}
+
+// buildViewSections loads the records of every enabled section of a view. Sections
+// with explicit items load them with a single IN query per section; the others
+// list their collection once.
+func buildViewSections(app core.App, view *core.Record, isDemoMode bool) viewSections {
+	// Get sections configuration
+	sectionsJSON := view.GetString("sections")
+	var sections []map[string]interface{}
+	if sectionsJSON != "" {
+		json.Unmarshal([]byte(sectionsJSON), &sections)
+	}
+
+	result := viewSections{
+		// Fetch content for each enabled section
+		Data: make(map[string]interface{}),
+		// Track layouts for each section
+		Layouts: make(map[string]string),
+		// Track widths for each section (Phase 6.3)
+		Widths: make(map[string]string),
+		Custom: make(map[string]customSectionInfo),
+	}
+
+	for _, section := range sections {
+		sectionName, ok := section["section"].(string)
+		if !ok {
+			continue
+		}
+		enabled, ok := section["enabled"].(bool)
+		if !ok || !enabled {
+			continue
+		}
+		def, known := resolveSection(app, sectionName, isDemoMode)
+		if !known {
+			continue
+		}
+		// Add to order list
+		result.Order = append(result.Order, sectionName)
+		if def.isCustom() {
+			result.Custom[sectionName] = customSectionInfo{Label: def.Label, Fields: def.Fields}
+		}
+
+		// Extract layout (default to the section's default layout if not specified)
+		if layout, ok := section["layout"].(string); ok && layout != "" {
+			result.Layouts[sectionName] = layout
+		} else {
+			result.Layouts[sectionName] = def.DefaultLayout
+		}
+
+		// Extract width (default to "full" if not specified)
+		if width, ok := section["width"].(string); ok && width != "" {
+			result.Widths[sectionName] = width
+		} else {
+			result.Widths[sectionName] = "full"
+		}
+
+		items, ok := section["items"].([]interface{})
+		collectionName := def.Collection
+		// Use demo collection if demo mode is enabled
+		if isDemoMode {
+			collectionName = "demo_" + collectionName
+		}
+
+		// Extract itemConfig for overrides
+		itemConfig := make(map[string]map[string]interface{})
+		if itemConfigRaw, ok := section["itemConfig"].(map[string]interface{}); ok {
+			for itemID, config := range itemConfigRaw {
+				if configMap, ok := config.(map[string]interface{}); ok {
+					itemConfig[itemID] = configMap
+				}
+			}
+		}
+
+		// Smart sections pick their records by rules instead of an items list
+		rules, err := parseSectionRules(section)
+		if err != nil {
+			app.Logger().Warn("Invalid section rules", "error", err, "section", sectionName)
+			continue
+		}
+		if rules != nil {
+			var matched []*core.Record
+			collection, err := app.FindCachedCollectionByNameOrId(collectionName)
+			if err == nil {
+				matched, err = findRuleSectionRecords(app, collection, def, view.Id, rules)
+			}
+			if err != nil {
+				app.Logger().Warn("Failed to evaluate section rules", "error", err, "section", sectionName)
+				continue
+			}
+			result.Data[sectionName] = serializeRecordsWithOverrides(matched, itemConfig, def)
+		} else if ok && len(items) > 0 {
+			ids := make([]string, 0, len(items))
+			for _, itemID := range items {
+				if id, ok := itemID.(string); ok {
+					ids = append(ids, id)
+				}
+			}
+			records, err := findRecordsByIdsOrdered(app, collectionName, ids)
+			if err != nil {
+				app.Logger().Warn("Failed to load section items", "error", err, "section", sectionName)
+			}
+			var itemRecords []*core.Record
+			for _, record := range records {
+				if isRecordVisibleForSection(record, sectionName, view.Id) {
+					itemRecords = append(itemRecords, record)
+				}
+			}
+			result.Data[sectionName] = serializeRecordsWithOverrides(itemRecords, itemConfig, def)
+		} else {
+			var allRecords []*core.Record
+			collection, err := app.FindCachedCollectionByNameOrId(collectionName)
+			if err == nil {
+				filter, sortField, params := sectionListQuery(collection, sectionName)
+				allRecords, err = app.FindRecordsByFilter(
+					collection,
+					filter,
+					sortField,
+					100,
+					0,
+					params,
+				)
+			}
+			if err == nil {
+				var visibleRecords []*core.Record
+				for _, record := range allRecords {
+					if isRecordVisibleForSection(record, sectionName, view.Id) {
+						visibleRecords = append(visibleRecords, record)
+					}
+				}
+				result.Data[sectionName] = serializeRecordsWithOverrides(visibleRecords, itemConfig, def)
+			}
+		}
+	}
+
+	return result
+}
+
+// findRecordsByIdsOrdered loads records with one IN query and returns them in the
+// order of ids. Ids that no longer exist are skipped.
+func findRecordsByIdsOrdered(app core.App, collection string, ids []string) ([]*core.Record, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	records, err := app.FindRecordsByIds(collection, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*core.Record, len(records))
+	for _, record := range records {
+		byID[record.Id] = record
+	}
+	ordered := make([]*core.Record, 0, len(records))
+	for _, id := range ids {
+		if record, ok := byID[id]; ok {
+			ordered = append(ordered, record)
+		}
+	}
+	return ordered, nil
+}
+
+// sectionListQuery returns the filter (with its params) and sort used when a section
+// lists every item of its collection. Skills have no drafts and posts have no
+// sort_order, so both are only applied when the collection has the field.
+func sectionListQuery(collection *core.Collection, sectionName string) (string, string, dbx.Params) {
 	switch sectionName {
-	case "experience":
-		return []string{"title", "description", "bullets"}
-	case "projects":
-		return []string{"title", "summary", "description"}
-	case "education":
-		return []string{"degree", "field", "description"}
-	case "talks":
-		return []string{"title", "description"}
-	default:
-		return []string{}
+	case "contacts":
+		return "", "-is_primary,-sort_order", nil
+	case "testimonials":
+		return "status = 'approved'", "-featured,-sort_order", nil
 	}
+
+	filter := ""
+	if collection.Fields.GetByName("is_draft") != nil {
+		filter = "is_draft = false"
+	}
+	// Custom sections share custom_items
+	var params dbx.Params
+	if strings.TrimPrefix(collection.Name, "demo_") == customItemsCollection {
+		if !isCustomSectionName(sectionName) {
+			return "id = ''", "", nil
+		}
+		if filter != "" {
+			filter += " && "
+		}
+		filter += "section = {:section}"
+		params = dbx.Params{"section": sectionName}
+	}
+
+	sortField := ""
+	if collection.Fields.GetByName("sort_order") != nil {
+		sortField = "sort_order"
+	} else if collection.Fields.GetByName("published_at") != nil {
+		sortField = "-published_at"
+	}
+
+	return filter, sortField, params
 }
 
 // containsString checks if a string slice contains a specific string
@@ -1880,6 +1931,106 @@ func containsString(slice []string, str string) bool {
 	return false
 }
 
+// viewAccessDenied is the response for a caller who may not open a view
+type viewAccessDenied struct {
+	Status int
+	Body   interface{}
+}
+
+// authorizeViewAccess applies a view's visibility to the caller: private views
+// are 404 to anonymous callers unless they hold a share token minted by an
+// approved access request, password views need a password JWT and unlisted
+// views a valid share token, plus a verified session when the token is
+// recipient-bound. On public views a valid token is optional. It returns the
+// share token that opened the view, if any; its scope limits what the caller
+// sees. countUse counts the open against the token's max_uses (recipient-bound
+// tokens count when their session is created instead).
+func authorizeViewAccess(app core.App, crypto *services.CryptoService, share *services.ShareService, counters *services.CounterService, e *core.RequestEvent, view *core.Record, countUse bool) (*core.Record, *viewAccessDenied) {
+	if e.Auth != nil {
+		return nil, nil
+	}
+
+	switch view.GetString("visibility") {
+	case "private":
+		// Private views return 404 to prevent leaking existence, unless an
+		// approved access request let this caller in
+		notFound := &viewAccessDenied{http.StatusNotFound, map[string]string{"error": "view not found"}}
+		valid, tokenRecord := validateShareToken(app, share, counters, extractShareToken(e), view.Id)
+		if !valid || !grantsPrivateAccess(app, tokenRecord) {
+			return nil, notFound
+		}
+		return authorizeShareTokenRecipient(crypto, counters, e, tokenRecord, countUse)
+
+	case "password":
+		token := extractPasswordToken(e)
+		if token == "" {
+			return nil, &viewAccessDenied{http.StatusUnauthorized, map[string]string{"error": "password token required"}}
+		}
+		viewID, err := crypto.ValidateViewAccessJWT(token)
+		if err != nil {
+			return nil, &viewAccessDenied{http.StatusUnauthorized, map[string]string{"error": "invalid or expired token"}}
+		}
+		if viewID != view.Id {
+			return nil, &viewAccessDenied{http.StatusUnauthorized, map[string]string{"error": "token not valid for this view"}}
+		}
+		return nil, nil
+
+	case "unlisted":
+		shareToken := extractShareToken(e)
+		if shareToken == "" {
+			return nil, &viewAccessDenied{http.StatusUnauthorized, map[string]string{"error": "share token required"}}
+		}
+		valid, tokenRecord := validateShareToken(app, share, counters, shareToken, view.Id)
+		if !valid {
+			return nil, &viewAccessDenied{http.StatusUnauthorized, map[string]string{"error": "invalid or expired share token"}}
+		}
+		return authorizeShareTokenRecipient(crypto, counters, e, tokenRecord, countUse)
+
+	case "public":
+		// A share token is optional here, but a valid one is still counted and
+		// can personalize the page
+		shareToken := extractShareToken(e)
+		if shareToken == "" {
+			return nil, nil
+		}
+		valid, tokenRecord := validateShareToken(app, share, counters, shareToken, view.Id)
+		if !valid || tokenRecord == nil {
+			return nil, nil
+		}
+		// Without a verified session a recipient-bound token is ignored and the
+		// visitor sees the public view
+		if !isRecipientBound(tokenRecord) {
+			if countUse {
+				counters.IncrementTokenUse(tokenRecord.Id)
+			}
+			return tokenRecord, nil
+		}
+		if hasShareSession(e, crypto, tokenRecord) {
+			return tokenRecord, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// authorizeShareTokenRecipient finishes opening a view with a valid share token.
+// A recipient-bound token only opens with a session from the emailed code; the
+// use was counted when the session was created.
+func authorizeShareTokenRecipient(crypto *services.CryptoService, counters *services.CounterService, e *core.RequestEvent, tokenRecord *core.Record, countUse bool) (*core.Record, *viewAccessDenied) {
+	if isRecipientBound(tokenRecord) {
+		if !hasShareSession(e, crypto, tokenRecord) {
+			return nil, &viewAccessDenied{http.StatusUnauthorized, map[string]interface{}{
+				"error":                 "verification required",
+				"verification_required": true,
+				"recipient_hint":        services.MaskEmail(tokenRecord.GetString("recipient_email")),
+			}}
+		}
+	} else if countUse {
+		counters.IncrementTokenUse(tokenRecord.Id)
+	}
+	return tokenRecord, nil
+}
+
 // extractPasswordToken extracts the password access token from request headers
 // Accepts: Authorization: Bearer <token> (preferred) or X-Password-Token: <token>
 func extractPasswordToken(e *core.RequestEvent) string {
@@ -1917,7 +2068,7 @@ func extractShareToken(e *core.RequestEvent) string {
 
 // validateShareToken validates a share token for a specific view
 // Returns (valid, tokenRecord) - tokenRecord is returned for usage tracking
-func validateShareToken(app *pocketbase.PocketBase, share *services.ShareService, token string, viewID string) (bool, *core.Record) {
+func validateShareToken(app core.App, share *services.ShareService, counters *services.CounterService, token string, viewID string) (bool, *core.Record) {
 	if token == "" {
 		return false, nil
 	}
@@ -1944,10 +2095,17 @@ func validateShareToken(app *pocketbase.PocketBase, share *services.ShareService
 		return false, nil
 	}
 
-	// Check max uses
-	useCount := tokenRecord.GetInt("use_count")
+	// Check max uses, including uses not yet flushed. Recipient-bound tokens
+	// count verified sessions, so a session stays usable once the limit is hit.
+	useCount := tokenRecord.GetInt("use_count") + counters.PendingTokenUses(tokenRecord.Id)
 	maxUses := tokenRecord.GetInt("max_uses")
-	if maxUses > 0 && useCount >= maxUses {
+	if maxUses > 0 && useCount >= maxUses && !isRecipientBound(tokenRecord) {
+		return false, nil
+	}
+
+	// A scope we cannot read must not fall back to unlocking the whole view
+	if _, err := shareTokenScope(tokenRecord); err != nil {
+		app.Logger().Warn("Share token has a malformed scope", "error", err, "token_id", tokenRecord.Id)
 		return false, nil
 	}
 
@@ -1984,9 +2142,21 @@ func registerViewsValidation(app *pocketbase.PocketBase, crypto *services.Crypto
 			app.Logger().Warn("Password visibility set but no password provided", "slug", slug)
 		}
 
+		// Bad request errors keep their message; plain errors reach the client as
+		// a generic "Failed to create/update record."
+		if err := validateViewSectionRules(e.App, e.Record); err != nil {
+			return apis.NewBadRequestError(err.Error(), nil)
+		}
+		if err := validateViewItemOverrides(e.App, e.Record); err != nil {
+			return apis.NewBadRequestError(err.Error(), nil)
+		}
+		if err := validateAccessRequestsSetting(e.Record); err != nil {
+			return apis.NewBadRequestError(err.Error(), nil)
+		}
+
 		// If this view is being set as default, clear other defaults
 		if e.Record.GetBool("is_default") {
-			if err := clearOtherDefaults(app, ""); err != nil {
+			if err := clearOtherDefaults(e.App, ""); err != nil {
 				return err
 			}
 		}
@@ -2014,9 +2184,19 @@ func registerViewsValidation(app *pocketbase.PocketBase, crypto *services.Crypto
 			e.Record.Set("password", "") // Clear plaintext password
 		}
 
+		if err := validateViewSectionRules(e.App, e.Record); err != nil {
+			return apis.NewBadRequestError(err.Error(), nil)
+		}
+		if err := validateViewItemOverrides(e.App, e.Record); err != nil {
+			return apis.NewBadRequestError(err.Error(), nil)
+		}
+		if err := validateAccessRequestsSetting(e.Record); err != nil {
+			return apis.NewBadRequestError(err.Error(), nil)
+		}
+
 		// If this view is being set as default, clear other defaults
 		if e.Record.GetBool("is_default") {
-			if err := clearOtherDefaults(app, e.Record.Id); err != nil {
+			if err := clearOtherDefaults(e.App, e.Record.Id); err != nil {
 				return err
 			}
 		}
@@ -2025,8 +2205,9 @@ func registerViewsValidation(app *pocketbase.PocketBase, crypto *services.Crypto
 	})
 }
 
-// clearOtherDefaults removes is_default from all views except the one with excludeID
-func clearOtherDefaults(app *pocketbase.PocketBase, excludeID string) error {
+// clearOtherDefaults removes is_default from all views except the one with excludeID.
+// Takes the event app so it participates in the caller's transaction, if any.
+func clearOtherDefaults(app core.App, excludeID string) error {
 	filter := "is_default = true"
 	if excludeID != "" {
 		filter += " && id != {:id}"
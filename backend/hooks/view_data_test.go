@@ -0,0 +1,198 @@
+package hooks
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"facet/services"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// countQueries counts SELECTs run against the app's databases from now on
+func countQueries(app core.App) *int64 {
+	var count int64
+	logQuery := func(ctx context.Context, t time.Duration, sql string, rows *sql.Rows, err error) {
+		atomic.AddInt64(&count, 1)
+	}
+	app.DB().(*dbx.DB).QueryLogFunc = logQuery
+	app.NonconcurrentDB().(*dbx.DB).QueryLogFunc = logQuery
+	return &count
+}
+
+// seedLargeView creates a view with many explicitly picked projects and skills,
+// plus sections that list their whole collection
+func seedLargeView(tb testing.TB, app core.App) *core.Record {
+	tb.Helper()
+
+	save := func(collection string, fields map[string]interface{}) *core.Record {
+		coll, err := app.FindCollectionByNameOrId(collection)
+		if err != nil {
+			tb.Fatalf("Failed to find %s: %v", collection, err)
+		}
+		record := core.NewRecord(coll)
+		for key, value := range fields {
+			record.Set(key, value)
+		}
+		if err := app.Save(record); err != nil {
+			tb.Fatalf("Failed to save %s: %v", collection, err)
+		}
+		return record
+	}
+
+	var projectIDs, skillIDs []string
+	for i := 0; i < 25; i++ {
+		visibility := "public"
+		if i == 3 {
+			visibility = "private"
+		}
+		project := save("projects", map[string]interface{}{
+			"title":      fmt.Sprintf("Project %d", i),
+			"slug":       fmt.Sprintf("project-%d", i),
+			"visibility": visibility,
+		})
+		projectIDs = append(projectIDs, project.Id)
+	}
+	for i := 0; i < 40; i++ {
+		skill := save("skills", map[string]interface{}{
+			"name":       fmt.Sprintf("Skill %d", i),
+			"visibility": "public",
+		})
+		skillIDs = append(skillIDs, skill.Id)
+	}
+	for i := 0; i < 5; i++ {
+		save("experience", map[string]interface{}{
+			"company":    fmt.Sprintf("Company %d", i),
+			"title":      "Engineer",
+			"start_date": "2020-01-01 00:00:00.000Z",
+			"visibility": "public",
+		})
+	}
+
+	// Reverse the projects so ordering by the items list is observable
+	reversed := make([]string, len(projectIDs))
+	for i, id := range projectIDs {
+		reversed[len(projectIDs)-1-i] = id
+	}
+
+	return save("views", map[string]interface{}{
+		"name":       "Everything",
+		"slug":       "everything",
+		"visibility": "public",
+		"is_active":  true,
+		"sections": []map[string]interface{}{
+			{"section": "experience", "enabled": true},
+			{"section": "projects", "enabled": true, "items": append(reversed, "missing0000000")},
+			{"section": "skills", "enabled": true, "items": skillIDs},
+			{"section": "posts", "enabled": true},
+			{"section": "talks", "enabled": false},
+		},
+	})
+}
+
+func TestBuildViewSectionsQueryCount(t *testing.T) {
+	app := newMigratedTestApp(t)
+	view := seedLargeView(t, app)
+
+	// Warm the demo mode and settings caches
+	isDemoModeEnabled(app)
+	if _, err := services.LoadSiteSettings(app); err != nil {
+		t.Fatalf("LoadSiteSettings() error = %v", err)
+	}
+
+	queries := countQueries(app)
+	demo := isDemoModeEnabled(app)
+	if _, err := services.LoadSiteSettings(app); err != nil {
+		t.Fatalf("LoadSiteSettings() error = %v", err)
+	}
+	sections := buildViewSections(app, view, demo)
+
+	// One query per enabled section, none for cached demo mode and settings
+	if got := atomic.LoadInt64(queries); got != 4 {
+		t.Errorf("queries = %d, want 4", got)
+	}
+
+	projects, _ := sections.Data["projects"].([]map[string]interface{})
+	if len(projects) != 24 {
+		t.Fatalf("projects = %d, want 24 (private and missing items skipped)", len(projects))
+	}
+	if projects[0]["title"] != "Project 24" || projects[23]["title"] != "Project 0" {
+		t.Errorf("projects not in items order: first %v, last %v", projects[0]["title"], projects[23]["title"])
+	}
+	if skills, _ := sections.Data["skills"].([]map[string]interface{}); len(skills) != 40 {
+		t.Errorf("skills = %d, want 40", len(skills))
+	}
+	if len(sections.Order) != 4 || sections.Widths["skills"] != "full" {
+		t.Errorf("order = %v, widths = %v", sections.Order, sections.Widths)
+	}
+}
+
+func TestViewCachesInvalidateOnSave(t *testing.T) {
+	app := newMigratedTestApp(t)
+
+	if isDemoModeEnabled(app) {
+		t.Fatal("demo mode enabled without demo data")
+	}
+	demoProfiles, err := app.FindCollectionByNameOrId("demo_profile")
+	if err != nil {
+		t.Fatalf("Failed to find demo_profile: %v", err)
+	}
+	demoProfile := core.NewRecord(demoProfiles)
+	demoProfile.Set("name", "The Doctor")
+	if err := app.Save(demoProfile); err != nil {
+		t.Fatalf("Failed to save demo profile: %v", err)
+	}
+	if !isDemoModeEnabled(app) {
+		t.Error("demo mode cache was not invalidated on create")
+	}
+	if err := app.Delete(demoProfile); err != nil {
+		t.Fatalf("Failed to delete demo profile: %v", err)
+	}
+	if isDemoModeEnabled(app) {
+		t.Error("demo mode cache was not invalidated on delete")
+	}
+
+	settings, err := services.LoadSiteSettings(app)
+	if err != nil {
+		t.Fatalf("LoadSiteSettings() error = %v", err)
+	}
+	// Changing the returned record must not leak into the cache
+	settings.Record.Set("landing_page_message", "unsaved")
+	if again, _ := services.LoadSiteSettings(app); again.LandingPageMessage == "unsaved" || again.Record.GetString("landing_page_message") == "unsaved" {
+		t.Error("cached settings were modified through a returned record")
+	}
+
+	settings.Record.Set("landing_page_message", "Back soon")
+	if err := app.Save(settings.Record); err != nil {
+		t.Fatalf("Failed to save settings: %v", err)
+	}
+	if reloaded, _ := services.LoadSiteSettings(app); reloaded.LandingPageMessage != "Back soon" {
+		t.Errorf("LandingPageMessage = %q after save", reloaded.LandingPageMessage)
+	}
+}
+
+// BenchmarkViewSections reports queries per view data build and fails if the
+// batched loading regresses
+func BenchmarkViewSections(b *testing.B) {
+	app := newMigratedTestApp(b)
+	view := seedLargeView(b, app)
+	isDemoModeEnabled(app)
+
+	queries := countQueries(app)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buildViewSections(app, view, isDemoModeEnabled(app))
+	}
+	b.StopTimer()
+
+	perOp := float64(atomic.LoadInt64(queries)) / float64(b.N)
+	b.ReportMetric(perOp, "queries/op")
+	if perOp > 4 {
+		b.Fatalf("queries/op = %.1f, want at most 4", perOp)
+	}
+}
@@ -0,0 +1,137 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// validateViewItemOverrides checks the itemConfig overrides of every section of
+// a view: each overridden field must be overridable for the section and the
+// value must fit the field it replaces.
+func validateViewItemOverrides(app core.App, view *core.Record) error {
+	var sections []map[string]interface{}
+	if raw := view.GetString("sections"); raw != "" && raw != "null" {
+		if err := json.Unmarshal([]byte(raw), &sections); err != nil {
+			return fmt.Errorf("sections must be a list")
+		}
+	}
+
+	isDemoMode := strings.HasPrefix(view.Collection().Name, "demo_")
+	for _, section := range sections {
+		sectionName, _ := section["section"].(string)
+		raw, ok := section["itemConfig"]
+		if !ok || raw == nil {
+			continue
+		}
+		itemConfig, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("section %s: itemConfig must be an object", sectionName)
+		}
+		if len(itemConfig) == 0 {
+			continue
+		}
+
+		def, ok := resolveSection(app, sectionName, isDemoMode)
+		if !ok {
+			return fmt.Errorf("section %s does not support overrides", sectionName)
+		}
+		collection, err := app.FindCachedCollectionByNameOrId(def.Collection)
+		if err != nil {
+			return fmt.Errorf("section %s: %w", sectionName, err)
+		}
+
+		for _, itemID := range sortedKeys(itemConfig) {
+			config, ok := itemConfig[itemID].(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("section %s item %s: config must be an object", sectionName, itemID)
+			}
+			if config["overrides"] == nil {
+				continue
+			}
+			overrides, ok := config["overrides"].(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("section %s item %s: overrides must be an object", sectionName, itemID)
+			}
+
+			for _, field := range sortedKeys(overrides) {
+				if !containsString(def.Overridable, field) {
+					if len(def.Overridable) == 0 {
+						return fmt.Errorf("section %s item %s: %s cannot be overridden, this section has no overridable fields", sectionName, itemID, field)
+					}
+					return fmt.Errorf("section %s item %s: %s cannot be overridden (allowed: %s)", sectionName, itemID, field, strings.Join(def.Overridable, ", "))
+				}
+				if err := validateOverrideValue(collection, def, field, overrides[field]); err != nil {
+					return fmt.Errorf("section %s item %s: override %s %w", sectionName, itemID, field, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateOverrideValue checks an override against the definition of the field
+// it replaces: the collection field, or the schema field of a custom section
+func validateOverrideValue(collection *core.Collection, def sectionDef, field string, value interface{}) error {
+	if def.isCustom() && field != "title" {
+		schemaField, _ := def.customField(field)
+		_, err := convertCustomFieldValue(schemaField, value)
+		return err
+	}
+
+	switch f := collection.Fields.GetByName(field).(type) {
+	case *core.TextField:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("must be text")
+		}
+		if f.Required && strings.TrimSpace(s) == "" {
+			return fmt.Errorf("cannot be empty")
+		}
+		if f.Max > 0 && utf8.RuneCountInString(s) > f.Max {
+			return fmt.Errorf("must be at most %d characters", f.Max)
+		}
+		return nil
+	case *core.EditorField:
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("must be text")
+		}
+		return nil
+	case *core.SelectField:
+		s, ok := value.(string)
+		if !ok || !containsString(f.Values, s) {
+			return fmt.Errorf("must be one of %s", strings.Join(f.Values, ", "))
+		}
+		return nil
+	case *core.JSONField:
+		// Every overridable JSON field is a list of strings (bullets, tech_stack, tags...)
+		_, err := convertCustomFieldValue(customSectionField{Type: "list"}, value)
+		return err
+	case *core.URLField:
+		_, err := convertCustomFieldValue(customSectionField{Type: "url"}, value)
+		return err
+	case *core.DateField:
+		_, err := convertCustomFieldValue(customSectionField{Type: "date"}, value)
+		return err
+	case *core.NumberField:
+		_, err := convertCustomFieldValue(customSectionField{Type: "number"}, value)
+		return err
+	case *core.BoolField:
+		_, err := convertCustomFieldValue(customSectionField{Type: "boolean"}, value)
+		return err
+	}
+	return fmt.Errorf("cannot be overridden")
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
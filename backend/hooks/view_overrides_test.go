@@ -0,0 +1,140 @@
+package hooks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestValidateViewItemOverrides(t *testing.T) {
+	app := newMigratedTestApp(t)
+	bindCustomSectionHooks(app)
+	seedCustomSection(t, app, "")
+
+	views, err := app.FindCollectionByNameOrId("views")
+	if err != nil {
+		t.Fatalf("Failed to find views: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		section   string
+		overrides interface{}
+		wantErr   string
+	}{
+		{"text", "experience", map[string]interface{}{"title": "Staff Engineer"}, ""},
+		{"list", "projects", map[string]interface{}{"tech_stack": []string{"Go", "SQLite"}}, ""},
+		{"select", "skills", map[string]interface{}{"proficiency": "expert", "category": "Backend"}, ""},
+		{"tags", "posts", map[string]interface{}{"tags": []string{"platform"}, "excerpt": ""}, ""},
+		{"testimonial context", "testimonials", map[string]interface{}{"relationship": "manager"}, ""},
+		{"contact label", "contacts", map[string]interface{}{"label": "Work email"}, ""},
+		{"custom field", "publications", map[string]interface{}{"venue": "GopherCon EU"}, ""},
+		{"not overridable", "experience", map[string]interface{}{"company": "Acme"}, "company cannot be overridden (allowed: title, description, bullets, skills)"},
+		{"testimonial quote", "testimonials", map[string]interface{}{"content": "Great!"}, "content cannot be overridden"},
+		{"custom not overridable", "publications", map[string]interface{}{"year": 2020}, "year cannot be overridden (allowed: venue)"},
+		{"wrong type", "experience", map[string]interface{}{"bullets": "one line"}, "override bullets must be a list"},
+		{"list of numbers", "projects", map[string]interface{}{"tech_stack": []int{1, 2}}, "must be a list of text"},
+		{"bad select", "skills", map[string]interface{}{"proficiency": "guru"}, "must be one of expert, proficient, familiar"},
+		{"required", "experience", map[string]interface{}{"title": " "}, "override title cannot be empty"},
+		{"too long", "skills", map[string]interface{}{"name": strings.Repeat("x", 101)}, "at most 100 characters"},
+		{"custom wrong type", "publications", map[string]interface{}{"venue": 12}, "override venue must be text"},
+		{"overrides not an object", "experience", []string{"title"}, "overrides must be an object"},
+		{"unknown section", "nope", map[string]interface{}{"title": "x"}, "does not support overrides"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := core.NewRecord(views)
+			view.Set("sections", []map[string]interface{}{{
+				"section":    tt.section,
+				"enabled":    true,
+				"itemConfig": map[string]interface{}{"item123": map[string]interface{}{"overrides": tt.overrides}},
+			}})
+			err := validateViewItemOverrides(app, view)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateViewItemOverrides() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateViewItemOverrides() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Malformed sections are rejected rather than skipping validation
+	for _, sections := range []interface{}{
+		map[string]interface{}{"section": "experience"},
+		[]interface{}{"experience"},
+	} {
+		view := core.NewRecord(views)
+		view.Set("sections", sections)
+		if err := validateViewItemOverrides(app, view); err == nil || !strings.Contains(err.Error(), "sections must be a list") {
+			t.Errorf("validateViewItemOverrides(%v) error = %v, want sections must be a list", sections, err)
+		}
+	}
+}
+
+func TestOverridesForEverySection(t *testing.T) {
+	app := newMigratedTestApp(t)
+
+	save := func(collection string, fields map[string]interface{}) *core.Record {
+		coll, err := app.FindCollectionByNameOrId(collection)
+		if err != nil {
+			t.Fatalf("Failed to find %s: %v", collection, err)
+		}
+		record := core.NewRecord(coll)
+		for key, value := range fields {
+			record.Set(key, value)
+		}
+		if err := app.Save(record); err != nil {
+			t.Fatalf("Failed to save %s: %v", collection, err)
+		}
+		return record
+	}
+
+	skill := save("skills", map[string]interface{}{"name": "Golang", "proficiency": "proficient", "visibility": "public"})
+	project := save("projects", map[string]interface{}{"title": "CLI", "tech_stack": []string{"Go"}, "visibility": "public"})
+	cert := save("certifications", map[string]interface{}{"name": "CKA", "issuer": "CNCF", "visibility": "public"})
+
+	view := save("views", map[string]interface{}{
+		"name":      "Platform",
+		"slug":      "platform",
+		"is_active": true,
+		"sections": []map[string]interface{}{
+			{"section": "skills", "enabled": true, "itemConfig": map[string]interface{}{
+				skill.Id: map[string]interface{}{"overrides": map[string]interface{}{"name": "Go", "proficiency": "expert"}},
+			}},
+			{"section": "projects", "enabled": true, "itemConfig": map[string]interface{}{
+				project.Id: map[string]interface{}{"overrides": map[string]interface{}{"tech_stack": []string{"Go", "Cobra"}}},
+			}},
+			{"section": "certifications", "enabled": true, "itemConfig": map[string]interface{}{
+				cert.Id: map[string]interface{}{"overrides": map[string]interface{}{"name": "Certified Kubernetes Administrator"}},
+			}},
+		},
+	})
+	if err := validateViewItemOverrides(app, view); err != nil {
+		t.Fatalf("validateViewItemOverrides() error = %v", err)
+	}
+
+	sections := buildViewSections(app, view, false)
+	first := func(section string) map[string]interface{} {
+		items, _ := sections.Data[section].([]map[string]interface{})
+		if len(items) != 1 {
+			t.Fatalf("%s = %v, want one item", section, items)
+		}
+		return items[0]
+	}
+
+	if got := first("skills"); got["name"] != "Go" || got["proficiency"] != "expert" {
+		t.Errorf("skill = %v, want overridden name and proficiency", got)
+	}
+	if got := first("projects"); len(got["tech_stack"].([]interface{})) != 2 {
+		t.Errorf("project tech_stack = %v, want the overridden list", got["tech_stack"])
+	}
+	if got := first("certifications"); got["name"] != "Certified Kubernetes Administrator" || got["issuer"] != "CNCF" {
+		t.Errorf("certification = %v, want overridden name and original issuer", got)
+	}
+}
@@ -0,0 +1,92 @@
+package hooks
+
+import (
+	"fmt"
+	"time"
+
+	"facet/services"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// defaultViewPasswordLabel names the view's own password_hash, which keeps
+// working next to the named passwords in view_passwords
+const defaultViewPasswordLabel = "Default"
+
+// bindViewPasswordHooks hashes the plaintext password of a named view password.
+// Like views, the admin UI sends "password" and only password_hash is kept.
+func bindViewPasswordHooks(app core.App, crypto *services.CryptoService) {
+	hashPassword := func(e *core.RecordEvent) error {
+		password := e.Record.GetString("password")
+		if password == "" {
+			if e.Record.GetString("password_hash") == "" {
+				return apis.NewBadRequestError("password is required", nil)
+			}
+			return e.Next()
+		}
+
+		hash, err := crypto.HashPassword(password)
+		if err != nil {
+			return fmt.Errorf("failed to hash password: %w", err)
+		}
+		e.Record.Set("password_hash", hash)
+		e.Record.Set("password", "") // Clear plaintext password
+		return e.Next()
+	}
+
+	app.OnRecordCreate("view_passwords").BindFunc(hashPassword)
+	app.OnRecordUpdate("view_passwords").BindFunc(hashPassword)
+}
+
+// matchViewPassword checks a password against the active, unexpired named
+// passwords of a view and its default password. It returns the label of the
+// matching password and its view_passwords record (nil for the default).
+// configured is false when the view has no usable password at all.
+func matchViewPassword(app core.App, crypto *services.CryptoService, view *core.Record, password string) (label string, record *core.Record, configured bool) {
+	named, err := app.FindRecordsByFilter(
+		"view_passwords",
+		"view = {:view} && is_active = true",
+		"created",
+		0,
+		0,
+		map[string]interface{}{"view": view.Id},
+	)
+	if err != nil {
+		app.Logger().Error("Failed to load view passwords", "error", err, "view_id", view.Id)
+	}
+
+	now := time.Now()
+	for _, candidate := range named {
+		if expiresAt := candidate.GetDateTime("expires_at"); !expiresAt.IsZero() && now.After(expiresAt.Time()) {
+			continue
+		}
+		configured = true
+		if crypto.CheckPassword(password, candidate.GetString("password_hash")) {
+			return candidate.GetString("label"), candidate, true
+		}
+	}
+
+	if hash := view.GetString("password_hash"); hash != "" {
+		configured = true
+		if crypto.CheckPassword(password, hash) {
+			return defaultViewPasswordLabel, nil, true
+		}
+	}
+
+	return "", nil, configured
+}
+
+// recordViewPasswordUse notes a successful check against a named password.
+// The increment happens in SQL so concurrent unlocks all count.
+func recordViewPasswordUse(app core.App, record *core.Record) error {
+	_, err := app.DB().NewQuery(
+		"UPDATE {{view_passwords}} SET [[use_count]] = COALESCE([[use_count]], 0) + 1, [[last_used_at]] = {:now} WHERE [[id]] = {:id}",
+	).Bind(dbx.Params{
+		"now": types.NowDateTime().String(),
+		"id":  record.Id,
+	}).Execute()
+	return err
+}
@@ -0,0 +1,123 @@
+package hooks
+
+import (
+	"testing"
+	"time"
+
+	"facet/services"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestViewPasswords(t *testing.T) {
+	app := newMigratedTestApp(t)
+	_, _, viewID := seedImportFixture(t, app)
+	crypto := services.NewCryptoService("test-encryption-key-32-chars-ok!")
+	bindViewPasswordHooks(app, crypto)
+
+	view, err := app.FindRecordById("views", viewID)
+	if err != nil {
+		t.Fatalf("Failed to find view: %v", err)
+	}
+	collection, err := app.FindCollectionByNameOrId("view_passwords")
+	if err != nil {
+		t.Fatalf("Failed to find view_passwords: %v", err)
+	}
+	addPassword := func(label, password string, active bool, expiresAt time.Time) *core.Record {
+		t.Helper()
+		record := core.NewRecord(collection)
+		record.Set("view", viewID)
+		record.Set("label", label)
+		record.Set("password", password)
+		record.Set("is_active", active)
+		if !expiresAt.IsZero() {
+			record.Set("expires_at", expiresAt)
+		}
+		if err := app.Save(record); err != nil {
+			t.Fatalf("Failed to save password %q: %v", label, err)
+		}
+		return record
+	}
+
+	if _, _, configured := matchViewPassword(app, crypto, view, "anything"); configured {
+		t.Error("view without passwords should not count as configured")
+	}
+
+	acme := addPassword("Acme recruiter", "acme-secret", true, time.Time{})
+	addPassword("Revoked", "revoked-secret", false, time.Time{})
+	addPassword("Meetup", "meetup-secret", true, time.Now().Add(-time.Hour))
+
+	stored, err := app.FindRecordById("view_passwords", acme.Id)
+	if err != nil {
+		t.Fatalf("Failed to reload password: %v", err)
+	}
+	if stored.GetString("password") != "" {
+		t.Error("plaintext password should be cleared")
+	}
+	if !crypto.CheckPassword("acme-secret", stored.GetString("password_hash")) {
+		t.Error("password_hash should hold the hashed password")
+	}
+
+	// Updates without a new password keep the hash
+	stored.Set("is_active", false)
+	if err := app.Save(stored); err != nil {
+		t.Fatalf("Failed to revoke password: %v", err)
+	}
+	stored.Set("is_active", true)
+	if err := app.Save(stored); err != nil {
+		t.Fatalf("Failed to restore password: %v", err)
+	}
+
+	missing := core.NewRecord(collection)
+	missing.Set("view", viewID)
+	missing.Set("label", "No password")
+	if err := app.Save(missing); err == nil {
+		t.Error("saving a password without one should fail")
+	}
+
+	view.Set("password_hash", mustHash(t, crypto, "default-secret"))
+	tests := []struct {
+		password  string
+		wantLabel string
+		wantNamed bool
+	}{
+		{"acme-secret", "Acme recruiter", true},
+		{"default-secret", defaultViewPasswordLabel, false},
+		{"revoked-secret", "", false},
+		{"meetup-secret", "", false},
+		{"wrong", "", false},
+	}
+	for _, tt := range tests {
+		label, named, configured := matchViewPassword(app, crypto, view, tt.password)
+		if !configured {
+			t.Errorf("matchViewPassword(%q) reported no passwords configured", tt.password)
+		}
+		if label != tt.wantLabel || (named != nil) != tt.wantNamed {
+			t.Errorf("matchViewPassword(%q) = %q, named %v; want %q, named %v", tt.password, label, named != nil, tt.wantLabel, tt.wantNamed)
+		}
+	}
+
+	// Uses recorded through the same stale record, as concurrent unlocks would, all count
+	_, named, _ := matchViewPassword(app, crypto, view, "acme-secret")
+	for i := 0; i < 2; i++ {
+		if err := recordViewPasswordUse(app, named); err != nil {
+			t.Fatalf("recordViewPasswordUse() error = %v", err)
+		}
+	}
+	used, err := app.FindRecordById("view_passwords", acme.Id)
+	if err != nil {
+		t.Fatalf("Failed to reload password: %v", err)
+	}
+	if used.GetInt("use_count") != 2 || used.GetDateTime("last_used_at").IsZero() {
+		t.Errorf("use_count = %d, last_used_at = %v; want the use recorded", used.GetInt("use_count"), used.GetDateTime("last_used_at"))
+	}
+}
+
+func mustHash(t *testing.T, crypto *services.CryptoService, password string) string {
+	t.Helper()
+	hash, err := crypto.HashPassword(password)
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	return hash
+}
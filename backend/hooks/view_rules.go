@@ -0,0 +1,449 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// SectionRules turn a view section into a "smart section": instead of an explicit
+// items list, the section shows every visible record matching the conditions.
+//
+//	"rules": {
+//	  "match": "all",
+//	  "conditions": [{"field": "categories", "op": "contains", "value": "go"}],
+//	  "sort": "-published_at",
+//	  "limit": 5
+//	}
+type SectionRules struct {
+	Match      string             `json:"match,omitempty"` // "all" (default) or "any"
+	Conditions []SectionCondition `json:"conditions"`
+	Sort       string             `json:"sort,omitempty"`
+	Limit      int                `json:"limit,omitempty"`
+}
+
+// SectionCondition compares one record field with a value
+type SectionCondition struct {
+	Field string      `json:"field"`
+	Op    string      `json:"op"`
+	Value interface{} `json:"value"`
+}
+
+const (
+	maxRuleConditions   = 20
+	maxRuleSectionItems = 100
+	// Records scanned before conditions are applied
+	ruleCandidateLimit = 1000
+)
+
+// Supported condition operators
+var sectionRuleOps = map[string]bool{
+	"eq":        true, // equal (case-insensitive for text)
+	"neq":       true, // not equal
+	"contains":  true, // array has the value, or text contains it
+	"gte":       true, // greater or equal: numbers, dates, ordered selects
+	"lte":       true,
+	"gt":        true,
+	"lt":        true,
+	"within":    true, // date within the last "30d", "6m" or "10y"; an empty end_date is ongoing
+	"empty":     true, // field has no value
+	"not_empty": true,
+}
+
+// ruleOrdinals orders select values from lowest to highest, so gte/lte compare
+// by rank rather than alphabetically
+var ruleOrdinals = map[string][]string{
+	"proficiency": {"familiar", "proficient", "expert"},
+}
+
+// parseSectionRules reads the rules of a section config. It returns nil when the
+// section has no rules.
+func parseSectionRules(section map[string]interface{}) (*SectionRules, error) {
+	raw, ok := section["rules"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var rules SectionRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("invalid rules: %w", err)
+	}
+	return &rules, nil
+}
+
+// validate checks the rules against the section's collection, and the schema
+// fields of a custom section
+func (r *SectionRules) validate(collection *core.Collection, def sectionDef) error {
+	if r.Match != "" && r.Match != "all" && r.Match != "any" {
+		return fmt.Errorf("match must be \"all\" or \"any\"")
+	}
+	if len(r.Conditions) > maxRuleConditions {
+		return fmt.Errorf("at most %d conditions are allowed", maxRuleConditions)
+	}
+	if r.Limit < 0 || r.Limit > maxRuleSectionItems {
+		return fmt.Errorf("limit must be between 0 and %d", maxRuleSectionItems)
+	}
+	// Sorting happens in the database, so only real columns can be used
+	if r.Sort != "" && collection.Fields.GetByName(strings.TrimPrefix(r.Sort, "-")) == nil {
+		return fmt.Errorf("unknown sort field %q", strings.TrimPrefix(r.Sort, "-"))
+	}
+
+	for _, c := range r.Conditions {
+		_, custom := def.customField(c.Field)
+		if collection.Fields.GetByName(c.Field) == nil && !custom {
+			return fmt.Errorf("unknown field %q", c.Field)
+		}
+		if !sectionRuleOps[c.Op] {
+			return fmt.Errorf("unknown operator %q", c.Op)
+		}
+		if c.Op == "within" {
+			if _, err := parseRuleWindow(fmt.Sprint(c.Value)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// findRuleSectionRecords loads the visible records of a collection that match
+// the rules, in rule sort order (or the section's default order)
+func findRuleSectionRecords(app core.App, collection *core.Collection, def sectionDef, viewID string, rules *SectionRules) ([]*core.Record, error) {
+	if err := rules.validate(collection, def); err != nil {
+		return nil, err
+	}
+
+	filter, sortField, params := sectionListQuery(collection, def.Name)
+	if rules.Sort != "" {
+		sortField = rules.Sort
+	}
+	candidates, err := app.FindRecordsByFilter(collection, filter, sortField, ruleCandidateLimit, 0, params)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := rules.Limit
+	if limit == 0 {
+		limit = maxRuleSectionItems
+	}
+
+	now := time.Now()
+	var matched []*core.Record
+	for _, record := range candidates {
+		if !isRecordVisibleForSection(record, def.Name, viewID) || !rules.matches(newRuleRecord(record, def), now) {
+			continue
+		}
+		matched = append(matched, record)
+		if len(matched) >= limit {
+			break
+		}
+	}
+	return matched, nil
+}
+
+// ruleRecord reads the fields conditions refer to: record columns, or the
+// schema fields a custom section item keeps in its data object
+type ruleRecord struct {
+	record *core.Record
+	data   map[string]interface{}
+	def    sectionDef
+}
+
+func newRuleRecord(record *core.Record, def sectionDef) ruleRecord {
+	rr := ruleRecord{record: record, def: def}
+	if def.isCustom() {
+		json.Unmarshal([]byte(record.GetString("data")), &rr.data)
+	}
+	return rr
+}
+
+// kind returns how a field is compared: "number", "date", "boolean" or "text"
+func (rr ruleRecord) kind(field string) string {
+	if custom, ok := rr.def.customField(field); ok && field != "title" {
+		switch custom.Type {
+		case "number", "date", "boolean":
+			return custom.Type
+		}
+		return "text"
+	}
+	switch rr.record.Collection().Fields.GetByName(field).(type) {
+	case *core.NumberField:
+		return "number"
+	case *core.DateField, *core.AutodateField:
+		return "date"
+	case *core.BoolField:
+		return "boolean"
+	}
+	return "text"
+}
+
+func (rr ruleRecord) get(field string) interface{} {
+	if rr.data != nil && rr.record.Collection().Fields.GetByName(field) == nil {
+		return rr.data[field]
+	}
+	return rr.record.Get(field)
+}
+
+func (rr ruleRecord) getString(field string) string {
+	switch v := rr.get(field).(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case types.DateTime:
+		return v.String()
+	case types.JSONRaw:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (rr ruleRecord) getDateTime(field string) types.DateTime {
+	if d, ok := rr.get(field).(types.DateTime); ok {
+		return d
+	}
+	d, _ := types.ParseDateTime(rr.getString(field))
+	return d
+}
+
+// matches evaluates the conditions against a record
+func (r *SectionRules) matches(record ruleRecord, now time.Time) bool {
+	if len(r.Conditions) == 0 {
+		return true
+	}
+	anyMode := r.Match == "any"
+	for _, c := range r.Conditions {
+		ok := c.matches(record, now)
+		if anyMode && ok {
+			return true
+		}
+		if !anyMode && !ok {
+			return false
+		}
+	}
+	return !anyMode
+}
+
+func (c SectionCondition) matches(record ruleRecord, now time.Time) bool {
+	switch c.Op {
+	case "empty":
+		return isRuleValueEmpty(record, c.Field)
+	case "not_empty":
+		return !isRuleValueEmpty(record, c.Field)
+	case "contains":
+		return ruleContains(record, c.Field, fmt.Sprint(c.Value))
+	case "eq":
+		return ruleCompare(record, c.Field, c.Value) == 0
+	case "neq":
+		return ruleCompare(record, c.Field, c.Value) != 0
+	case "gte":
+		cmp := ruleCompare(record, c.Field, c.Value)
+		return cmp != ruleIncomparable && cmp >= 0
+	case "lte":
+		cmp := ruleCompare(record, c.Field, c.Value)
+		return cmp != ruleIncomparable && cmp <= 0
+	case "gt":
+		return ruleCompare(record, c.Field, c.Value) == 1
+	case "lt":
+		return ruleCompare(record, c.Field, c.Value) == -1
+	case "within":
+		window, err := parseRuleWindow(fmt.Sprint(c.Value))
+		if err != nil {
+			return false
+		}
+		date := record.getDateTime(c.Field)
+		if date.IsZero() {
+			// A job or degree without an end date is still ongoing
+			return c.Field == "end_date"
+		}
+		return !date.Time().Before(window(now))
+	}
+	return false
+}
+
+// ruleIncomparable is returned by ruleCompare when the values cannot be ordered
+const ruleIncomparable = 2
+
+// ruleCompare orders a record field against a value: -1, 0, 1 or ruleIncomparable
+func ruleCompare(record ruleRecord, field string, value interface{}) int {
+	target := fmt.Sprint(value)
+
+	if scale, ok := ruleOrdinals[field]; ok && !record.def.isCustom() {
+		a, b := indexOf(scale, strings.ToLower(record.getString(field))), indexOf(scale, strings.ToLower(target))
+		if a < 0 || b < 0 {
+			return ruleIncomparable
+		}
+		return compareInts(a, b)
+	}
+
+	switch record.kind(field) {
+	case "number":
+		n, err := strconv.ParseFloat(target, 64)
+		if err != nil {
+			return ruleIncomparable
+		}
+		current, err := strconv.ParseFloat(record.getString(field), 64)
+		if err != nil {
+			current = 0
+		}
+		switch {
+		case current < n:
+			return -1
+		case current > n:
+			return 1
+		}
+		return 0
+	case "date":
+		current := record.getDateTime(field)
+		parsed, err := time.Parse("2006-01-02", target)
+		if err != nil || current.IsZero() {
+			return ruleIncomparable
+		}
+		day := current.Time().UTC().Truncate(24 * time.Hour)
+		switch {
+		case day.Before(parsed):
+			return -1
+		case day.After(parsed):
+			return 1
+		}
+		return 0
+	case "boolean":
+		if (record.getString(field) == "true") == (target == "true") {
+			return 0
+		}
+		return ruleIncomparable
+	}
+
+	current := strings.ToLower(record.getString(field))
+	return strings.Compare(current, strings.ToLower(target))
+}
+
+// ruleContains reports whether an array field has the value (case-insensitive),
+// or a text field contains it
+func ruleContains(record ruleRecord, field, value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return false
+	}
+	if values := ruleStringSlice(record.get(field)); values != nil {
+		for _, v := range values {
+			if strings.ToLower(strings.TrimSpace(v)) == value {
+				return true
+			}
+		}
+		return false
+	}
+	return strings.Contains(strings.ToLower(record.getString(field)), value)
+}
+
+// ruleStringSlice returns the values of a multi-value or JSON array field, or nil
+func ruleStringSlice(raw interface{}) []string {
+	switch v := raw.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			out = append(out, fmt.Sprint(item))
+		}
+		return out
+	case types.JSONRaw:
+		var out []interface{}
+		if json.Unmarshal(v, &out) == nil {
+			return ruleStringSlice(out)
+		}
+	}
+	return nil
+}
+
+func isRuleValueEmpty(record ruleRecord, field string) bool {
+	if values := ruleStringSlice(record.get(field)); values != nil {
+		return len(values) == 0
+	}
+	value := strings.TrimSpace(record.getString(field))
+	return value == "" || value == "null" || value == "[]" || value == "{}"
+}
+
+// parseRuleWindow parses "30d", "6m" or "10y" into a function returning the
+// start of the window for a given time
+func parseRuleWindow(value string) (func(time.Time) time.Time, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+	if len(value) < 2 {
+		return nil, fmt.Errorf("invalid window %q (use e.g. 30d, 6m, 10y)", value)
+	}
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("invalid window %q (use e.g. 30d, 6m, 10y)", value)
+	}
+	switch value[len(value)-1] {
+	case 'd':
+		return func(t time.Time) time.Time { return t.AddDate(0, 0, -n) }, nil
+	case 'm':
+		return func(t time.Time) time.Time { return t.AddDate(0, -n, 0) }, nil
+	case 'y':
+		return func(t time.Time) time.Time { return t.AddDate(-n, 0, 0) }, nil
+	}
+	return nil, fmt.Errorf("invalid window %q (use e.g. 30d, 6m, 10y)", value)
+}
+
+// validateViewSectionRules checks the rules of every section of a view record
+func validateViewSectionRules(app core.App, view *core.Record) error {
+	var sections []map[string]interface{}
+	if raw := view.GetString("sections"); raw != "" && raw != "null" {
+		if err := json.Unmarshal([]byte(raw), &sections); err != nil {
+			return nil
+		}
+	}
+
+	isDemoMode := strings.HasPrefix(view.Collection().Name, "demo_")
+	for _, section := range sections {
+		sectionName, _ := section["section"].(string)
+		rules, err := parseSectionRules(section)
+		if err != nil {
+			return fmt.Errorf("section %s: %w", sectionName, err)
+		}
+		if rules == nil {
+			continue
+		}
+		def, ok := resolveSection(app, sectionName, isDemoMode)
+		if !ok {
+			return fmt.Errorf("section %s does not support rules", sectionName)
+		}
+		collection, err := app.FindCachedCollectionByNameOrId(def.Collection)
+		if err != nil {
+			return fmt.Errorf("section %s: %w", sectionName, err)
+		}
+		if err := rules.validate(collection, def); err != nil {
+			return fmt.Errorf("section %s rules: %w", sectionName, err)
+		}
+	}
+	return nil
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,159 @@
+package hooks
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestRuleBasedSections(t *testing.T) {
+	app := newMigratedTestApp(t)
+
+	save := func(collection string, fields map[string]interface{}) *core.Record {
+		coll, err := app.FindCollectionByNameOrId(collection)
+		if err != nil {
+			t.Fatalf("Failed to find %s: %v", collection, err)
+		}
+		record := core.NewRecord(coll)
+		record.Set("visibility", "public")
+		for key, value := range fields {
+			record.Set(key, value)
+		}
+		if err := app.Save(record); err != nil {
+			t.Fatalf("Failed to save %s: %v", collection, err)
+		}
+		return record
+	}
+
+	save("projects", map[string]interface{}{"title": "CLI", "categories": []string{"Go", "tools"}})
+	save("projects", map[string]interface{}{"title": "Website", "categories": []string{"svelte"}})
+	save("projects", map[string]interface{}{"title": "Secret", "categories": []string{"go"}, "visibility": "private"})
+	save("projects", map[string]interface{}{"title": "Draft", "categories": []string{"go"}, "is_draft": true})
+
+	now := time.Now().UTC()
+	save("experience", map[string]interface{}{"company": "Current", "title": "Engineer", "start_date": now.AddDate(-2, 0, 0)})
+	save("experience", map[string]interface{}{"company": "Recent", "title": "Engineer", "end_date": now.AddDate(-3, 0, 0)})
+	save("experience", map[string]interface{}{"company": "Ancient", "title": "Engineer", "end_date": now.AddDate(-15, 0, 0)})
+
+	save("skills", map[string]interface{}{"name": "Go", "proficiency": "expert"})
+	save("skills", map[string]interface{}{"name": "Kubernetes", "proficiency": "proficient"})
+	save("skills", map[string]interface{}{"name": "Rust", "proficiency": "familiar"})
+	save("skills", map[string]interface{}{"name": "COBOL"})
+
+	for i := 0; i < 7; i++ {
+		save("posts", map[string]interface{}{
+			"title":        fmt.Sprintf("K8s %d", i),
+			"tags":         []string{"kubernetes"},
+			"published_at": now.AddDate(0, 0, -i),
+		})
+	}
+	save("posts", map[string]interface{}{"title": "Cooking", "tags": []string{"food"}, "published_at": now})
+
+	view := save("views", map[string]interface{}{
+		"name":      "Platform",
+		"slug":      "platform",
+		"is_active": true,
+		"sections": []map[string]interface{}{
+			{"section": "projects", "enabled": true, "rules": map[string]interface{}{
+				"conditions": []map[string]interface{}{{"field": "categories", "op": "contains", "value": "go"}},
+			}},
+			{"section": "experience", "enabled": true, "rules": map[string]interface{}{
+				"conditions": []map[string]interface{}{{"field": "end_date", "op": "within", "value": "10y"}},
+			}},
+			{"section": "skills", "enabled": true, "rules": map[string]interface{}{
+				"conditions": []map[string]interface{}{{"field": "proficiency", "op": "gte", "value": "proficient"}},
+			}},
+			{"section": "posts", "enabled": true, "rules": map[string]interface{}{
+				"conditions": []map[string]interface{}{{"field": "tags", "op": "contains", "value": "kubernetes"}},
+				"sort":       "-published_at",
+				"limit":      5,
+			}},
+			{"section": "talks", "enabled": true, "rules": map[string]interface{}{
+				"match": "any",
+				"conditions": []map[string]interface{}{
+					{"field": "title", "op": "contains", "value": "nothing"},
+					{"field": "event", "op": "empty"},
+				},
+			}},
+		},
+	})
+	save("talks", map[string]interface{}{"title": "Keynote", "event": "GopherCon"})
+	save("talks", map[string]interface{}{"title": "Meetup"})
+
+	sections := buildViewSections(app, view, false)
+	names := func(section, field string) string {
+		items, _ := sections.Data[section].([]map[string]interface{})
+		var out []string
+		for _, item := range items {
+			out = append(out, fmt.Sprint(item[field]))
+		}
+		return strings.Join(out, ",")
+	}
+
+	if got := names("projects", "title"); got != "CLI" {
+		t.Errorf("projects = %q, want CLI (private and drafts excluded)", got)
+	}
+	if got := names("experience", "company"); !strings.Contains(got, "Current") || !strings.Contains(got, "Recent") || strings.Contains(got, "Ancient") {
+		t.Errorf("experience = %q, want Current and Recent", got)
+	}
+	if got := names("skills", "name"); got != "Go,Kubernetes" {
+		t.Errorf("skills = %q, want Go,Kubernetes", got)
+	}
+	if got := names("posts", "title"); got != "K8s 0,K8s 1,K8s 2,K8s 3,K8s 4" {
+		t.Errorf("posts = %q, want the 5 newest kubernetes posts", got)
+	}
+	if got := names("talks", "title"); got != "Meetup" {
+		t.Errorf("talks = %q, want Meetup", got)
+	}
+
+	// New content lands in the section without editing the view
+	save("projects", map[string]interface{}{"title": "Daemon", "categories": []string{"go"}, "sort_order": 1})
+	sections = buildViewSections(app, view, false)
+	if got := names("projects", "title"); got != "CLI,Daemon" {
+		t.Errorf("projects after adding one = %q, want CLI,Daemon", got)
+	}
+}
+
+func TestValidateViewSectionRules(t *testing.T) {
+	app := newMigratedTestApp(t)
+	views, err := app.FindCollectionByNameOrId("views")
+	if err != nil {
+		t.Fatalf("Failed to find views: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		rules   interface{}
+		section string
+		wantErr string
+	}{
+		{"valid", map[string]interface{}{"conditions": []map[string]interface{}{{"field": "tags", "op": "contains", "value": "go"}}, "sort": "-published_at", "limit": 5}, "posts", ""},
+		{"unknown field", map[string]interface{}{"conditions": []map[string]interface{}{{"field": "nope", "op": "eq", "value": "x"}}}, "posts", "unknown field"},
+		{"unknown operator", map[string]interface{}{"conditions": []map[string]interface{}{{"field": "tags", "op": "like", "value": "x"}}}, "posts", "unknown operator"},
+		{"bad window", map[string]interface{}{"conditions": []map[string]interface{}{{"field": "end_date", "op": "within", "value": "ten years"}}}, "experience", "invalid window"},
+		{"bad sort", map[string]interface{}{"sort": "-nope"}, "posts", "unknown sort field"},
+		{"bad limit", map[string]interface{}{"limit": 1000}, "posts", "limit"},
+		{"bad match", map[string]interface{}{"match": "some"}, "posts", "match"},
+		{"unsupported section", map[string]interface{}{}, "profile", "does not support rules"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := core.NewRecord(views)
+			view.Set("sections", []map[string]interface{}{{"section": tt.section, "enabled": true, "rules": tt.rules}})
+			err := validateViewSectionRules(app, view)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateViewSectionRules() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateViewSectionRules() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
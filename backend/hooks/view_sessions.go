@@ -0,0 +1,171 @@
+package hooks
+
+import (
+	"time"
+
+	"facet/services"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// viewSessionRetention is how long expired sessions stay listed before pruning
+const viewSessionRetention = 30 * 24 * time.Hour
+
+// viewSessionJSON is a session as returned by the admin sessions endpoint
+type viewSessionJSON struct {
+	ID            string `json:"id"`
+	ViewID        string `json:"view_id"`
+	PasswordLabel string `json:"password_label"`
+	IssuedAt      string `json:"issued_at"`
+	ExpiresAt     string `json:"expires_at"`
+	RevokedAt     string `json:"revoked_at,omitempty"`
+	Active        bool   `json:"active"`
+}
+
+// recordViewSession stores a session issued by /api/password/check under its
+// jti. named is the view_passwords record used, nil for the default password.
+func recordViewSession(app core.App, claims *services.ViewAccessClaims, label string, named *core.Record) error {
+	collection, err := app.FindCollectionByNameOrId("view_sessions")
+	if err != nil {
+		return err
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("view", claims.ViewID)
+	record.Set("jti", claims.ID)
+	if named != nil {
+		record.Set("view_password", named.Id)
+	}
+	record.Set("password_label", label)
+	record.Set("issued_at", claims.IssuedAt.Time)
+	record.Set("expires_at", claims.ExpiresAt.Time)
+	return app.Save(record)
+}
+
+// isViewSessionRevoked backs ValidateViewAccessJWT: a session is usable only
+// if it was stored for the view and has not been revoked since
+func isViewSessionRevoked(app core.App, jti, viewID string) bool {
+	if jti == "" {
+		return true
+	}
+	record, err := app.FindFirstRecordByFilter("view_sessions", "jti = {:jti}", dbx.Params{"jti": jti})
+	if err != nil {
+		return true
+	}
+	return record.GetString("view") != viewID || !record.GetDateTime("revoked_at").IsZero()
+}
+
+// revokeViewSessions marks the outstanding sessions matching where as revoked
+func revokeViewSessions(app core.App, where dbx.Expression) (int64, error) {
+	now := types.NowDateTime().String()
+	result, err := app.DB().Update(
+		"view_sessions",
+		dbx.Params{"revoked_at": now},
+		dbx.And(where, dbx.NewExp("revoked_at = '' AND expires_at > {:now}", dbx.Params{"now": now})),
+	).Execute()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// bindViewSessionRevocation revokes the sessions opened with a password once
+// that password changes: all sessions of a view when its default password
+// changes, and those of a named password when it is changed, revoked or deleted
+//
+// The stored record is compared rather than Original(), which a reused record
+// does not refresh between saves.
+func bindViewSessionRevocation(app core.App) {
+	app.OnRecordUpdate("views").BindFunc(func(e *core.RecordEvent) error {
+		stored, err := e.App.FindRecordById("views", e.Record.Id)
+		if err != nil {
+			return err
+		}
+		oldHash := stored.GetString("password_hash")
+		if err := e.Next(); err != nil {
+			return err
+		}
+		if e.Record.GetString("password_hash") == oldHash {
+			return nil
+		}
+		if _, err := revokeViewSessions(e.App, dbx.HashExp{"view": e.Record.Id}); err != nil {
+			e.App.Logger().Error("Failed to revoke view sessions", "error", err, "view_id", e.Record.Id)
+		}
+		return nil
+	})
+
+	app.OnRecordUpdate("view_passwords").BindFunc(func(e *core.RecordEvent) error {
+		original, err := e.App.FindRecordById("view_passwords", e.Record.Id)
+		if err != nil {
+			return err
+		}
+		if err := e.Next(); err != nil {
+			return err
+		}
+		if e.Record.GetString("password_hash") == original.GetString("password_hash") &&
+			(e.Record.GetBool("is_active") || !original.GetBool("is_active")) {
+			return nil
+		}
+		if _, err := revokeViewSessions(e.App, dbx.HashExp{"view_password": e.Record.Id}); err != nil {
+			e.App.Logger().Error("Failed to revoke view sessions", "error", err, "password_id", e.Record.Id)
+		}
+		return nil
+	})
+
+	// Revoke before deleting, while the sessions still reference the password
+	app.OnRecordDelete("view_passwords").BindFunc(func(e *core.RecordEvent) error {
+		if _, err := revokeViewSessions(e.App, dbx.HashExp{"view_password": e.Record.Id}); err != nil {
+			return err
+		}
+		return e.Next()
+	})
+}
+
+// listViewSessions returns the sessions of a view, newest first
+func listViewSessions(app core.App, viewID string, now time.Time) ([]viewSessionJSON, error) {
+	records, err := app.FindRecordsByFilter(
+		"view_sessions",
+		"view = {:view}",
+		"-issued_at",
+		100,
+		0,
+		dbx.Params{"view": viewID},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]viewSessionJSON, 0, len(records))
+	for _, record := range records {
+		revokedAt := record.GetDateTime("revoked_at")
+		expiresAt := record.GetDateTime("expires_at")
+		session := viewSessionJSON{
+			ID:            record.Id,
+			ViewID:        record.GetString("view"),
+			PasswordLabel: record.GetString("password_label"),
+			IssuedAt:      record.GetDateTime("issued_at").String(),
+			ExpiresAt:     expiresAt.String(),
+			Active:        revokedAt.IsZero() && now.Before(expiresAt.Time()),
+		}
+		if !revokedAt.IsZero() {
+			session.RevokedAt = revokedAt.String()
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// pruneViewSessions deletes sessions that expired longer than the retention ago
+func pruneViewSessions(app core.App, now time.Time) (int64, error) {
+	cutoff, err := types.ParseDateTime(now.Add(-viewSessionRetention))
+	if err != nil {
+		return 0, err
+	}
+	result, err := app.DB().Delete("view_sessions", dbx.NewExp("expires_at < {:cutoff}", dbx.Params{"cutoff": cutoff.String()})).Execute()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,154 @@
+package hooks
+
+import (
+	"testing"
+	"time"
+
+	"facet/services"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestViewSessionRevocation(t *testing.T) {
+	app := newMigratedTestApp(t)
+	_, _, viewID := seedImportFixture(t, app)
+	crypto := services.NewCryptoService("test-encryption-key-32-chars-ok!")
+	bindViewPasswordHooks(app, crypto)
+	bindViewSessionRevocation(app)
+	crypto.SetViewAccessRevocationCheck(func(jti, viewID string) bool {
+		return isViewSessionRevoked(app, jti, viewID)
+	})
+
+	view, err := app.FindRecordById("views", viewID)
+	if err != nil {
+		t.Fatalf("Failed to find view: %v", err)
+	}
+	view.Set("visibility", "password")
+	view.Set("password_hash", mustHash(t, crypto, "default-secret"))
+	if err := app.Save(view); err != nil {
+		t.Fatalf("Failed to protect view: %v", err)
+	}
+
+	passwords, err := app.FindCollectionByNameOrId("view_passwords")
+	if err != nil {
+		t.Fatalf("Failed to find view_passwords: %v", err)
+	}
+	named := core.NewRecord(passwords)
+	named.Set("view", viewID)
+	named.Set("label", "Acme recruiter")
+	named.Set("password", "acme-secret")
+	named.Set("is_active", true)
+	if err := app.Save(named); err != nil {
+		t.Fatalf("Failed to save named password: %v", err)
+	}
+
+	issue := func(label string, password *core.Record) string {
+		t.Helper()
+		token, claims, err := crypto.IssueViewAccessJWT(viewID, time.Hour)
+		if err != nil {
+			t.Fatalf("IssueViewAccessJWT() error = %v", err)
+		}
+		if err := recordViewSession(app, claims, label, password); err != nil {
+			t.Fatalf("recordViewSession() error = %v", err)
+		}
+		return token
+	}
+	valid := func(token string) bool {
+		_, err := crypto.ValidateViewAccessJWT(token)
+		return err == nil
+	}
+
+	// Tokens without a stored session are not accepted
+	unstored, _, _ := crypto.GenerateViewAccessJWT(viewID, time.Hour)
+	if valid(unstored) {
+		t.Error("token without a stored session should be rejected")
+	}
+
+	// Revoking one session leaves the others alone
+	first := issue(defaultViewPasswordLabel, nil)
+	second := issue(defaultViewPasswordLabel, nil)
+	sessions, err := listViewSessions(app, viewID, time.Now())
+	if err != nil {
+		t.Fatalf("listViewSessions() error = %v", err)
+	}
+	if len(sessions) != 2 || !sessions[0].Active || sessions[0].PasswordLabel != defaultViewPasswordLabel {
+		t.Fatalf("sessions = %+v, want two active default sessions", sessions)
+	}
+	session, err := app.FindFirstRecordByFilter("view_sessions", "view = {:view}", map[string]interface{}{"view": viewID})
+	if err != nil {
+		t.Fatalf("Failed to find session: %v", err)
+	}
+	if revoked, _ := revokeViewSessions(app, dbx.HashExp{"id": session.Id}); revoked != 1 {
+		t.Fatalf("revokeViewSessions() revoked %d, want 1", revoked)
+	}
+	if valid(first) == valid(second) {
+		t.Error("revoking one session should leave exactly one of the two valid")
+	}
+
+	// Revoking a named password revokes its sessions only
+	acme := issue("Acme recruiter", named)
+	other := issue(defaultViewPasswordLabel, nil)
+	named.Set("is_active", false)
+	if err := app.Save(named); err != nil {
+		t.Fatalf("Failed to revoke named password: %v", err)
+	}
+	if valid(acme) {
+		t.Error("session of a revoked password should be rejected")
+	}
+	if !valid(other) {
+		t.Error("sessions of other passwords should stay valid")
+	}
+
+	// Deleting a named password revokes its sessions too
+	named.Set("is_active", true)
+	if err := app.Save(named); err != nil {
+		t.Fatalf("Failed to restore named password: %v", err)
+	}
+	acme = issue("Acme recruiter", named)
+	if err := app.Delete(named); err != nil {
+		t.Fatalf("Failed to delete named password: %v", err)
+	}
+	if valid(acme) {
+		t.Error("session of a deleted password should be rejected")
+	}
+
+	// Changing the view's password signs everyone out
+	view.Set("password_hash", mustHash(t, crypto, "new-secret"))
+	if err := app.Save(view); err != nil {
+		t.Fatalf("Failed to change view password: %v", err)
+	}
+	if valid(other) {
+		t.Error("sessions should be revoked when the view password changes")
+	}
+
+	// Unrelated view updates keep sessions
+	fresh := issue(defaultViewPasswordLabel, nil)
+	view.Set("description", "Updated")
+	if err := app.Save(view); err != nil {
+		t.Fatalf("Failed to update view: %v", err)
+	}
+	if !valid(fresh) {
+		t.Error("updating a view without changing its password should keep sessions")
+	}
+
+	// Sessions of another view are not accepted here
+	_, claims, _ := crypto.IssueViewAccessJWT(viewID, time.Hour)
+	if !isViewSessionRevoked(app, claims.ID, viewID) {
+		t.Error("unknown jti should count as revoked")
+	}
+	if err := recordViewSession(app, claims, defaultViewPasswordLabel, nil); err != nil {
+		t.Fatalf("recordViewSession() error = %v", err)
+	}
+	if !isViewSessionRevoked(app, claims.ID, "otherview123456") {
+		t.Error("session should not be valid for another view")
+	}
+
+	// Long-expired sessions are pruned
+	if deleted, err := pruneViewSessions(app, time.Now()); err != nil || deleted != 0 {
+		t.Errorf("pruneViewSessions() = %d, %v; want recent sessions kept", deleted, err)
+	}
+	if deleted, err := pruneViewSessions(app, time.Now().Add(viewSessionRetention+2*time.Hour)); err != nil || deleted == 0 {
+		t.Errorf("pruneViewSessions() = %d, %v; want expired sessions deleted", deleted, err)
+	}
+}
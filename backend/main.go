@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"facet/hooks"
 	"facet/services"
@@ -38,6 +39,10 @@ func main() {
 	shareService := services.NewShareService(cryptoService)
 	testimonialService := services.NewTestimonialService(cryptoService)
 	rateLimitService := services.NewRateLimitService()
+	lockoutService := services.NewLockoutService()
+	accessLogService := services.NewAccessLogService(app.Logger())
+	counterService := services.NewCounterService(10 * time.Second)
+	responseCache := services.NewResponseCache(1000, 10*time.Minute)
 
 	// Register migrations
 	migratecmd.MustRegister(app, app.RootCmd, migratecmd.Config{
@@ -51,15 +56,29 @@ func main() {
 	hooks.RegisterPasswordChangeEndpoint(app, rateLimitService) // Password change endpoint for first-time setup
 	hooks.RegisterGitHubHooks(app, githubService, aiService, cryptoService)
 	hooks.RegisterAIHooks(app, aiService, cryptoService)
-	hooks.RegisterShareHooks(app, shareService, cryptoService, rateLimitService)
-	hooks.RegisterPasswordHooks(app, cryptoService, rateLimitService)
+	hooks.RegisterShareHooks(app, shareService, cryptoService, rateLimitService, counterService)
+	hooks.RegisterApplicationHooks(app)
+	hooks.RegisterPasswordHooks(app, cryptoService, rateLimitService, lockoutService)
+	hooks.RegisterAccessRequestHooks(app, shareService, rateLimitService)
 	hooks.RegisterSiteSettingsHooks(app)
 	hooks.RegisterMediaHooks(app)
-	hooks.RegisterViewHooks(app, cryptoService, shareService, rateLimitService)
+	hooks.RegisterViewHooks(app, cryptoService, shareService, rateLimitService, accessLogService, counterService, responseCache)
+	hooks.RegisterCustomSectionHooks(app)
+	hooks.RegisterResponseCacheHooks(app, responseCache)
+	hooks.RegisterCounterHooks(app, counterService)
+	hooks.RegisterAccessLogHooks(app, accessLogService)
+	hooks.RegisterAnalyticsHooks(app)
 	hooks.RegisterOAuthEnvConfig(app)
 	hooks.RegisterExportHooks(app)
-	hooks.RegisterResumeHooks(app, cryptoService)
+	hooks.RegisterImportHooks(app)
+	hooks.RegisterJSONResumeHooks(app)
+	hooks.RegisterLinkedInImportHooks(app)
+	hooks.RegisterResumeHooks(app, cryptoService, shareService, counterService)
+	hooks.RegisterResumeTemplateHooks(app)
+	hooks.RegisterResumeReferenceHooks(app)
 	hooks.RegisterResumeUploadHooks(app, cryptoService) // Resume upload & parsing
+	hooks.RegisterTailorHooks(app, aiService, cryptoService)
+	hooks.RegisterATSHooks(app)
 	hooks.RegisterSeedHook(app)
 	hooks.RegisterDemoHandlers(app)
 	hooks.RegisterTestimonialHooks(app, testimonialService, rateLimitService)
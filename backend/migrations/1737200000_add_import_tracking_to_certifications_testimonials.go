@@ -0,0 +1,62 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// Certifications and testimonials can now be imported (LinkedIn data export),
+		// so they get the same import tracking fields as experience, skills, etc.
+		collectionsToUpdate := []string{"certifications", "testimonials"}
+
+		for _, collectionName := range collectionsToUpdate {
+			collection, err := app.FindCollectionByNameOrId(collectionName)
+			if err != nil {
+				continue
+			}
+
+			if collection.Fields.GetByName("import_session_id") == nil {
+				collection.Fields.Add(&core.TextField{
+					Name: "import_session_id",
+					Max:  36, // UUID length
+				})
+			}
+			if collection.Fields.GetByName("import_filename") == nil {
+				collection.Fields.Add(&core.TextField{
+					Name: "import_filename",
+					Max:  255,
+				})
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func(app core.App) error {
+		collectionsToUpdate := []string{"certifications", "testimonials"}
+
+		for _, collectionName := range collectionsToUpdate {
+			collection, err := app.FindCollectionByNameOrId(collectionName)
+			if err != nil {
+				continue
+			}
+
+			if field := collection.Fields.GetByName("import_session_id"); field != nil {
+				collection.Fields.RemoveById(field.GetId())
+			}
+			if field := collection.Fields.GetByName("import_filename"); field != nil {
+				collection.Fields.RemoveById(field.GetId())
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Resume generation runs as a background job queue backed by view_exports.
+// These fields let workers pick jobs in order, report progress, and recover
+// jobs interrupted by a restart.
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("view_exports")
+		if err != nil {
+			return err
+		}
+
+		// When the job was queued (jobs are processed oldest first)
+		if collection.Fields.GetByName("queued_at") == nil {
+			collection.Fields.Add(&core.DateField{
+				Name: "queued_at",
+			})
+		}
+
+		// When a worker last picked up the job
+		if collection.Fields.GetByName("started_at") == nil {
+			collection.Fields.Add(&core.DateField{
+				Name: "started_at",
+			})
+		}
+
+		// Progress percentage (0-100) and current stage for status polling and SSE
+		if collection.Fields.GetByName("progress") == nil {
+			collection.Fields.Add(&core.NumberField{Name: "progress"})
+		}
+
+		if collection.Fields.GetByName("progress_message") == nil {
+			collection.Fields.Add(&core.TextField{
+				Name: "progress_message",
+				Max:  200,
+			})
+		}
+
+		// Number of times a worker has started this job (restarts re-queue it)
+		if collection.Fields.GetByName("attempts") == nil {
+			collection.Fields.Add(&core.NumberField{Name: "attempts"})
+		}
+
+		collection.Indexes = append(collection.Indexes, "CREATE INDEX idx_view_exports_queue ON view_exports(status, queued_at)")
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("view_exports")
+		if err != nil {
+			return nil
+		}
+
+		collection.RemoveIndex("idx_view_exports_queue")
+		for _, name := range []string{"queued_at", "started_at", "progress", "progress_message", "attempts"} {
+			collection.Fields.RemoveByName(name)
+		}
+
+		return app.Save(collection)
+	})
+}
@@ -0,0 +1,81 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Resume templates render view data to Markdown or HTML with Go text/template,
+// so resumes can be generated without an AI provider. Built-in layouts ship
+// with the binary; this collection holds user-managed ones.
+func init() {
+	m.Register(func(app core.App) error {
+		collection := core.NewBaseCollection("resume_templates")
+
+		collection.Fields.Add(&core.TextField{Name: "name", Required: true, Max: 200})
+		collection.Fields.Add(&core.TextField{
+			Name:     "slug",
+			Required: true,
+			Max:      100,
+			Pattern:  `^[a-z0-9]+(?:-[a-z0-9]+)*$`,
+		})
+		collection.Fields.Add(&core.TextField{Name: "description", Max: 500})
+		collection.Fields.Add(&core.SelectField{
+			Name:      "format",
+			Values:    []string{"markdown", "html"},
+			Required:  true,
+			MaxSelect: 1,
+		})
+		collection.Fields.Add(&core.TextField{Name: "content", Required: true, Max: 200000})
+
+		collection.Indexes = append(collection.Indexes, "CREATE UNIQUE INDEX idx_resume_templates_slug ON resume_templates(slug)")
+
+		// Only authenticated users can manage templates
+		authRule := "@request.auth.id != ''"
+		collection.ListRule = &authRule
+		collection.ViewRule = &authRule
+		collection.CreateRule = &authRule
+		collection.UpdateRule = &authRule
+		collection.DeleteRule = &authRule
+
+		if err := app.Save(collection); err != nil {
+			return err
+		}
+
+		// Template exports can be delivered as Markdown or HTML as well as PDF/DOCX
+		exports, err := app.FindCollectionByNameOrId("view_exports")
+		if err != nil {
+			return err
+		}
+
+		if format, ok := exports.Fields.GetByName("format").(*core.SelectField); ok {
+			format.Values = []string{"pdf", "docx", "md", "html"}
+		}
+		if file, ok := exports.Fields.GetByName("file").(*core.FileField); ok {
+			file.MimeTypes = append(file.MimeTypes, "text/plain", "text/markdown", "text/html")
+		}
+
+		return app.Save(exports)
+	}, func(app core.App) error {
+		if exports, err := app.FindCollectionByNameOrId("view_exports"); err == nil {
+			if format, ok := exports.Fields.GetByName("format").(*core.SelectField); ok {
+				format.Values = []string{"pdf", "docx"}
+			}
+			if file, ok := exports.Fields.GetByName("file").(*core.FileField); ok {
+				file.MimeTypes = []string{
+					"application/pdf",
+					"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+				}
+			}
+			if err := app.Save(exports); err != nil {
+				return err
+			}
+		}
+
+		collection, err := app.FindCollectionByNameOrId("resume_templates")
+		if err != nil {
+			return nil
+		}
+		return app.Delete(collection)
+	})
+}
@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Adds an optional reference Word document whose styles DOCX resume exports use
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("site_settings")
+		if err != nil {
+			return nil
+		}
+
+		collection.Fields.Add(&core.FileField{
+			Name:      "resume_reference_docx",
+			MaxSelect: 1,
+			MaxSize:   10485760,
+		})
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("site_settings")
+		if err != nil {
+			return nil
+		}
+
+		field := collection.Fields.GetByName("resume_reference_docx")
+		if field != nil {
+			collection.Fields.RemoveById(field.GetId())
+			return app.Save(collection)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,71 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Applications track a job hunt: one record per company and role. Share tokens can
+// point at an application so the person holding the link gets a personal greeting
+// and the pipeline shows whether they opened it.
+func init() {
+	m.Register(func(app core.App) error {
+		collection := core.NewBaseCollection("applications")
+
+		collection.Fields.Add(&core.TextField{Name: "company", Required: true, Max: 200})
+		collection.Fields.Add(&core.TextField{Name: "role", Max: 200})
+		collection.Fields.Add(&core.URLField{Name: "job_url"})
+		collection.Fields.Add(&core.SelectField{
+			Name:      "status",
+			Values:    []string{"applied", "interviewing", "offer", "rejected"},
+			MaxSelect: 1,
+		})
+		collection.Fields.Add(&core.TextField{Name: "contact_name", Max: 200})
+		collection.Fields.Add(&core.TextField{Name: "notes", Max: 10000})
+		collection.Fields.Add(&core.DateField{Name: "applied_at"})
+		collection.Fields.Add(&core.DateField{Name: "follow_up_at"})
+		// Shown to the token holder; empty values fall back to a default greeting and the view's CTA
+		collection.Fields.Add(&core.TextField{Name: "greeting", Max: 300})
+		collection.Fields.Add(&core.TextField{Name: "cta_text", Max: 100})
+		collection.Fields.Add(&core.URLField{Name: "cta_url"})
+		collection.Fields.Add(&core.AutodateField{Name: "created", OnCreate: true})
+		collection.Fields.Add(&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true})
+
+		authRule := "@request.auth.id != ''"
+		collection.ListRule = &authRule
+		collection.ViewRule = &authRule
+		collection.CreateRule = &authRule
+		collection.UpdateRule = &authRule
+		collection.DeleteRule = &authRule
+
+		if err := app.Save(collection); err != nil {
+			return err
+		}
+
+		tokens, err := app.FindCollectionByNameOrId("share_tokens")
+		if err != nil {
+			return nil
+		}
+		tokens.Fields.Add(&core.RelationField{
+			Name:         "application",
+			CollectionId: collection.Id,
+			MaxSelect:    1,
+		})
+		return app.Save(tokens)
+	}, func(app core.App) error {
+		if tokens, err := app.FindCollectionByNameOrId("share_tokens"); err == nil {
+			if field := tokens.Fields.GetByName("application"); field != nil {
+				tokens.Fields.RemoveById(field.GetId())
+				if err := app.Save(tokens); err != nil {
+					return err
+				}
+			}
+		}
+
+		collection, err := app.FindCollectionByNameOrId("applications")
+		if err != nil {
+			return nil
+		}
+		return app.Delete(collection)
+	})
+}
@@ -0,0 +1,103 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Access events record each anonymous visit to a view: when, through which share
+// token, and a coarse description of the visitor. IPs are stored only as a hash
+// with a daily salt. Events are pruned after the retention set in site_settings.
+func init() {
+	m.Register(func(app core.App) error {
+		views, err := app.FindCollectionByNameOrId("views")
+		if err != nil {
+			return err
+		}
+		tokens, err := app.FindCollectionByNameOrId("share_tokens")
+		if err != nil {
+			return err
+		}
+
+		collection := core.NewBaseCollection("access_events")
+
+		collection.Fields.Add(&core.RelationField{
+			Name:          "view",
+			CollectionId:  views.Id,
+			Required:      true,
+			MaxSelect:     1,
+			CascadeDelete: true,
+		})
+		collection.Fields.Add(&core.RelationField{
+			Name:         "share_token",
+			CollectionId: tokens.Id,
+			MaxSelect:    1,
+		})
+		collection.Fields.Add(&core.TextField{Name: "ip_hash", Max: 64})
+		collection.Fields.Add(&core.TextField{Name: "ua_family", Max: 100})
+		collection.Fields.Add(&core.BoolField{Name: "is_bot"})
+		collection.Fields.Add(&core.TextField{Name: "referrer_host", Max: 255})
+		collection.Fields.Add(&core.TextField{Name: "country", Max: 2})
+		collection.Fields.Add(&core.AutodateField{Name: "created", OnCreate: true})
+
+		collection.Indexes = append(collection.Indexes,
+			"CREATE INDEX idx_access_events_view ON access_events(view, created)",
+			"CREATE INDEX idx_access_events_token ON access_events(share_token, created)",
+			"CREATE INDEX idx_access_events_created ON access_events(created)",
+		)
+
+		// Authenticated users can read and delete; only the server records events
+		authRule := "@request.auth.id != ''"
+		collection.ListRule = &authRule
+		collection.ViewRule = &authRule
+		collection.DeleteRule = &authRule
+
+		if err := app.Save(collection); err != nil {
+			return err
+		}
+
+		settings, err := app.FindCollectionByNameOrId("site_settings")
+		if err != nil {
+			return nil
+		}
+		if settings.Fields.GetByName("access_log_retention_days") == nil {
+			settings.Fields.Add(&core.NumberField{
+				Name:    "access_log_retention_days",
+				Min:     floatPtr(0),
+				Max:     floatPtr(3650),
+				OnlyInt: true,
+			})
+			if err := app.Save(settings); err != nil {
+				return err
+			}
+		}
+
+		// Keep 90 days by default; 0 keeps events forever
+		records, err := app.FindAllRecords("site_settings")
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			record.Set("access_log_retention_days", 90)
+			if err := app.Save(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, func(app core.App) error {
+		if settings, err := app.FindCollectionByNameOrId("site_settings"); err == nil {
+			if field := settings.Fields.GetByName("access_log_retention_days"); field != nil {
+				settings.Fields.RemoveById(field.GetId())
+				if err := app.Save(settings); err != nil {
+					return err
+				}
+			}
+		}
+
+		collection, err := app.FindCollectionByNameOrId("access_events")
+		if err != nil {
+			return nil
+		}
+		return app.Delete(collection)
+	})
+}
@@ -0,0 +1,68 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// First-party analytics keep one counter per day, kind (view, post, project,
+// talk, referrer, token) and key. No cookies, IPs or per-visit rows are stored,
+// which replaces the optional Google Analytics integration.
+func init() {
+	m.Register(func(app core.App) error {
+		collection := core.NewBaseCollection("analytics_daily")
+
+		collection.Fields.Add(&core.TextField{
+			Name:     "day",
+			Required: true,
+			Pattern:  `^\d{4}-\d{2}-\d{2}$`,
+		})
+		collection.Fields.Add(&core.SelectField{
+			Name:      "kind",
+			Values:    []string{"view", "post", "project", "talk", "referrer", "token"},
+			Required:  true,
+			MaxSelect: 1,
+		})
+		collection.Fields.Add(&core.TextField{Name: "key", Required: true, Max: 255})
+		collection.Fields.Add(&core.NumberField{Name: "count", Min: floatPtr(0), OnlyInt: true})
+
+		collection.Indexes = append(collection.Indexes,
+			"CREATE UNIQUE INDEX idx_analytics_daily_counter ON analytics_daily(day, kind, key)",
+			"CREATE INDEX idx_analytics_daily_kind ON analytics_daily(kind, day)",
+		)
+
+		// Authenticated users can read; counters are only written by the server
+		authRule := "@request.auth.id != ''"
+		collection.ListRule = &authRule
+		collection.ViewRule = &authRule
+
+		if err := app.Save(collection); err != nil {
+			return err
+		}
+
+		settings, err := app.FindCollectionByNameOrId("site_settings")
+		if err != nil {
+			return nil
+		}
+		if field := settings.Fields.GetByName("ga_measurement_id"); field != nil {
+			settings.Fields.RemoveById(field.GetId())
+			return app.Save(settings)
+		}
+		return nil
+	}, func(app core.App) error {
+		if settings, err := app.FindCollectionByNameOrId("site_settings"); err == nil {
+			if settings.Fields.GetByName("ga_measurement_id") == nil {
+				settings.Fields.Add(&core.TextField{Name: "ga_measurement_id", Max: 100})
+				if err := app.Save(settings); err != nil {
+					return err
+				}
+			}
+		}
+
+		collection, err := app.FindCollectionByNameOrId("analytics_daily")
+		if err != nil {
+			return nil
+		}
+		return app.Delete(collection)
+	})
+}
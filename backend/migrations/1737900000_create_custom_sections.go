@@ -0,0 +1,80 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// User-defined section types ("Open Source", "Publications", "Volunteering")
+// describe their fields in section_types; every item of every custom section
+// lives in the generic custom_items collection, keyed by the type's name.
+// Both get demo_* shadow tables like the built-in content collections.
+func init() {
+	m.Register(func(app core.App) error {
+		authRule := "@request.auth.id != ''"
+
+		sectionTypes := core.NewBaseCollection("section_types")
+		sectionTypes.Fields.Add(&core.TextField{
+			Name:     "name",
+			Required: true,
+			Max:      50,
+			Pattern:  `^[a-z][a-z0-9_]*$`,
+		})
+		sectionTypes.Fields.Add(&core.TextField{Name: "label", Required: true, Max: 100})
+		sectionTypes.Fields.Add(&core.TextField{Name: "description", Max: 500})
+		// [{"name": "venue", "label": "Venue", "type": "text", "overridable": true}, ...]
+		sectionTypes.Fields.Add(&core.JSONField{Name: "fields"})
+		sectionTypes.Fields.Add(&core.TextField{Name: "default_layout", Max: 50})
+		sectionTypes.Fields.Add(&core.NumberField{Name: "sort_order"})
+
+		customItems := core.NewBaseCollection("custom_items")
+		customItems.Fields.Add(&core.TextField{
+			Name:     "section",
+			Required: true,
+			Max:      50,
+			Pattern:  `^[a-z][a-z0-9_]*$`,
+		})
+		customItems.Fields.Add(&core.TextField{Name: "title", Required: true, Max: 500})
+		// Values of the section type's fields, keyed by field name
+		customItems.Fields.Add(&core.JSONField{Name: "data"})
+		customItems.Fields.Add(&core.SelectField{Name: "visibility", Values: []string{"public", "unlisted", "private"}, MaxSelect: 1})
+		customItems.Fields.Add(&core.JSONField{Name: "view_visibility"})
+		customItems.Fields.Add(&core.BoolField{Name: "is_draft"})
+		customItems.Fields.Add(&core.NumberField{Name: "sort_order"})
+
+		for _, collection := range []*core.Collection{sectionTypes, customItems} {
+			for _, name := range []string{collection.Name, "demo_" + collection.Name} {
+				clone := core.NewBaseCollection(name)
+				for _, field := range collection.Fields {
+					clone.Fields.Add(field)
+				}
+				clone.ListRule = &authRule
+				clone.ViewRule = &authRule
+				clone.CreateRule = &authRule
+				clone.UpdateRule = &authRule
+				clone.DeleteRule = &authRule
+
+				if collection.Name == "section_types" {
+					clone.Indexes = append(clone.Indexes, "CREATE UNIQUE INDEX idx_"+name+"_name ON "+name+"(name)")
+				} else {
+					clone.Indexes = append(clone.Indexes, "CREATE INDEX idx_"+name+"_section ON "+name+"(section, sort_order)")
+				}
+
+				if err := app.Save(clone); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}, func(app core.App) error {
+		for _, name := range []string{"demo_custom_items", "custom_items", "demo_section_types", "section_types"} {
+			if collection, err := app.FindCollectionByNameOrId(name); err == nil {
+				if err := app.Delete(collection); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// A share token can narrow what it unlocks: a subset of the view's sections,
+// hidden items and redacted fields, e.g. a link for an agency recruiter that
+// leaves out the phone number. Empty means the whole view.
+func init() {
+	m.Register(func(app core.App) error {
+		for _, name := range []string{"share_tokens", "demo_share_tokens"} {
+			collection, err := app.FindCollectionByNameOrId(name)
+			if err != nil {
+				continue
+			}
+			// {"sections": ["experience"], "hidden_items": ["abc..."], "redact": {"experience": ["company"]}}
+			collection.Fields.Add(&core.JSONField{Name: "scope"})
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, func(app core.App) error {
+		for _, name := range []string{"share_tokens", "demo_share_tokens"} {
+			collection, err := app.FindCollectionByNameOrId(name)
+			if err != nil {
+				continue
+			}
+			if field := collection.Fields.GetByName("scope"); field != nil {
+				collection.Fields.RemoveById(field.GetId())
+				if err := app.Save(collection); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,77 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// A share token can be bound to one recipient email. Opening it then needs a
+// one-time code or link sent to that address; share_verifications holds the
+// pending codes (hashed, like the tokens themselves) until they are used or expire.
+func init() {
+	m.Register(func(app core.App) error {
+		for _, name := range []string{"share_tokens", "demo_share_tokens"} {
+			collection, err := app.FindCollectionByNameOrId(name)
+			if err != nil {
+				continue
+			}
+			collection.Fields.Add(&core.EmailField{Name: "recipient_email"})
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+		}
+
+		tokens, err := app.FindCollectionByNameOrId("share_tokens")
+		if err != nil {
+			return err
+		}
+
+		collection := core.NewBaseCollection("share_verifications")
+		collection.Fields.Add(&core.RelationField{
+			Name:          "share_token",
+			CollectionId:  tokens.Id,
+			Required:      true,
+			MaxSelect:     1,
+			CascadeDelete: true,
+		})
+		collection.Fields.Add(&core.TextField{Name: "code_hash", Required: true, Max: 100})
+		collection.Fields.Add(&core.TextField{Name: "link_hash", Required: true, Max: 100})
+		collection.Fields.Add(&core.DateField{Name: "expires_at", Required: true})
+		collection.Fields.Add(&core.NumberField{Name: "attempts", OnlyInt: true})
+		collection.Fields.Add(&core.DateField{Name: "verified_at"})
+		collection.Fields.Add(&core.AutodateField{Name: "created", OnCreate: true})
+
+		collection.Indexes = append(collection.Indexes,
+			"CREATE UNIQUE INDEX idx_share_verifications_link ON share_verifications(link_hash)",
+			"CREATE INDEX idx_share_verifications_token ON share_verifications(share_token, created)",
+		)
+
+		// Only the server creates and checks codes
+		authRule := "@request.auth.id != ''"
+		collection.ListRule = &authRule
+		collection.ViewRule = &authRule
+		collection.DeleteRule = &authRule
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		if collection, err := app.FindCollectionByNameOrId("share_verifications"); err == nil {
+			if err := app.Delete(collection); err != nil {
+				return err
+			}
+		}
+
+		for _, name := range []string{"share_tokens", "demo_share_tokens"} {
+			collection, err := app.FindCollectionByNameOrId(name)
+			if err != nil {
+				continue
+			}
+			if field := collection.Fields.GetByName("recipient_email"); field != nil {
+				collection.Fields.RemoveById(field.GetId())
+				if err := app.Save(collection); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
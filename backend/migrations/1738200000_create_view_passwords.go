@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// A password-protected view can have several named passwords, one per
+// recruiter or event, each with its own expiry and revocation. The view's own
+// password_hash keeps working as its default password.
+func init() {
+	m.Register(func(app core.App) error {
+		views, err := app.FindCollectionByNameOrId("views")
+		if err != nil {
+			return err
+		}
+
+		collection := core.NewBaseCollection("view_passwords")
+		collection.Fields.Add(&core.RelationField{
+			Name:          "view",
+			CollectionId:  views.Id,
+			Required:      true,
+			MaxSelect:     1,
+			CascadeDelete: true,
+		})
+		collection.Fields.Add(&core.TextField{Name: "label", Required: true, Max: 100})
+		// Plaintext is accepted on write, hashed into password_hash and cleared
+		collection.Fields.Add(&core.TextField{Name: "password", Max: 200})
+		collection.Fields.Add(&core.TextField{Name: "password_hash", Max: 100, Hidden: true})
+		collection.Fields.Add(&core.DateField{Name: "expires_at"})
+		collection.Fields.Add(&core.BoolField{Name: "is_active"})
+		collection.Fields.Add(&core.NumberField{Name: "use_count", OnlyInt: true})
+		collection.Fields.Add(&core.DateField{Name: "last_used_at"})
+		collection.Fields.Add(&core.AutodateField{Name: "created", OnCreate: true})
+		collection.Fields.Add(&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true})
+
+		collection.Indexes = append(collection.Indexes,
+			"CREATE INDEX idx_view_passwords_view ON view_passwords(view)",
+		)
+
+		authRule := "@request.auth.id != ''"
+		collection.ListRule = &authRule
+		collection.ViewRule = &authRule
+		collection.CreateRule = &authRule
+		collection.UpdateRule = &authRule
+		collection.DeleteRule = &authRule
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("view_passwords")
+		if err != nil {
+			return nil
+		}
+		return app.Delete(collection)
+	})
+}
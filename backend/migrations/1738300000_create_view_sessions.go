@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Sessions issued by /api/password/check, stored under the jti of their JWT so
+// they can be listed and revoked. Only the server writes them.
+func init() {
+	m.Register(func(app core.App) error {
+		views, err := app.FindCollectionByNameOrId("views")
+		if err != nil {
+			return err
+		}
+		passwords, err := app.FindCollectionByNameOrId("view_passwords")
+		if err != nil {
+			return err
+		}
+
+		collection := core.NewBaseCollection("view_sessions")
+		collection.Fields.Add(&core.RelationField{
+			Name:          "view",
+			CollectionId:  views.Id,
+			Required:      true,
+			MaxSelect:     1,
+			CascadeDelete: true,
+		})
+		collection.Fields.Add(&core.TextField{Name: "jti", Required: true, Max: 64})
+		// The named password used, empty for the view's default password
+		collection.Fields.Add(&core.RelationField{
+			Name:         "view_password",
+			CollectionId: passwords.Id,
+			MaxSelect:    1,
+		})
+		collection.Fields.Add(&core.TextField{Name: "password_label", Max: 100})
+		collection.Fields.Add(&core.DateField{Name: "issued_at", Required: true})
+		collection.Fields.Add(&core.DateField{Name: "expires_at", Required: true})
+		collection.Fields.Add(&core.DateField{Name: "revoked_at"})
+		collection.Fields.Add(&core.AutodateField{Name: "created", OnCreate: true})
+
+		collection.Indexes = append(collection.Indexes,
+			"CREATE UNIQUE INDEX idx_view_sessions_jti ON view_sessions(jti)",
+			"CREATE INDEX idx_view_sessions_view ON view_sessions(view, expires_at)",
+		)
+
+		// Read-only for the admin; issued and revoked through custom endpoints
+		authRule := "@request.auth.id != ''"
+		collection.ListRule = &authRule
+		collection.ViewRule = &authRule
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("view_sessions")
+		if err != nil {
+			return nil
+		}
+		return app.Delete(collection)
+	})
+}
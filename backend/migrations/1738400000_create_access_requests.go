@@ -0,0 +1,93 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Visitors of an unlisted or private view can ask for access when the view
+// opts in. Approving a request mints a share token for it.
+func init() {
+	m.Register(func(app core.App) error {
+		for _, name := range []string{"views", "demo_views"} {
+			collection, err := app.FindCollectionByNameOrId(name)
+			if err != nil {
+				continue
+			}
+			collection.Fields.Add(&core.BoolField{Name: "allow_access_requests"})
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+		}
+
+		views, err := app.FindCollectionByNameOrId("views")
+		if err != nil {
+			return err
+		}
+		tokens, err := app.FindCollectionByNameOrId("share_tokens")
+		if err != nil {
+			return err
+		}
+
+		collection := core.NewBaseCollection("access_requests")
+		collection.Fields.Add(&core.RelationField{
+			Name:          "view",
+			CollectionId:  views.Id,
+			Required:      true,
+			MaxSelect:     1,
+			CascadeDelete: true,
+		})
+		collection.Fields.Add(&core.TextField{Name: "name", Required: true, Max: 100})
+		collection.Fields.Add(&core.EmailField{Name: "email", Required: true})
+		collection.Fields.Add(&core.TextField{Name: "company", Max: 200})
+		collection.Fields.Add(&core.TextField{Name: "reason", Max: 1000})
+		collection.Fields.Add(&core.SelectField{
+			Name:      "status",
+			Required:  true,
+			MaxSelect: 1,
+			Values:    []string{"pending", "approved", "declined"},
+		})
+		// The token minted on approval
+		collection.Fields.Add(&core.RelationField{
+			Name:         "share_token",
+			CollectionId: tokens.Id,
+			MaxSelect:    1,
+		})
+		collection.Fields.Add(&core.DateField{Name: "reviewed_at"})
+		collection.Fields.Add(&core.AutodateField{Name: "created", OnCreate: true})
+		collection.Fields.Add(&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true})
+
+		collection.Indexes = append(collection.Indexes,
+			"CREATE INDEX idx_access_requests_status ON access_requests(status, created)",
+			"CREATE INDEX idx_access_requests_view ON access_requests(view, email)",
+		)
+
+		// Visitors submit through /api/view/{slug}/request-access; the admin reviews
+		authRule := "@request.auth.id != ''"
+		collection.ListRule = &authRule
+		collection.ViewRule = &authRule
+		collection.UpdateRule = &authRule
+		collection.DeleteRule = &authRule
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		if collection, err := app.FindCollectionByNameOrId("access_requests"); err == nil {
+			if err := app.Delete(collection); err != nil {
+				return err
+			}
+		}
+		for _, name := range []string{"views", "demo_views"} {
+			collection, err := app.FindCollectionByNameOrId(name)
+			if err != nil {
+				continue
+			}
+			if field := collection.Fields.GetByName("allow_access_requests"); field != nil {
+				collection.Fields.RemoveById(field.GetId())
+				if err := app.Save(collection); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Exports queued through a share link remember it, so the worker renders only
+// what the link's scope unlocks and only that link can follow the job.
+// Deleting the link deletes its exports rather than leaving them unscoped.
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("view_exports")
+		if err != nil {
+			return err
+		}
+		tokens, err := app.FindCollectionByNameOrId("share_tokens")
+		if err != nil {
+			return err
+		}
+		collection.Fields.Add(&core.RelationField{
+			Name:          "share_token",
+			CollectionId:  tokens.Id,
+			MaxSelect:     1,
+			CascadeDelete: true,
+		})
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("view_exports")
+		if err != nil {
+			return nil
+		}
+		if field := collection.Fields.GetByName("share_token"); field != nil {
+			collection.Fields.RemoveById(field.GetId())
+			return app.Save(collection)
+		}
+		return nil
+	})
+}
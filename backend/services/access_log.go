@@ -0,0 +1,240 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// AccessVisit is what the access log keeps about one visit. The raw IP address
+// is never stored: it is hashed with a salt that changes every day, so visits
+// can be counted as unique within a day but not linked across days.
+type AccessVisit struct {
+	IPHash       string
+	UAFamily     string
+	IsBot        bool
+	ReferrerHost string
+	Country      string
+}
+
+// AccessLogService describes visits for the access_events collection.
+// Country lookup is optional and uses a local MaxMind-format database
+// (GeoLite2 or DB-IP Lite) set with GEOIP_DB_PATH; nothing leaves the server.
+type AccessLogService struct {
+	trustProxy bool
+	geo        *maxminddb.Reader
+
+	// Daily salt, kept in memory only so old hashes cannot be recomputed
+	mu      sync.Mutex
+	saltDay string
+	salt    []byte
+}
+
+// NewAccessLogService creates the service, opening the GeoIP database if configured
+func NewAccessLogService(logger *slog.Logger) *AccessLogService {
+	svc := &AccessLogService{
+		trustProxy: os.Getenv("TRUST_PROXY") == "true",
+	}
+
+	if path := strings.TrimSpace(os.Getenv("GEOIP_DB_PATH")); path != "" {
+		geo, err := maxminddb.Open(path)
+		if err != nil {
+			if logger != nil {
+				logger.Warn("Failed to open GeoIP database, visits will not have a country", "path", path, "error", err)
+			}
+		} else {
+			svc.geo = geo
+		}
+	}
+
+	return svc
+}
+
+// Close releases the GeoIP database
+func (s *AccessLogService) Close() error {
+	if s.geo != nil {
+		return s.geo.Close()
+	}
+	return nil
+}
+
+// SaveAccessEvent stores one anonymous visit to a view
+func SaveAccessEvent(app core.App, viewID, tokenID string, visit AccessVisit) error {
+	collection, err := app.FindCollectionByNameOrId("access_events")
+	if err != nil {
+		return err
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("view", viewID)
+	record.Set("share_token", tokenID)
+	record.Set("ip_hash", visit.IPHash)
+	record.Set("ua_family", visit.UAFamily)
+	record.Set("is_bot", visit.IsBot)
+	record.Set("referrer_host", visit.ReferrerHost)
+	record.Set("country", visit.Country)
+	return app.Save(record)
+}
+
+// Describe builds the stored description of a request
+func (s *AccessLogService) Describe(r *http.Request, now time.Time) AccessVisit {
+	ip := clientIP(r, s.trustProxy)
+	family, bot := UserAgentFamily(r.Header.Get("User-Agent"))
+	return AccessVisit{
+		IPHash:       s.HashIP(ip, now),
+		UAFamily:     family,
+		IsBot:        bot,
+		ReferrerHost: ReferrerHost(r.Header.Get("Referer")),
+		Country:      s.Country(ip),
+	}
+}
+
+// HashIP hashes an IP address with the salt of the given day (UTC)
+func (s *AccessLogService) HashIP(ip string, now time.Time) string {
+	if ip == "" {
+		return ""
+	}
+	h := sha256.New()
+	h.Write(s.dailySalt(now))
+	h.Write([]byte(ip))
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+func (s *AccessLogService) dailySalt(now time.Time) []byte {
+	day := now.UTC().Format("2006-01-02")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.saltDay != day {
+		salt := make([]byte, 32)
+		if _, err := rand.Read(salt); err != nil {
+			// crypto/rand does not fail on supported platforms; a time-based salt still rotates daily
+			salt = []byte(now.UTC().Format(time.RFC3339Nano))
+		}
+		s.salt = salt
+		s.saltDay = day
+	}
+	return s.salt
+}
+
+// Country returns the ISO country code of an IP, or "" without a GeoIP database
+func (s *AccessLogService) Country(ip string) string {
+	if s.geo == nil {
+		return ""
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ""
+	}
+
+	var record struct {
+		Country struct {
+			ISOCode string `maxminddb:"iso_code"`
+		} `maxminddb:"country"`
+	}
+	if err := s.geo.Lookup(parsed, &record); err != nil {
+		return ""
+	}
+	return record.Country.ISOCode
+}
+
+// ReferrerHost reduces a Referer header to its host name
+func ReferrerHost(referer string) string {
+	if referer == "" {
+		return ""
+	}
+	u, err := url.Parse(referer)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// userAgentBots maps User-Agent fragments of link previews, crawlers and HTTP
+// tools to a display name. Link previews matter here: pasting a share link into
+// Slack or LinkedIn fetches it before any person opens it.
+var userAgentBots = []struct{ fragment, name string }{
+	{"slackbot", "Slack"},
+	{"linkedinbot", "LinkedIn"},
+	{"twitterbot", "Twitter"},
+	{"facebookexternalhit", "Facebook"},
+	{"whatsapp", "WhatsApp"},
+	{"discordbot", "Discord"},
+	{"telegrambot", "Telegram"},
+	{"skypeuripreview", "Skype"},
+	{"microsoft office", "Microsoft Office"},
+	{"googlebot", "Googlebot"},
+	{"bingbot", "Bingbot"},
+	{"curl/", "curl"},
+	{"wget/", "Wget"},
+	{"python-requests", "Python"},
+	{"go-http-client", "Go"},
+	{"bot", "Bot"},
+	{"crawler", "Bot"},
+	{"spider", "Bot"},
+}
+
+// userAgentBrowsers is checked in order: most browsers also claim to be Safari
+// or Chrome, so the more specific names come first
+var userAgentBrowsers = []struct{ fragment, name string }{
+	{"edg/", "Edge"},
+	{"edgios/", "Edge"},
+	{"opr/", "Opera"},
+	{"samsungbrowser/", "Samsung Internet"},
+	{"firefox/", "Firefox"},
+	{"fxios/", "Firefox"},
+	{"crios/", "Chrome"},
+	{"chrome/", "Chrome"},
+	{"safari/", "Safari"},
+}
+
+var userAgentSystems = []struct{ fragment, name string }{
+	{"iphone", "iOS"},
+	{"ipad", "iOS"},
+	{"android", "Android"},
+	{"cros ", "ChromeOS"},
+	{"windows", "Windows"},
+	{"macintosh", "macOS"},
+	{"mac os x", "macOS"},
+	{"linux", "Linux"},
+}
+
+// UserAgentFamily reduces a User-Agent to a coarse family such as
+// "Firefox on Windows", and reports whether it looks automated
+func UserAgentFamily(ua string) (string, bool) {
+	lower := strings.ToLower(ua)
+	if strings.TrimSpace(lower) == "" {
+		return "Unknown", false
+	}
+
+	for _, bot := range userAgentBots {
+		if strings.Contains(lower, bot.fragment) {
+			return bot.name, true
+		}
+	}
+
+	browser := "Other"
+	for _, b := range userAgentBrowsers {
+		if strings.Contains(lower, b.fragment) {
+			browser = b.name
+			break
+		}
+	}
+	for _, system := range userAgentSystems {
+		if strings.Contains(lower, system.fragment) {
+			return browser + " on " + system.name, false
+		}
+	}
+	return browser, false
+}
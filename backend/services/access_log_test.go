@@ -0,0 +1,84 @@
+package services
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUserAgentFamily(t *testing.T) {
+	tests := []struct {
+		ua     string
+		family string
+		bot    bool
+	}{
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15", "Safari on macOS", false},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0", "Edge on Windows", false},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0 Mobile/15E148 Safari/604.1", "Chrome on iOS", false},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0", "Firefox on Linux", false},
+		{"Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "Chrome on ChromeOS", false},
+		{"Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)", "Slack", true},
+		{"LinkedInBot/1.0 (compatible; Mozilla/5.0; Apache-HttpClient +http://www.linkedin.com)", "LinkedIn", true},
+		{"curl/8.4.0", "curl", true},
+		{"", "Unknown", false},
+	}
+
+	for _, tt := range tests {
+		family, bot := UserAgentFamily(tt.ua)
+		if family != tt.family || bot != tt.bot {
+			t.Errorf("UserAgentFamily(%q) = %q, %v; want %q, %v", tt.ua, family, bot, tt.family, tt.bot)
+		}
+	}
+}
+
+func TestReferrerHost(t *testing.T) {
+	tests := map[string]string{
+		"https://www.linkedin.com/messaging/thread/123": "linkedin.com",
+		"https://Mail.Google.com/mail/u/0/":             "mail.google.com",
+		"android-app://com.slack/":                      "com.slack",
+		"not a url":                                     "",
+		"":                                              "",
+	}
+	for referer, want := range tests {
+		if got := ReferrerHost(referer); got != want {
+			t.Errorf("ReferrerHost(%q) = %q, want %q", referer, got, want)
+		}
+	}
+}
+
+func TestAccessLogHashIPRotatesDaily(t *testing.T) {
+	svc := &AccessLogService{}
+	day := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	morning := svc.HashIP("203.0.113.7", day)
+	evening := svc.HashIP("203.0.113.7", day.Add(12*time.Hour))
+	if morning == "" || morning != evening {
+		t.Errorf("hashes within a day differ: %q vs %q", morning, evening)
+	}
+	if svc.HashIP("203.0.113.8", day) == morning {
+		t.Error("different IPs hashed the same")
+	}
+	if svc.HashIP("203.0.113.7", day.Add(24*time.Hour)) == morning {
+		t.Error("hash did not change with the day")
+	}
+	if len(morning) != 32 {
+		t.Errorf("hash length = %d, want 32", len(morning))
+	}
+}
+
+func TestAccessLogDescribe(t *testing.T) {
+	svc := &AccessLogService{trustProxy: true}
+	req := httptest.NewRequest("GET", "/api/view/recruiters/data", nil)
+	req.Header.Set("X-Forwarded-For", "198.51.100.4, 10.0.0.2")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0")
+	req.Header.Set("Referer", "https://www.linkedin.com/feed/")
+
+	now := time.Now()
+	visit := svc.Describe(req, now)
+	if visit.IPHash != svc.HashIP("198.51.100.4", now) {
+		t.Error("visit was not hashed from the forwarded client IP")
+	}
+	if visit.UAFamily != "Firefox on Linux" || visit.ReferrerHost != "linkedin.com" || visit.Country != "" {
+		t.Errorf("visit = %+v", visit)
+	}
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// Analytics counter kinds. Content kinds count page views; referrer and token
+// count where those views came from, so they are not added to the totals.
+const (
+	AnalyticsView     = "view"
+	AnalyticsPost     = "post"
+	AnalyticsProject  = "project"
+	AnalyticsTalk     = "talk"
+	AnalyticsReferrer = "referrer"
+	AnalyticsToken    = "token"
+)
+
+// AnalyticsKinds lists every counter kind
+var AnalyticsKinds = []string{AnalyticsView, AnalyticsPost, AnalyticsProject, AnalyticsTalk, AnalyticsReferrer, AnalyticsToken}
+
+// AnalyticsContentKinds lists the kinds that count page views
+var AnalyticsContentKinds = []string{AnalyticsView, AnalyticsPost, AnalyticsProject, AnalyticsTalk}
+
+// AnalyticsDayFormat is the layout of the day column
+const AnalyticsDayFormat = "2006-01-02"
+
+// AnalyticsCount is one aggregated counter
+type AnalyticsCount struct {
+	Day   string `db:"day" json:"day,omitempty"`
+	Kind  string `db:"kind" json:"kind,omitempty"`
+	Key   string `db:"key" json:"key,omitempty"`
+	Count int    `db:"count" json:"count"`
+}
+
+// IsAnalyticsKind reports whether kind is a known counter kind
+func IsAnalyticsKind(kind string) bool {
+	for _, k := range AnalyticsKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// IncrementAnalytics adds one to the day's counter for kind and key, creating
+// the row on first use. The upsert is a single statement, so concurrent
+// requests do not lose counts.
+func IncrementAnalytics(app core.App, day time.Time, kind, key string) error {
+	return addAnalytics(app, day.UTC().Format(AnalyticsDayFormat), kind, key, 1)
+}
+
+// addAnalytics adds n to the counter of a day (formatted with AnalyticsDayFormat)
+func addAnalytics(app core.App, day, kind, key string, n int) error {
+	if key == "" || !IsAnalyticsKind(kind) {
+		return nil
+	}
+	if len(key) > 255 {
+		key = key[:255]
+	}
+
+	_, err := app.DB().NewQuery(`
+		INSERT INTO analytics_daily (id, day, kind, key, count)
+		VALUES ({:id}, {:day}, {:kind}, {:key}, {:n})
+		ON CONFLICT(day, kind, key) DO UPDATE SET count = count + {:n}
+	`).Bind(dbx.Params{
+		"id":   core.GenerateDefaultRandomId(),
+		"day":  day,
+		"kind": kind,
+		"key":  key,
+		"n":    n,
+	}).Execute()
+	return err
+}
+
+// AnalyticsTotals sums counters per kind between from and to (inclusive days)
+func AnalyticsTotals(app core.App, from, to time.Time) (map[string]int, error) {
+	var rows []AnalyticsCount
+	err := app.DB().Select("kind", "SUM(count) AS count").
+		From("analytics_daily").
+		Where(analyticsRange(from, to)).
+		GroupBy("kind").
+		All(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := map[string]int{}
+	for _, kind := range AnalyticsKinds {
+		totals[kind] = 0
+	}
+	for _, row := range rows {
+		totals[row.Kind] = row.Count
+	}
+	return totals, nil
+}
+
+// AnalyticsTimeseries returns one point per day from from to to, with zeros for
+// days without hits. An empty kind sums all content kinds; an empty key sums
+// all keys of the kind.
+func AnalyticsTimeseries(app core.App, from, to time.Time, kind, key string) ([]AnalyticsCount, error) {
+	where := analyticsRange(from, to)
+	if kind != "" {
+		where = dbx.And(where, dbx.HashExp{"kind": kind})
+	} else {
+		where = dbx.And(where, dbx.In("kind", stringsToInterfaces(AnalyticsContentKinds)...))
+	}
+	if key != "" {
+		where = dbx.And(where, dbx.HashExp{"key": key})
+	}
+
+	var rows []AnalyticsCount
+	err := app.DB().Select("day", "SUM(count) AS count").
+		From("analytics_daily").
+		Where(where).
+		GroupBy("day").
+		All(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	byDay := map[string]int{}
+	for _, row := range rows {
+		byDay[row.Day] = row.Count
+	}
+
+	points := []AnalyticsCount{}
+	for day := truncateDay(from); !day.After(truncateDay(to)); day = day.AddDate(0, 0, 1) {
+		label := day.Format(AnalyticsDayFormat)
+		points = append(points, AnalyticsCount{Day: label, Count: byDay[label]})
+	}
+	return points, nil
+}
+
+// AnalyticsTop returns the keys of a kind with the most hits
+func AnalyticsTop(app core.App, from, to time.Time, kind string, limit int) ([]AnalyticsCount, error) {
+	if !IsAnalyticsKind(kind) {
+		return nil, fmt.Errorf("unknown analytics kind %q", kind)
+	}
+
+	var rows []AnalyticsCount
+	err := app.DB().Select("key", "SUM(count) AS count").
+		From("analytics_daily").
+		Where(dbx.And(analyticsRange(from, to), dbx.HashExp{"kind": kind})).
+		GroupBy("key").
+		OrderBy("count DESC", "key ASC").
+		Limit(int64(limit)).
+		All(&rows)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].Kind = kind
+	}
+	return rows, nil
+}
+
+// AnalyticsRows returns every counter in the range, ordered for export
+func AnalyticsRows(app core.App, from, to time.Time) ([]AnalyticsCount, error) {
+	var rows []AnalyticsCount
+	err := app.DB().Select("day", "kind", "key", "count").
+		From("analytics_daily").
+		Where(analyticsRange(from, to)).
+		OrderBy("day ASC", "kind ASC", "count DESC").
+		All(&rows)
+	return rows, err
+}
+
+func analyticsRange(from, to time.Time) dbx.Expression {
+	return dbx.Between("day", from.UTC().Format(AnalyticsDayFormat), to.UTC().Format(AnalyticsDayFormat))
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func stringsToInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
@@ -0,0 +1,499 @@
+package services
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// The ATS report compares a view's content with a job description the way applicant
+// tracking systems do: by keyword. Skills are recognised through a built-in taxonomy
+// so "k8s" in a resume counts for "Kubernetes" in a posting. It also flags resume
+// Markdown that ATS parsers tend to mangle. Everything runs offline.
+
+const atsMaxTerms = 15
+
+// ATSReport is the keyword coverage of a view against a job description
+type ATSReport struct {
+	Coverage     int          `json:"coverage"` // percent of keywords found, synonyms included
+	Matched      []ATSKeyword `json:"matched"`
+	Synonyms     []ATSKeyword `json:"synonyms"` // found only under another name
+	Missing      []ATSKeyword `json:"missing"`
+	Formatting   []ATSIssue   `json:"formatting"`
+	ResumeSource string       `json:"resume_source,omitempty"`
+}
+
+// ATSKeyword is a keyword from the job description and where the view covers it
+type ATSKeyword struct {
+	Keyword    string        `json:"keyword"`         // as written in the job description
+	Skill      string        `json:"skill,omitempty"` // taxonomy name, for recognised skills
+	Count      int           `json:"count"`           // mentions in the job description
+	ResumeTerm string        `json:"resume_term,omitempty"`
+	Locations  []ATSLocation `json:"locations,omitempty"`
+}
+
+// ATSLocation is a place in the view where a keyword appears
+type ATSLocation struct {
+	Section string `json:"section"`
+	ItemID  string `json:"item_id,omitempty"`
+	Label   string `json:"label"`
+}
+
+// ATSIssue is resume formatting that ATS parsers are likely to misread
+type ATSIssue struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"` // warning or info
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+	Excerpt  string `json:"excerpt,omitempty"`
+}
+
+// atsText is one searchable piece of view content
+type atsText struct {
+	location ATSLocation
+	raw      string
+	tokens   []string
+	terms    map[string]bool
+}
+
+// atsSkipSections are not part of a resume an ATS would read
+var atsSkipSections = map[string]bool{"contacts": true, "testimonials": true}
+
+// BuildATSReport compares the view's content with a job description. When markdown
+// is given (the rendered resume), its formatting is checked too.
+func BuildATSReport(jobDescription string, data *ViewData, markdown string) *ATSReport {
+	texts := atsTexts(data)
+	keywords := extractATSKeywords(jobDescription)
+
+	report := &ATSReport{Matched: []ATSKeyword{}, Synonyms: []ATSKeyword{}, Missing: []ATSKeyword{}, Formatting: []ATSIssue{}}
+	for _, kw := range keywords {
+		keyword := kw.ATSKeyword
+		exact := false
+		for _, text := range texts {
+			if kw.skill == nil {
+				if text.terms[kw.term] {
+					keyword.Locations = append(keyword.Locations, text.location)
+					exact = true
+				}
+				continue
+			}
+			for _, form := range kw.skill.forms() {
+				if !atsContainsForm(text, kw.skill, form) {
+					continue
+				}
+				keyword.Locations = append(keyword.Locations, text.location)
+				if kw.jdForms[form] > 0 {
+					exact = true
+				} else if keyword.ResumeTerm == "" {
+					keyword.ResumeTerm = form
+				}
+				break
+			}
+		}
+
+		switch {
+		case exact:
+			keyword.ResumeTerm = ""
+			report.Matched = append(report.Matched, keyword)
+		case len(keyword.Locations) > 0:
+			report.Synonyms = append(report.Synonyms, keyword)
+		default:
+			report.Missing = append(report.Missing, keyword)
+		}
+	}
+
+	if len(keywords) > 0 {
+		found := len(report.Matched) + len(report.Synonyms)
+		report.Coverage = int(math.Round(float64(found) / float64(len(keywords)) * 100))
+	}
+	if markdown != "" {
+		report.Formatting = CheckATSFormatting(markdown)
+	}
+	return report
+}
+
+type atsJDKeyword struct {
+	ATSKeyword
+	skill   *atsSkill
+	jdForms map[string]int // skill spellings used in the job description, with counts
+	term    string         // stemmed term, for keywords outside the taxonomy
+}
+
+// extractATSKeywords finds the taxonomy skills in a job description, then its most
+// frequent other terms. Skills come first, each group by mention count.
+func extractATSKeywords(jobDescription string) []atsJDKeyword {
+	tokens := atsTokens(jobDescription)
+	used := make([]bool, len(tokens))
+
+	// Longest spellings first, so "React Native" is not also counted as "React"
+	type spelling struct {
+		skill *atsSkill
+		form  string
+	}
+	var spellings []spelling
+	for i := range atsTaxonomy {
+		for _, form := range atsTaxonomy[i].forms() {
+			spellings = append(spellings, spelling{&atsTaxonomy[i], form})
+		}
+	}
+	sort.SliceStable(spellings, func(a, b int) bool {
+		return len(atsTokens(spellings[a].form)) > len(atsTokens(spellings[b].form))
+	})
+
+	bySkill := make(map[*atsSkill]*atsJDKeyword)
+	var skills []*atsJDKeyword
+	skillTokens := make(map[string]bool)
+	for _, s := range spellings {
+		var count int
+		if s.skill.caseSensitive && s.form == s.skill.name {
+			count = len(atsCaseSensitivePatterns[s.form].FindAllStringIndex(jobDescription, -1))
+		} else {
+			count = atsCountForm(tokens, used, atsTokens(s.form))
+		}
+		if count == 0 {
+			continue
+		}
+		for _, token := range atsTokens(s.form) {
+			skillTokens[token] = true
+		}
+
+		kw := bySkill[s.skill]
+		if kw == nil {
+			kw = &atsJDKeyword{skill: s.skill, jdForms: make(map[string]int)}
+			kw.Skill = s.skill.name
+			bySkill[s.skill] = kw
+			skills = append(skills, kw)
+		}
+		kw.jdForms[s.form] = count
+		kw.Count += count
+		// Show the spelling the posting uses most
+		if count > kw.jdForms[kw.Keyword] {
+			kw.Keyword = s.form
+		}
+	}
+
+	// Other terms, by frequency then first appearance
+	terms, surface := keywordTerms(jobDescription)
+	freq := make(map[string]int)
+	var order []string
+	for _, term := range terms {
+		if skillTokens[term] || len(term) < 3 {
+			continue
+		}
+		if freq[term] == 0 {
+			order = append(order, term)
+		}
+		freq[term]++
+	}
+	sort.SliceStable(order, func(a, b int) bool { return freq[order[a]] > freq[order[b]] })
+	if len(order) > atsMaxTerms {
+		order = order[:atsMaxTerms]
+	}
+
+	sort.SliceStable(skills, func(a, b int) bool { return skills[a].Count > skills[b].Count })
+	keywords := make([]atsJDKeyword, 0, len(skills)+len(order))
+	for _, kw := range skills {
+		keywords = append(keywords, *kw)
+	}
+	for _, term := range order {
+		keywords = append(keywords, atsJDKeyword{ATSKeyword: ATSKeyword{Keyword: surface[term], Count: freq[term]}, term: term})
+	}
+	return keywords
+}
+
+// forms lists every spelling of a skill, its name first
+func (s *atsSkill) forms() []string {
+	return append([]string{s.name}, s.aliases...)
+}
+
+// atsTexts splits view content into searchable pieces labelled with where they come from
+func atsTexts(data *ViewData) []atsText {
+	var texts []atsText
+	add := func(location ATSLocation, parts []string) {
+		raw := joinText(parts...)
+		if raw == "" {
+			return
+		}
+		terms, _ := keywordTerms(raw)
+		set := make(map[string]bool, len(terms))
+		for _, term := range terms {
+			set[term] = true
+		}
+		texts = append(texts, atsText{location: location, raw: raw, tokens: atsTokens(raw), terms: set})
+	}
+
+	profile := []string{data.HeroHeadline, plainText(data.HeroSummary)}
+	for _, key := range []string{"headline", "summary"} {
+		if value, ok := data.Profile[key].(string); ok {
+			profile = append(profile, plainText(value))
+		}
+	}
+	add(ATSLocation{Section: "profile", Label: "Headline and summary"}, profile)
+
+	sections := append([]string(nil), data.SectionOrder...)
+	seen := make(map[string]bool)
+	for _, name := range sections {
+		seen[name] = true
+	}
+	var rest []string
+	for name := range data.Sections {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	sections = append(sections, rest...)
+
+	for _, section := range sections {
+		if atsSkipSections[section] {
+			continue
+		}
+		for _, item := range data.Sections[section] {
+			id, _ := item["id"].(string)
+			add(ATSLocation{Section: section, ItemID: id, Label: atsItemLabel(section, item)}, atsItemText(item))
+		}
+	}
+	return texts
+}
+
+// atsItemLabel names an item the way a reader would recognise it
+func atsItemLabel(section string, item map[string]interface{}) string {
+	str := func(key string) string {
+		value, _ := item[key].(string)
+		return strings.TrimSpace(value)
+	}
+
+	label := ""
+	for _, key := range []string{"title", "name", "degree", "institution"} {
+		if label = str(key); label != "" {
+			break
+		}
+	}
+	switch section {
+	case "experience":
+		if company := str("company"); company != "" {
+			label = strings.TrimSpace(label + " at " + company)
+		}
+	case "education":
+		if institution := str("institution"); institution != "" && institution != label {
+			label = strings.TrimSpace(label + ", " + institution)
+		}
+	}
+	if label == "" {
+		label = resumeSectionTitles[section]
+	}
+	return label
+}
+
+// atsItemText gathers an item's readable text: strings and string lists, without
+// identifiers, dates or links
+func atsItemText(item map[string]interface{}) []string {
+	keys := make([]string, 0, len(item))
+	for key := range item {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		if key == "id" || key == "slug" || key == "visibility" || key == "collectionId" || key == "collectionName" ||
+			strings.HasSuffix(key, "_date") || strings.HasSuffix(key, "_at") || strings.HasSuffix(key, "_url") ||
+			key == "date" || key == "url" || key == "created" || key == "updated" {
+			continue
+		}
+		switch value := item[key].(type) {
+		case string:
+			if !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://") {
+				parts = append(parts, plainText(value))
+			}
+		case []interface{}, []string:
+			parts = append(parts, stringList(value)...)
+		}
+	}
+	return parts
+}
+
+// atsTokens splits text into lowercase, plural-folded words for phrase matching
+func atsTokens(text string) []string {
+	tokens := keywordTokenPattern.FindAllString(strings.ToLower(text), -1)
+	for i, token := range tokens {
+		tokens[i] = keywordStem(token)
+	}
+	return tokens
+}
+
+// atsCountForm counts non-overlapping occurrences of a phrase, marking the tokens it
+// used so shorter spellings inside it are not counted again
+func atsCountForm(tokens []string, used []bool, form []string) int {
+	if len(form) == 0 {
+		return 0
+	}
+	count := 0
+	for i := 0; i+len(form) <= len(tokens); i++ {
+		match := true
+		for j, token := range form {
+			if tokens[i+j] != token || (used != nil && used[i+j]) {
+				match = false
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+		count++
+		if used != nil {
+			for j := range form {
+				used[i+j] = true
+			}
+		}
+		i += len(form) - 1
+	}
+	return count
+}
+
+func atsContainsForm(text atsText, skill *atsSkill, form string) bool {
+	if skill.caseSensitive && form == skill.name {
+		return atsCaseSensitivePatterns[form].MatchString(text.raw)
+	}
+	return atsCountForm(text.tokens, nil, atsTokens(form)) > 0
+}
+
+// atsCaseSensitivePatterns match case-sensitive skill names exactly as written, as whole words
+var atsCaseSensitivePatterns = func() map[string]*regexp.Regexp {
+	patterns := make(map[string]*regexp.Regexp)
+	for _, skill := range atsTaxonomy {
+		if skill.caseSensitive {
+			patterns[skill.name] = regexp.MustCompile(`(?:^|[^A-Za-z0-9+#])` + regexp.QuoteMeta(skill.name) + `(?:$|[^A-Za-z0-9+#])`)
+		}
+	}
+	return patterns
+}()
+
+var (
+	atsTablePattern    = regexp.MustCompile(`^\s*\|.*\|\s*$`)
+	atsImagePattern    = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	atsHTMLPattern     = regexp.MustCompile(`</?[a-zA-Z][a-zA-Z0-9]*(?:\s[^>]*)?/?>`)
+	atsLinkPattern     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	atsEmailPattern    = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	atsListItemPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+(.*)$`)
+)
+
+// atsStandardHeadings are section titles ATS parsers recognise
+var atsStandardHeadings = map[string]bool{
+	"summary": true, "professional summary": true, "profile": true, "about": true, "about me": true, "objective": true,
+	"experience": true, "work experience": true, "professional experience": true, "employment": true,
+	"employment history": true, "work history": true, "career history": true,
+	"education": true, "skills": true, "technical skills": true, "core skills": true, "key skills": true,
+	"projects": true, "certifications": true, "licenses and certifications": true, "awards": true,
+	"honors": true, "honours": true, "awards and honors": true, "publications": true, "talks": true,
+	"speaking": true, "presentations": true, "writing": true, "volunteer experience": true, "volunteering": true,
+	"languages": true, "interests": true, "training": true, "courses": true, "references": true, "contact": true,
+}
+
+// CheckATSFormatting flags resume Markdown that applicant tracking systems commonly
+// misread: tables, images, raw HTML, decorative symbols, hidden link targets,
+// non-standard section headings and a missing email address
+func CheckATSFormatting(markdown string) []ATSIssue {
+	issues := []ATSIssue{}
+	add := func(rule, severity string, line int, message, excerpt string) {
+		issues = append(issues, ATSIssue{Rule: rule, Severity: severity, Line: line, Message: message, Excerpt: truncateRunes(strings.TrimSpace(excerpt), 80)})
+	}
+
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	inCode, inTable := false, false
+	headings := 0
+	for i, line := range lines {
+		n := i + 1
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			if !inCode {
+				add("code", "info", n, "Code blocks are often dropped or run together; write the content as plain text.", line)
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+
+		if atsTablePattern.MatchString(line) {
+			if !inTable {
+				add("table", "warning", n, "Tables are often read out of order or skipped; use plain lines instead.", line)
+			}
+			inTable = true
+			continue
+		}
+		inTable = false
+
+		if m := mdHeadingRe.FindStringSubmatch(line); m != nil {
+			headings++
+			if len(m[1]) == 2 {
+				title := strings.ToLower(strings.TrimSpace(strings.TrimRight(plainSpans(parseInline(m[2])), ":")))
+				title = strings.ReplaceAll(title, "&", "and")
+				if !atsStandardHeadings[title] {
+					add("heading", "info", n, "Use a standard section title such as \"Experience\" or \"Skills\" so parsers can file this section.", line)
+				}
+			}
+		}
+
+		if atsImagePattern.MatchString(line) {
+			add("image", "warning", n, "Text in images cannot be read; ATS parsers skip images.", line)
+		}
+		if atsHTMLPattern.MatchString(line) {
+			add("html", "warning", n, "Raw HTML may be shown as literal tags or dropped.", line)
+		}
+		for _, m := range atsLinkPattern.FindAllStringSubmatch(atsImagePattern.ReplaceAllString(line, ""), -1) {
+			if !atsLinkShowsTarget(m[1], m[2]) {
+				add("link", "info", n, "Parsers drop hyperlinks, so the address behind \""+m[1]+"\" is lost; write the URL out.", m[0])
+			}
+		}
+
+		text := line
+		if m := atsListItemPattern.FindStringSubmatch(line); m != nil {
+			text = m[1]
+		}
+		if atsDecorativeRune(text) != 0 {
+			add("symbols", "warning", n, "Decorative symbols and emoji can turn into garbage characters; use plain text and standard bullets.", line)
+		}
+	}
+
+	if headings == 0 && strings.TrimSpace(markdown) != "" {
+		add("heading", "warning", 0, "No section headings found; parsers rely on headings like \"Experience\" to split a resume.", "")
+	}
+	if !atsEmailPattern.MatchString(markdown) {
+		add("contact", "warning", 0, "No email address found; most ATS forms pull contact details from the resume.", "")
+	}
+	if words := len(strings.Fields(markdown)); words > 1200 {
+		add("length", "info", 0, "This resume is long (over 1,200 words); recruiters skim, and some systems truncate long documents.", "")
+	}
+	return issues
+}
+
+// atsLinkShowsTarget reports whether link text already shows where the link goes
+func atsLinkShowsTarget(text, target string) bool {
+	target = strings.TrimPrefix(target, "mailto:")
+	target = strings.TrimPrefix(strings.TrimPrefix(target, "https://"), "http://")
+	target = strings.TrimPrefix(strings.TrimSuffix(target, "/"), "www.")
+	text = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(text), "https://"), "http://")
+	text = strings.TrimPrefix(strings.TrimSuffix(text, "/"), "www.")
+	return strings.EqualFold(text, target)
+}
+
+// atsDecorativeRune returns the first symbol or emoji in text that ATS parsers
+// commonly garble, or 0. Typographic punctuation and accented letters are fine.
+func atsDecorativeRune(text string) rune {
+	for _, r := range text {
+		switch {
+		case r < 0x2000, r == '•', r == '–', r == '—', r == '…', r == '€', r == '™':
+			continue
+		case r >= 0x2018 && r <= 0x201F: // curly quotes
+			continue
+		case unicode.Is(unicode.So, r), r >= 0x2190 && r <= 0x2BFF, r >= 0x1F000:
+			return r
+		}
+	}
+	return 0
+}
@@ -0,0 +1,141 @@
+package services
+
+// atsSkill is a recognised skill with the other names it goes by. Names with
+// caseSensitive set are also everyday words ("Go", "Swift"), so the bare name only
+// counts when written with a capital letter (a sentence starting "Go ahead" still
+// counts; the aliases are matched normally).
+type atsSkill struct {
+	name          string
+	aliases       []string
+	caseSensitive bool
+}
+
+// atsTaxonomy is the built-in skill list used to spot skills in job descriptions and
+// to treat different spellings of the same skill as one keyword
+var atsTaxonomy = []atsSkill{
+	// Languages
+	{name: "JavaScript", aliases: []string{"js", "ecmascript", "es6"}},
+	{name: "TypeScript", aliases: []string{"ts"}},
+	{name: "Python", aliases: []string{"py", "python3"}},
+	{name: "Go", aliases: []string{"golang"}, caseSensitive: true},
+	{name: "Java"},
+	{name: "Kotlin"},
+	{name: "Swift", caseSensitive: true},
+	{name: "Objective-C", aliases: []string{"objc", "objective c"}},
+	{name: "C++", aliases: []string{"cpp"}},
+	{name: "C#", aliases: []string{"csharp", "c sharp"}},
+	{name: ".NET", aliases: []string{"dotnet", "asp.net", ".net core"}},
+	{name: "Ruby"},
+	{name: "PHP"},
+	{name: "Rust"},
+	{name: "Scala"},
+	{name: "Elixir"},
+	{name: "Haskell"},
+	{name: "Dart"},
+	{name: "SQL"},
+	{name: "Bash", aliases: []string{"shell scripting", "shell"}},
+	{name: "HTML", aliases: []string{"html5"}},
+	{name: "CSS", aliases: []string{"css3"}},
+	{name: "Sass", aliases: []string{"scss"}},
+
+	// Frameworks and libraries
+	{name: "React", aliases: []string{"react.js", "reactjs"}},
+	{name: "React Native"},
+	{name: "Vue", aliases: []string{"vue.js", "vuejs"}},
+	{name: "Angular", aliases: []string{"angularjs", "angular.js"}},
+	{name: "Svelte", aliases: []string{"sveltekit"}},
+	{name: "Next.js", aliases: []string{"nextjs"}},
+	{name: "Node.js", aliases: []string{"nodejs", "node"}},
+	{name: "Express", aliases: []string{"express.js", "expressjs"}, caseSensitive: true},
+	{name: "Django"},
+	{name: "Flask"},
+	{name: "FastAPI"},
+	{name: "Ruby on Rails", aliases: []string{"rails", "ror"}},
+	{name: "Spring", aliases: []string{"spring boot"}},
+	{name: "Laravel"},
+	{name: "Tailwind CSS", aliases: []string{"tailwind", "tailwindcss"}},
+	{name: "GraphQL"},
+	{name: "REST", aliases: []string{"restful", "rest api"}, caseSensitive: true},
+	{name: "gRPC"},
+	{name: "TensorFlow"},
+	{name: "PyTorch"},
+	{name: "scikit-learn", aliases: []string{"sklearn"}},
+	{name: "pandas"},
+	{name: "NumPy"},
+
+	// Data stores and messaging
+	{name: "PostgreSQL", aliases: []string{"postgres", "psql"}},
+	{name: "MySQL"},
+	{name: "SQLite"},
+	{name: "MongoDB", aliases: []string{"mongo"}},
+	{name: "Redis"},
+	{name: "Elasticsearch", aliases: []string{"elastic search", "opensearch"}},
+	{name: "DynamoDB"},
+	{name: "Cassandra"},
+	{name: "Snowflake"},
+	{name: "BigQuery"},
+	{name: "Kafka", aliases: []string{"apache kafka"}},
+	{name: "RabbitMQ"},
+	{name: "Spark", aliases: []string{"apache spark", "pyspark"}, caseSensitive: true},
+	{name: "Airflow", aliases: []string{"apache airflow"}},
+	{name: "dbt"},
+	{name: "NoSQL"},
+
+	// Cloud and infrastructure
+	{name: "AWS", aliases: []string{"amazon web services"}},
+	{name: "Azure", aliases: []string{"microsoft azure"}},
+	{name: "Google Cloud", aliases: []string{"gcp", "google cloud platform"}},
+	{name: "Kubernetes", aliases: []string{"k8s"}},
+	{name: "Docker", aliases: []string{"containers", "containerization"}},
+	{name: "Terraform"},
+	{name: "Ansible"},
+	{name: "Helm", caseSensitive: true},
+	{name: "Linux", aliases: []string{"unix"}},
+	{name: "CI/CD", aliases: []string{"continuous integration", "continuous delivery", "continuous deployment"}},
+	{name: "Jenkins"},
+	{name: "GitHub Actions"},
+	{name: "GitLab CI", aliases: []string{"gitlab"}},
+	{name: "Git"},
+	{name: "Serverless", aliases: []string{"aws lambda", "lambda"}},
+	{name: "Microservices", aliases: []string{"microservice", "micro-services", "service-oriented architecture", "soa"}},
+	{name: "DevOps"},
+	{name: "Site Reliability Engineering", aliases: []string{"sre"}},
+	{name: "Observability", aliases: []string{"monitoring", "prometheus", "grafana", "datadog"}},
+	{name: "Infrastructure as Code", aliases: []string{"iac"}},
+
+	// Data and AI
+	{name: "Machine Learning", aliases: []string{"ml"}},
+	{name: "Deep Learning"},
+	{name: "Artificial Intelligence", aliases: []string{"ai"}},
+	{name: "Large Language Models", aliases: []string{"llm", "llms", "generative ai", "genai"}},
+	{name: "Natural Language Processing", aliases: []string{"nlp"}},
+	{name: "Computer Vision"},
+	{name: "Data Science"},
+	{name: "Data Engineering", aliases: []string{"etl", "data pipelines"}},
+	{name: "Data Analysis", aliases: []string{"data analytics", "analytics"}},
+	{name: "Tableau"},
+	{name: "Power BI", aliases: []string{"powerbi"}},
+	{name: "Excel", aliases: []string{"microsoft excel", "spreadsheets"}, caseSensitive: true},
+
+	// Practices and roles
+	{name: "Agile", aliases: []string{"scrum", "kanban"}},
+	{name: "Test-Driven Development", aliases: []string{"tdd"}},
+	{name: "Automated Testing", aliases: []string{"unit testing", "test automation", "integration testing"}},
+	{name: "System Design", aliases: []string{"distributed systems", "software architecture"}},
+	{name: "API Design", aliases: []string{"api development"}},
+	{name: "Security", aliases: []string{"cybersecurity", "application security", "appsec", "infosec"}},
+	{name: "Accessibility", aliases: []string{"a11y", "wcag"}},
+	{name: "SEO", aliases: []string{"search engine optimization", "search engine optimisation"}},
+	{name: "UX Design", aliases: []string{"ux", "user experience"}},
+	{name: "UI Design", aliases: []string{"ui", "user interface"}},
+	{name: "Figma"},
+	{name: "Product Management", aliases: []string{"product manager", "product owner"}},
+	{name: "Project Management", aliases: []string{"project manager", "pmp"}},
+	{name: "Technical Writing"},
+	{name: "Mentoring", aliases: []string{"mentorship", "coaching", "mentor"}},
+	{name: "Leadership", aliases: []string{"team lead", "tech lead", "people management"}},
+	{name: "Stakeholder Management", aliases: []string{"stakeholder communication"}},
+	{name: "Salesforce"},
+	{name: "Jira"},
+	{name: "SaaS", aliases: []string{"software as a service"}},
+}
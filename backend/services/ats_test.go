@@ -0,0 +1,105 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildATSReport(t *testing.T) {
+	jd := `Platform Engineer. We run Go services on Kubernetes with PostgreSQL and React Native apps.
+You will own observability and mentor engineers. Terraform required. Some go-to-market experience is a plus.`
+
+	data := &ViewData{
+		Profile:      map[string]interface{}{"headline": "Platform engineer", "summary": "<p>I like k8s.</p>"},
+		SectionOrder: []string{"experience", "projects", "skills", "contacts"},
+		Sections: map[string][]map[string]interface{}{
+			"experience": {{
+				"id": "exp1", "title": "SRE", "company": "Acme",
+				"bullets":    []interface{}{"Ran Postgres clusters", "Mentored two engineers"},
+				"start_date": "2020-01-01 00:00:00.000Z",
+			}},
+			"projects": {{"id": "p1", "title": "kubectl-tree", "tech_stack": []interface{}{"Go", "Kubernetes"}}},
+			"skills":   {{"id": "s1", "name": "Terraform"}},
+			"contacts": {{"id": "c1", "value": "React Native"}},
+		},
+	}
+
+	report := BuildATSReport(jd, data, "")
+	byKeyword := func(list []ATSKeyword, skill string) *ATSKeyword {
+		for i := range list {
+			if list[i].Skill == skill || list[i].Keyword == skill {
+				return &list[i]
+			}
+		}
+		return nil
+	}
+
+	k8s := byKeyword(report.Matched, "Kubernetes")
+	if k8s == nil || len(k8s.Locations) != 2 {
+		t.Fatalf("Kubernetes = %+v, want matched in the profile (as k8s) and the project", k8s)
+	}
+	if k8s.Locations[0].Section != "profile" || k8s.Locations[1].ItemID != "p1" {
+		t.Errorf("Kubernetes locations = %+v", k8s.Locations)
+	}
+
+	postgres := byKeyword(report.Synonyms, "PostgreSQL")
+	if postgres == nil || postgres.ResumeTerm != "postgres" || postgres.Locations[0].Label != "SRE at Acme" {
+		t.Errorf("PostgreSQL = %+v, want a synonym match on the Acme role", postgres)
+	}
+
+	if native := byKeyword(report.Missing, "React Native"); native == nil {
+		t.Error("React Native should be missing (contacts are not resume content)")
+	}
+	if byKeyword(report.Matched, "React") != nil || byKeyword(report.Missing, "React") != nil {
+		t.Error("React Native was also counted as React")
+	}
+	if goSkill := byKeyword(report.Matched, "Go"); goSkill == nil || goSkill.Count != 1 {
+		t.Errorf("Go = %+v, want one mention (\"go-to-market\" is not the language)", goSkill)
+	}
+	if byKeyword(report.Matched, "Terraform") == nil || byKeyword(report.Matched, "Mentoring") == nil {
+		t.Error("Terraform and mentoring should match")
+	}
+	if report.Coverage <= 0 || report.Coverage >= 100 {
+		t.Errorf("coverage = %d", report.Coverage)
+	}
+}
+
+func TestCheckATSFormatting(t *testing.T) {
+	markdown := strings.Join([]string{
+		"# Ada Lovelace",
+		"",
+		"ada@example.com · [Portfolio](https://ada.dev) · [ada.dev](https://ada.dev/)",
+		"",
+		"## Where I've Been",
+		"",
+		"| Role | Years |",
+		"|------|-------|",
+		"| Programmer | 1842 |",
+		"",
+		"- ★ Wrote the first program",
+		"- Built <span>things</span>",
+		"- Café owner – 1850",
+		"",
+		"![headshot](ada.png)",
+		"",
+		"## Skills",
+	}, "\n")
+
+	rules := map[string]int{}
+	for _, issue := range CheckATSFormatting(markdown) {
+		rules[issue.Rule]++
+	}
+	want := map[string]int{"link": 1, "heading": 1, "table": 1, "symbols": 1, "html": 1, "image": 1}
+	for rule, count := range want {
+		if rules[rule] != count {
+			t.Errorf("%s issues = %d, want %d (all: %v)", rule, rules[rule], count, rules)
+		}
+	}
+	if rules["contact"] != 0 {
+		t.Error("email address was not found")
+	}
+
+	if issues := CheckATSFormatting("# Name\n\n## Experience\n\nPlain text\n"); len(issues) != 1 || issues[0].Rule != "contact" {
+		t.Errorf("issues = %+v, want only the missing email", issues)
+	}
+}
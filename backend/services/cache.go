@@ -0,0 +1,79 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/hook"
+)
+
+// cacheEntry holds one cached value. gen is bumped on every invalidation so a
+// load that raced with a write does not store the stale result.
+type cacheEntry struct {
+	mu     sync.Mutex
+	loaded bool
+	value  any
+	gen    uint64
+}
+
+func (c *cacheEntry) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.loaded = false
+	c.value = nil
+	c.gen++
+}
+
+// CachedValue returns the value cached in the app store under key, calling load
+// on a miss. Creating, updating or deleting a record in any of collections drops
+// the cached value, so callers always see committed changes.
+func CachedValue[T any](app core.App, key string, collections []string, load func() (T, error)) (T, error) {
+	entry := app.Store().GetOrSet(key, func() any {
+		entry := &cacheEntry{}
+		bindCacheInvalidation(app, key, collections, entry)
+		return entry
+	}).(*cacheEntry)
+
+	entry.mu.Lock()
+	if entry.loaded {
+		value := entry.value.(T)
+		entry.mu.Unlock()
+		return value, nil
+	}
+	gen := entry.gen
+	entry.mu.Unlock()
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	entry.mu.Lock()
+	if entry.gen == gen {
+		entry.value = value
+		entry.loaded = true
+	}
+	entry.mu.Unlock()
+
+	return value, nil
+}
+
+// bindCacheInvalidation drops entry after any committed change to collections.
+// Handler ids are fixed per key, so binding again replaces rather than stacks.
+func bindCacheInvalidation(app core.App, key string, collections []string, entry *cacheEntry) {
+	for _, collection := range collections {
+		handler := func(id string) *hook.Handler[*core.RecordEvent] {
+			return &hook.Handler[*core.RecordEvent]{
+				Id: "facetCache:" + key + ":" + collection + ":" + id,
+				Func: func(e *core.RecordEvent) error {
+					entry.invalidate()
+					return e.Next()
+				},
+			}
+		}
+		app.OnRecordAfterCreateSuccess(collection).Bind(handler("create"))
+		app.OnRecordAfterUpdateSuccess(collection).Bind(handler("update"))
+		app.OnRecordAfterDeleteSuccess(collection).Bind(handler("delete"))
+	}
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// counterTarget identifies one counter column on one record
+type counterTarget struct {
+	table    string
+	countCol string
+	timeCol  string
+	recordID string
+}
+
+// counterDelta is an unflushed increment
+type counterDelta struct {
+	n    int
+	last time.Time
+}
+
+// analyticsTarget identifies one analytics_daily counter
+type analyticsTarget struct {
+	day  string
+	kind string
+	key  string
+}
+
+// pendingAccessEvent is a visit not yet written to access_events
+type pendingAccessEvent struct {
+	viewID  string
+	tokenID string
+	visit   AccessVisit
+}
+
+// CounterService batches view_count and use_count increments in memory and
+// writes them with one atomic UPDATE per record. Concurrent hits cannot lose
+// counts, and the full record is not re-saved (so record hooks do not fire).
+// Daily analytics counters and access log events are batched the same way, so
+// a public hit costs no database write of its own.
+type CounterService struct {
+	mu        sync.Mutex
+	pending   map[counterTarget]*counterDelta
+	analytics map[analyticsTarget]int
+	events    []pendingAccessEvent
+	interval  time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewCounterService creates a counter aggregator that flushes every interval
+// once started
+func NewCounterService(interval time.Duration) *CounterService {
+	return &CounterService{
+		pending:   make(map[counterTarget]*counterDelta),
+		analytics: make(map[analyticsTarget]int),
+		interval:  interval,
+	}
+}
+
+// IncrementViewCount counts one public view fetch and updates last_viewed_at.
+// collection is the table the view was read from ("views" or "demo_views").
+func (s *CounterService) IncrementViewCount(collection, viewID string) {
+	s.add(counterTarget{collection, "view_count", "last_viewed_at", viewID}, time.Now())
+}
+
+// IncrementTokenUse counts one share token use and updates last_used_at
+func (s *CounterService) IncrementTokenUse(tokenID string) {
+	s.add(counterTarget{"share_tokens", "use_count", "last_used_at", tokenID}, time.Now())
+}
+
+// PendingTokenUses returns the uses of a token not yet written to the
+// database, so max_uses checks can include them
+func (s *CounterService) PendingTokenUses(tokenID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if delta, ok := s.pending[counterTarget{"share_tokens", "use_count", "last_used_at", tokenID}]; ok {
+		return delta.n
+	}
+	return 0
+}
+
+// CountAnalytics adds one to the day's analytics counter for kind and key
+func (s *CounterService) CountAnalytics(day time.Time, kind, key string) {
+	if key == "" || !IsAnalyticsKind(kind) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.analytics[analyticsTarget{day.UTC().Format(AnalyticsDayFormat), kind, key}]++
+}
+
+// RecordAccessEvent queues one anonymous visit to a view for the access log
+func (s *CounterService) RecordAccessEvent(viewID, tokenID string, visit AccessVisit) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, pendingAccessEvent{viewID, tokenID, visit})
+}
+
+func (s *CounterService) add(target counterTarget, at time.Time) {
+	if target.recordID == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delta, ok := s.pending[target]
+	if !ok {
+		delta = &counterDelta{}
+		s.pending[target] = delta
+	}
+	delta.n++
+	if at.After(delta.last) {
+		delta.last = at
+	}
+}
+
+// Flush writes all pending increments in a single transaction. On failure the
+// increments are kept for the next flush.
+func (s *CounterService) Flush(app core.App) error {
+	s.mu.Lock()
+	batch, analytics, events := s.pending, s.analytics, s.events
+	s.pending = make(map[counterTarget]*counterDelta)
+	s.analytics = make(map[analyticsTarget]int)
+	s.events = nil
+	s.mu.Unlock()
+
+	if len(batch) == 0 && len(analytics) == 0 && len(events) == 0 {
+		return nil
+	}
+
+	err := app.RunInTransaction(func(txApp core.App) error {
+		for target, delta := range batch {
+			lastAt, err := types.ParseDateTime(delta.last)
+			if err != nil {
+				return err
+			}
+			_, err = txApp.DB().NewQuery(
+				"UPDATE {{" + target.table + "}} SET [[" + target.countCol + "]] = COALESCE([[" + target.countCol + "]], 0) + {:n}, [[" + target.timeCol + "]] = {:at} WHERE [[id]] = {:id}",
+			).Bind(dbx.Params{
+				"n":  delta.n,
+				"at": lastAt.String(),
+				"id": target.recordID,
+			}).Execute()
+			if err != nil {
+				return err
+			}
+		}
+		for target, n := range analytics {
+			if err := addAnalytics(txApp, target.day, target.kind, target.key, n); err != nil {
+				return err
+			}
+		}
+		for _, event := range events {
+			if err := SaveAccessEvent(txApp, event.viewID, event.tokenID, event.visit); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.restore(batch, analytics, events)
+	}
+	return err
+}
+
+// restore puts a failed batch back so it is retried
+func (s *CounterService) restore(batch map[counterTarget]*counterDelta, analytics map[analyticsTarget]int, events []pendingAccessEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for target, n := range analytics {
+		s.analytics[target] += n
+	}
+	s.events = append(events, s.events...)
+
+	for target, delta := range batch {
+		if current, ok := s.pending[target]; ok {
+			current.n += delta.n
+			if delta.last.After(current.last) {
+				current.last = delta.last
+			}
+			continue
+		}
+		s.pending[target] = delta
+	}
+}
+
+// Start flushes pending increments every interval until Stop is called
+func (s *CounterService) Start(app core.App) {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return
+	}
+	stop, done := make(chan struct{}), make(chan struct{})
+	s.stop, s.done = stop, done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.Flush(app); err != nil {
+					app.Logger().Warn("Failed to flush counters", "error", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the flush loop and writes whatever is still pending
+func (s *CounterService) Stop(app core.App) error {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+	return s.Flush(app)
+}
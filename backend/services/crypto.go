@@ -21,6 +21,8 @@ const (
 	JWTIssuer       = "facet"
 	JWTIssuerLegacy = "me.yaml" // Accept tokens from before rebrand
 	JWTAudience     = "view-access"
+	// JWTAudienceShareSession marks sessions of verified recipients of bound share links
+	JWTAudienceShareSession = "share-session"
 )
 
 // ViewAccessClaims represents the JWT claims for password-protected view access
@@ -29,11 +31,25 @@ type ViewAccessClaims struct {
 	jwt.RegisteredClaims
 }
 
+// ShareSessionClaims represents the JWT claims of a verified recipient of a
+// recipient-bound share token
+type ShareSessionClaims struct {
+	ViewID       string `json:"vid"`
+	ShareTokenID string `json:"stid"`
+	jwt.RegisteredClaims
+}
+
+// ViewAccessRevocationCheck reports whether the view access session with the
+// given JWT ID was revoked. Unknown IDs should count as revoked.
+type ViewAccessRevocationCheck func(jti, viewID string) bool
+
 // CryptoService handles encryption/decryption of sensitive data
 type CryptoService struct {
 	key     []byte
 	hmacKey []byte
 	jwtKey  []byte
+
+	viewAccessRevoked ViewAccessRevocationCheck
 }
 
 // NewCryptoService creates a new crypto service with the given key
@@ -148,20 +164,36 @@ func (c *CryptoService) ValidateTokenHMAC(token, storedHMAC string) bool {
 	return subtle.ConstantTimeCompare([]byte(expectedHMAC), []byte(storedHMAC)) == 1
 }
 
+// SetViewAccessRevocationCheck makes ValidateViewAccessJWT reject sessions the
+// check reports as revoked. Must be called before tokens are validated.
+func (c *CryptoService) SetViewAccessRevocationCheck(check ViewAccessRevocationCheck) {
+	c.viewAccessRevoked = check
+}
+
 // GenerateViewAccessJWT creates a signed JWT for password-protected view access
 // Returns the token string and expiration time
 func (c *CryptoService) GenerateViewAccessJWT(viewID string, duration time.Duration) (string, time.Time, error) {
-	// Generate random JWT ID for audit/future revocation
+	token, claims, err := c.IssueViewAccessJWT(viewID, duration)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, claims.ExpiresAt.Time, nil
+}
+
+// IssueViewAccessJWT creates a signed JWT for password-protected view access and
+// returns its claims, so the session can be stored under its ID (jti) for revocation
+func (c *CryptoService) IssueViewAccessJWT(viewID string, duration time.Duration) (string, *ViewAccessClaims, error) {
+	// Generate random JWT ID for audit and revocation
 	jtiBytes := make([]byte, 16)
 	if _, err := rand.Read(jtiBytes); err != nil {
-		return "", time.Time{}, err
+		return "", nil, err
 	}
 	jti := base64.URLEncoding.EncodeToString(jtiBytes)
 
 	now := time.Now()
 	expiresAt := now.Add(duration)
 
-	claims := ViewAccessClaims{
+	claims := &ViewAccessClaims{
 		ViewID: viewID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    JWTIssuer,
@@ -175,10 +207,10 @@ func (c *CryptoService) GenerateViewAccessJWT(viewID string, duration time.Durat
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	signedToken, err := token.SignedString(c.jwtKey)
 	if err != nil {
-		return "", time.Time{}, err
+		return "", nil, err
 	}
 
-	return signedToken, expiresAt, nil
+	return signedToken, claims, nil
 }
 
 // ValidateViewAccessJWT validates a JWT and returns the view ID if valid
@@ -225,5 +257,77 @@ func (c *CryptoService) ValidateViewAccessJWT(tokenString string) (string, error
 		return "", errors.New("missing view ID")
 	}
 
+	if c.viewAccessRevoked != nil && c.viewAccessRevoked(claims.ID, claims.ViewID) {
+		return "", errors.New("token revoked")
+	}
+
 	return claims.ViewID, nil
 }
+
+// GenerateShareSessionJWT creates a signed JWT for the verified recipient of a
+// recipient-bound share token. Returns the token string and expiration time
+func (c *CryptoService) GenerateShareSessionJWT(viewID, shareTokenID string, duration time.Duration) (string, time.Time, error) {
+	jtiBytes := make([]byte, 16)
+	if _, err := rand.Read(jtiBytes); err != nil {
+		return "", time.Time{}, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(duration)
+
+	claims := ShareSessionClaims{
+		ViewID:       viewID,
+		ShareTokenID: shareTokenID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    JWTIssuer,
+			Audience:  jwt.ClaimStrings{JWTAudienceShareSession},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			ID:        base64.URLEncoding.EncodeToString(jtiBytes),
+		},
+	}
+
+	signedToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(c.jwtKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return signedToken, expiresAt, nil
+}
+
+// ValidateShareSessionJWT validates a share session JWT and returns its claims.
+// View access JWTs are rejected by audience, and the other way round.
+func (c *CryptoService) ValidateShareSessionJWT(tokenString string) (*ShareSessionClaims, error) {
+	if tokenString == "" {
+		return nil, errors.New("token required")
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &ShareSessionClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("invalid signing method")
+		}
+		return c.jwtKey, nil
+	})
+	if err != nil {
+		return nil, errors.New("invalid token")
+	}
+
+	claims, ok := token.Claims.(*ShareSessionClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	if claims.Issuer != JWTIssuer {
+		return nil, errors.New("invalid issuer")
+	}
+	if !claims.VerifyAudience(JWTAudienceShareSession, true) {
+		return nil, errors.New("invalid audience")
+	}
+	if claims.ExpiresAt == nil || claims.ExpiresAt.Before(time.Now()) {
+		return nil, errors.New("token expired")
+	}
+	if claims.ViewID == "" || claims.ShareTokenID == "" {
+		return nil, errors.New("missing view or share token ID")
+	}
+
+	return claims, nil
+}
@@ -320,3 +320,70 @@ func TestJWTConstants(t *testing.T) {
 		t.Fatalf("Expected audience 'view-access', got '%s'", JWTAudience)
 	}
 }
+
+func TestValidateViewAccessJWT_Revoked(t *testing.T) {
+	crypto := NewCryptoService("test-encryption-key-32-chars-ok!")
+
+	token, claims, err := crypto.IssueViewAccessJWT("view_1", time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to issue token: %v", err)
+	}
+	if claims.ID == "" || claims.ViewID != "view_1" {
+		t.Fatalf("Expected claims with a jti for view_1, got %+v", claims)
+	}
+
+	revoked := map[string]bool{}
+	crypto.SetViewAccessRevocationCheck(func(jti, viewID string) bool {
+		return revoked[jti]
+	})
+	if _, err := crypto.ValidateViewAccessJWT(token); err != nil {
+		t.Fatalf("Expected valid token before revocation, got error: %v", err)
+	}
+
+	revoked[claims.ID] = true
+	if _, err := crypto.ValidateViewAccessJWT(token); err == nil {
+		t.Fatal("Expected revoked token to be rejected")
+	}
+}
+
+func TestShareSessionJWT(t *testing.T) {
+	crypto := NewCryptoService("test-encryption-key-32-chars-ok!")
+
+	token, expiresAt, err := crypto.GenerateShareSessionJWT("view_1", "token_1", time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	if expiresAt.Before(time.Now()) {
+		t.Fatal("Expected expiry in the future")
+	}
+
+	claims, err := crypto.ValidateShareSessionJWT(token)
+	if err != nil {
+		t.Fatalf("Expected valid token, got error: %v", err)
+	}
+	if claims.ViewID != "view_1" || claims.ShareTokenID != "token_1" {
+		t.Fatalf("Expected view_1/token_1, got %s/%s", claims.ViewID, claims.ShareTokenID)
+	}
+
+	// Sessions and password access tokens are not interchangeable
+	if _, err := crypto.ValidateViewAccessJWT(token); err == nil {
+		t.Fatal("Share session should not validate as view access")
+	}
+	viewAccess, _, err := crypto.GenerateViewAccessJWT("view_1", time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	if _, err := crypto.ValidateShareSessionJWT(viewAccess); err == nil {
+		t.Fatal("View access token should not validate as a share session")
+	}
+
+	expired, _, _ := crypto.GenerateShareSessionJWT("view_1", "token_1", -time.Hour)
+	if _, err := crypto.ValidateShareSessionJWT(expired); err == nil {
+		t.Fatal("Expected expired session to be rejected")
+	}
+
+	other := NewCryptoService("another-encryption-key-32-chars!")
+	if _, err := other.ValidateShareSessionJWT(token); err == nil {
+		t.Fatal("Expected session signed with another key to be rejected")
+	}
+}
@@ -0,0 +1,457 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// JSONResumeSchema is the schema URL written into exported documents
+const JSONResumeSchema = "https://raw.githubusercontent.com/jsonresume/resume-schema/v1.0.0/schema.json"
+
+// JSONResume is a JSON Resume v1 document (https://jsonresume.org/schema).
+// Only the sections Facet can represent are modelled; other keys are ignored on import.
+type JSONResume struct {
+	Schema       string                  `json:"$schema,omitempty"`
+	Basics       JSONResumeBasics        `json:"basics"`
+	Work         []JSONResumeWork        `json:"work,omitempty"`
+	Education    []JSONResumeEducation   `json:"education,omitempty"`
+	Skills       []JSONResumeSkill       `json:"skills,omitempty"`
+	Projects     []JSONResumeProject     `json:"projects,omitempty"`
+	Awards       []JSONResumeAward       `json:"awards,omitempty"`
+	Certificates []JSONResumeCertificate `json:"certificates,omitempty"`
+	Publications []JSONResumePublication `json:"publications,omitempty"`
+	Meta         *JSONResumeMeta         `json:"meta,omitempty"`
+}
+
+type JSONResumeBasics struct {
+	Name     string              `json:"name,omitempty"`
+	Label    string              `json:"label,omitempty"`
+	Email    string              `json:"email,omitempty"`
+	Phone    string              `json:"phone,omitempty"`
+	URL      string              `json:"url,omitempty"`
+	Summary  string              `json:"summary,omitempty"`
+	Location *JSONResumeLocation `json:"location,omitempty"`
+	Profiles []JSONResumeProfile `json:"profiles,omitempty"`
+}
+
+type JSONResumeLocation struct {
+	Address     string `json:"address,omitempty"`
+	PostalCode  string `json:"postalCode,omitempty"`
+	City        string `json:"city,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+	Region      string `json:"region,omitempty"`
+}
+
+type JSONResumeProfile struct {
+	Network  string `json:"network,omitempty"`
+	Username string `json:"username,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+type JSONResumeWork struct {
+	Name        string   `json:"name,omitempty"`
+	Position    string   `json:"position,omitempty"`
+	Location    string   `json:"location,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Highlights  []string `json:"highlights,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+type JSONResumeEducation struct {
+	Institution string   `json:"institution,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Area        string   `json:"area,omitempty"`
+	StudyType   string   `json:"studyType,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	Score       string   `json:"score,omitempty"`
+	Courses     []string `json:"courses,omitempty"`
+}
+
+type JSONResumeSkill struct {
+	Name     string   `json:"name,omitempty"`
+	Level    string   `json:"level,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+type JSONResumeProject struct {
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Highlights  []string `json:"highlights,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	URL         string   `json:"url,omitempty"`
+}
+
+type JSONResumeAward struct {
+	Title   string `json:"title,omitempty"`
+	Date    string `json:"date,omitempty"`
+	Awarder string `json:"awarder,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+type JSONResumeCertificate struct {
+	Name   string `json:"name,omitempty"`
+	Date   string `json:"date,omitempty"`
+	Issuer string `json:"issuer,omitempty"`
+	URL    string `json:"url,omitempty"`
+}
+
+type JSONResumePublication struct {
+	Name        string `json:"name,omitempty"`
+	Publisher   string `json:"publisher,omitempty"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Summary     string `json:"summary,omitempty"`
+}
+
+type JSONResumeMeta struct {
+	Canonical    string `json:"canonical,omitempty"`
+	Version      string `json:"version,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// BuildJSONResume converts view data (see collectViewData) into a JSON Resume document.
+// Posts become publications linking to baseURL/posts/{slug}; pass "" to omit the links.
+func BuildJSONResume(data *ViewData, baseURL string) *JSONResume {
+	resume := &JSONResume{
+		Schema: JSONResumeSchema,
+		Basics: JSONResumeBasics{
+			Name:    itemString(data.Profile, "name"),
+			Label:   firstNonEmpty(data.HeroHeadline, itemString(data.Profile, "headline")),
+			Email:   itemString(data.Profile, "contact_email"),
+			Summary: firstNonEmpty(data.HeroSummary, itemString(data.Profile, "summary")),
+		},
+		Meta: &JSONResumeMeta{
+			Version:      "v1.0.0",
+			LastModified: time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+	if location := itemString(data.Profile, "location"); location != "" {
+		resume.Basics.Location = &JSONResumeLocation{City: location}
+	}
+
+	for _, item := range data.Sections["experience"] {
+		resume.Work = append(resume.Work, JSONResumeWork{
+			Name:       itemString(item, "company"),
+			Position:   itemString(item, "title"),
+			Location:   itemString(item, "location"),
+			StartDate:  jsonResumeDate(itemString(item, "start_date")),
+			EndDate:    jsonResumeDate(itemString(item, "end_date")),
+			Summary:    itemString(item, "description"),
+			Highlights: itemStrings(item, "bullets"),
+		})
+	}
+
+	for _, item := range data.Sections["education"] {
+		resume.Education = append(resume.Education, JSONResumeEducation{
+			Institution: itemString(item, "institution"),
+			Area:        itemString(item, "field"),
+			StudyType:   itemString(item, "degree"),
+			StartDate:   jsonResumeDate(itemString(item, "start_date")),
+			EndDate:     jsonResumeDate(itemString(item, "end_date")),
+		})
+	}
+
+	resume.Skills = buildJSONResumeSkills(data.Sections["skills"])
+
+	for _, item := range data.Sections["projects"] {
+		project := JSONResumeProject{
+			Name:        itemString(item, "title"),
+			Description: firstNonEmpty(itemString(item, "summary"), itemString(item, "description")),
+			Keywords:    itemStrings(item, "tech_stack"),
+		}
+		if links := itemLinks(item); len(links) > 0 {
+			project.URL = links[0]
+		}
+		resume.Projects = append(resume.Projects, project)
+	}
+
+	for _, item := range data.Sections["awards"] {
+		resume.Awards = append(resume.Awards, JSONResumeAward{
+			Title:   itemString(item, "title"),
+			Date:    jsonResumeDate(itemString(item, "awarded_at")),
+			Awarder: itemString(item, "issuer"),
+			Summary: itemString(item, "description"),
+		})
+	}
+
+	for _, item := range data.Sections["certifications"] {
+		resume.Certificates = append(resume.Certificates, JSONResumeCertificate{
+			Name:   itemString(item, "name"),
+			Date:   jsonResumeDate(itemString(item, "issue_date")),
+			Issuer: itemString(item, "issuer"),
+			URL:    itemString(item, "credential_url"),
+		})
+	}
+
+	for _, item := range data.Sections["posts"] {
+		publication := JSONResumePublication{
+			Name:        itemString(item, "title"),
+			ReleaseDate: jsonResumeDate(itemString(item, "published_at")),
+			Summary:     itemString(item, "excerpt"),
+		}
+		if slug := itemString(item, "slug"); slug != "" && baseURL != "" {
+			publication.URL = strings.TrimRight(baseURL, "/") + "/posts/" + slug
+		}
+		resume.Publications = append(resume.Publications, publication)
+	}
+
+	return resume
+}
+
+// buildJSONResumeSkills groups skills by category and proficiency. Categorized skills
+// become {name: category, level, keywords: [skills]}; uncategorized skills stay single
+// entries. ParseJSONResume reverses both shapes.
+func buildJSONResumeSkills(items []map[string]interface{}) []JSONResumeSkill {
+	var result []JSONResumeSkill
+	groups := make(map[string]int)
+
+	for _, item := range items {
+		name := itemString(item, "name")
+		if name == "" {
+			continue
+		}
+		category := itemString(item, "category")
+		level := itemString(item, "proficiency")
+
+		if category == "" {
+			result = append(result, JSONResumeSkill{Name: name, Level: level})
+			continue
+		}
+
+		key := category + "\x00" + level
+		if idx, ok := groups[key]; ok {
+			result[idx].Keywords = append(result[idx].Keywords, name)
+			continue
+		}
+		groups[key] = len(result)
+		result = append(result, JSONResumeSkill{Name: category, Level: level, Keywords: []string{name}})
+	}
+
+	return result
+}
+
+// ParseJSONResume converts a JSON Resume document into the ParsedResume structure
+// used by resume upload, so both go through the same record creation and deduplication.
+func ParseJSONResume(payload []byte) (*ParsedResume, *JSONResume, error) {
+	var resume JSONResume
+	if err := json.Unmarshal(payload, &resume); err != nil {
+		return nil, nil, fmt.Errorf("invalid JSON Resume document: %w", err)
+	}
+
+	parsed := &ParsedResume{
+		Profile: ProfileData{
+			Name:         resume.Basics.Name,
+			Headline:     resume.Basics.Label,
+			Summary:      resume.Basics.Summary,
+			ContactEmail: resume.Basics.Email,
+		},
+		Metadata: MetadataData{Confidence: "high"},
+	}
+	if loc := resume.Basics.Location; loc != nil {
+		parsed.Profile.Location = joinNonEmpty(", ", loc.City, loc.Region, loc.CountryCode)
+	}
+
+	for _, work := range resume.Work {
+		if work.Name == "" || work.Position == "" {
+			parsed.Metadata.Warnings = append(parsed.Metadata.Warnings,
+				fmt.Sprintf("Skipped work entry without company or position: %q", firstNonEmpty(work.Name, work.Position)))
+			continue
+		}
+		parsed.Experience = append(parsed.Experience, ExperienceData{
+			Company:     work.Name,
+			Title:       work.Position,
+			Location:    work.Location,
+			StartDate:   work.StartDate,
+			EndDate:     work.EndDate,
+			Description: joinNonEmpty("\n\n", work.Summary, work.Description),
+			Bullets:     work.Highlights,
+		})
+	}
+
+	for _, edu := range resume.Education {
+		if edu.Institution == "" {
+			continue
+		}
+		description := ""
+		if len(edu.Courses) > 0 {
+			description = "Courses: " + strings.Join(edu.Courses, ", ")
+		}
+		if edu.Score != "" {
+			description = joinNonEmpty("\n\n", description, "Score: "+edu.Score)
+		}
+		parsed.Education = append(parsed.Education, EducationData{
+			Institution: edu.Institution,
+			Degree:      edu.StudyType,
+			Field:       edu.Area,
+			StartDate:   edu.StartDate,
+			EndDate:     edu.EndDate,
+			Description: description,
+		})
+	}
+
+	for _, skill := range resume.Skills {
+		level := jsonResumeProficiency(skill.Level)
+		if len(skill.Keywords) == 0 {
+			if skill.Name != "" {
+				parsed.Skills = append(parsed.Skills, SkillData{Name: skill.Name, Proficiency: level})
+			}
+			continue
+		}
+		for _, keyword := range skill.Keywords {
+			if keyword == "" {
+				continue
+			}
+			parsed.Skills = append(parsed.Skills, SkillData{Name: keyword, Category: skill.Name, Proficiency: level})
+		}
+	}
+
+	for _, project := range resume.Projects {
+		if project.Name == "" {
+			continue
+		}
+		data := ProjectData{
+			Title:     project.Name,
+			Summary:   project.Description,
+			TechStack: project.Keywords,
+		}
+		if len(project.Highlights) > 0 {
+			data.Description = "- " + strings.Join(project.Highlights, "\n- ")
+		}
+		if project.URL != "" {
+			data.Links = []map[string]string{{"type": "website", "url": project.URL}}
+		}
+		parsed.Projects = append(parsed.Projects, data)
+	}
+
+	for _, award := range resume.Awards {
+		if award.Title == "" {
+			continue
+		}
+		parsed.Awards = append(parsed.Awards, AwardData{
+			Title:       award.Title,
+			Issuer:      award.Awarder,
+			AwardedAt:   award.Date,
+			Description: award.Summary,
+		})
+	}
+
+	for _, cert := range resume.Certificates {
+		if cert.Name == "" {
+			continue
+		}
+		parsed.Certifications = append(parsed.Certifications, CertificationData{
+			Name:          cert.Name,
+			Issuer:        cert.Issuer,
+			IssueDate:     cert.Date,
+			CredentialURL: cert.URL,
+		})
+	}
+
+	for _, pub := range resume.Publications {
+		if pub.Name == "" {
+			continue
+		}
+		parsed.Publications = append(parsed.Publications, PublicationData{
+			Title:       pub.Name,
+			Publisher:   pub.Publisher,
+			ReleaseDate: pub.ReleaseDate,
+			URL:         pub.URL,
+			Summary:     pub.Summary,
+		})
+	}
+
+	return parsed, &resume, nil
+}
+
+// jsonResumeProficiency maps free-form JSON Resume skill levels onto Facet's proficiency values
+func jsonResumeProficiency(level string) string {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "expert", "master", "advanced", "senior":
+		return "expert"
+	case "proficient", "intermediate", "experienced":
+		return "proficient"
+	case "familiar", "beginner", "basic", "novice", "junior":
+		return "familiar"
+	}
+	return ""
+}
+
+// jsonResumeDate shortens a stored date ("2020-03-01 00:00:00.000Z") to ISO 8601 ("2020-03-01")
+func jsonResumeDate(value string) string {
+	if idx := strings.IndexAny(value, "T "); idx != -1 {
+		value = value[:idx]
+	}
+	if _, err := time.Parse("2006-01-02", value); err != nil {
+		return ""
+	}
+	return value
+}
+
+// itemString reads a string value from a view data item
+func itemString(item map[string]interface{}, key string) string {
+	if s, ok := item[key].(string); ok {
+		return strings.TrimSpace(s)
+	}
+	return ""
+}
+
+// itemStrings reads a list of strings from a view data item
+func itemStrings(item map[string]interface{}, key string) []string {
+	var result []string
+	switch v := item[key].(type) {
+	case []string:
+		result = v
+	case []interface{}:
+		for _, entry := range v {
+			if s, ok := entry.(string); ok && strings.TrimSpace(s) != "" {
+				result = append(result, strings.TrimSpace(s))
+			}
+		}
+	}
+	return result
+}
+
+// itemLinks returns the URLs of a project's links ([{"type": "...", "url": "..."}])
+func itemLinks(item map[string]interface{}) []string {
+	links, ok := item["links"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var urls []string
+	for _, link := range links {
+		if m, ok := link.(map[string]interface{}); ok {
+			if url := itemString(m, "url"); url != "" {
+				urls = append(urls, url)
+			}
+		}
+	}
+	return urls
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func joinNonEmpty(sep string, values ...string) string {
+	var parts []string
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			parts = append(parts, strings.TrimSpace(v))
+		}
+	}
+	return strings.Join(parts, sep)
+}
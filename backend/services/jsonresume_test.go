@@ -0,0 +1,139 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestBuildJSONResume(t *testing.T) {
+	data := &ViewData{
+		Profile: map[string]interface{}{
+			"name":     "Ada Lovelace",
+			"headline": "Engineer",
+			"location": "London",
+		},
+		HeroHeadline: "Analytical Engine Programmer",
+		Sections: map[string][]map[string]interface{}{
+			"experience": {{
+				"company":    "Analytical Engines",
+				"title":      "Programmer",
+				"start_date": "1842-01-01 00:00:00.000Z",
+				"end_date":   "",
+				"bullets":    []interface{}{"Wrote the first program"},
+			}},
+			"projects": {{
+				"title":      "Note G",
+				"summary":    "Bernoulli numbers",
+				"tech_stack": []interface{}{"Punch cards"},
+				"links":      []interface{}{map[string]interface{}{"type": "website", "url": "https://example.com/g"}},
+			}},
+			"posts": {{
+				"title":        "Sketch of the Analytical Engine",
+				"slug":         "sketch",
+				"published_at": "1843-09-01 00:00:00.000Z",
+			}},
+		},
+	}
+
+	resume := BuildJSONResume(data, "https://ada.example.com/")
+
+	if resume.Basics.Label != "Analytical Engine Programmer" {
+		t.Errorf("Label = %q, want view hero headline", resume.Basics.Label)
+	}
+	if resume.Basics.Location == nil || resume.Basics.Location.City != "London" {
+		t.Errorf("Location = %+v", resume.Basics.Location)
+	}
+	if len(resume.Work) != 1 || resume.Work[0].StartDate != "1842-01-01" || resume.Work[0].EndDate != "" {
+		t.Errorf("Work = %+v", resume.Work)
+	}
+	if !reflect.DeepEqual(resume.Work[0].Highlights, []string{"Wrote the first program"}) {
+		t.Errorf("Highlights = %v", resume.Work[0].Highlights)
+	}
+	if len(resume.Projects) != 1 || resume.Projects[0].URL != "https://example.com/g" {
+		t.Errorf("Projects = %+v", resume.Projects)
+	}
+	if len(resume.Publications) != 1 || resume.Publications[0].URL != "https://ada.example.com/posts/sketch" {
+		t.Errorf("Publications = %+v", resume.Publications)
+	}
+}
+
+func TestJSONResumeSkillsRoundTrip(t *testing.T) {
+	skills := []map[string]interface{}{
+		{"name": "Go", "category": "Languages", "proficiency": "expert"},
+		{"name": "Python", "category": "Languages", "proficiency": "expert"},
+		{"name": "Rust", "category": "Languages", "proficiency": "familiar"},
+		{"name": "Public speaking", "category": "", "proficiency": "proficient"},
+	}
+
+	resume := BuildJSONResume(&ViewData{Sections: map[string][]map[string]interface{}{"skills": skills}}, "")
+	if len(resume.Skills) != 3 {
+		t.Fatalf("Skills = %+v, want 3 groups", resume.Skills)
+	}
+	if !reflect.DeepEqual(resume.Skills[0].Keywords, []string{"Go", "Python"}) {
+		t.Errorf("First group keywords = %v", resume.Skills[0].Keywords)
+	}
+
+	payload, err := json.Marshal(resume)
+	if err != nil {
+		t.Fatalf("Failed to marshal resume: %v", err)
+	}
+	parsed, _, err := ParseJSONResume(payload)
+	if err != nil {
+		t.Fatalf("ParseJSONResume() error = %v", err)
+	}
+
+	want := []SkillData{
+		{Name: "Go", Category: "Languages", Proficiency: "expert"},
+		{Name: "Python", Category: "Languages", Proficiency: "expert"},
+		{Name: "Rust", Category: "Languages", Proficiency: "familiar"},
+		{Name: "Public speaking", Proficiency: "proficient"},
+	}
+	if !reflect.DeepEqual(parsed.Skills, want) {
+		t.Errorf("Skills = %+v, want %+v", parsed.Skills, want)
+	}
+}
+
+func TestParseJSONResume(t *testing.T) {
+	payload := []byte(`{
+		"basics": {"name": "Grace Hopper", "label": "Rear Admiral", "location": {"city": "Arlington", "region": "VA"}},
+		"work": [
+			{"name": "US Navy", "position": "Computer Scientist", "startDate": "1943-12", "highlights": ["COBOL"]},
+			{"position": "Missing company"}
+		],
+		"education": [{"institution": "Yale", "area": "Mathematics", "studyType": "PhD", "endDate": "1934", "courses": ["Algebra"]}],
+		"skills": [{"name": "Compilers", "level": "Master"}],
+		"publications": [{"name": "The Education of a Computer", "publisher": "ACM", "releaseDate": "1952-05-02"}],
+		"interests": [{"name": "ignored"}]
+	}`)
+
+	parsed, resume, err := ParseJSONResume(payload)
+	if err != nil {
+		t.Fatalf("ParseJSONResume() error = %v", err)
+	}
+
+	if resume.Basics.Name != "Grace Hopper" || parsed.Profile.Location != "Arlington, VA" {
+		t.Errorf("Profile = %+v", parsed.Profile)
+	}
+	if len(parsed.Experience) != 1 || parsed.Experience[0].StartDate != "1943-12" {
+		t.Errorf("Experience = %+v", parsed.Experience)
+	}
+	if len(parsed.Metadata.Warnings) != 1 {
+		t.Errorf("Warnings = %v, want one for the incomplete work entry", parsed.Metadata.Warnings)
+	}
+	if len(parsed.Education) != 1 || parsed.Education[0].Degree != "PhD" || parsed.Education[0].Description != "Courses: Algebra" {
+		t.Errorf("Education = %+v", parsed.Education)
+	}
+	if len(parsed.Skills) != 1 || parsed.Skills[0].Proficiency != "expert" {
+		t.Errorf("Skills = %+v", parsed.Skills)
+	}
+	if len(parsed.Publications) != 1 || parsed.Publications[0].Publisher != "ACM" {
+		t.Errorf("Publications = %+v", parsed.Publications)
+	}
+}
+
+func TestParseJSONResumeInvalid(t *testing.T) {
+	if _, _, err := ParseJSONResume([]byte(`not json`)); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+}
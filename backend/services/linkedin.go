@@ -0,0 +1,275 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// LinkedIn "Download your data" archive files understood by the importer
+const (
+	linkedInProfileFile         = "Profile.csv"
+	linkedInPositionsFile       = "Positions.csv"
+	linkedInEducationFile       = "Education.csv"
+	linkedInSkillsFile          = "Skills.csv"
+	linkedInCertificationsFile  = "Certifications.csv"
+	linkedInProjectsFile        = "Projects.csv"
+	linkedInRecommendationsFile = "Recommendations_Received.csv"
+)
+
+// linkedInHeaders identifies the header row of each file. Some exports prepend
+// "Notes:" paragraphs before the header, so rows are skipped until one matches.
+var linkedInHeaders = map[string]string{
+	linkedInProfileFile:         "First Name",
+	linkedInPositionsFile:       "Company Name",
+	linkedInEducationFile:       "School Name",
+	linkedInSkillsFile:          "Name",
+	linkedInCertificationsFile:  "Name",
+	linkedInProjectsFile:        "Title",
+	linkedInRecommendationsFile: "Text",
+}
+
+// ParseLinkedInExport reads a LinkedIn data export ZIP into a ParsedResume.
+// Parsing is deterministic: rows keep their order in the CSV files and no AI is involved.
+// Files missing from the archive are skipped (LinkedIn omits empty sections).
+func ParseLinkedInExport(r io.ReaderAt, size int64) (*ParsedResume, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("not a ZIP archive: %w", err)
+	}
+
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[strings.ToLower(path.Base(f.Name))] = f
+	}
+
+	parsed := &ParsedResume{Metadata: MetadataData{Confidence: "high"}}
+	found := 0
+
+	for _, name := range []string{
+		linkedInProfileFile, linkedInPositionsFile, linkedInEducationFile, linkedInSkillsFile,
+		linkedInCertificationsFile, linkedInProjectsFile, linkedInRecommendationsFile,
+	} {
+		f, ok := files[strings.ToLower(name)]
+		if !ok {
+			continue
+		}
+
+		rows, err := readLinkedInCSV(f, linkedInHeaders[name])
+		if err != nil {
+			parsed.Metadata.Warnings = append(parsed.Metadata.Warnings, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		found++
+
+		switch name {
+		case linkedInProfileFile:
+			if len(rows) > 0 {
+				parsed.Profile = linkedInProfile(rows[0])
+			}
+		case linkedInPositionsFile:
+			for _, row := range rows {
+				if row["Company Name"] == "" || row["Title"] == "" {
+					continue
+				}
+				parsed.Experience = append(parsed.Experience, ExperienceData{
+					Company:     row["Company Name"],
+					Title:       row["Title"],
+					Location:    row["Location"],
+					StartDate:   linkedInDate(row["Started On"]),
+					EndDate:     linkedInDate(row["Finished On"]),
+					Description: row["Description"],
+				})
+			}
+		case linkedInEducationFile:
+			for _, row := range rows {
+				if row["School Name"] == "" {
+					continue
+				}
+				degree, field := splitLinkedInDegree(row["Degree Name"])
+				description := row["Notes"]
+				if activities := row["Activities"]; activities != "" {
+					description = joinNonEmpty("\n\n", description, "Activities: "+activities)
+				}
+				parsed.Education = append(parsed.Education, EducationData{
+					Institution: row["School Name"],
+					Degree:      degree,
+					Field:       field,
+					StartDate:   linkedInDate(row["Start Date"]),
+					EndDate:     linkedInDate(row["End Date"]),
+					Description: description,
+				})
+			}
+		case linkedInSkillsFile:
+			for _, row := range rows {
+				if row["Name"] != "" {
+					parsed.Skills = append(parsed.Skills, SkillData{Name: row["Name"]})
+				}
+			}
+		case linkedInCertificationsFile:
+			for _, row := range rows {
+				if row["Name"] == "" {
+					continue
+				}
+				parsed.Certifications = append(parsed.Certifications, CertificationData{
+					Name:          row["Name"],
+					Issuer:        row["Authority"],
+					IssueDate:     linkedInDate(row["Started On"]),
+					ExpiryDate:    linkedInDate(row["Finished On"]),
+					CredentialID:  row["License Number"],
+					CredentialURL: row["Url"],
+				})
+			}
+		case linkedInProjectsFile:
+			for _, row := range rows {
+				if row["Title"] == "" {
+					continue
+				}
+				project := ProjectData{
+					Title:       row["Title"],
+					Description: row["Description"],
+				}
+				if url := row["Url"]; url != "" {
+					project.Links = []map[string]string{{"type": "website", "url": url}}
+				}
+				parsed.Projects = append(parsed.Projects, project)
+			}
+		case linkedInRecommendationsFile:
+			for _, row := range rows {
+				author := joinNonEmpty(" ", row["First Name"], row["Last Name"])
+				if author == "" || row["Text"] == "" {
+					continue
+				}
+				if status := row["Status"]; status != "" && !strings.EqualFold(status, "VISIBLE") {
+					continue
+				}
+				parsed.Testimonials = append(parsed.Testimonials, TestimonialData{
+					AuthorName:    author,
+					AuthorTitle:   row["Job Title"],
+					AuthorCompany: row["Company"],
+					Content:       row["Text"],
+					Date:          linkedInDate(row["Creation Date"]),
+					Source:        "linkedin",
+				})
+			}
+		}
+	}
+
+	if found == 0 {
+		return nil, fmt.Errorf("no LinkedIn export files found (expected Positions.csv, Education.csv, Skills.csv, ...)")
+	}
+
+	return parsed, nil
+}
+
+// readLinkedInCSV returns the rows of a CSV file as maps keyed by column name
+func readLinkedInCSV(f *zip.File, headerColumn string) ([]map[string]string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	reader := csv.NewReader(rc)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var header []string
+	var rows []map[string]string
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		if header == nil {
+			for i, cell := range record {
+				record[i] = strings.TrimSpace(strings.TrimPrefix(cell, "\ufeff"))
+			}
+			for _, cell := range record {
+				if cell == headerColumn {
+					header = record
+					break
+				}
+			}
+			continue
+		}
+
+		row := make(map[string]string, len(header))
+		empty := true
+		for i, column := range header {
+			if i < len(record) {
+				row[column] = strings.TrimSpace(record[i])
+				if row[column] != "" {
+					empty = false
+				}
+			}
+		}
+		if !empty {
+			rows = append(rows, row)
+		}
+	}
+
+	if header == nil {
+		return nil, fmt.Errorf("missing %q column", headerColumn)
+	}
+	return rows, nil
+}
+
+// linkedInProfile maps Profile.csv onto profile data
+func linkedInProfile(row map[string]string) ProfileData {
+	return ProfileData{
+		Name:     joinNonEmpty(" ", row["First Name"], row["Last Name"]),
+		Headline: row["Headline"],
+		Location: firstNonEmpty(row["Geo Location"], row["Address"]),
+		Summary:  row["Summary"],
+	}
+}
+
+// linkedInDateLayouts are the date formats found in LinkedIn exports
+var linkedInDateLayouts = []struct {
+	layout string
+	format string
+}{
+	{"Jan 2006", "2006-01"},
+	{"January 2006", "2006-01"},
+	{"01/02/06, 03:04 PM", "2006-01-02"},
+	{"01/02/06", "2006-01-02"},
+	{"2006-01-02 15:04:05 MST", "2006-01-02"},
+	{"2006-01-02", "2006-01-02"},
+	{"2006-01", "2006-01"},
+	{"2006", "2006"},
+}
+
+// linkedInDate converts a LinkedIn date ("Jan 2020", "2019", "06/15/21, 10:22 AM")
+// into the ISO form resume imports expect. Unknown formats become "".
+func linkedInDate(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+
+	for _, l := range linkedInDateLayouts {
+		if t, err := time.Parse(l.layout, value); err == nil {
+			return t.Format(l.format)
+		}
+	}
+	return ""
+}
+
+// splitLinkedInDegree splits "Bachelor of Science - BS, Computer Science" into degree and field
+func splitLinkedInDegree(value string) (string, string) {
+	degree, field, found := strings.Cut(value, ", ")
+	if !found {
+		return strings.TrimSpace(value), ""
+	}
+	return strings.TrimSpace(degree), strings.TrimSpace(field)
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func buildLinkedInExport(t *testing.T, files map[string]string) *bytes.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+var linkedInFixture = map[string]string{
+	"Profile.csv": "\ufeffFirst Name,Last Name,Maiden Name,Address,Birth Date,Headline,Summary,Industry,Zip Code,Geo Location\n" +
+		"Ada,Lovelace,,,,Engineer,\"Poet of science,\nand mathematics\",Computing,,\"London, United Kingdom\"\n",
+	"Positions.csv": "Company Name,Title,Description,Location,Started On,Finished On\n" +
+		"Analytical Engines,Programmer,Wrote Note G,London,Jan 1842,\n" +
+		"Babbage & Co,Translator,,,1840,Dec 1841\n",
+	"Education.csv": "School Name,Start Date,End Date,Notes,Degree Name,Activities\n" +
+		"University of London,2015,2019,Thesis on engines,\"Bachelor of Science - BS, Mathematics\",Chess club\n",
+	"Skills.csv": "Name\nMathematics\nPoetry\n",
+	"Basic_LinkedInData/Certifications.csv": "Name,Url,Authority,Started On,Finished On,License Number\n" +
+		"Engine Operator,https://example.com/cert,Royal Society,Mar 2020,Mar 2023,ABC-123\n",
+	"Projects.csv": "Title,Description,Url,Started On,Finished On\n" +
+		"Note G,Bernoulli numbers,https://example.com/g,Jan 1843,\n",
+	"Recommendations_Received.csv": "Notes:\n\"Recommendations you received on LinkedIn\"\n\n" +
+		"First Name,Last Name,Company,Job Title,Text,Creation Date,Status\n" +
+		"Charles,Babbage,Analytical Engines,Inventor,She understood the engine better than I did.,\"07/01/21, 10:22 AM\",VISIBLE\n" +
+		"Hidden,Person,,,Not shown,\"07/02/21, 10:22 AM\",HIDDEN\n",
+}
+
+func TestParseLinkedInExport(t *testing.T) {
+	archive := buildLinkedInExport(t, linkedInFixture)
+
+	parsed, err := ParseLinkedInExport(archive, archive.Size())
+	if err != nil {
+		t.Fatalf("ParseLinkedInExport() error = %v", err)
+	}
+
+	if parsed.Profile.Name != "Ada Lovelace" || parsed.Profile.Location != "London, United Kingdom" {
+		t.Errorf("Profile = %+v", parsed.Profile)
+	}
+
+	wantExperience := []ExperienceData{
+		{Company: "Analytical Engines", Title: "Programmer", Location: "London", StartDate: "1842-01", Description: "Wrote Note G"},
+		{Company: "Babbage & Co", Title: "Translator", StartDate: "1840", EndDate: "1841-12"},
+	}
+	if !reflect.DeepEqual(parsed.Experience, wantExperience) {
+		t.Errorf("Experience = %+v, want %+v", parsed.Experience, wantExperience)
+	}
+
+	if len(parsed.Education) != 1 {
+		t.Fatalf("Education = %+v", parsed.Education)
+	}
+	edu := parsed.Education[0]
+	if edu.Degree != "Bachelor of Science - BS" || edu.Field != "Mathematics" || edu.Description != "Thesis on engines\n\nActivities: Chess club" {
+		t.Errorf("Education = %+v", edu)
+	}
+
+	if len(parsed.Skills) != 2 || parsed.Skills[1].Name != "Poetry" {
+		t.Errorf("Skills = %+v", parsed.Skills)
+	}
+
+	wantCert := CertificationData{
+		Name: "Engine Operator", Issuer: "Royal Society", IssueDate: "2020-03", ExpiryDate: "2023-03",
+		CredentialID: "ABC-123", CredentialURL: "https://example.com/cert",
+	}
+	if len(parsed.Certifications) != 1 || parsed.Certifications[0] != wantCert {
+		t.Errorf("Certifications = %+v", parsed.Certifications)
+	}
+
+	if len(parsed.Projects) != 1 || parsed.Projects[0].Links[0]["url"] != "https://example.com/g" {
+		t.Errorf("Projects = %+v", parsed.Projects)
+	}
+
+	wantTestimonial := TestimonialData{
+		AuthorName: "Charles Babbage", AuthorTitle: "Inventor", AuthorCompany: "Analytical Engines",
+		Content: "She understood the engine better than I did.", Date: "2021-07-01", Source: "linkedin",
+	}
+	if len(parsed.Testimonials) != 1 || parsed.Testimonials[0] != wantTestimonial {
+		t.Errorf("Testimonials = %+v, want only the visible recommendation", parsed.Testimonials)
+	}
+
+	again, err := ParseLinkedInExport(archive, archive.Size())
+	if err != nil || !reflect.DeepEqual(parsed, again) {
+		t.Error("Parsing the same export twice gave different results")
+	}
+}
+
+func TestParseLinkedInExportRejectsUnrelatedZip(t *testing.T) {
+	archive := buildLinkedInExport(t, map[string]string{"readme.txt": "hello"})
+	if _, err := ParseLinkedInExport(archive, archive.Size()); err == nil {
+		t.Error("Expected an error for a ZIP without LinkedIn files")
+	}
+
+	notZip := bytes.NewReader([]byte("not a zip"))
+	if _, err := ParseLinkedInExport(notZip, notZip.Size()); err == nil {
+		t.Error("Expected an error for a non-ZIP payload")
+	}
+}
+
+func TestLinkedInDate(t *testing.T) {
+	tests := map[string]string{
+		"Jan 2020":           "2020-01",
+		"September 2019":     "2019-09",
+		"2018":               "2018",
+		"2021-06":            "2021-06",
+		"06/15/21, 10:22 AM": "2021-06-15",
+		"":                   "",
+		"sometime":           "",
+	}
+	for input, want := range tests {
+		if got := linkedInDate(input); got != want {
+			t.Errorf("linkedInDate(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
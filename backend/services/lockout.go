@@ -0,0 +1,109 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// LockoutService counts failed attempts per key (a view ID for passwords) and
+// backs off exponentially once a few have failed. The per-IP rate limiter does
+// not stop guessing spread over many addresses; this does, at the cost of also
+// slowing down legitimate visitors of a view under attack, so the backoff is capped.
+type LockoutService struct {
+	mu      sync.Mutex
+	entries map[string]*lockoutEntry
+
+	// Failures allowed before the first lockout
+	freeAttempts int
+	// First lockout; each further failure doubles it up to maxDelay
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	// Failures are forgotten after this long without a new one
+	resetAfter time.Duration
+
+	now func() time.Time
+}
+
+type lockoutEntry struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// NewLockoutService creates a lockout allowing 5 failures, then locking for
+// 2s, 4s, 8s... up to 15 minutes. An hour without failures starts over.
+func NewLockoutService() *LockoutService {
+	return &LockoutService{
+		entries:      make(map[string]*lockoutEntry),
+		freeAttempts: 5,
+		baseDelay:    2 * time.Second,
+		maxDelay:     15 * time.Minute,
+		resetAfter:   time.Hour,
+		now:          time.Now,
+	}
+}
+
+// RetryAfter returns how long the key is still locked, or 0 if an attempt is allowed
+func (s *LockoutService) RetryAfter(key string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.entry(key)
+	if entry == nil {
+		return 0
+	}
+	if wait := entry.lockedUntil.Sub(s.now()); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// Fail records a failed attempt and returns the lockout it triggered, if any
+func (s *LockoutService) Fail(key string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	entry := s.entry(key)
+	if entry == nil {
+		entry = &lockoutEntry{}
+		s.entries[key] = entry
+	}
+	entry.failures++
+	entry.lastFailure = now
+
+	over := entry.failures - s.freeAttempts
+	if over <= 0 {
+		return 0
+	}
+	delay := s.maxDelay
+	if over <= 30 {
+		if d := s.baseDelay << (over - 1); d < s.maxDelay {
+			delay = d
+		}
+	}
+	entry.lockedUntil = now.Add(delay)
+	return delay
+}
+
+// Succeed clears the failures of a key
+func (s *LockoutService) Succeed(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+}
+
+// entry returns the state of a key, dropping it once it has been quiet long enough.
+// Must be called with the lock held.
+func (s *LockoutService) entry(key string) *lockoutEntry {
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+	now := s.now()
+	if now.Sub(entry.lastFailure) > s.resetAfter && !now.Before(entry.lockedUntil) {
+		delete(s.entries, key)
+		return nil
+	}
+	return entry
+}
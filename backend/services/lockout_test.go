@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockoutBackoff(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	lockout := NewLockoutService()
+	lockout.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		if delay := lockout.Fail("view1"); delay != 0 {
+			t.Fatalf("failure %d locked for %v, want free attempts first", i+1, delay)
+		}
+	}
+	if wait := lockout.RetryAfter("view1"); wait != 0 {
+		t.Fatalf("RetryAfter() = %v after free attempts, want 0", wait)
+	}
+
+	for i, want := range []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second} {
+		if delay := lockout.Fail("view1"); delay != want {
+			t.Errorf("lockout %d = %v, want %v", i+1, delay, want)
+		}
+	}
+	if wait := lockout.RetryAfter("view1"); wait != 8*time.Second {
+		t.Errorf("RetryAfter() = %v, want 8s", wait)
+	}
+	if wait := lockout.RetryAfter("view2"); wait != 0 {
+		t.Errorf("other view RetryAfter() = %v, want 0", wait)
+	}
+
+	now = now.Add(9 * time.Second)
+	if wait := lockout.RetryAfter("view1"); wait != 0 {
+		t.Errorf("RetryAfter() = %v once the lockout passed, want 0", wait)
+	}
+
+	// The backoff is capped
+	for i := 0; i < 40; i++ {
+		lockout.Fail("view1")
+	}
+	if wait := lockout.RetryAfter("view1"); wait != 15*time.Minute {
+		t.Errorf("RetryAfter() = %v, want the 15m cap", wait)
+	}
+
+	// A quiet hour forgets the failures
+	now = now.Add(2 * time.Hour)
+	if delay := lockout.Fail("view1"); delay != 0 {
+		t.Errorf("failure after a quiet hour locked for %v, want a fresh start", delay)
+	}
+
+	// Success clears the count
+	for i := 0; i < 6; i++ {
+		lockout.Fail("view3")
+	}
+	lockout.Succeed("view3")
+	if wait := lockout.RetryAfter("view3"); wait != 0 {
+		t.Errorf("RetryAfter() = %v after success, want 0", wait)
+	}
+}
@@ -196,7 +196,12 @@ func (s *RateLimitService) getLimiter(ip, tier string) *rate.Limiter {
 // getClientIP extracts the client IP address from the request
 // Respects TRUST_PROXY setting for X-Forwarded-For and CF-Connecting-IP
 func (s *RateLimitService) getClientIP(r *http.Request) string {
-	if s.trustProxy {
+	return clientIP(r, s.trustProxy)
+}
+
+// clientIP extracts the client IP address, trusting proxy headers only if asked
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
 		// Priority 1: Cloudflare's CF-Connecting-IP (most reliable when using Cloudflare)
 		if cfIP := r.Header.Get("CF-Connecting-IP"); cfIP != "" {
 			return cfIP
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// CachedResponse is a rendered public response with its validators
+type CachedResponse struct {
+	ContentType  string
+	Body         []byte
+	ETag         string
+	LastModified time.Time
+
+	expires time.Time
+}
+
+// ResponseCache keeps rendered public responses in memory until content
+// changes or they reach their max age. Keys combine endpoint, slug and access
+// context, so a share link's personalized response is never served to another
+// visitor.
+type ResponseCache struct {
+	mu         sync.RWMutex
+	entries    map[string]*CachedResponse
+	gen        uint64
+	maxEntries int
+	maxAge     time.Duration
+}
+
+// NewResponseCache creates a response cache holding at most maxEntries
+// responses; when full it is cleared rather than evicting one by one. Entries
+// are rebuilt after maxAge because some responses depend on the clock as well
+// as on content (section rules like "within the last 30d").
+func NewResponseCache(maxEntries int, maxAge time.Duration) *ResponseCache {
+	return &ResponseCache{
+		entries:    make(map[string]*CachedResponse),
+		maxEntries: maxEntries,
+		maxAge:     maxAge,
+	}
+}
+
+// Get returns the cached response for key and the cache generation. Pass the
+// generation to Set so a response built while content changed is not stored.
+// Expired entries are reported as a miss.
+func (c *ResponseCache) Get(key string) (*CachedResponse, uint64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry := c.entries[key]
+	if entry != nil && !time.Now().Before(entry.expires) {
+		return nil, c.gen
+	}
+	return entry, c.gen
+}
+
+// Set stores a rendered response and returns it with its ETag. If the cache
+// was invalidated since gen, the response is returned but not stored.
+func (c *ResponseCache) Set(key string, gen uint64, contentType string, body []byte) *CachedResponse {
+	sum := sha256.Sum256(body)
+	now := time.Now()
+	entry := &CachedResponse{
+		ContentType:  contentType,
+		Body:         body,
+		ETag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		LastModified: now.UTC().Truncate(time.Second),
+		expires:      now.Add(c.maxAge),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if gen != c.gen {
+		return entry
+	}
+	if len(c.entries) >= c.maxEntries {
+		c.entries = make(map[string]*CachedResponse)
+	}
+	c.entries[key] = entry
+	return entry
+}
+
+// Invalidate drops every cached response
+func (c *ResponseCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*CachedResponse)
+	c.gen++
+}
+
+// Len returns the number of cached responses
+func (c *ResponseCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.entries)
+}
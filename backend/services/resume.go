@@ -10,9 +10,18 @@ import (
 	"strings"
 )
 
+// PDF engines. The native engine is built in; Pandoc (with LaTeX) is an optional
+// higher-fidelity alternative.
+const (
+	PDFEngineNative = "native"
+	PDFEnginePandoc = "pandoc"
+)
+
 // ResumeService handles AI-powered resume generation
 type ResumeService struct {
-	ai *AIService
+	ai            *AIService
+	pdfEngine     string
+	docxReference func() []byte
 }
 
 // GenerationConfig contains settings for resume generation
@@ -21,6 +30,7 @@ type GenerationConfig struct {
 	Style      string   `json:"style"`  // chronological, functional, hybrid
 	Length     string   `json:"length"` // one-page, two-page, full
 	Emphasis   []string `json:"emphasis"`
+	Template   string   `json:"template,omitempty"` // template slug; empty means AI generation
 }
 
 // ViewData represents the complete view data for resume generation
@@ -28,13 +38,39 @@ type ViewData struct {
 	Profile      map[string]interface{}              `json:"profile"`
 	Sections     map[string][]map[string]interface{} `json:"sections"`
 	SectionOrder []string                            `json:"section_order"`
-	HeroHeadline string                              `json:"hero_headline,omitempty"`
-	HeroSummary  string                              `json:"hero_summary,omitempty"`
+	// SectionTitles labels user-defined sections; built-in sections use their own titles
+	SectionTitles map[string]string `json:"section_titles,omitempty"`
+	HeroHeadline  string            `json:"hero_headline,omitempty"`
+	HeroSummary   string            `json:"hero_summary,omitempty"`
 }
 
 // NewResumeService creates a new resume service
 func NewResumeService(ai *AIService) *ResumeService {
-	return &ResumeService{ai: ai}
+	return &ResumeService{ai: ai, pdfEngine: PDFEngineNative}
+}
+
+// SetPDFEngine selects the preferred PDF engine ("native" or "pandoc"); unknown values mean native
+func (r *ResumeService) SetPDFEngine(engine string) {
+	if strings.EqualFold(strings.TrimSpace(engine), PDFEnginePandoc) {
+		r.pdfEngine = PDFEnginePandoc
+		return
+	}
+	r.pdfEngine = PDFEngineNative
+}
+
+// PDFEngine returns the engine PDFs will be rendered with. Pandoc is only used when it
+// was selected and is installed.
+func (r *ResumeService) PDFEngine() string {
+	if r.pdfEngine == PDFEnginePandoc && r.CheckPandocAvailable() {
+		return PDFEnginePandoc
+	}
+	return PDFEngineNative
+}
+
+// SetDOCXReference sets the loader for the reference .docx whose styles DOCX exports use.
+// The loader returns nil when no reference document is uploaded.
+func (r *ResumeService) SetDOCXReference(loader func() []byte) {
+	r.docxReference = loader
 }
 
 // GenerateResume generates a resume from view data
@@ -45,11 +81,27 @@ func (r *ResumeService) GenerateResume(
 	config *GenerationConfig,
 	format string,
 ) ([]byte, error) {
+	return r.GenerateResumeWithProgress(ctx, provider, viewData, config, format, nil)
+}
+
+// GenerateResumeWithProgress generates a resume and reports each stage through progress (may be nil)
+func (r *ResumeService) GenerateResumeWithProgress(
+	ctx context.Context,
+	provider *AIProvider,
+	viewData *ViewData,
+	config *GenerationConfig,
+	format string,
+	progress func(percent int, message string),
+) ([]byte, error) {
+	if progress == nil {
+		progress = func(int, string) {}
+	}
 
 	// 1. Build the prompt
 	prompt := r.buildResumePrompt(viewData, config)
 
 	// 2. Call AI to generate markdown
+	progress(30, "Writing resume content with AI")
 	markdown, err := r.ai.ImproveContent(ctx, provider, prompt)
 	if err != nil {
 		log.Printf("[AI-PRINT] AI call failed: %v", err)
@@ -60,10 +112,11 @@ func (r *ResumeService) GenerateResume(
 	markdown = r.cleanMarkdown(markdown)
 
 	// 4. Convert to requested format
+	progress(80, fmt.Sprintf("Converting to %s", strings.ToUpper(format)))
 	var output []byte
 	switch format {
 	case "pdf":
-		output, err = r.convertToPDF(markdown)
+		output, err = r.convertToPDF(markdown, false)
 	case "docx":
 		output, err = r.convertToDOCX(markdown)
 	default:
@@ -78,6 +131,47 @@ func (r *ResumeService) GenerateResume(
 	return output, nil
 }
 
+// ResumeTemplateOutputs maps export formats to the template format they can be served from
+// without conversion; Markdown to PDF and DOCX uses the native renderers and everything else goes through Pandoc
+var ResumeTemplateOutputs = map[string]string{
+	"md":   "markdown",
+	"html": "html",
+}
+
+// TemplateNeedsPandoc reports whether rendering a template to format requires Pandoc
+func TemplateNeedsPandoc(tmpl ResumeTemplate, format string) bool {
+	if ResumeTemplateOutputs[format] == tmpl.Format {
+		return false
+	}
+	return !(tmpl.Format == "markdown" && (format == "pdf" || format == "docx"))
+}
+
+// GenerateFromTemplate renders view data through a template, without AI.
+// Output is reproducible: Markdown and HTML byte-for-byte, PDF and DOCX with pinned timestamps.
+func (r *ResumeService) GenerateFromTemplate(tmpl ResumeTemplate, viewData *ViewData, format string) ([]byte, error) {
+	rendered, err := RenderResumeTemplate(tmpl, viewData)
+	if err != nil {
+		return nil, err
+	}
+
+	if !TemplateNeedsPandoc(tmpl, format) {
+		switch format {
+		case "pdf":
+			return r.convertToPDF(rendered, true)
+		case "docx":
+			return r.convertToDOCX(rendered)
+		}
+		return []byte(rendered), nil
+	}
+
+	switch format {
+	case "pdf", "docx", "html", "md":
+		return r.runPandoc(rendered, tmpl.Format, format, true)
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
 // buildResumePrompt creates the AI prompt for resume generation
 func (r *ResumeService) buildResumePrompt(viewData *ViewData, config *GenerationConfig) string {
 	var sb strings.Builder
@@ -147,7 +241,11 @@ IMPORTANT WRITING STYLE RULES:
 			continue
 		}
 
-		sb.WriteString(fmt.Sprintf("=== %s ===\n", strings.ToUpper(sectionName)))
+		heading := sectionName
+		if title := viewData.SectionTitles[sectionName]; title != "" {
+			heading = title
+		}
+		sb.WriteString(fmt.Sprintf("=== %s ===\n", strings.ToUpper(heading)))
 		for _, item := range items {
 			r.writeItem(&sb, sectionName, item)
 		}
@@ -294,25 +392,48 @@ func (r *ResumeService) CheckPandocAvailable() bool {
 	return err == nil
 }
 
-// convertToPDF converts markdown to PDF using Pandoc
-func (r *ResumeService) convertToPDF(markdown string) ([]byte, error) {
-	return r.runPandoc(markdown, "pdf")
+// convertToPDF converts markdown to PDF with the selected engine. If Pandoc was selected
+// but fails (usually a missing LaTeX package), the native renderer is used instead.
+func (r *ResumeService) convertToPDF(markdown string, reproducible bool) ([]byte, error) {
+	if r.PDFEngine() == PDFEnginePandoc {
+		output, err := r.runPandoc(markdown, "markdown", "pdf", reproducible)
+		if err == nil {
+			return output, nil
+		}
+		log.Printf("[AI-PRINT] Pandoc PDF failed, using native renderer: %v", err)
+	}
+	return RenderMarkdownPDF(markdown, reproducible)
 }
 
-// convertToDOCX converts markdown to DOCX using Pandoc
+// convertToDOCX converts markdown to DOCX with the native writer, styled by the reference
+// document when one is uploaded. An unreadable reference falls back to the default styles.
 func (r *ResumeService) convertToDOCX(markdown string) ([]byte, error) {
-	return r.runPandoc(markdown, "docx")
+	var reference []byte
+	if r.docxReference != nil {
+		reference = r.docxReference()
+	}
+	output, err := RenderMarkdownDOCX(markdown, reference)
+	if err != nil && reference != nil {
+		log.Printf("[AI-PRINT] Reference DOCX unusable, using default styles: %v", err)
+		return RenderMarkdownDOCX(markdown, nil)
+	}
+	return output, err
 }
 
-// runPandoc executes Pandoc to convert markdown to the target format
-func (r *ResumeService) runPandoc(markdown string, format string) ([]byte, error) {
+// runPandoc executes Pandoc to convert markdown or HTML (from) to the target format.
+// reproducible pins document timestamps (SOURCE_DATE_EPOCH) so identical input gives identical output.
+func (r *ResumeService) runPandoc(markdown string, from string, format string, reproducible bool) ([]byte, error) {
 	// Check if Pandoc is available
 	if !r.CheckPandocAvailable() {
-		return nil, fmt.Errorf("Pandoc is not installed. PDF/DOCX generation requires Pandoc.")
+		return nil, fmt.Errorf("Pandoc is not installed. This conversion requires Pandoc.")
 	}
 
 	// Create temp input file
-	tmpIn, err := os.CreateTemp("", "resume-*.md")
+	inputExt := "md"
+	if from == "html" {
+		inputExt = "html"
+	}
+	tmpIn, err := os.CreateTemp("", "resume-*."+inputExt)
 	if err != nil {
 		log.Printf("[AI-PRINT] Failed to create temp input file: %v", err)
 		return nil, fmt.Errorf("failed to create temp file: %w", err)
@@ -340,6 +461,7 @@ func (r *ResumeService) runPandoc(markdown string, format string) ([]byte, error
 	// Build Pandoc command
 	args := []string{
 		tmpIn.Name(),
+		"-f", from,
 		"-o", tmpOut,
 		"-V", "geometry:margin=0.75in",
 		"-V", "fontsize=11pt",
@@ -356,7 +478,13 @@ func (r *ResumeService) runPandoc(markdown string, format string) ([]byte, error
 	}
 
 
+	var env []string
+	if reproducible {
+		env = append(os.Environ(), "SOURCE_DATE_EPOCH=0")
+	}
+
 	cmd := exec.Command("pandoc", args...)
+	cmd.Env = env
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
@@ -391,6 +519,7 @@ func (r *ResumeService) runPandoc(markdown string, format string) ([]byte, error
 			)
 
 			cmd = exec.Command("pandoc", args...)
+			cmd.Env = env
 			stderr.Reset()
 			cmd.Stderr = &stderr
 			if err := cmd.Run(); err != nil {
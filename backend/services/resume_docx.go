@@ -0,0 +1,395 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	_ "embed"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fumiama/go-docx"
+)
+
+// The native DOCX writer builds Word documents from parsed resume Markdown. Headings and
+// bullets use Word's built-in styles (Heading 1, List Bullet, ...) so ATS parsers see real
+// document structure, and an optional reference .docx supplies fonts and colours.
+
+//go:embed resume_docx/styles.xml
+var docxDefaultStyles string
+
+const (
+	docxPageWidth  = 12240 // US Letter, in twentieths of a point
+	docxPageHeight = 15840
+	docxMargin     = 1080 // 0.75in, matching the PDF renderer
+	docxLinkColor  = "1D4ED8"
+	docxMonoFont   = "Consolas"
+	maxDOCXPart    = 10 << 20
+)
+
+// Zip entries carry a fixed timestamp so identical input gives identical bytes
+var docxEpoch = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	docxStyleRe = regexp.MustCompile(`(?s)<w:style\s[^>]*w:styleId="([^"]+)".*?</w:style>`)
+	docxNumPrRe = regexp.MustCompile(`(?s)<w:numPr\s*/>|<w:numPr>.*?</w:numPr>`)
+)
+
+// docxListStyles always come from the built-in styles, since they point at the generated numbering
+var docxListStyles = map[string]bool{"ListBullet": true, "ListBullet2": true, "ListBullet3": true}
+
+// docxWriter appends parsed blocks to a go-docx document
+type docxWriter struct {
+	doc     *docx.Docx
+	ordered []docxOrderedList // list i uses numbering instance i+2
+	open    map[int]int       // nesting level -> numbering instance of the ordered list open there
+}
+
+// docxOrderedList is one ordered list, numbered from start
+type docxOrderedList struct {
+	level int
+	start int
+}
+
+// RenderMarkdownDOCX renders resume Markdown to a Word document without external tools.
+// reference is an optional .docx whose styles and theme replace the defaults. Output is
+// reproducible: identical input gives identical bytes.
+func RenderMarkdownDOCX(markdown string, reference []byte) ([]byte, error) {
+	styles := docxDefaultStyles
+	var theme []byte
+	if len(reference) > 0 {
+		ref, err := readReferenceDOCX(reference)
+		if err != nil {
+			return nil, err
+		}
+		styles = mergeDOCXStyles(ref.styles)
+		theme = ref.theme
+	}
+
+	w := &docxWriter{doc: docx.New(), open: map[int]int{}}
+	title := ""
+	for _, block := range parseMarkdownBlocks(markdown) {
+		if title == "" && block.kind == "heading" && block.level == 1 {
+			title = plainSpans(parseInline(block.text))
+		}
+		w.block(block)
+	}
+	w.doc.Document.Body.Items = append(w.doc.Document.Body.Items, &docx.SectPr{
+		PgSz:  &docx.PgSz{W: docxPageWidth, H: docxPageHeight},
+		PgMar: &docx.PgMar{Top: docxMargin, Left: docxMargin, Bottom: docxMargin, Right: docxMargin, Header: 720, Footer: 720},
+	})
+
+	return w.pack(title, styles, theme)
+}
+
+// ValidateReferenceDOCX checks that data is a Word document with a styles part
+func ValidateReferenceDOCX(data []byte) error {
+	_, err := readReferenceDOCX(data)
+	return err
+}
+
+func (w *docxWriter) block(block markdownBlock) {
+	if block.kind != "item" {
+		w.closeLists(0)
+	}
+
+	p := w.doc.AddParagraph()
+	switch block.kind {
+	case "heading":
+		p.Style("Heading" + strconv.Itoa(min(block.level, 4)))
+		w.inline(p, block.text)
+	case "item":
+		level := min(block.level, 2)
+		w.closeLists(level + 1)
+		if block.ordinal == "" {
+			w.closeLists(level)
+			style := "ListBullet"
+			if level > 0 {
+				style += strconv.Itoa(level + 1)
+			}
+			p.Style(style)
+		} else {
+			// Each ordered list gets its own numbering instance so it restarts at its first number.
+			// Only numId is set: go-docx writes numPr children out of schema order.
+			numID, ok := w.open[level]
+			if !ok {
+				start, _ := strconv.Atoi(strings.TrimSuffix(block.ordinal, "."))
+				w.ordered = append(w.ordered, docxOrderedList{level: level, start: max(start, 1)})
+				numID = len(w.ordered) + 1
+				w.open[level] = numID
+			}
+			p.Properties = &docx.ParagraphProperties{
+				NumProperties: &docx.NumProperties{NumID: &docx.NumID{Val: strconv.Itoa(numID)}},
+			}
+		}
+		w.inline(p, block.text)
+	case "code":
+		p.Style("SourceCode")
+		run := &docx.Run{}
+		for i, line := range strings.Split(block.text, "\n") {
+			if i > 0 {
+				run.Children = append(run.Children, &docx.BarterRabbet{})
+			}
+			run.Children = append(run.Children, &docx.Text{Text: line, XMLSpace: "preserve"})
+		}
+		p.Children = append(p.Children, run)
+	case "rule":
+		p.Style("HorizontalRule")
+	case "pagebreak":
+		p.AddPageBreaks()
+	default:
+		w.inline(p, block.text)
+	}
+}
+
+// closeLists ends the ordered lists open at nesting levels from and deeper
+func (w *docxWriter) closeLists(from int) {
+	for level := range w.open {
+		if level >= from {
+			delete(w.open, level)
+		}
+	}
+}
+
+// inline appends styled runs for a line of Markdown. Plain links use the Hyperlink character
+// style; emphasised links are formatted directly since go-docx cannot order rStyle with b/i.
+func (w *docxWriter) inline(p *docx.Paragraph, text string) {
+	for _, span := range parseInline(text) {
+		props := &docx.RunProperties{}
+		if span.code {
+			props.Fonts = &docx.RunFonts{ASCII: docxMonoFont, HAnsi: docxMonoFont}
+		}
+		if span.bold {
+			props.Bold = &docx.Bold{}
+		}
+		if span.italic {
+			props.Italic = &docx.Italic{}
+		}
+		run := docx.Run{
+			RunProperties: props,
+			Children:      []interface{}{&docx.Text{Text: span.text, XMLSpace: "preserve"}},
+		}
+
+		if span.link == "" {
+			if *props == (docx.RunProperties{}) {
+				run.RunProperties = nil
+			}
+			p.Children = append(p.Children, &run)
+			continue
+		}
+
+		if *props == (docx.RunProperties{}) {
+			props.RunStyle = &docx.RunStyle{Val: "Hyperlink"}
+		} else {
+			props.Color = &docx.Color{Val: docxLinkColor}
+			props.Underline = &docx.Underline{Val: "single"}
+		}
+		link := p.AddLink(span.text, span.link)
+		link.Run = run
+	}
+}
+
+// docxRelationships is the document part's relationships file
+type docxRelationships struct {
+	XMLName       xml.Name            `xml:"Relationships"`
+	Xmlns         string              `xml:"xmlns,attr"`
+	Relationships []docx.Relationship `xml:"Relationship"`
+}
+
+// pack writes the document parts into a .docx archive in a fixed order
+func (w *docxWriter) pack(title, styles string, theme []byte) ([]byte, error) {
+	document, err := xml.Marshal(&w.doc.Document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document: %w", err)
+	}
+
+	// Keep go-docx's hyperlink relationships (rId4 onwards); rId1-3 are the parts written here
+	rels := docxRelationships{Xmlns: docx.XMLNS_REL}
+	w.doc.RangeRelationships(func(rel *docx.Relationship) error {
+		if rel.Type == docx.REL_HYPERLINK {
+			rels.Relationships = append(rels.Relationships, *rel)
+		}
+		return nil
+	})
+	rels.Relationships = append(rels.Relationships,
+		docx.Relationship{ID: "rId1", Type: docxRelStyles, Target: "styles.xml"},
+		docx.Relationship{ID: "rId2", Type: docxRelNumbering, Target: "numbering.xml"},
+	)
+	contentTypes := docxContentTypes
+	if theme != nil {
+		rels.Relationships = append(rels.Relationships, docx.Relationship{ID: "rId3", Type: docxRelTheme, Target: "theme/theme1.xml"})
+		contentTypes = strings.Replace(contentTypes, "</Types>", docxThemeContentType+"</Types>", 1)
+	}
+	documentRels, err := xml.Marshal(&rels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode relationships: %w", err)
+	}
+
+	parts := []struct {
+		name string
+		data []byte
+	}{
+		{"[Content_Types].xml", []byte(contentTypes)},
+		{"_rels/.rels", []byte(docxPackageRels)},
+		{"docProps/app.xml", []byte(docxAppProps)},
+		{"docProps/core.xml", []byte(fmt.Sprintf(docxCoreProps, xmlEscape(title), xmlEscape(title)))},
+		{"word/_rels/document.xml.rels", append([]byte(xml.Header), documentRels...)},
+		{"word/document.xml", append([]byte(xml.Header), document...)},
+		{"word/numbering.xml", []byte(docxNumbering(w.ordered))},
+		{"word/styles.xml", []byte(styles)},
+	}
+	if theme != nil {
+		parts = append(parts, struct {
+			name string
+			data []byte
+		}{"word/theme/theme1.xml", theme})
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, part := range parts {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: part.name, Method: zip.Deflate, Modified: docxEpoch})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Write(part.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// docxNumbering builds the numbering part: instance 1 is the bullet list used by the List
+// Bullet styles, and each ordered list gets an instance of its level's format starting at its first number.
+func docxNumbering(ordered []docxOrderedList) string {
+	bullets := []string{"•", "◦", "▪"}
+	formats := []string{"decimal", "lowerLetter", "lowerRoman"}
+
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`)
+	sb.WriteString(`<w:abstractNum w:abstractNumId="0"><w:multiLevelType w:val="hybridMultilevel"/>`)
+	for i, bullet := range bullets {
+		fmt.Fprintf(&sb, `<w:lvl w:ilvl="%d"><w:start w:val="1"/><w:numFmt w:val="bullet"/><w:lvlText w:val="%s"/><w:lvlJc w:val="left"/><w:pPr><w:ind w:left="%d" w:hanging="270"/></w:pPr></w:lvl>`, i, bullet, 360*(i+1))
+	}
+	sb.WriteString(`</w:abstractNum>`)
+	for i, format := range formats {
+		fmt.Fprintf(&sb, `<w:abstractNum w:abstractNumId="%d"><w:multiLevelType w:val="singleLevel"/>`, i+1)
+		fmt.Fprintf(&sb, `<w:lvl w:ilvl="0"><w:start w:val="1"/><w:numFmt w:val="%s"/><w:lvlText w:val="%%1."/><w:lvlJc w:val="left"/><w:pPr><w:ind w:left="%d" w:hanging="360"/></w:pPr></w:lvl>`, format, 360*(i+1))
+		sb.WriteString(`</w:abstractNum>`)
+	}
+	sb.WriteString(`<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num>`)
+	for i, list := range ordered {
+		fmt.Fprintf(&sb, `<w:num w:numId="%d"><w:abstractNumId w:val="%d"/><w:lvlOverride w:ilvl="0"><w:startOverride w:val="%d"/></w:lvlOverride></w:num>`, i+2, list.level+1, list.start)
+	}
+	sb.WriteString(`</w:numbering>`)
+	return sb.String()
+}
+
+// docxReference holds the parts taken from an uploaded reference document
+type docxReference struct {
+	styles string
+	theme  []byte
+}
+
+func readReferenceDOCX(data []byte) (*docxReference, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("reference document is not a valid .docx file")
+	}
+
+	ref := &docxReference{}
+	for _, f := range zr.File {
+		if f.Name != "word/styles.xml" && f.Name != "word/theme/theme1.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("reference document is not a valid .docx file")
+		}
+		part, err := io.ReadAll(io.LimitReader(rc, maxDOCXPart))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reference document is not a valid .docx file")
+		}
+		if f.Name == "word/styles.xml" {
+			ref.styles = string(part)
+		} else {
+			ref.theme = part
+		}
+	}
+
+	if !strings.Contains(ref.styles, "</w:styles>") {
+		return nil, fmt.Errorf("reference document has no Word styles")
+	}
+	return ref, nil
+}
+
+// mergeDOCXStyles adapts a reference styles part: numbering is stripped (it points at the
+// reference's own numbering part), and any style the writer uses but the reference lacks is
+// added from the defaults.
+func mergeDOCXStyles(reference string) string {
+	styles := docxStyleRe.ReplaceAllStringFunc(reference, func(style string) string {
+		if docxListStyles[docxStyleRe.FindStringSubmatch(style)[1]] {
+			return ""
+		}
+		return style
+	})
+	styles = docxNumPrRe.ReplaceAllString(styles, "")
+
+	present := map[string]bool{}
+	for _, m := range docxStyleRe.FindAllStringSubmatch(styles, -1) {
+		present[m[1]] = true
+	}
+	var missing strings.Builder
+	for _, m := range docxStyleRe.FindAllStringSubmatch(docxDefaultStyles, -1) {
+		if !present[m[1]] {
+			missing.WriteString(m[0])
+		}
+	}
+
+	end := strings.LastIndex(styles, "</w:styles>")
+	return styles[:end] + missing.String() + styles[end:]
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+const (
+	docxRelStyles    = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles"
+	docxRelNumbering = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering"
+	docxRelTheme     = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/theme"
+)
+
+const docxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+	`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+	`<Override PartName="/word/numbering.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"/>` +
+	`<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>` +
+	`<Override PartName="/docProps/app.xml" ContentType="application/vnd.openxmlformats-officedocument.extended-properties+xml"/>` +
+	`</Types>`
+
+const docxThemeContentType = `<Override PartName="/word/theme/theme1.xml" ContentType="application/vnd.openxmlformats-officedocument.theme+xml"/>`
+
+const docxPackageRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>` +
+	`<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/extended-properties" Target="docProps/app.xml"/>` +
+	`</Relationships>`
+
+const docxAppProps = xml.Header + `<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties"><Application>Facet</Application></Properties>`
+
+const docxCoreProps = xml.Header + `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/">` +
+	`<dc:title>%s</dc:title><dc:creator>%s</dc:creator></cp:coreProperties>`
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/fumiama/go-docx"
+)
+
+// docxParts unzips a generated document into part name -> contents, checking each XML part is well formed
+func docxParts(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("output is not a zip archive: %v", err)
+	}
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		body, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(body)
+
+		d := xml.NewDecoder(bytes.NewReader(body))
+		for {
+			if _, err := d.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s is not well-formed XML: %v", f.Name, err)
+			}
+		}
+	}
+	return parts
+}
+
+func TestRenderMarkdownDOCX(t *testing.T) {
+	first, err := RenderMarkdownDOCX(resumeTestMarkdown, nil)
+	if err != nil {
+		t.Fatalf("RenderMarkdownDOCX() error = %v", err)
+	}
+	parts := docxParts(t, first)
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "word/document.xml", "word/styles.xml", "word/numbering.xml", "word/_rels/document.xml.rels"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("missing part %s", name)
+		}
+	}
+
+	document := parts["word/document.xml"]
+	for _, want := range []string{
+		`<w:pStyle w:val="Heading1"></w:pStyle></w:pPr><w:r><w:t xml:space="preserve">Ada Lovelace</w:t>`,
+		`<w:pStyle w:val="Heading2"></w:pStyle></w:pPr><w:r><w:t xml:space="preserve">Experience</w:t>`,
+		`<w:pStyle w:val="ListBullet"></w:pStyle>`,
+		`<w:pStyle w:val="ListBullet2"></w:pStyle>`,
+		`<w:rStyle w:val="Hyperlink"></w:rStyle>`,
+		`<w:br w:type="page"></w:br>`,
+		`<w:numId w:val="2"></w:numId>`,
+	} {
+		if !strings.Contains(document, want) {
+			t.Errorf("document.xml missing %s", want)
+		}
+	}
+	if !strings.Contains(parts["word/_rels/document.xml.rels"], `Target="mailto:ada@example.com" TargetMode="External"`) {
+		t.Error("hyperlink relationship missing")
+	}
+	if !strings.Contains(parts["docProps/core.xml"], "<dc:title>Ada Lovelace</dc:title>") {
+		t.Error("document title not set from the first heading")
+	}
+
+	second, _ := RenderMarkdownDOCX(resumeTestMarkdown, nil)
+	if !bytes.Equal(first, second) {
+		t.Error("output differs between renders")
+	}
+
+	// The result opens with go-docx itself
+	parsed, err := docx.Parse(bytes.NewReader(first), int64(len(first)))
+	if err != nil {
+		t.Fatalf("docx.Parse() error = %v", err)
+	}
+	if len(parsed.Document.Body.Items) == 0 {
+		t.Error("parsed document has no body")
+	}
+}
+
+func TestDOCXOrderedListsRestart(t *testing.T) {
+	output, err := RenderMarkdownDOCX("1. One\n2. Two\n   1. Nested\n\nBreak\n\n3. Three\n", nil)
+	if err != nil {
+		t.Fatalf("RenderMarkdownDOCX() error = %v", err)
+	}
+	numbering := docxParts(t, output)["word/numbering.xml"]
+	for _, want := range []string{
+		`<w:num w:numId="2"><w:abstractNumId w:val="1"/><w:lvlOverride w:ilvl="0"><w:startOverride w:val="1"/>`,
+		`<w:num w:numId="3"><w:abstractNumId w:val="2"/>`,
+		`<w:num w:numId="4"><w:abstractNumId w:val="1"/><w:lvlOverride w:ilvl="0"><w:startOverride w:val="3"/>`,
+	} {
+		if !strings.Contains(numbering, want) {
+			t.Errorf("numbering.xml missing %s", want)
+		}
+	}
+}
+
+func TestRenderMarkdownDOCXWithReference(t *testing.T) {
+	referenceStyles := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Georgia" w:hAnsi="Georgia"/></w:rPr></w:rPrDefault></w:docDefaults>
+<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:pPr><w:numPr><w:numId w:val="7"/></w:numPr></w:pPr><w:rPr><w:color w:val="8B0000"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="ListBullet"><w:name w:val="List Bullet"/><w:pPr><w:numPr><w:numId w:val="9"/></w:numPr></w:pPr></w:style>
+</w:styles>`
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range map[string]string{"word/styles.xml": referenceStyles, "word/theme/theme1.xml": `<a:theme xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" name="Ref"/>`} {
+		f, _ := zw.Create(name)
+		f.Write([]byte(body))
+	}
+	zw.Close()
+	reference := buf.Bytes()
+
+	if err := ValidateReferenceDOCX(reference); err != nil {
+		t.Fatalf("ValidateReferenceDOCX() error = %v", err)
+	}
+	if err := ValidateReferenceDOCX([]byte("not a zip")); err == nil {
+		t.Error("expected an error for a non-docx reference")
+	}
+
+	output, err := RenderMarkdownDOCX(resumeTestMarkdown, reference)
+	if err != nil {
+		t.Fatalf("RenderMarkdownDOCX() error = %v", err)
+	}
+	parts := docxParts(t, output)
+	styles := parts["word/styles.xml"]
+
+	if !strings.Contains(styles, "Georgia") || !strings.Contains(styles, `<w:color w:val="8B0000"/>`) {
+		t.Error("reference fonts and colours were not kept")
+	}
+	if strings.Contains(styles, `<w:numId w:val="7"/>`) || strings.Contains(styles, `<w:numId w:val="9"/>`) {
+		t.Error("reference numbering was not stripped")
+	}
+	if strings.Count(styles, `w:styleId="ListBullet"`) != 1 || !strings.Contains(styles, `w:styleId="Heading2"`) {
+		t.Error("built-in list and missing heading styles were not added")
+	}
+	if strings.Count(styles, `w:styleId="Heading1"`) != 1 {
+		t.Error("reference heading style was duplicated")
+	}
+	if _, ok := parts["word/theme/theme1.xml"]; !ok || !strings.Contains(parts["[Content_Types].xml"], "/word/theme/theme1.xml") {
+		t.Error("reference theme was not included")
+	}
+}
+
+func TestTemplateDOCXDoesNotNeedPandoc(t *testing.T) {
+	tmpl, _ := BuiltinResumeTemplate("chronological")
+	if TemplateNeedsPandoc(tmpl, "docx") {
+		t.Error("Markdown templates should render DOCX natively")
+	}
+
+	r := NewResumeService(nil)
+	r.SetDOCXReference(func() []byte { return []byte("corrupt") })
+	output, err := r.GenerateFromTemplate(tmpl, templateTestViewData(), "docx")
+	if err != nil {
+		t.Fatalf("GenerateFromTemplate(docx) error = %v", err)
+	}
+	again, _ := r.GenerateFromTemplate(tmpl, templateTestViewData(), "docx")
+	if !bytes.Equal(output, again) {
+		t.Error("template DOCX is not reproducible")
+	}
+	if !strings.Contains(docxParts(t, output)["word/document.xml"], "Analytical Engines") {
+		t.Error("template content missing from DOCX")
+	}
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+)
+
+// The native PDF and DOCX renderers share this parser for the Markdown subset resumes use:
+// headings, lists, paragraphs, bold/italic, inline code, links, rules and \newpage.
+
+// markdownBlock is one block-level element of the parsed Markdown
+type markdownBlock struct {
+	kind    string // heading, paragraph, item, rule, code, pagebreak
+	level   int    // heading level or list nesting depth
+	ordinal string // "1." for ordered list items; empty for bullets
+	text    string
+}
+
+var (
+	mdHeadingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdBulletRe  = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	mdOrderedRe = regexp.MustCompile(`^(\s*)(\d+)[.)]\s+(.*)$`)
+	mdRuleRe    = regexp.MustCompile(`^\s*(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+)
+
+// parseMarkdownBlocks splits Markdown into blocks. Anything outside the supported subset
+// is kept as paragraph text rather than dropped.
+func parseMarkdownBlocks(markdown string) []markdownBlock {
+	var blocks []markdownBlock
+	var paragraph []string
+	var code []string
+	inCode := false
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, markdownBlock{kind: "paragraph", text: strings.Join(paragraph, " ")})
+			paragraph = nil
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			if inCode {
+				blocks = append(blocks, markdownBlock{kind: "code", text: strings.Join(code, "\n")})
+				code = nil
+			} else {
+				flush()
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			code = append(code, lines[i])
+			continue
+		}
+
+		// Setext headings: a line of text underlined with === or ---
+		if len(paragraph) == 1 && trimmed != "" && i > 0 {
+			if strings.Trim(trimmed, "=") == "" {
+				blocks = append(blocks, markdownBlock{kind: "heading", level: 1, text: paragraph[0]})
+				paragraph = nil
+				continue
+			}
+			if strings.Trim(trimmed, "-") == "" {
+				blocks = append(blocks, markdownBlock{kind: "heading", level: 2, text: paragraph[0]})
+				paragraph = nil
+				continue
+			}
+		}
+
+		switch {
+		case trimmed == "":
+			flush()
+		case trimmed == `\newpage` || trimmed == `\pagebreak`:
+			flush()
+			blocks = append(blocks, markdownBlock{kind: "pagebreak"})
+		case mdRuleRe.MatchString(line):
+			flush()
+			blocks = append(blocks, markdownBlock{kind: "rule"})
+		case mdHeadingRe.MatchString(trimmed):
+			flush()
+			m := mdHeadingRe.FindStringSubmatch(trimmed)
+			blocks = append(blocks, markdownBlock{kind: "heading", level: len(m[1]), text: m[2]})
+		case mdBulletRe.MatchString(line):
+			flush()
+			m := mdBulletRe.FindStringSubmatch(line)
+			blocks = append(blocks, markdownBlock{kind: "item", level: listDepth(m[1]), text: m[2]})
+		case mdOrderedRe.MatchString(line):
+			flush()
+			m := mdOrderedRe.FindStringSubmatch(line)
+			blocks = append(blocks, markdownBlock{kind: "item", level: listDepth(m[1]), ordinal: m[2] + ".", text: m[3]})
+		case strings.HasPrefix(line, " ") && len(paragraph) == 0 && len(blocks) > 0 && blocks[len(blocks)-1].kind == "item":
+			// Continuation line of a list item
+			blocks[len(blocks)-1].text += " " + trimmed
+		default:
+			paragraph = append(paragraph, strings.TrimPrefix(trimmed, "> "))
+		}
+	}
+	if inCode {
+		blocks = append(blocks, markdownBlock{kind: "code", text: strings.Join(code, "\n")})
+	}
+	flush()
+
+	return blocks
+}
+
+// listDepth converts leading indentation to a nesting level (two spaces or a tab per level)
+func listDepth(indent string) int {
+	width := 0
+	for _, r := range indent {
+		if r == '\t' {
+			width += 4
+		} else {
+			width++
+		}
+	}
+	return width / 2
+}
+
+// markdownSpan is a run of inline text with a single style
+type markdownSpan struct {
+	text   string
+	bold   bool
+	italic bool
+	code   bool
+	link   string
+}
+
+var mdAutoLinkRe = regexp.MustCompile(`^<(https?://[^>\s]+|mailto:[^>\s]+)>`)
+
+// parseInline splits a line of Markdown into styled spans. Emphasis markers without a
+// matching closer are printed literally.
+func parseInline(text string) []markdownSpan {
+	var spans []markdownSpan
+	var current strings.Builder
+	bold, italic := false, false
+
+	emit := func() {
+		if current.Len() > 0 {
+			spans = append(spans, markdownSpan{text: current.String(), bold: bold, italic: italic})
+			current.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && strings.ContainsRune("\\`*_[]()#+-.!<>", rune(rest[1])):
+			current.WriteByte(rest[1])
+			i += 2
+		case rest[0] == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				emit()
+				spans = append(spans, markdownSpan{text: rest[1 : end+1], code: true})
+				i += end + 2
+				continue
+			}
+			current.WriteByte('`')
+			i++
+		case rest[0] == '[':
+			if label, url, n, ok := parseInlineLink(rest); ok {
+				emit()
+				for _, span := range parseInline(label) {
+					span.bold = span.bold || bold
+					span.italic = span.italic || italic
+					span.link = url
+					spans = append(spans, span)
+				}
+				i += n
+				continue
+			}
+			current.WriteByte('[')
+			i++
+		case rest[0] == '<' && mdAutoLinkRe.MatchString(rest):
+			m := mdAutoLinkRe.FindStringSubmatch(rest)
+			emit()
+			spans = append(spans, markdownSpan{text: strings.TrimPrefix(m[1], "mailto:"), bold: bold, italic: italic, link: m[1]})
+			i += len(m[0])
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			marker := rest[:2]
+			if bold || strings.Contains(rest[2:], marker) {
+				emit()
+				bold = !bold
+				i += 2
+				continue
+			}
+			current.WriteString(marker)
+			i += 2
+		case rest[0] == '*' || (rest[0] == '_' && !intraword(text, i)):
+			marker := rest[:1]
+			if italic || strings.Contains(rest[1:], marker) {
+				emit()
+				italic = !italic
+				i++
+				continue
+			}
+			current.WriteString(marker)
+			i++
+		default:
+			current.WriteByte(rest[0])
+			i++
+		}
+	}
+	emit()
+
+	return spans
+}
+
+// parseInlineLink parses [label](url) at the start of s
+func parseInlineLink(s string) (label, url string, n int, ok bool) {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				if i+1 >= len(s) || s[i+1] != '(' {
+					return "", "", 0, false
+				}
+				end := strings.IndexByte(s[i+2:], ')')
+				if end < 0 {
+					return "", "", 0, false
+				}
+				target := strings.Fields(s[i+2 : i+2+end])
+				if len(target) == 0 {
+					return "", "", 0, false
+				}
+				return s[1:i], target[0], i + 3 + end, true
+			}
+		}
+	}
+	return "", "", 0, false
+}
+
+// intraword reports whether the underscore at i sits inside a word (snake_case), where it is literal
+func intraword(s string, i int) bool {
+	isWord := func(b byte) bool {
+		return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+	}
+	return i > 0 && i+1 < len(s) && isWord(s[i-1]) && isWord(s[i+1])
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+const resumeTestMarkdown = `# Ada Lovelace
+
+**Analytical Engine Programmer** · London · [ada@example.com](mailto:ada@example.com)
+
+## Experience
+
+### Programmer — Analytical Engines
+
+*Jan 1842 – Present*
+
+Wrote the first published algorithm, see <https://example.com/note-g>.
+
+- Translated Menabrea's article
+  and tripled its length with notes
+  - Nested detail with ` + "`code`" + `
+1. First
+2. Second
+
+---
+
+\newpage
+
+## Skills
+
+- **Languages:** Go, Rust
+`
+
+func TestParseMarkdownBlocks(t *testing.T) {
+	blocks := parseMarkdownBlocks(resumeTestMarkdown)
+
+	var kinds []string
+	for _, b := range blocks {
+		kinds = append(kinds, b.kind)
+	}
+	want := "heading paragraph heading heading paragraph paragraph item item item item rule pagebreak heading item"
+	if got := strings.Join(kinds, " "); got != want {
+		t.Fatalf("block kinds =\n%s\nwant\n%s", got, want)
+	}
+
+	if blocks[2].level != 2 || blocks[2].text != "Experience" {
+		t.Errorf("heading = %+v", blocks[2])
+	}
+	if blocks[6].text != "Translated Menabrea's article and tripled its length with notes" {
+		t.Errorf("list continuation not joined: %q", blocks[6].text)
+	}
+	if blocks[7].level != 1 {
+		t.Errorf("nested item level = %d, want 1", blocks[7].level)
+	}
+	if blocks[9].ordinal != "2." {
+		t.Errorf("ordered item ordinal = %q", blocks[9].ordinal)
+	}
+}
+
+func TestParseInline(t *testing.T) {
+	spans := parseInline("Built **fast _and_ safe** tools in [Go](https://go.dev), snake_case and 2 * 3 stay literal")
+
+	var got []string
+	for _, s := range spans {
+		style := ""
+		if s.bold {
+			style += "B"
+		}
+		if s.italic {
+			style += "I"
+		}
+		if s.link != "" {
+			style += "->" + s.link
+		}
+		got = append(got, s.text+"|"+style)
+	}
+	want := []string{
+		"Built |",
+		"fast |B",
+		"and|BI",
+		" safe|B",
+		" tools in |",
+		"Go|->https://go.dev",
+		", snake_case and 2 * 3 stay literal|",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("spans =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -34,6 +34,8 @@ type ParsedResume struct {
 	Projects       []ProjectData       `json:"projects"`
 	Awards         []AwardData         `json:"awards"`
 	Talks          []TalkData          `json:"talks"`
+	Publications   []PublicationData   `json:"publications,omitempty"`
+	Testimonials   []TestimonialData   `json:"testimonials,omitempty"`
 	Metadata       MetadataData        `json:"metadata"`
 }
 
@@ -103,6 +105,25 @@ type TalkData struct {
 	Description string `json:"description"`
 }
 
+// PublicationData is a published article, paper or book (imported as a post)
+type PublicationData struct {
+	Title       string `json:"title"`
+	Publisher   string `json:"publisher"`
+	ReleaseDate string `json:"release_date"`
+	URL         string `json:"url"`
+	Summary     string `json:"summary"`
+}
+
+// TestimonialData is a recommendation written about the profile owner
+type TestimonialData struct {
+	AuthorName    string `json:"author_name"`
+	AuthorTitle   string `json:"author_title"`
+	AuthorCompany string `json:"author_company"`
+	Content       string `json:"content"`
+	Date          string `json:"date"`
+	Source        string `json:"source"` // verification method, e.g. "linkedin"
+}
+
 type MetadataData struct {
 	Confidence string   `json:"confidence"` // high, medium, low
 	Warnings   []string `json:"warnings"`
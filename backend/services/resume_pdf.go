@@ -0,0 +1,211 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// The native PDF renderer lays out parsed resume Markdown with embedded Go fonts,
+// so PDF export works without Pandoc or a TeX installation.
+
+const (
+	pdfMargin       = 19.05 // 0.75in, matching the Pandoc geometry
+	pdfBodySize     = 10.5
+	pdfLineHeight   = 1.35 // line height as a multiple of the font size
+	pdfListIndent   = 5.0
+	pdfParagraphGap = 2.2
+	ptToMM          = 25.4 / 72
+)
+
+var pdfHeadingSizes = map[int]float64{1: 20, 2: 13.5, 3: 11.5}
+
+// pdfRenderer writes parsed blocks to an fpdf document
+type pdfRenderer struct {
+	pdf *fpdf.Fpdf
+}
+
+// RenderMarkdownPDF renders resume Markdown to a PDF without external tools.
+// With reproducible set, document dates are pinned so identical input gives identical bytes.
+func RenderMarkdownPDF(markdown string, reproducible bool) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "Letter", "")
+	// fpdf edits font data in place while subsetting, so every document gets its own copy
+	pdf.AddUTF8FontFromBytes("go", "", bytes.Clone(goregular.TTF))
+	pdf.AddUTF8FontFromBytes("go", "B", bytes.Clone(gobold.TTF))
+	pdf.AddUTF8FontFromBytes("go", "I", bytes.Clone(goitalic.TTF))
+	pdf.AddUTF8FontFromBytes("go", "BI", bytes.Clone(gobolditalic.TTF))
+	pdf.AddUTF8FontFromBytes("gomono", "", bytes.Clone(gomono.TTF))
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.SetCreator("Facet", true)
+	pdf.SetProducer("Facet", true)
+	if reproducible {
+		pdf.SetCatalogSort(true)
+		pdf.SetCreationDate(time.Unix(0, 0).UTC())
+		pdf.SetModificationDate(time.Unix(0, 0).UTC())
+	}
+
+	blocks := parseMarkdownBlocks(markdown)
+	for _, block := range blocks {
+		if block.kind == "heading" && block.level == 1 {
+			pdf.SetTitle(plainSpans(parseInline(block.text)), true)
+			break
+		}
+	}
+
+	pdf.AddPage()
+	r := &pdfRenderer{pdf: pdf}
+	for i, block := range blocks {
+		var next *markdownBlock
+		if i+1 < len(blocks) {
+			next = &blocks[i+1]
+		}
+		r.block(block, next)
+	}
+
+	if err := pdf.Error(); err != nil {
+		return nil, fmt.Errorf("PDF rendering failed: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("PDF rendering failed: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (r *pdfRenderer) block(block markdownBlock, next *markdownBlock) {
+	pdf := r.pdf
+	left, _, _, _ := pdf.GetMargins()
+
+	switch block.kind {
+	case "heading":
+		size, ok := pdfHeadingSizes[block.level]
+		if !ok {
+			size = pdfBodySize
+		}
+		// Keep a heading with at least the first lines of what follows it
+		r.keepSpace(size*ptToMM*pdfLineHeight + 3*pdfBodySize*ptToMM*pdfLineHeight)
+		if pdf.GetY() > pdfMargin+0.1 {
+			pdf.Ln(size * ptToMM * 0.6)
+		}
+		r.write(parseInline(block.text), size, true)
+		pdf.Ln(size * ptToMM * pdfLineHeight)
+		if block.level == 2 {
+			r.rule(0.2)
+		}
+		pdf.Ln(pdfParagraphGap / 2)
+
+	case "paragraph":
+		r.write(parseInline(block.text), pdfBodySize, false)
+		pdf.Ln(pdfBodySize*ptToMM*pdfLineHeight + pdfParagraphGap)
+
+	case "item":
+		indent := left + pdfListIndent*float64(block.level+1)
+		marker := "•"
+		if block.level%2 == 1 {
+			marker = "◦"
+		}
+		if block.ordinal != "" {
+			marker = block.ordinal
+		}
+		lineHeight := pdfBodySize * ptToMM * pdfLineHeight
+		r.keepSpace(lineHeight)
+		pdf.SetFont("go", "", pdfBodySize)
+		markerWidth := pdf.GetStringWidth(marker)
+		pdf.SetX(indent - markerWidth - 1.5)
+		pdf.CellFormat(markerWidth+1.5, lineHeight, marker, "", 0, "L", false, 0, "")
+		pdf.SetLeftMargin(indent)
+		r.write(parseInline(block.text), pdfBodySize, false)
+		pdf.SetLeftMargin(left)
+		pdf.Ln(lineHeight)
+		if next == nil || next.kind != "item" {
+			pdf.Ln(pdfParagraphGap)
+		}
+
+	case "code":
+		pdf.SetFont("gomono", "", pdfBodySize-1)
+		pdf.SetTextColor(60, 60, 60)
+		for _, line := range strings.Split(block.text, "\n") {
+			pdf.Write((pdfBodySize-1)*ptToMM*pdfLineHeight, line)
+			pdf.Ln((pdfBodySize - 1) * ptToMM * pdfLineHeight)
+		}
+		pdf.SetTextColor(0, 0, 0)
+		pdf.Ln(pdfParagraphGap)
+
+	case "rule":
+		pdf.Ln(pdfParagraphGap)
+		r.rule(0.3)
+		pdf.Ln(pdfParagraphGap)
+
+	case "pagebreak":
+		pdf.AddPage()
+	}
+}
+
+// write lays out styled spans as flowing text that wraps at the margins
+func (r *pdfRenderer) write(spans []markdownSpan, size float64, heading bool) {
+	pdf := r.pdf
+	lineHeight := size * ptToMM * pdfLineHeight
+
+	for _, span := range spans {
+		style := ""
+		if span.bold || heading {
+			style += "B"
+		}
+		if span.italic {
+			style += "I"
+		}
+		family := "go"
+		if span.code {
+			family, style = "gomono", ""
+		}
+
+		if span.link != "" {
+			pdf.SetTextColor(30, 64, 175)
+			pdf.SetFont(family, style+"U", size)
+			pdf.WriteLinkString(lineHeight, span.text, span.link)
+			pdf.SetTextColor(0, 0, 0)
+			continue
+		}
+		pdf.SetFont(family, style, size)
+		pdf.Write(lineHeight, span.text)
+	}
+}
+
+// rule draws a thin horizontal line across the text width
+func (r *pdfRenderer) rule(width float64) {
+	pdf := r.pdf
+	pageWidth, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	y := pdf.GetY()
+	pdf.SetDrawColor(160, 160, 160)
+	pdf.SetLineWidth(width)
+	pdf.Line(left, y, pageWidth-right, y)
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.Ln(1)
+}
+
+// keepSpace starts a new page if less than height remains on the current one
+func (r *pdfRenderer) keepSpace(height float64) {
+	_, pageHeight := r.pdf.GetPageSize()
+	if r.pdf.GetY()+height > pageHeight-pdfMargin {
+		r.pdf.AddPage()
+	}
+}
+
+func plainSpans(spans []markdownSpan) string {
+	var sb strings.Builder
+	for _, span := range spans {
+		sb.WriteString(span.text)
+	}
+	return sb.String()
+}
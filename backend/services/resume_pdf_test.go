@@ -0,0 +1,58 @@
+package services
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdownPDF(t *testing.T) {
+	first, err := RenderMarkdownPDF(resumeTestMarkdown, true)
+	if err != nil {
+		t.Fatalf("RenderMarkdownPDF() error = %v", err)
+	}
+	if !bytes.HasPrefix(first, []byte("%PDF-")) {
+		t.Fatalf("output is not a PDF: %q", first[:16])
+	}
+	if !bytes.Contains(first, []byte("/FontFile2")) {
+		t.Error("fonts are not embedded")
+	}
+	if n := bytes.Count(first, []byte("/Type /Page\n")); n != 2 {
+		t.Errorf("page count = %d, want 2 (explicit page break)", n)
+	}
+
+	second, _ := RenderMarkdownPDF(resumeTestMarkdown, true)
+	if !bytes.Equal(first, second) {
+		t.Error("reproducible output differs between renders")
+	}
+
+	// Long documents flow onto further pages
+	long := "# Long\n\n" + strings.Repeat("- A bullet point that is long enough to need wrapping across the width of a page.\n", 200)
+	output, err := RenderMarkdownPDF(long, true)
+	if err != nil {
+		t.Fatalf("RenderMarkdownPDF(long) error = %v", err)
+	}
+	if n := bytes.Count(output, []byte("/Type /Page\n")); n < 3 {
+		t.Errorf("page count = %d, want automatic page breaks", n)
+	}
+}
+
+func TestTemplatePDFDoesNotNeedPandoc(t *testing.T) {
+	tmpl, _ := BuiltinResumeTemplate("chronological")
+	if TemplateNeedsPandoc(tmpl, "pdf") {
+		t.Error("Markdown templates should render PDF natively")
+	}
+	if !TemplateNeedsPandoc(ResumeTemplate{Format: "html"}, "pdf") {
+		t.Error("HTML-to-PDF should still need Pandoc")
+	}
+
+	r := NewResumeService(nil)
+	output, err := r.GenerateFromTemplate(tmpl, templateTestViewData(), "pdf")
+	if err != nil {
+		t.Fatalf("GenerateFromTemplate(pdf) error = %v", err)
+	}
+	again, _ := r.GenerateFromTemplate(tmpl, templateTestViewData(), "pdf")
+	if !bytes.HasPrefix(output, []byte("%PDF-")) || !bytes.Equal(output, again) {
+		t.Error("template PDF is not a reproducible PDF")
+	}
+}
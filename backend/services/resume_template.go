@@ -0,0 +1,290 @@
+package services
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed resume_templates/*.md.tmpl
+var builtinResumeTemplateFS embed.FS
+
+// ResumeTemplate is a text/template that renders view data to Markdown or HTML
+type ResumeTemplate struct {
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Format      string `json:"format"` // markdown or html
+	Content     string `json:"content,omitempty"`
+	Builtin     bool   `json:"builtin"`
+}
+
+// builtinResumeTemplates lists the layouts shipped with Facet, in display order
+var builtinResumeTemplates = []ResumeTemplate{
+	{Slug: "chronological", Name: "Chronological", Description: "Sections in the order the view shows them", Format: "markdown"},
+	{Slug: "skills-first", Name: "Skills-first", Description: "Skills and projects before work history", Format: "markdown"},
+	{Slug: "academic", Name: "Academic CV", Description: "Education, publications, talks and awards first", Format: "markdown"},
+}
+
+// ResumeTemplateData is the value templates execute against
+type ResumeTemplateData struct {
+	Name         string
+	Headline     string
+	Location     string
+	Email        string
+	Summary      string
+	Profile      map[string]interface{}
+	Sections     map[string][]map[string]interface{}
+	SectionOrder []string
+	// SectionTitles labels user-defined sections
+	SectionTitles map[string]string
+}
+
+// ResumeSection is one section with its heading, as returned by the section template function
+type ResumeSection struct {
+	Name  string
+	Title string
+	Items []map[string]interface{}
+}
+
+// SkillGroup is a category of skills, as returned by the groupSkills template function
+type SkillGroup struct {
+	Category string
+	Names    []string
+}
+
+var resumeSectionTitles = map[string]string{
+	"experience":     "Experience",
+	"education":      "Education",
+	"skills":         "Skills",
+	"projects":       "Projects",
+	"certifications": "Certifications",
+	"awards":         "Awards",
+	"talks":          "Talks",
+	"posts":          "Writing",
+}
+
+// BuiltinResumeTemplates returns the built-in templates including their content
+func BuiltinResumeTemplates() []ResumeTemplate {
+	templates := make([]ResumeTemplate, 0, len(builtinResumeTemplates))
+	for _, tmpl := range builtinResumeTemplates {
+		content, err := builtinResumeTemplateFS.ReadFile("resume_templates/" + tmpl.Slug + ".md.tmpl")
+		if err != nil {
+			continue
+		}
+		tmpl.Content = string(content)
+		tmpl.Builtin = true
+		templates = append(templates, tmpl)
+	}
+	return templates
+}
+
+// BuiltinResumeTemplate returns the built-in template with the given slug
+func BuiltinResumeTemplate(slug string) (ResumeTemplate, bool) {
+	for _, tmpl := range BuiltinResumeTemplates() {
+		if tmpl.Slug == slug {
+			return tmpl, true
+		}
+	}
+	return ResumeTemplate{}, false
+}
+
+// ValidateResumeTemplate checks that a template parses and renders sample data
+func ValidateResumeTemplate(content string) error {
+	sample := &ViewData{
+		Profile: map[string]interface{}{"name": "Sample"},
+		Sections: map[string][]map[string]interface{}{
+			"experience": {{"title": "Role", "company": "Company", "start_date": "2020-01-01 00:00:00.000Z"}},
+		},
+		SectionOrder: []string{"experience"},
+	}
+	_, err := RenderResumeTemplate(ResumeTemplate{Format: "markdown", Content: content}, sample)
+	return err
+}
+
+// RenderResumeTemplate renders view data through a template. No clock, randomness or
+// map ordering leaks into the output, so the same view and template always give the same bytes.
+func RenderResumeTemplate(tmpl ResumeTemplate, data *ViewData) (string, error) {
+	templateData := newResumeTemplateData(data)
+
+	partials, err := builtinResumeTemplateFS.ReadFile("resume_templates/partials.md.tmpl")
+	if err != nil {
+		return "", err
+	}
+
+	t, err := template.New("partials").
+		Option("missingkey=zero").
+		Funcs(resumeTemplateFuncs(templateData)).
+		Parse(string(partials))
+	if err != nil {
+		return "", fmt.Errorf("invalid partials: %w", err)
+	}
+	if _, err := t.New("resume").Parse(tmpl.Content); err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, "resume", templateData); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return normalizeTemplateOutput(buf.String()), nil
+}
+
+// newResumeTemplateData flattens view data, applying the view's hero overrides
+func newResumeTemplateData(data *ViewData) *ResumeTemplateData {
+	profile := data.Profile
+	if profile == nil {
+		profile = map[string]interface{}{}
+	}
+	sections := data.Sections
+	if sections == nil {
+		sections = map[string][]map[string]interface{}{}
+	}
+
+	return &ResumeTemplateData{
+		Name:          itemString(profile, "name"),
+		Headline:      firstNonEmpty(data.HeroHeadline, itemString(profile, "headline")),
+		Location:      itemString(profile, "location"),
+		Email:         itemString(profile, "contact_email"),
+		Summary:       firstNonEmpty(data.HeroSummary, itemString(profile, "summary")),
+		Profile:       profile,
+		Sections:      sections,
+		SectionOrder:  data.SectionOrder,
+		SectionTitles: data.SectionTitles,
+	}
+}
+
+func resumeTemplateFuncs(data *ResumeTemplateData) template.FuncMap {
+	sectionTitled := func(name, title string) ResumeSection {
+		return ResumeSection{Name: name, Title: title, Items: data.Sections[name]}
+	}
+
+	return template.FuncMap{
+		"section": func(name string) ResumeSection {
+			title, ok := resumeSectionTitles[name]
+			if !ok {
+				title, ok = data.SectionTitles[name]
+			}
+			if !ok && name != "" {
+				title = strings.ToUpper(name[:1]) + strings.ReplaceAll(name[1:], "_", " ")
+			}
+			return sectionTitled(name, title)
+		},
+		"sectionTitled": sectionTitled,
+		"field":         itemString,
+		"list":          itemStrings,
+		"links":         itemLinks,
+		"text":          plainText,
+		"date":          templateDate,
+		"year": func(value string) string {
+			if d := jsonResumeDate(value); d != "" {
+				return d[:4]
+			}
+			return ""
+		},
+		"dateRange": func(start, end string) string {
+			start, end = templateDate(start), templateDate(end)
+			switch {
+			case start == "":
+				return end
+			case end == "":
+				return start + " – Present"
+			default:
+				return start + " – " + end
+			}
+		},
+		"join":          strings.Join,
+		"joinNonEmpty":  joinNonEmpty,
+		"firstNonEmpty": firstNonEmpty,
+		"upper":         strings.ToUpper,
+		"lower":         strings.ToLower,
+		"in": func(value string, options ...string) bool {
+			for _, option := range options {
+				if value == option {
+					return true
+				}
+			}
+			return false
+		},
+		"groupSkills": groupSkills,
+	}
+}
+
+// templateDate formats a stored date as "Jan 2006"
+func templateDate(value string) string {
+	d := jsonResumeDate(value)
+	if d == "" {
+		return ""
+	}
+	t, _ := time.Parse("2006-01-02", d)
+	return t.Format("Jan 2006")
+}
+
+// groupSkills groups skills by category in order of first appearance; uncategorized skills come last
+func groupSkills(items []map[string]interface{}) []SkillGroup {
+	var groups []SkillGroup
+	index := make(map[string]int)
+	var uncategorized []string
+
+	for _, item := range items {
+		name := itemString(item, "name")
+		if name == "" {
+			continue
+		}
+		category := itemString(item, "category")
+		if category == "" {
+			uncategorized = append(uncategorized, name)
+			continue
+		}
+		if i, ok := index[category]; ok {
+			groups[i].Names = append(groups[i].Names, name)
+			continue
+		}
+		index[category] = len(groups)
+		groups = append(groups, SkillGroup{Category: category, Names: []string{name}})
+	}
+
+	if len(uncategorized) > 0 {
+		groups = append(groups, SkillGroup{Names: uncategorized})
+	}
+	return groups
+}
+
+var (
+	htmlBreakPattern     = regexp.MustCompile(`(?i)<br\s*/?>|</li>`)
+	htmlParagraphPattern = regexp.MustCompile(`(?i)</p>|</h[1-6]>|</ul>|</ol>`)
+	htmlTagPattern       = regexp.MustCompile(`<[^>]*>`)
+	blankLinePattern     = regexp.MustCompile(`\n{3,}`)
+)
+
+// plainText turns editor HTML into plain text paragraphs
+func plainText(value string) string {
+	value = htmlParagraphPattern.ReplaceAllString(value, "\n\n")
+	value = htmlBreakPattern.ReplaceAllString(value, "\n")
+	value = htmlTagPattern.ReplaceAllString(value, "")
+	value = html.UnescapeString(value)
+
+	lines := strings.Split(value, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.TrimSpace(blankLinePattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// normalizeTemplateOutput trims trailing whitespace, collapses runs of blank lines
+// and ends the document with exactly one newline
+func normalizeTemplateOutput(output string) string {
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	output = blankLinePattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(output) + "\n"
+}
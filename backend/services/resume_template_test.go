@@ -0,0 +1,131 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func templateTestViewData() *ViewData {
+	return &ViewData{
+		Profile: map[string]interface{}{
+			"name":          "Ada Lovelace",
+			"headline":      "Engineer",
+			"location":      "London",
+			"contact_email": "ada@example.com",
+			"summary":       "<p>First programmer.</p><p>Poet &amp; mathematician.</p>",
+		},
+		HeroHeadline: "Analytical Engine Programmer",
+		Sections: map[string][]map[string]interface{}{
+			"experience": {{
+				"title":       "Programmer",
+				"company":     "Analytical Engines",
+				"start_date":  "1842-01-01 00:00:00.000Z",
+				"end_date":    "",
+				"description": "<p>Wrote Note G</p>",
+				"bullets":     []interface{}{"First published algorithm"},
+			}},
+			"education": {{
+				"institution": "Private tutoring",
+				"degree":      "Mathematics",
+				"start_date":  "1830-01-01 00:00:00.000Z",
+				"end_date":    "1835-06-01 00:00:00.000Z",
+			}},
+			"skills": {
+				{"name": "Go", "category": "Languages"},
+				{"name": "Poetry"},
+				{"name": "Rust", "category": "Languages"},
+			},
+			"posts": {{"title": "Sketch of the Analytical Engine", "published_at": "1843-09-01 00:00:00.000Z"}},
+		},
+		SectionOrder: []string{"experience", "education", "skills", "posts"},
+	}
+}
+
+func TestBuiltinResumeTemplatesRenderDeterministically(t *testing.T) {
+	templates := BuiltinResumeTemplates()
+	if len(templates) != 3 {
+		t.Fatalf("BuiltinResumeTemplates() = %d templates, want 3", len(templates))
+	}
+
+	for _, tmpl := range templates {
+		first, err := RenderResumeTemplate(tmpl, templateTestViewData())
+		if err != nil {
+			t.Fatalf("%s: RenderResumeTemplate() error = %v", tmpl.Slug, err)
+		}
+		for i := 0; i < 5; i++ {
+			again, _ := RenderResumeTemplate(tmpl, templateTestViewData())
+			if again != first {
+				t.Fatalf("%s: output changed between renders", tmpl.Slug)
+			}
+		}
+
+		for _, want := range []string{
+			"# Ada Lovelace\n",
+			"**Analytical Engine Programmer**",
+			"### Programmer — Analytical Engines",
+			"*Jan 1842 – Present*",
+			"- First published algorithm",
+			"- **Languages:** Go, Rust\n- Poetry",
+			"Poet & mathematician.",
+		} {
+			if !strings.Contains(first, want) {
+				t.Errorf("%s: output missing %q:\n%s", tmpl.Slug, want, first)
+			}
+		}
+		if strings.Contains(first, "\n\n\n") || !strings.HasSuffix(first, "\n") || strings.HasSuffix(first, "\n\n") {
+			t.Errorf("%s: output is not normalized:\n%q", tmpl.Slug, first)
+		}
+	}
+}
+
+func TestBuiltinResumeTemplateOrdering(t *testing.T) {
+	render := func(slug string) string {
+		tmpl, ok := BuiltinResumeTemplate(slug)
+		if !ok {
+			t.Fatalf("BuiltinResumeTemplate(%q) not found", slug)
+		}
+		out, err := RenderResumeTemplate(tmpl, templateTestViewData())
+		if err != nil {
+			t.Fatalf("%s: %v", slug, err)
+		}
+		return out
+	}
+
+	before := func(out, first, second string) bool {
+		return strings.Index(out, first) < strings.Index(out, second)
+	}
+
+	if out := render("skills-first"); !before(out, "## Skills", "## Experience") {
+		t.Errorf("skills-first should list skills before experience:\n%s", out)
+	}
+	out := render("academic")
+	if !before(out, "## Education", "## Publications") || !before(out, "## Publications", "## Appointments") {
+		t.Errorf("academic should list education, publications, then appointments:\n%s", out)
+	}
+}
+
+func TestRenderResumeTemplateCustom(t *testing.T) {
+	tmpl := ResumeTemplate{
+		Format:  "markdown",
+		Content: `{{.Name}} | {{range (section "experience").Items}}{{field . "company"}} ({{year (field . "start_date")}}){{end}}`,
+	}
+	out, err := RenderResumeTemplate(tmpl, templateTestViewData())
+	if err != nil {
+		t.Fatalf("RenderResumeTemplate() error = %v", err)
+	}
+	if out != "Ada Lovelace | Analytical Engines (1842)\n" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestValidateResumeTemplate(t *testing.T) {
+	if err := ValidateResumeTemplate(`{{template "header" .}}`); err != nil {
+		t.Errorf("valid template rejected: %v", err)
+	}
+	if err := ValidateResumeTemplate(`{{if .Name}}`); err == nil {
+		t.Error("expected an error for an unterminated action")
+	}
+	if err := ValidateResumeTemplate(`{{unknownFunc .Name}}`); err == nil {
+		t.Error("expected an error for an unknown function")
+	}
+}
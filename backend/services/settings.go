@@ -12,13 +12,35 @@ type SiteSettings struct {
 	HomepageEnabled    bool
 	LandingPageMessage string
 	CustomCSS          string
-	GAMeasurementID    string
+	ReferenceDOCX      string // stored filename of the reference Word document for DOCX exports
 	Record             *core.Record
+
+	// Days to keep access events; 0 keeps them forever
+	AccessLogRetentionDays int
 }
 
+const siteSettingsCacheKey = "facet.siteSettings"
+
 // LoadSiteSettings returns the current site settings, ensuring a default record exists.
-// Falls back to sensible defaults if the collection is missing.
+// Falls back to sensible defaults if the collection is missing. The settings are
+// cached in-process until the site_settings record changes; callers get their
+// own copy of the record, so setting fields on it does not touch the cache.
 func LoadSiteSettings(app core.App) (*SiteSettings, error) {
+	cached, err := CachedValue(app, siteSettingsCacheKey, []string{"site_settings"}, func() (*SiteSettings, error) {
+		return loadSiteSettings(app)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	settings := *cached
+	if cached.Record != nil {
+		settings.Record = cached.Record.Clone()
+	}
+	return &settings, nil
+}
+
+func loadSiteSettings(app core.App) (*SiteSettings, error) {
 	collection, err := app.FindCollectionByNameOrId("site_settings")
 	if err != nil {
 		return &SiteSettings{
@@ -48,6 +70,9 @@ func LoadSiteSettings(app core.App) (*SiteSettings, error) {
 		record = core.NewRecord(collection)
 		record.Set("homepage_enabled", true)
 		record.Set("landing_page_message", "This profile is being set up.")
+		if collection.Fields.GetByName("access_log_retention_days") != nil {
+			record.Set("access_log_retention_days", 90)
+		}
 		if err := app.Save(record); err != nil {
 			return nil, err
 		}
@@ -57,8 +82,10 @@ func LoadSiteSettings(app core.App) (*SiteSettings, error) {
 		HomepageEnabled:    record.GetBool("homepage_enabled"),
 		LandingPageMessage: record.GetString("landing_page_message"),
 		CustomCSS:          record.GetString("custom_css"),
-		GAMeasurementID:    record.GetString("ga_measurement_id"),
+		ReferenceDOCX:      record.GetString("resume_reference_docx"),
 		Record:             record,
+
+		AccessLogRetentionDays: record.GetInt("access_log_retention_days"),
 	}, nil
 }
 
@@ -86,11 +113,12 @@ func UpdateSiteSettings(app core.App, updates map[string]any, logger *slog.Logge
 			logger.Warn("custom_css field missing on site_settings, skipping update")
 		}
 	}
-	if ga, ok := updates["ga_measurement_id"].(string); ok {
-		if settings.Record.Collection().Fields.GetByName("ga_measurement_id") != nil {
-			settings.Record.Set("ga_measurement_id", ga)
+
+	if days, ok := updates["access_log_retention_days"].(int); ok {
+		if settings.Record.Collection().Fields.GetByName("access_log_retention_days") != nil {
+			settings.Record.Set("access_log_retention_days", days)
 		} else if logger != nil {
-			logger.Warn("ga_measurement_id field missing on site_settings, skipping update")
+			logger.Warn("access_log_retention_days field missing on site_settings, skipping update")
 		}
 	}
 
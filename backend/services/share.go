@@ -1,6 +1,11 @@
 package services
 
 import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
 	"time"
 )
 
@@ -8,6 +13,20 @@ import (
 // 12 chars of base64 = ~72 bits of entropy, sufficient for narrowing to 1-2 candidates
 const TokenPrefixLength = 12
 
+// Recipient-bound share tokens: the emailed code and link expire quickly and a
+// code allows a few typos before it must be requested again. Per token, only so
+// many codes are sent and so many wrong codes accepted within a window, however
+// often a new code is requested. A verified recipient keeps access for a week,
+// or until the token expires.
+const (
+	ShareVerificationTTL         = 15 * time.Minute
+	ShareVerificationMaxAttempts = 5
+	ShareVerificationWindow      = time.Hour
+	ShareVerificationMaxCodes    = 10
+	ShareVerificationMaxFailures = 10
+	ShareSessionDuration         = 7 * 24 * time.Hour
+)
+
 // ShareService handles share token operations
 type ShareService struct {
 	crypto *CryptoService
@@ -24,6 +43,9 @@ type ShareTokenValidation struct {
 	ViewID   string `json:"view_id"`
 	ViewSlug string `json:"view_slug"`
 	Error    string `json:"error,omitempty"`
+	// Recipient-bound tokens only open after the recipient confirms the emailed code
+	VerificationRequired bool   `json:"verification_required,omitempty"`
+	RecipientHint        string `json:"recipient_hint,omitempty"`
 }
 
 // GenerateToken generates a new share token (32 bytes, URL-safe base64)
@@ -42,6 +64,16 @@ func (s *ShareService) ValidateTokenHMAC(token, storedHMAC string) bool {
 	return s.crypto.ValidateTokenHMAC(token, storedHMAC)
 }
 
+// GenerateVerificationCode generates the 6-digit code emailed to the recipient
+// of a recipient-bound share token
+func (s *ShareService) GenerateVerificationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
 // IsTokenExpired checks if a token has expired
 func (s *ShareService) IsTokenExpired(expiresAt *time.Time) bool {
 	if expiresAt == nil {
@@ -66,3 +98,74 @@ func (s *ShareService) TokenPrefix(token string) string {
 	}
 	return token[:TokenPrefixLength]
 }
+
+// MaskEmail hides most of the local part of an address so a page can say where
+// a code was sent without revealing the address: "jane@example.com" -> "j***@example.com"
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return "***"
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// ShareScope narrows what a share token unlocks. The zero value unlocks the
+// whole view.
+type ShareScope struct {
+	// Sections limits the view to these sections; empty keeps them all
+	Sections []string `json:"sections,omitempty"`
+	// HiddenItems are record IDs left out of every section
+	HiddenItems []string `json:"hidden_items,omitempty"`
+	// Redact lists fields removed per section; the "profile" key covers the profile
+	Redact map[string][]string `json:"redact,omitempty"`
+}
+
+// ParseShareScope decodes the scope stored on a share token. Empty and null
+// values are the unrestricted scope.
+func ParseShareScope(raw string) (ShareScope, error) {
+	var scope ShareScope
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "null" {
+		return scope, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &scope); err != nil {
+		return ShareScope{}, err
+	}
+	return scope, nil
+}
+
+// IsZero reports whether the scope leaves the view untouched
+func (s ShareScope) IsZero() bool {
+	if len(s.Sections) > 0 || len(s.HiddenItems) > 0 {
+		return false
+	}
+	for _, fields := range s.Redact {
+		if len(fields) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// AllowsSection reports whether the scope keeps a section of the view
+func (s ShareScope) AllowsSection(section string) bool {
+	if len(s.Sections) == 0 {
+		return true
+	}
+	for _, name := range s.Sections {
+		if name == section {
+			return true
+		}
+	}
+	return false
+}
+
+// HidesItem reports whether the scope leaves out the item with the given ID
+func (s ShareScope) HidesItem(id string) bool {
+	for _, hidden := range s.HiddenItems {
+		if hidden == id {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -345,3 +346,67 @@ func TestLegacyTokenWithoutPrefix(t *testing.T) {
 		t.Error("Prefix extraction should work on legacy tokens")
 	}
 }
+
+func TestParseShareScope(t *testing.T) {
+	for _, raw := range []string{"", "null", "{}", `{"redact": {"profile": []}}`} {
+		scope, err := ParseShareScope(raw)
+		if err != nil {
+			t.Fatalf("ParseShareScope(%q) error = %v", raw, err)
+		}
+		if !scope.IsZero() {
+			t.Errorf("ParseShareScope(%q) = %+v, want the unrestricted scope", raw, scope)
+		}
+		if !scope.AllowsSection("experience") || scope.HidesItem("abc") {
+			t.Errorf("unrestricted scope should keep every section and item")
+		}
+	}
+
+	scope, err := ParseShareScope(`{"sections": ["experience", "skills"], "hidden_items": ["abc"], "redact": {"experience": ["company"]}}`)
+	if err != nil {
+		t.Fatalf("ParseShareScope() error = %v", err)
+	}
+	if scope.IsZero() {
+		t.Error("scope with sections should not be zero")
+	}
+	if !scope.AllowsSection("skills") || scope.AllowsSection("contacts") {
+		t.Errorf("AllowsSection() should only keep the listed sections")
+	}
+	if !scope.HidesItem("abc") || scope.HidesItem("def") {
+		t.Errorf("HidesItem() should only hide the listed items")
+	}
+	if got := scope.Redact["experience"]; len(got) != 1 || got[0] != "company" {
+		t.Errorf("Redact[experience] = %v, want [company]", got)
+	}
+
+	if _, err := ParseShareScope(`{"sections": "experience"}`); err == nil {
+		t.Error("ParseShareScope() should reject a malformed scope")
+	}
+}
+
+func TestGenerateVerificationCode(t *testing.T) {
+	share := NewShareService(NewCryptoService("test-encryption-key-32-chars-ok!"))
+
+	for i := 0; i < 20; i++ {
+		code, err := share.GenerateVerificationCode()
+		if err != nil {
+			t.Fatalf("GenerateVerificationCode() error = %v", err)
+		}
+		if len(code) != 6 || strings.Trim(code, "0123456789") != "" {
+			t.Fatalf("GenerateVerificationCode() = %q, want 6 digits", code)
+		}
+	}
+}
+
+func TestMaskEmail(t *testing.T) {
+	tests := map[string]string{
+		"jane@example.com": "j***@example.com",
+		"j@example.com":    "j***@example.com",
+		"not-an-email":     "***",
+		"@example.com":     "***",
+	}
+	for email, want := range tests {
+		if got := MaskEmail(email); got != want {
+			t.Errorf("MaskEmail(%q) = %q, want %q", email, got, want)
+		}
+	}
+}
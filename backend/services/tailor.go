@@ -0,0 +1,519 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Job-description tailoring scores a view's content against a pasted job description
+// and proposes a focused view: the most relevant items, ordered by relevance, with
+// experience bullets trimmed to the ones that match.
+
+// Tailoring modes
+const (
+	TailorModeKeyword = "keyword"
+	TailorModeAI      = "ai"
+)
+
+// TailorSections are the sections tailoring selects content for; any other
+// section of the source view is copied unchanged
+var TailorSections = []string{"experience", "projects", "skills", "talks"}
+
+// tailorLimits caps how many items a tailored section keeps
+var tailorLimits = map[string]int{"experience": 6, "projects": 4, "skills": 15, "talks": 3}
+
+const (
+	tailorMaxBullets   = 5
+	tailorMaxMatched   = 8
+	tailorPromptJDSize = 8000
+	tailorPromptText   = 300
+)
+
+// TailorCandidate is one piece of content that can be scored against a job description
+type TailorCandidate struct {
+	Key     string `json:"key"` // item ID, or "itemID#index" for an experience bullet
+	Section string `json:"section"`
+	ItemID  string `json:"item_id"`
+	Bullet  int    `json:"bullet"` // index into the item's bullets, -1 for the item itself
+	Label   string `json:"label"`
+	Text    string `json:"-"`
+}
+
+// TailorScore is a candidate's relevance to a job description, from 0 to 1
+type TailorScore struct {
+	TailorCandidate
+	Score   float64  `json:"score"`
+	Matched []string `json:"matched,omitempty"`
+	Reason  string   `json:"reason,omitempty"`
+}
+
+// TailorService scores view content against job descriptions
+type TailorService struct {
+	ai *AIService
+}
+
+// NewTailorService creates a new tailoring service; ai may be nil when only keyword mode is used
+func NewTailorService(ai *AIService) *TailorService {
+	return &TailorService{ai: ai}
+}
+
+// TailorCandidates lists the scoreable content of a view: every experience item and
+// bullet, project, skill and talk, in the order the view data holds them
+func TailorCandidates(data *ViewData) []TailorCandidate {
+	var candidates []TailorCandidate
+	for _, section := range TailorSections {
+		for _, item := range data.Sections[section] {
+			id, _ := item["id"].(string)
+			if id == "" {
+				continue
+			}
+			str := func(key string) string {
+				value, _ := item[key].(string)
+				return value
+			}
+
+			candidate := TailorCandidate{Key: id, Section: section, ItemID: id, Bullet: -1}
+			switch section {
+			case "experience":
+				candidate.Label = strings.TrimSpace(str("title") + " at " + str("company"))
+				candidate.Text = joinText(str("title"), str("company"), plainText(str("description")), strings.Join(stringList(item["skills"]), ", "))
+			case "projects":
+				candidate.Label = str("title")
+				candidate.Text = joinText(str("title"), str("summary"), plainText(str("description")),
+					strings.Join(stringList(item["tech_stack"]), ", "), strings.Join(stringList(item["categories"]), ", "))
+			case "skills":
+				candidate.Label = str("name")
+				candidate.Text = str("name")
+			case "talks":
+				candidate.Label = str("title")
+				candidate.Text = joinText(str("title"), str("event"), plainText(str("description")))
+			}
+			candidates = append(candidates, candidate)
+
+			if section != "experience" {
+				continue
+			}
+			for i, bullet := range stringList(item["bullets"]) {
+				candidates = append(candidates, TailorCandidate{
+					Key:     fmt.Sprintf("%s#%d", id, i),
+					Section: section,
+					ItemID:  id,
+					Bullet:  i,
+					Label:   bullet,
+					Text:    bullet,
+				})
+			}
+		}
+	}
+	return candidates
+}
+
+// ScoreByKeywords ranks candidates by TF-IDF cosine similarity to the job description.
+// The result is deterministic and in candidate order.
+func ScoreByKeywords(jobDescription string, candidates []TailorCandidate) []TailorScore {
+	jdTerms, surface := keywordTerms(jobDescription)
+
+	docs := make([]map[string]float64, len(candidates))
+	df := make(map[string]int)
+	for i, candidate := range candidates {
+		terms, _ := keywordTerms(candidate.Text)
+		docs[i] = termFrequencies(terms)
+		for term := range docs[i] {
+			df[term]++
+		}
+	}
+
+	n := float64(len(candidates))
+	idf := func(term string) float64 {
+		return math.Log((1+n)/(1+float64(df[term]))) + 1
+	}
+
+	jdVector := termFrequencies(jdTerms)
+	var jdNorm float64
+	for term, tf := range jdVector {
+		jdVector[term] = tf * idf(term)
+		jdNorm += jdVector[term] * jdVector[term]
+	}
+	jdNorm = math.Sqrt(jdNorm)
+
+	scores := make([]TailorScore, len(candidates))
+	for i, candidate := range candidates {
+		scores[i] = TailorScore{TailorCandidate: candidate}
+
+		var dot, norm float64
+		var matched []string
+		for term, tf := range docs[i] {
+			weight := tf * idf(term)
+			norm += weight * weight
+			if jdWeight, ok := jdVector[term]; ok {
+				dot += weight * jdWeight
+				matched = append(matched, term)
+			}
+		}
+		if dot == 0 || jdNorm == 0 {
+			continue
+		}
+
+		scores[i].Score = math.Round(dot/(math.Sqrt(norm)*jdNorm)*10000) / 10000
+		sort.Slice(matched, func(a, b int) bool {
+			if jdVector[matched[a]] != jdVector[matched[b]] {
+				return jdVector[matched[a]] > jdVector[matched[b]]
+			}
+			return matched[a] < matched[b]
+		})
+		if len(matched) > tailorMaxMatched {
+			matched = matched[:tailorMaxMatched]
+		}
+		for j, term := range matched {
+			matched[j] = surface[term]
+		}
+		scores[i].Matched = matched
+	}
+	return scores
+}
+
+// ScoreWithAI asks the provider to rate each candidate's relevance. Matched keywords
+// come from keyword scoring so both modes explain their picks the same way.
+func (t *TailorService) ScoreWithAI(ctx context.Context, provider *AIProvider, jobDescription string, candidates []TailorCandidate) ([]TailorScore, error) {
+	if t.ai == nil {
+		return nil, fmt.Errorf("AI service not configured")
+	}
+
+	response, err := t.ai.ImproveContentWithTokens(ctx, provider, buildTailorPrompt(jobDescription, candidates), 4096)
+	if err != nil {
+		return nil, fmt.Errorf("AI scoring failed: %w", err)
+	}
+
+	ratings, err := parseTailorRatings(response)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := ScoreByKeywords(jobDescription, candidates)
+	for i := range scores {
+		rating, ok := ratings[i+1]
+		scores[i].Score = 0
+		scores[i].Reason = ""
+		if ok {
+			scores[i].Score = math.Max(0, math.Min(rating.Score, 100)) / 100
+			scores[i].Reason = strings.TrimSpace(rating.Reason)
+		}
+	}
+	return scores, nil
+}
+
+type tailorRating struct {
+	N      int     `json:"n"`
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+func buildTailorPrompt(jobDescription string, candidates []TailorCandidate) string {
+	var sb strings.Builder
+	sb.WriteString(`You are helping tailor a resume to a job description. Rate how relevant each numbered piece of resume content is to the job, from 0 (irrelevant) to 100 (essential for this role).
+Judge relevance only; do not rewrite anything.
+
+JOB DESCRIPTION:
+`)
+	sb.WriteString(truncateRunes(strings.TrimSpace(jobDescription), tailorPromptJDSize))
+	sb.WriteString("\n\nRESUME CONTENT:\n")
+	for i, candidate := range candidates {
+		kind := strings.TrimSuffix(candidate.Section, "s")
+		if candidate.Bullet >= 0 {
+			kind = "experience bullet"
+		}
+		text := strings.Join(strings.Fields(candidate.Text), " ")
+		fmt.Fprintf(&sb, "[%d] (%s) %s\n", i+1, kind, truncateRunes(text, tailorPromptText))
+	}
+	sb.WriteString(`
+Respond with JSON only, no other text, in this exact format:
+{"scores": [{"n": 1, "score": 85, "reason": "under 12 words"}]}
+Include every number exactly once.`)
+	return sb.String()
+}
+
+// parseTailorRatings reads the AI response into ratings keyed by candidate number
+func parseTailorRatings(response string) (map[int]tailorRating, error) {
+	jsonStr := extractJSONFromResponse(response)
+	if start, end := strings.Index(jsonStr, "{"), strings.LastIndex(jsonStr, "}"); start >= 0 && end > start {
+		jsonStr = jsonStr[start : end+1]
+	}
+
+	var parsed struct {
+		Scores []tailorRating `json:"scores"`
+	}
+	if err := json.Unmarshal([]byte(jsonStr), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse AI scores: %w", err)
+	}
+
+	ratings := make(map[int]tailorRating, len(parsed.Scores))
+	for _, rating := range parsed.Scores {
+		ratings[rating.N] = rating
+	}
+	return ratings, nil
+}
+
+// ProposeTailoredSections builds the sections for a tailored view from the source
+// view's sections and candidate scores. Relevant projects, skills and talks are kept
+// in score order; experience keeps its original order with matching bullets ranked
+// first and the rest dropped. Sections with nothing relevant are disabled, except
+// experience, which falls back to the full list.
+func ProposeTailoredSections(source []map[string]interface{}, scores []TailorScore) []map[string]interface{} {
+	type itemScore struct {
+		id      string
+		score   float64
+		order   int
+		bullets []TailorScore
+	}
+
+	bySection := make(map[string][]*itemScore)
+	index := make(map[string]*itemScore)
+	for _, score := range scores {
+		item := index[score.ItemID]
+		if item == nil {
+			item = &itemScore{id: score.ItemID, order: len(bySection[score.Section])}
+			index[score.ItemID] = item
+			bySection[score.Section] = append(bySection[score.Section], item)
+		}
+		if score.Bullet >= 0 {
+			item.bullets = append(item.bullets, score)
+		}
+		item.score = math.Max(item.score, score.Score)
+	}
+
+	tailorable := make(map[string]bool, len(TailorSections))
+	for _, name := range TailorSections {
+		tailorable[name] = true
+	}
+
+	build := func(name string, original map[string]interface{}) map[string]interface{} {
+		section := make(map[string]interface{}, len(original)+4)
+		for key, value := range original {
+			section[key] = value
+		}
+		section["section"] = name
+
+		var selected []*itemScore
+		for _, item := range bySection[name] {
+			if item.score > 0 {
+				selected = append(selected, item)
+			}
+		}
+		if len(selected) == 0 && name == "experience" {
+			selected = bySection[name]
+		}
+		sort.SliceStable(selected, func(a, b int) bool {
+			if name == "experience" {
+				return selected[a].order < selected[b].order
+			}
+			return selected[a].score > selected[b].score
+		})
+		if limit := tailorLimits[name]; len(selected) > limit {
+			if name == "experience" {
+				// Drop the weakest roles but keep the survivors in their original order
+				ranked := append([]*itemScore(nil), selected...)
+				sort.SliceStable(ranked, func(a, b int) bool { return ranked[a].score > ranked[b].score })
+				keep := make(map[string]bool, limit)
+				for _, item := range ranked[:limit] {
+					keep[item.id] = true
+				}
+				kept := selected[:0]
+				for _, item := range selected {
+					if keep[item.id] {
+						kept = append(kept, item)
+					}
+				}
+				selected = kept
+			} else {
+				selected = selected[:limit]
+			}
+		}
+
+		originalConfig, _ := original["itemConfig"].(map[string]interface{})
+		items := make([]string, 0, len(selected))
+		itemConfig := make(map[string]interface{})
+		for _, item := range selected {
+			items = append(items, item.id)
+
+			config := make(map[string]interface{})
+			overrides := make(map[string]interface{})
+			if existing, ok := originalConfig[item.id].(map[string]interface{}); ok {
+				for key, value := range existing {
+					config[key] = value
+				}
+				if existingOverrides, ok := existing["overrides"].(map[string]interface{}); ok {
+					for key, value := range existingOverrides {
+						overrides[key] = value
+					}
+				}
+			}
+
+			if bullets := rankedBullets(item.bullets); bullets != nil {
+				overrides["bullets"] = bullets
+			}
+			if len(overrides) > 0 {
+				config["overrides"] = overrides
+			}
+			if len(config) > 0 {
+				itemConfig[item.id] = config
+			}
+		}
+
+		section["items"] = items
+		section["itemConfig"] = itemConfig
+		section["enabled"] = len(items) > 0
+		return section
+	}
+
+	var sections []map[string]interface{}
+	seen := make(map[string]bool)
+	for _, original := range source {
+		name, _ := original["section"].(string)
+		if !tailorable[name] || seen[name] {
+			sections = append(sections, original)
+			continue
+		}
+		seen[name] = true
+		sections = append(sections, build(name, original))
+	}
+	// Tailorable sections the source view left out are added when they have matches
+	for _, name := range TailorSections {
+		if seen[name] {
+			continue
+		}
+		if section := build(name, nil); section["enabled"] == true {
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
+
+// rankedBullets returns the matching bullets, best first, or nil to keep them all
+func rankedBullets(bullets []TailorScore) []string {
+	var matching []TailorScore
+	for _, bullet := range bullets {
+		if bullet.Score > 0 {
+			matching = append(matching, bullet)
+		}
+	}
+	if len(matching) == 0 {
+		return nil
+	}
+	sort.SliceStable(matching, func(a, b int) bool { return matching[a].Score > matching[b].Score })
+	if len(matching) > tailorMaxBullets {
+		matching = matching[:tailorMaxBullets]
+	}
+
+	result := make([]string, len(matching))
+	for i, bullet := range matching {
+		result[i] = bullet.Text
+	}
+	return result
+}
+
+var keywordTokenPattern = regexp.MustCompile(`[a-z0-9][a-z0-9+#]*(?:\.[a-z0-9+#]+)*`)
+
+// keywordStopWords are common English words and job-ad boilerplate that say nothing
+// about the role itself
+var keywordStopWords = func() map[string]bool {
+	words := map[string]bool{}
+	for _, word := range strings.Fields(`
+		a about above across after again against all also am an and any are as at be because been
+		before being below between both but by can could did do does doing down during each etc
+		few for from further had has have having he her here hers him his how i if in into is it
+		its itself just me more most my no nor not now of off on once only or other our ours out
+		over own per same she should so some such than that the their theirs them then there these
+		they this those through to too under until up very via was we were what when where which
+		while who whom why will with within without would you your yours
+		ability able applicant applicants apply benefit candidate candidates company day degree
+		equal excellent experience familiarity good great help ideal include including job join
+		looking must new opportunity plus preferred position required requirement responsibilities
+		responsibility role skill strong team teams understanding using well work working year years`) {
+		words[word] = true
+	}
+	return words
+}()
+
+// keywordTerms splits text into stemmed keyword terms. surface maps each term back
+// to the first way it was written, for display.
+func keywordTerms(text string) (terms []string, surface map[string]string) {
+	surface = make(map[string]string)
+	for _, token := range keywordTokenPattern.FindAllString(strings.ToLower(text), -1) {
+		if len(token) < 2 || keywordStopWords[token] || strings.Trim(token, "0123456789.") == "" {
+			continue
+		}
+		term := keywordStem(token)
+		if _, ok := surface[term]; !ok {
+			surface[term] = token
+		}
+		terms = append(terms, term)
+	}
+	return terms, surface
+}
+
+// keywordStem folds simple plurals and verb endings so "APIs" matches "API" and
+// "mentored" matches "mentoring"
+func keywordStem(word string) string {
+	if strings.ContainsAny(word, ".+#") {
+		return word
+	}
+	switch {
+	case len(word) > 5 && strings.HasSuffix(word, "ing"):
+		return word[:len(word)-3]
+	case len(word) > 5 && strings.HasSuffix(word, "ed"):
+		return word[:len(word)-2]
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+func termFrequencies(terms []string) map[string]float64 {
+	freq := make(map[string]float64, len(terms))
+	for _, term := range terms {
+		freq[term]++
+	}
+	return freq
+}
+
+func joinText(parts ...string) string {
+	var kept []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// stringList reads a JSON list of strings, skipping anything else
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		var result []string
+		for _, entry := range v {
+			if s, ok := entry.(string); ok && strings.TrimSpace(s) != "" {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+func truncateRunes(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit]) + "…"
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const tailorTestJD = `Senior Backend Engineer. You will design Go microservices and REST APIs on
+Kubernetes, own PostgreSQL performance and mentor engineers. Experience with Terraform is a plus.`
+
+func tailorTestViewData() *ViewData {
+	return &ViewData{
+		Sections: map[string][]map[string]interface{}{
+			"experience": {
+				{
+					"id": "exp1", "title": "Backend Engineer", "company": "Acme",
+					"description": "<p>Payments platform</p>",
+					"bullets": []interface{}{
+						"Organised the office book club",
+						"Built Go microservices on Kubernetes",
+						"Tuned PostgreSQL queries for the billing APIs",
+					},
+				},
+				{"id": "exp2", "title": "Barista", "company": "Cafe", "bullets": []interface{}{"Made coffee"}},
+			},
+			"projects": {
+				{"id": "p1", "title": "Latte art gallery", "summary": "Photos of coffee"},
+				{"id": "p2", "title": "kubectl-tree", "summary": "Kubernetes plugin", "tech_stack": []interface{}{"Go"}},
+			},
+			"skills": {
+				{"id": "s1", "name": "Watercolour"},
+				{"id": "s2", "name": "Terraform"},
+				{"id": "s3", "name": "Go"},
+			},
+			"talks": {{"id": "t1", "title": "Knitting for beginners"}},
+		},
+	}
+}
+
+func TestScoreByKeywords(t *testing.T) {
+	candidates := TailorCandidates(tailorTestViewData())
+	if len(candidates) != 12 {
+		t.Fatalf("TailorCandidates() = %d candidates, want 12", len(candidates))
+	}
+
+	scores := ScoreByKeywords(tailorTestJD, candidates)
+	byKey := make(map[string]TailorScore, len(scores))
+	for _, score := range scores {
+		byKey[score.Key] = score
+	}
+
+	if byKey["exp1#1"].Score <= 0 || byKey["exp1#0"].Score != 0 {
+		t.Errorf("bullet scores = %v / %v, want only the Kubernetes bullet to match", byKey["exp1#1"].Score, byKey["exp1#0"].Score)
+	}
+	if byKey["p2"].Score <= byKey["p1"].Score {
+		t.Errorf("project scores = %v (relevant) vs %v, want the relevant project ahead", byKey["p2"].Score, byKey["p1"].Score)
+	}
+	if byKey["s1"].Score != 0 || byKey["s2"].Score == 0 {
+		t.Errorf("skill scores = %v / %v", byKey["s1"].Score, byKey["s2"].Score)
+	}
+	if matched := strings.Join(byKey["exp1#2"].Matched, ","); !strings.Contains(matched, "postgresql") || !strings.Contains(matched, "apis") {
+		t.Errorf("matched terms = %q, want postgresql and apis as written in the job description", matched)
+	}
+
+	for i := 0; i < 5; i++ {
+		if again := ScoreByKeywords(tailorTestJD, candidates); !reflect.DeepEqual(again, scores) {
+			t.Fatal("keyword scores changed between runs")
+		}
+	}
+}
+
+func TestProposeTailoredSections(t *testing.T) {
+	source := []map[string]interface{}{
+		{"section": "experience", "enabled": true, "layout": "timeline", "itemConfig": map[string]interface{}{
+			"exp1": map[string]interface{}{"overrides": map[string]interface{}{"title": "Lead Engineer"}},
+		}},
+		{"section": "education", "enabled": true, "items": []interface{}{"edu1"}},
+		{"section": "projects", "enabled": true},
+		{"section": "talks", "enabled": true},
+	}
+	scores := ScoreByKeywords(tailorTestJD, TailorCandidates(tailorTestViewData()))
+	sections := ProposeTailoredSections(source, scores)
+
+	names := make([]string, len(sections))
+	for i, section := range sections {
+		names[i], _ = section["section"].(string)
+	}
+	if want := []string{"experience", "education", "projects", "talks", "skills"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("sections = %v, want %v", names, want)
+	}
+
+	experience := sections[0]
+	if experience["layout"] != "timeline" || !reflect.DeepEqual(experience["items"], []string{"exp1"}) {
+		t.Errorf("experience = %v", experience)
+	}
+	overrides := experience["itemConfig"].(map[string]interface{})["exp1"].(map[string]interface{})["overrides"].(map[string]interface{})
+	if overrides["title"] != "Lead Engineer" {
+		t.Error("existing overrides were not kept")
+	}
+	bullets, _ := overrides["bullets"].([]string)
+	if len(bullets) != 2 || strings.Contains(strings.Join(bullets, " "), "book club") {
+		t.Errorf("bullets = %v, want only the two matching bullets", bullets)
+	}
+
+	if !reflect.DeepEqual(sections[1], source[1]) {
+		t.Error("untailored section was changed")
+	}
+	if !reflect.DeepEqual(sections[2]["items"], []string{"p2"}) {
+		t.Errorf("projects = %v", sections[2]["items"])
+	}
+	if sections[3]["enabled"] != false {
+		t.Error("talks with no match should be disabled")
+	}
+	if skills := sections[4]["items"].([]string); len(skills) != 2 || skills[0] != "s2" && skills[0] != "s3" {
+		t.Errorf("skills = %v", skills)
+	}
+}
+
+func TestParseTailorRatings(t *testing.T) {
+	ratings, err := parseTailorRatings("Here you go:\n```json\n{\"scores\": [{\"n\": 2, \"score\": 90, \"reason\": \"Kubernetes\"}]}\n```")
+	if err != nil {
+		t.Fatalf("parseTailorRatings() error = %v", err)
+	}
+	if ratings[2].Score != 90 || ratings[2].Reason != "Kubernetes" {
+		t.Errorf("ratings = %v", ratings)
+	}
+	if _, err := parseTailorRatings("no scores today"); err == nil {
+		t.Error("expected an error for a non-JSON response")
+	}
+}
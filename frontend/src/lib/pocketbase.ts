@@ -194,6 +194,48 @@ export interface Award {
 	sort_order: number;
 }
 
+export type CustomFieldType = 'text' | 'textarea' | 'url' | 'date' | 'number' | 'boolean' | 'list';
+
+export interface CustomSectionField {
+	name: string;
+	label?: string;
+	type: CustomFieldType;
+	overridable?: boolean;
+}
+
+// A user-defined section ("Publications", "Volunteering", ...). Its items live
+// in custom_items with section = name and the field values in data.
+export interface SectionType {
+	id: string;
+	name: string;
+	label: string;
+	description?: string;
+	fields: CustomSectionField[] | null;
+	default_layout?: string;
+	sort_order: number;
+}
+
+export interface CustomItem {
+	id: string;
+	section: string;
+	title: string;
+	data: Record<string, unknown> | null;
+	visibility: 'public' | 'unlisted' | 'private';
+	view_visibility?: Record<string, boolean>;
+	is_draft: boolean;
+	sort_order: number;
+}
+
+export const CUSTOM_FIELD_TYPES: { value: CustomFieldType; label: string }[] = [
+	{ value: 'text', label: 'Text' },
+	{ value: 'textarea', label: 'Long text' },
+	{ value: 'url', label: 'Link' },
+	{ value: 'date', label: 'Date' },
+	{ value: 'number', label: 'Number' },
+	{ value: 'boolean', label: 'Yes / No' },
+	{ value: 'list', label: 'List' }
+];
+
 export type ContactMethodType =
 	| 'email'
 	| 'phone'
@@ -237,11 +279,13 @@ export interface View {
 	is_active: boolean;
 	is_default?: boolean;
 	accent_color?: 'sky' | 'indigo' | 'emerald' | 'rose' | 'amber' | 'slate' | null;
+	// Unlisted and private views can offer a request access form
+	allow_access_requests?: boolean;
 }
 
 export interface ItemConfig {
 	order?: number;
-	overrides?: Record<string, string | string[]>;
+	overrides?: Record<string, string | string[] | number | boolean>;
 }
 
 // Layout types for each section
@@ -258,8 +302,52 @@ export interface ViewSection {
 	layout?: SectionLayout;
 	width?: SectionWidth;
 	itemConfig?: Record<string, ItemConfig>;
+	rules?: SectionRules;
+}
+
+// Smart section rules: the section shows every visible record matching the conditions
+export type SectionRuleOp = 'eq' | 'neq' | 'contains' | 'gte' | 'lte' | 'gt' | 'lt' | 'within' | 'empty' | 'not_empty';
+
+export interface SectionCondition {
+	field: string;
+	op: SectionRuleOp;
+	value?: string;
+}
+
+export interface SectionRules {
+	match?: 'all' | 'any';
+	conditions: SectionCondition[];
+	sort?: string;
+	limit?: number;
 }
 
+export const RULE_OPERATORS: { value: SectionRuleOp; label: string }[] = [
+	{ value: 'contains', label: 'contains' },
+	{ value: 'eq', label: 'is' },
+	{ value: 'neq', label: 'is not' },
+	{ value: 'gte', label: 'at least' },
+	{ value: 'lte', label: 'at most' },
+	{ value: 'gt', label: 'greater than' },
+	{ value: 'lt', label: 'less than' },
+	{ value: 'within', label: 'within the last' },
+	{ value: 'empty', label: 'is empty' },
+	{ value: 'not_empty', label: 'is not empty' }
+];
+
+// Fields that rules can filter and sort on, per section
+export const RULE_FIELDS: Record<string, string[]> = {
+	experience: ['title', 'company', 'location', 'start_date', 'end_date', 'skills'],
+	projects: ['title', 'summary', 'categories', 'tech_stack', 'is_featured'],
+	education: ['institution', 'degree', 'field', 'start_date', 'end_date'],
+	certifications: ['name', 'issuer', 'issue_date', 'expiry_date'],
+	awards: ['title', 'issuer', 'awarded_at'],
+	skills: ['name', 'category', 'proficiency'],
+	posts: ['title', 'tags', 'published_at'],
+	talks: ['title', 'event', 'location', 'date'],
+	contacts: ['type', 'label', 'is_primary'],
+	testimonials: ['author_name', 'relationship', 'featured']
+};
+
 // Valid width options with labels
 export const VALID_WIDTHS: { value: SectionWidth; label: string }[] = [
 	{ value: 'full', label: 'Full Width' },
@@ -363,10 +451,21 @@ export const VALID_LAYOUTS: Record<string, { layouts: string[]; default: string;
 	}
 };
 
+// Layouts shared by every custom section (keep in sync with customSectionLayouts in hooks/custom_sections.go)
+export const CUSTOM_SECTION_LAYOUTS = {
+	layouts: ['default', 'grid-2', 'grid-3', 'compact'],
+	default: 'default',
+	labels: {
+		default: 'Default',
+		'grid-2': '2-Column Grid',
+		'grid-3': '3-Column Grid',
+		compact: 'Compact'
+	} as Record<string, string>
+};
+
 // Helper to get section layout with fallback to default
 export function getSectionLayout(section: string, layout?: string): string {
-	const config = VALID_LAYOUTS[section];
-	if (!config) return 'default';
+	const config = VALID_LAYOUTS[section] ?? CUSTOM_SECTION_LAYOUTS;
 	if (layout && config.layouts.includes(layout)) return layout;
 	return config.default;
 }
@@ -414,12 +513,30 @@ export function isWidthValidForLayout(
 	return true;
 }
 
-// Define which fields can be overridden per collection
+// Define which fields can be overridden per section (keep in sync with builtinSections in hooks/custom_sections.go)
 export const OVERRIDABLE_FIELDS: Record<string, string[]> = {
-	experience: ['title', 'description', 'bullets'],
-	projects: ['title', 'summary', 'description'],
+	experience: ['title', 'description', 'bullets', 'skills'],
+	projects: ['title', 'summary', 'description', 'tech_stack', 'categories'],
 	education: ['degree', 'field', 'description'],
-	talks: ['title', 'description']
+	certifications: ['name'],
+	awards: ['title', 'description'],
+	skills: ['name', 'category', 'proficiency'],
+	posts: ['title', 'excerpt', 'tags'],
+	talks: ['title', 'description'],
+	contacts: ['label'],
+	testimonials: ['relationship', 'project']
+};
+
+// Overridable fields that hold a list of strings (edited one entry per line)
+export const OVERRIDE_LIST_FIELDS = ['bullets', 'skills', 'tech_stack', 'categories', 'tags'];
+
+// Overridable fields that hold long text
+export const OVERRIDE_TEXTAREA_FIELDS = ['description', 'summary', 'excerpt'];
+
+// Overridable select fields and their allowed values
+export const OVERRIDE_SELECT_OPTIONS: Record<string, string[]> = {
+	proficiency: ['expert', 'proficient', 'familiar'],
+	relationship: ['client', 'colleague', 'manager', 'report', 'mentor', 'other']
 };
 
 export interface AIProvider {
@@ -454,6 +571,42 @@ export interface ImportProposal {
 	status: 'pending' | 'applied' | 'rejected';
 }
 
+// Narrows what a share token unlocks; an empty scope shows the whole view
+export interface ShareScope {
+	sections?: string[];
+	hidden_items?: string[];
+	// Fields left out per section; the "profile" key covers the profile
+	redact?: Record<string, string[]>;
+}
+
+// A visitor's request for access to an unlisted or private view
+export interface AccessRequest {
+	id: string;
+	view_id: string;
+	view_name: string;
+	name: string;
+	email: string;
+	company: string;
+	reason: string;
+	status: 'pending' | 'approved' | 'declined';
+	share_token: string;
+	reviewed_at: string;
+	created: string;
+}
+
+// A named password of a password-protected view (the hash is never returned)
+export interface ViewPassword {
+	id: string;
+	view: string;
+	label: string;
+	expires_at?: string;
+	is_active: boolean;
+	use_count: number;
+	last_used_at?: string;
+	created: string;
+	updated: string;
+}
+
 export interface ShareToken {
 	id: string;
 	view_id: string;
@@ -465,13 +618,35 @@ export interface ShareToken {
 	use_count: number;
 	is_active: boolean;
 	last_used_at?: string;
+	application?: string;
+	scope?: ShareScope | null;
+	// Only this address can open the link, after confirming an emailed code
+	recipient_email?: string;
 	created: string;
 	updated: string;
 	expand?: {
 		view_id?: View;
+		application?: Application;
 	};
 }
 
+export interface Application {
+	id: string;
+	company: string;
+	role?: string;
+	job_url?: string;
+	status?: 'applied' | 'interviewing' | 'offer' | 'rejected';
+	contact_name?: string;
+	notes?: string;
+	applied_at?: string;
+	follow_up_at?: string;
+	greeting?: string;
+	cta_text?: string;
+	cta_url?: string;
+	created: string;
+	updated: string;
+}
+
 // API helpers
 export async function fetchProfile(): Promise<Profile | null> {
 	try {
@@ -0,0 +1,74 @@
+// Resume exports are generated by a background job queue. POST /api/view/{slug}/generate
+// returns a status_url; these helpers follow that job until it finishes.
+
+export interface ExportJobEvent {
+	export_id: string;
+	status: string;
+	format: string;
+	progress: number;
+	message?: string;
+	error?: string;
+	download_url?: string;
+	generated_at?: string;
+}
+
+function isDone(ev: ExportJobEvent): boolean {
+	return ev.status === 'completed' || ev.status === 'failed';
+}
+
+// Follow a queued export over server-sent events until it completes or fails.
+// Uses fetch rather than EventSource so the access headers (auth, share token or
+// password token; the same ones the generate request used) are sent; falls back to polling.
+export async function followExport(
+	statusUrl: string,
+	headers: Record<string, string>,
+	onProgress?: (ev: ExportJobEvent) => void
+): Promise<ExportJobEvent> {
+
+	try {
+		const response = await fetch(`${statusUrl}/events`, { headers });
+		if (response.ok && response.body) {
+			const reader = response.body.pipeThrough(new TextDecoderStream()).getReader();
+			let buffer = '';
+			while (true) {
+				const { value, done } = await reader.read();
+				if (done) break;
+				buffer += value;
+				let boundary;
+				while ((boundary = buffer.indexOf('\n\n')) !== -1) {
+					const chunk = buffer.slice(0, boundary);
+					buffer = buffer.slice(boundary + 2);
+					const data = chunk.split('\n').find((line) => line.startsWith('data: '));
+					if (!data) continue;
+					const ev: ExportJobEvent = JSON.parse(data.slice(6));
+					onProgress?.(ev);
+					if (isDone(ev)) {
+						reader.cancel();
+						return ev;
+					}
+				}
+			}
+		}
+	} catch (err) {
+		console.warn('[AI-PRINT] Progress stream interrupted, polling instead:', err);
+	}
+
+	while (true) {
+		const response = await fetch(statusUrl, { headers });
+		const ev: ExportJobEvent = await response.json();
+		if (!response.ok) throw new Error((ev as unknown as { error?: string }).error || 'Failed to check status');
+		onProgress?.(ev);
+		if (isDone(ev)) return ev;
+		await new Promise((resolve) => setTimeout(resolve, 2000));
+	}
+}
+
+// Trigger a browser download for a generated export
+export function downloadExport(url: string, format: string) {
+	const link = document.createElement('a');
+	link.href = url;
+	link.download = `resume.${format}`;
+	document.body.appendChild(link);
+	link.click();
+	document.body.removeChild(link);
+}
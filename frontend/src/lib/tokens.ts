@@ -8,6 +8,8 @@ import type { Cookies } from '@sveltejs/kit';
 
 export const TOKEN_COOKIES = {
 	SHARE: 'me_share_token',
+	// Session of a verified recipient of a recipient-bound share token
+	SHARE_SESSION: 'me_share_session',
 	PASSWORD: 'me_password_token'
 } as const;
 
@@ -44,6 +46,51 @@ export function clearShareToken(cookies: Cookies) {
 	cookies.delete(TOKEN_COOKIES.SHARE, { path: '/' });
 }
 
+/**
+ * Set the share session JWT cookie of a verified recipient
+ */
+export function setShareSession(cookies: Cookies, session: string, maxAge = 7 * 24 * 60 * 60) {
+	cookies.set(TOKEN_COOKIES.SHARE_SESSION, session, {
+		...COOKIE_OPTIONS,
+		maxAge
+	});
+}
+
+/**
+ * Get share session from cookies
+ */
+export function getShareSession(cookies: Cookies): string | null {
+	return cookies.get(TOKEN_COOKIES.SHARE_SESSION) || null;
+}
+
+/**
+ * Verify a recipient-bound share token with the emailed code or magic link
+ * secret and store the resulting session. Returns false if the code was rejected.
+ * Pass the visitor headers so the backend rate limits the visitor, not this server.
+ */
+export async function confirmShareRecipient(
+	fetchFn: typeof fetch,
+	cookies: Cookies,
+	token: string,
+	proof: { code?: string; link?: string },
+	headers: Record<string, string> = {}
+): Promise<boolean> {
+	const pbUrl = process.env.POCKETBASE_URL || 'http://localhost:8090';
+	const response = await fetchFn(`${pbUrl}/api/share/verify/confirm`, {
+		method: 'POST',
+		headers: { 'Content-Type': 'application/json', ...headers },
+		body: JSON.stringify({ token, ...proof })
+	});
+	if (!response.ok) {
+		return false;
+	}
+
+	const result = await response.json();
+	const maxAge = Math.max(60, Math.floor((new Date(result.expires_at).getTime() - Date.now()) / 1000));
+	setShareSession(cookies, result.session, maxAge);
+	return true;
+}
+
 /**
  * Set a password JWT cookie
  */
@@ -74,14 +121,22 @@ export function clearPasswordToken(cookies: Cookies) {
  * Token transport:
  * - Password JWT: Authorization: Bearer <jwt> (standards compliant)
  * - Share token: X-Share-Token: <token> (custom header, avoids Authorization conflict)
+ * - Share session: X-Share-Session: <jwt> (recipient-bound share tokens only)
  */
-export function buildTokenHeaders(shareToken: string | null, passwordToken: string | null): Record<string, string> {
+export function buildTokenHeaders(
+	shareToken: string | null,
+	passwordToken: string | null,
+	shareSession: string | null = null
+): Record<string, string> {
 	const headers: Record<string, string> = {};
 
 	// Share tokens use a custom header to avoid conflict with password JWT
 	if (shareToken) {
 		headers['X-Share-Token'] = shareToken;
 	}
+	if (shareToken && shareSession) {
+		headers['X-Share-Session'] = shareSession;
+	}
 
 	// Password JWT uses standard Bearer authentication
 	if (passwordToken) {
@@ -0,0 +1,25 @@
+/**
+ * Visitor details forwarded from SvelteKit server loads to the backend.
+ *
+ * The backend only sees this server's requests, so the browser's User-Agent,
+ * Referer and address are passed along for first-party analytics and the
+ * access log. The backend keeps only a daily-salted IP hash, the browser
+ * family and the referrer host.
+ */
+export function visitorHeaders(request: Request, getClientAddress: () => string): Record<string, string> {
+	const headers: Record<string, string> = {};
+	const userAgent = request.headers.get('user-agent');
+	if (userAgent) {
+		headers['User-Agent'] = userAgent;
+	}
+	const referer = request.headers.get('referer');
+	if (referer) {
+		headers['Referer'] = referer;
+	}
+	try {
+		headers['X-Forwarded-For'] = getClientAddress();
+	} catch {
+		// Address unavailable (e.g. during prerendering)
+	}
+	return headers;
+}
@@ -5,22 +5,35 @@
  * - Renders public views directly
  * - Handles unlisted views with share token (from cookie)
  * - Handles password-protected views with password prompt and JWT flow
- * - Returns 404 for private views (non-discoverable)
+ * - Returns 404 for private views (non-discoverable), unless the visitor holds
+ *   a link from an approved access request
+ * - Offers a request access form on unlisted and private views that opt in
  *
  * Token flow:
  * - Share tokens: Set by /s/[token], stored in me_share_token cookie
+ * - Share sessions: Recipient-bound share tokens also need the emailed code;
+ *   the resulting JWT is stored in me_share_session
  * - Password JWTs: Set via form action, stored in me_password_token cookie
  */
 
 import type { PageServerLoad, Actions } from './$types';
-import { error, redirect } from '@sveltejs/kit';
-import { getShareToken, getPasswordToken, setPasswordToken, setShareToken } from '$lib/tokens';
+import { error, fail, redirect } from '@sveltejs/kit';
+import {
+	confirmShareRecipient,
+	getShareToken,
+	getShareSession,
+	getPasswordToken,
+	setPasswordToken,
+	setShareToken
+} from '$lib/tokens';
+import { visitorHeaders } from '$lib/visitor';
 
-export const load: PageServerLoad = async ({ params, cookies, url, fetch, locals }) => {
+export const load: PageServerLoad = async ({ params, cookies, url, fetch, locals, request, getClientAddress }) => {
 	const pbUrl = process.env.POCKETBASE_URL || 'http://localhost:8090';
 	const { slug } = params;
 
 	const shareToken = getShareToken(cookies);
+	const shareSession = getShareSession(cookies);
 	const passwordToken = getPasswordToken(cookies);
 	
 	const pbAuthToken = locals.pb?.authStore?.isValid ? locals.pb.authStore.token : null;
@@ -54,6 +67,10 @@ export const load: PageServerLoad = async ({ params, cookies, url, fetch, locals
 		if (pbAuthToken) {
 			accessHeaders['Authorization'] = `Bearer ${pbAuthToken}`;
 		}
+		// A private view only answers to a visitor holding an approved link
+		if (effectiveShareToken) {
+			accessHeaders['X-Share-Token'] = effectiveShareToken;
+		}
 		
 		const response = await fetch(`${pbUrl}/api/view/${slug}/access`, {
 			headers: accessHeaders
@@ -67,12 +84,32 @@ export const load: PageServerLoad = async ({ params, cookies, url, fetch, locals
 
 		const isAuthenticated = accessInfo.is_authenticated === true;
 
-		if (accessInfo.visibility === 'private' && !isAuthenticated) {
+		// Views that opt in offer a request access form instead of a 404
+		const notFoundOrRequestAccess = () => {
+			if (accessInfo.accepts_access_requests) {
+				return {
+					view: {
+						id: accessInfo.view_id,
+						slug,
+						name: accessInfo.view_name || 'Shared View',
+						hero_headline: undefined,
+						hero_summary: undefined,
+						cta_text: undefined,
+						cta_url: undefined,
+						accent_color: undefined,
+						hero_image_url: undefined
+					},
+					profile: null,
+					sections: {},
+					requiresPassword: false,
+					requiresAccessRequest: true
+				};
+			}
 			throw error(404, 'Not Found');
-		}
+		};
 
-		if (accessInfo.visibility === 'unlisted' && !isAuthenticated && !effectiveShareToken) {
-			throw error(404, 'Not Found');
+		if ((accessInfo.visibility === 'unlisted' || accessInfo.visibility === 'private') && !isAuthenticated && !effectiveShareToken) {
+			return notFoundOrRequestAccess();
 		}
 
 		if (accessInfo.visibility === 'password' && !isAuthenticated && !passwordToken) {
@@ -94,9 +131,12 @@ export const load: PageServerLoad = async ({ params, cookies, url, fetch, locals
 			};
 		}
 
-		const dataHeaders: Record<string, string> = {};
+		const dataHeaders: Record<string, string> = visitorHeaders(request, getClientAddress);
 		if (effectiveShareToken) {
 			dataHeaders['X-Share-Token'] = effectiveShareToken;
+			if (shareSession) {
+				dataHeaders['X-Share-Session'] = shareSession;
+			}
 		}
 		if (pbAuthToken) {
 			dataHeaders['Authorization'] = `Bearer ${pbAuthToken}`;
@@ -126,8 +166,34 @@ export const load: PageServerLoad = async ({ params, cookies, url, fetch, locals
 						requiresPassword: true
 					};
 				}
-				// Invalid share token for unlisted = 404 (not discoverable)
-				throw error(404, 'Not Found');
+				// A valid recipient-bound token whose recipient has not confirmed yet
+				const body = await dataResponse.json().catch(() => ({}));
+				if (body.verification_required) {
+					return {
+						view: {
+							id: accessInfo.view_id,
+							slug,
+							name: accessInfo.view_name || 'Shared View',
+							hero_headline: undefined,
+							hero_summary: undefined,
+							cta_text: undefined,
+							cta_url: undefined,
+							accent_color: undefined,
+							hero_image_url: undefined
+						},
+						profile: null,
+						sections: {},
+						requiresPassword: false,
+						requiresVerification: true,
+						recipientHint: body.recipient_hint || ''
+					};
+				}
+				// Invalid share token for unlisted or private = 404 (not discoverable)
+				return notFoundOrRequestAccess();
+			}
+			// Private views answer 404 to a link that does not open them
+			if (dataResponse.status === 404 && accessInfo.visibility === 'private') {
+				return notFoundOrRequestAccess();
 			}
 			throw error(404, 'Not Found');
 		}
@@ -146,6 +212,7 @@ export const load: PageServerLoad = async ({ params, cookies, url, fetch, locals
 				hero_summary: viewData.hero_summary,
 				cta_text: viewData.cta_text,
 				cta_url: viewData.cta_url,
+				greeting: viewData.greeting || null,
 				accent_color: viewData.accent_color || null,
 				hero_image_url: viewData.hero_image_url || null
 			},
@@ -158,8 +225,12 @@ export const load: PageServerLoad = async ({ params, cookies, url, fetch, locals
 			sectionOrder: viewData.section_order || [],
 			sectionLayouts: viewData.section_layouts || {},
 			sectionWidths: viewData.section_widths || {},
+			customSections: viewData.custom_sections || {},
 			requiresPassword: false,
-			shareToken: effectiveShareToken || null
+			shareToken: effectiveShareToken || null,
+			// Resume generation runs from the browser and needs the same access
+			shareSession: (effectiveShareToken && shareSession) || null,
+			passwordToken: (!isAuthenticated && passwordToken) || null
 		};
 	} catch (err) {
 		if ((err as { status?: number }).status === 404) {
@@ -182,5 +253,70 @@ export const actions: Actions = {
 		}
 
 		return { success: true };
+	},
+
+	// Ask the owner for access to an unlisted or private view
+	requestAccess: async ({ params, fetch, request, getClientAddress }) => {
+		const pbUrl = process.env.POCKETBASE_URL || 'http://localhost:8090';
+		const data = await request.formData();
+		const field = (name: string) => ((data.get(name) as string | null) || '').trim();
+
+		const response = await fetch(`${pbUrl}/api/view/${params.slug}/request-access`, {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json', ...visitorHeaders(request, getClientAddress) },
+			body: JSON.stringify({
+				name: field('name'),
+				email: field('email'),
+				company: field('company'),
+				reason: field('reason'),
+				website: field('website')
+			})
+		});
+		if (!response.ok) {
+			const body = await response.json().catch(() => ({}));
+			return fail(response.status, {
+				error: response.status === 429 ? 'Too many requests, try again later' : body.error || 'Failed to send request'
+			});
+		}
+
+		return { requested: true };
+	},
+
+	// Email a code to the recipient of the share token in the cookie
+	sendShareCode: async ({ cookies, fetch, request, getClientAddress }) => {
+		const pbUrl = process.env.POCKETBASE_URL || 'http://localhost:8090';
+		const token = getShareToken(cookies);
+		if (!token) {
+			return fail(400, { error: 'This link is no longer valid' });
+		}
+
+		const response = await fetch(`${pbUrl}/api/share/verify/start`, {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json', ...visitorHeaders(request, getClientAddress) },
+			body: JSON.stringify({ token })
+		});
+		if (!response.ok) {
+			const data = await response.json().catch(() => ({}));
+			return fail(response.status, {
+				error: response.status === 429 ? data.error || 'Too many requests, try again later' : data.error || 'Failed to send code'
+			});
+		}
+
+		return { sent: true };
+	},
+
+	// Exchange the emailed code for a share session cookie
+	verifyShareCode: async ({ cookies, fetch, request, getClientAddress }) => {
+		const token = getShareToken(cookies);
+		const code = ((await request.formData()).get('code') as string | null)?.trim() || '';
+		if (!token || !code) {
+			return fail(400, { error: 'Enter the code from the email' });
+		}
+
+		if (!(await confirmShareRecipient(fetch, cookies, token, { code }, visitorHeaders(request, getClientAddress)))) {
+			return fail(400, { error: 'That code is invalid or has expired' });
+		}
+
+		return { verified: true };
 	}
 };
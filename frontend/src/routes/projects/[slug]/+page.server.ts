@@ -8,13 +8,14 @@
 
 import type { PageServerLoad } from './$types';
 import { error } from '@sveltejs/kit';
+import { visitorHeaders } from '$lib/visitor';
 
-export const load: PageServerLoad = async ({ params, fetch, url, locals }) => {
+export const load: PageServerLoad = async ({ params, fetch, url, locals, request, getClientAddress }) => {
 	const pbUrl = process.env.POCKETBASE_URL || 'http://localhost:8090';
 	const { slug } = params;
 	const fromView = url.searchParams.get('from');
 
-	const headers: Record<string, string> = {};
+	const headers: Record<string, string> = visitorHeaders(request, getClientAddress);
 	const pbAuthToken = locals.pb?.authStore?.isValid ? locals.pb.authStore.token : null;
 	if (pbAuthToken) {
 		headers['Authorization'] = `Bearer ${pbAuthToken}`;
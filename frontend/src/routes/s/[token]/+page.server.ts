@@ -6,6 +6,11 @@
  * 2. Sets an httpOnly cookie (me_share_token) for subsequent requests
  * 3. Redirects to the canonical URL /<slug> (token NOT in URL)
  *
+ * Recipient-bound tokens also need the recipient to confirm their email. The
+ * emailed magic link comes back here with ?verify=<secret>, which is exchanged
+ * for a session cookie (me_share_session) before redirecting. Without it the
+ * view page asks for the emailed code.
+ *
  * The token is never exposed in the final URL, which:
  * - Prevents token leakage via browser history
  * - Prevents token leakage via Referer headers
@@ -14,9 +19,10 @@
 
 import type { PageServerLoad } from './$types';
 import { redirect } from '@sveltejs/kit';
-import { setShareToken } from '$lib/tokens';
+import { confirmShareRecipient, setShareToken } from '$lib/tokens';
+import { visitorHeaders } from '$lib/visitor';
 
-export const load: PageServerLoad = async ({ params, fetch, cookies }) => {
+export const load: PageServerLoad = async ({ params, url, fetch, cookies, request, getClientAddress }) => {
 	const { token } = params;
 	const pbUrl = process.env.POCKETBASE_URL || 'http://localhost:8090';
 
@@ -42,6 +48,12 @@ export const load: PageServerLoad = async ({ params, fetch, cookies }) => {
 		// Token is valid for 7 days (same as backend expiry)
 		setShareToken(cookies, token, 7 * 24 * 60 * 60);
 
+		const verify = url.searchParams.get('verify');
+		if (result.verification_required && verify) {
+			// A used or expired link falls back to asking for a code on the view page
+			await confirmShareRecipient(fetch, cookies, token, { link: verify }, visitorHeaders(request, getClientAddress));
+		}
+
 		// Redirect to the canonical URL WITHOUT token in URL (clean URLs)
 		throw redirect(302, `/${result.view_slug}`);
 	} catch (err) {